@@ -0,0 +1,14 @@
+// Package openapi holds the OpenAPI 3.1 description of the HTTP API.
+//
+// openapi.json is maintained by hand alongside router.go. Tests in
+// handler/http fail when a route is missing from it or a documented one no
+// longer exists, and every response in the handler and integration tests
+// is validated against it, so drift fails the build.
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI document served at /openapi.json.
+//
+//go:embed openapi.json
+var Spec []byte
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/srgjo27/agora/internal/config"
+)
+
+const configUsage = "penggunaan: agora-api config print [--redacted]"
+
+// runConfigCommand menangani `agora-api config print [--redacted]`, yang
+// mencetak konfigurasi hasil resolve beserta asal setiap nilainya.
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, configUsage)
+
+		return 2
+	}
+
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	redacted := fs.Bool("redacted", false, "ganti nilai rahasia (password, secret key) dengan <redacted>")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if err := config.Print(os.Stdout, ".", *redacted); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+
+		return 1
+	}
+
+	return 0
+}
@@ -1,58 +1,117 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/srgjo27/agora/internal/app"
 	"github.com/srgjo27/agora/internal/config"
-	"github.com/srgjo27/agora/internal/handler/http"
+	"github.com/srgjo27/agora/internal/logging"
 	"github.com/srgjo27/agora/internal/repository/postgres"
-	"github.com/srgjo27/agora/internal/service"
-	"github.com/srgjo27/agora/internal/usecase"
+	"github.com/srgjo27/agora/internal/tracing"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+
 	cfg, err := config.LoadConfig(".")
 	if err != nil {
 		log.Fatalf("[ERROR]: Tidak bisa memuat config: %v", err)
 	}
 
-	db := postgres.ConnectDB(&cfg)
-	log.Printf("[SUCCESS]: Berhasil terhubung ke DB: %s di host %s", cfg.DBName, cfg.DBHost)
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		log.Fatalf("[ERROR]: Tidak bisa menyiapkan logger: %v", err)
+	}
+	// Pesan dari package log ikut ditulis lewat logger yang sama.
+	slog.SetDefault(logger)
 
-	userRepo := postgres.NewPostgresUserRepo(db)
-	categoryRepo := postgres.NewPostgresCategoryRepo(db)
-	threadRepo := postgres.NewPostgresThreadRepo(db)
-	postRepo := postgres.NewPostgresPostRepo(db)
-	voteRepo := postgres.NewPostgresVoteRepo(db)
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg, "agora-api")
+	if err != nil {
+		log.Fatalf("[ERROR]: Tidak bisa menyiapkan tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
 
-	tokenSvc := service.NewTokenService(&cfg)
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("[ERROR]: Gagal mengirim sisa span tracing: %v", err)
+		}
+	}()
 
-	userUsecase := usecase.NewUserUsecase(userRepo, tokenSvc)
-	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo)
-	threadUsecase := usecase.NewThreadUsecase(threadRepo, categoryRepo, userRepo)
-	postUsecase := usecase.NewPostUsecase(postRepo, threadRepo, userRepo)
-	voteUsecase := usecase.NewVoteUsecase(db, voteRepo, threadRepo, postRepo)
+	// SIGINT/SIGTERM cancels ctx: during startup it aborts the database
+	// retries, afterwards it starts the graceful shutdown below.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	userHandler := http.NewUserHandler(userUsecase, &cfg)
-	categoryHandler := http.NewCategoryHandler(categoryUsecase)
-	threadHandler := http.NewThreadHandler(threadUsecase)
-	postHandler := http.NewPostHandler(postUsecase)
-	voteHandler := http.NewVoteHandler(voteUsecase)
+	db, err := postgres.ConnectDB(ctx, &cfg, logger)
+	if err != nil {
+		log.Fatalf("[ERROR]: %v", err)
+	}
+	defer db.Close()
+	log.Printf("[SUCCESS]: Berhasil terhubung ke DB: %s di host %s", cfg.DB.Name, cfg.DB.Host)
 
-	authMiddleware := http.NewAuthMiddleware(tokenSvc)
+	appCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
 
-	router := http.NewRouter(
-		userHandler,
-		authMiddleware,
-		categoryHandler,
-		threadHandler,
-		postHandler,
-		voteHandler,
-	)
+	api, err := app.New(appCtx, &cfg, db, logger)
+	if err != nil {
+		log.Fatalf("[ERROR]: %v", err)
+	}
 
-	serverAddress := ":" + cfg.APIPort
-	log.Printf("[SUCCESS]: Menjalankan server di %s", serverAddress)
-	if err := router.Run(serverAddress); err != nil {
-		log.Fatalf("[ERROR]: Gagal menjalankan server: %v", err)
+	serverAddress := ":" + cfg.HTTP.Port
+	srv := &http.Server{
+		Addr:         serverAddress,
+		Handler:      api.Router,
+		ReadTimeout:  time.Duration(cfg.HTTP.ReadTimeoutSeconds) * time.Second,
+		WriteTimeout: time.Duration(cfg.HTTP.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:  time.Duration(cfg.HTTP.IdleTimeoutSeconds) * time.Second,
 	}
+	srv.RegisterOnShutdown(api.CloseStreams)
+
+	go func() {
+		log.Printf("[SUCCESS]: Menjalankan server di %s", serverAddress)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("[ERROR]: Gagal menjalankan server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	// Sinyal kedua menghentikan proses tanpa menunggu.
+	stop()
+
+	log.Printf("[INFO]: Mematikan server...")
+
+	api.Drain()
+	if cfg.HTTP.ShutdownDrainSeconds > 0 {
+		log.Printf("[INFO]: Menunggu %d detik agar load balancer berhenti mengirim request", cfg.HTTP.ShutdownDrainSeconds)
+		time.Sleep(time.Duration(cfg.HTTP.ShutdownDrainSeconds) * time.Second)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.HTTP.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	if err := api.CloseWebSockets(shutdownCtx); err != nil {
+		log.Printf("[ERROR]: Gagal menutup koneksi WebSocket: %v", err)
+	}
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("[ERROR]: Gagal mematikan server dengan bersih: %v", err)
+	}
+
+	stopBackground()
+	if err := api.Wait(shutdownCtx); err != nil {
+		log.Printf("[ERROR]: Pekerjaan latar belakang belum selesai: %v", err)
+	}
+
+	log.Printf("[SUCCESS]: Server berhenti")
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/srgjo27/agora/internal/config"
+	"github.com/srgjo27/agora/internal/logging"
+	"github.com/srgjo27/agora/internal/mailer"
+	"github.com/srgjo27/agora/internal/repository/postgres"
+	"github.com/srgjo27/agora/internal/service"
+	"github.com/srgjo27/agora/internal/tracing"
+	"github.com/srgjo27/agora/internal/usecase"
+	"github.com/srgjo27/agora/internal/usecase/traced"
+	"github.com/srgjo27/agora/internal/worker"
+	"go.opentelemetry.io/otel"
+)
+
+func main() {
+	once := flag.Bool("once", false, "jalankan digest dan pengiriman webhook yang jatuh tempo satu kali lalu keluar (untuk cron)")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("[ERROR]: Tidak bisa memuat config: %v", err)
+	}
+
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		log.Fatalf("[ERROR]: Tidak bisa menyiapkan logger: %v", err)
+	}
+	// Pesan dari package log ikut ditulis lewat logger yang sama.
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg, "agora-worker")
+	if err != nil {
+		log.Fatalf("[ERROR]: Tidak bisa menyiapkan tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("[ERROR]: Gagal mengirim sisa span tracing: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := postgres.ConnectDB(ctx, &cfg, logger)
+	if err != nil {
+		log.Fatalf("[ERROR]: %v", err)
+	}
+	defer db.Close()
+
+	txManager := postgres.NewPostgresTxManager(db)
+	categoryRepo := postgres.NewPostgresCategoryRepo(db)
+	threadRepo := postgres.NewPostgresThreadRepo(db)
+	subscriptionRepo := postgres.NewPostgresSubscriptionRepo(db)
+	digestRepo := postgres.NewPostgresDigestRepo(db)
+	auditLogRepo := postgres.NewPostgresAuditLogRepo(db)
+	userRepo := postgres.NewPostgresUserRepo(db)
+	webhookRepo := postgres.NewPostgresWebhookRepo(db)
+
+	mailSender, err := mailer.New(&cfg)
+	if err != nil {
+		log.Fatalf("[ERROR]: Gagal menyiapkan pengirim email: %v", err)
+	}
+
+	digestRenderer := service.NewDigestRenderer(cfg.App.BaseURL, cfg.App.APIBaseURL)
+	digestUsecase := usecase.NewDigestUsecase(digestRepo, categoryRepo, threadRepo, subscriptionRepo, digestRenderer, mailSender, logger)
+	digestJob := worker.NewDigestJob(traced.NewTracedDigestUsecase(digestUsecase, otel.GetTracerProvider()), time.Duration(cfg.Digest.IntervalMinutes)*time.Minute, logger)
+
+	auditLogUsecase := usecase.NewAuditLogUsecase(auditLogRepo, userRepo)
+	webhookSender := service.NewWebhookSender(time.Duration(cfg.Webhooks.TimeoutSeconds) * time.Second)
+	webhookUsecase := usecase.NewWebhookUsecase(txManager, webhookRepo, webhookSender, auditLogUsecase, logger)
+	webhookJob := worker.NewWebhookDeliveryJob(
+		traced.NewTracedWebhookUsecase(webhookUsecase, otel.GetTracerProvider()),
+		time.Duration(cfg.Webhooks.DeliveryRetentionDays)*24*time.Hour,
+		time.Duration(cfg.Webhooks.PollIntervalSeconds)*time.Second,
+		logger,
+	)
+
+	if *once {
+		digestJob.RunOnce(ctx)
+		webhookJob.RunOnce(ctx)
+
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		digestJob.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		webhookJob.Run(ctx)
+	}()
+
+	log.Printf("[SUCCESS]: Worker digest berjalan setiap %d menit", cfg.Digest.IntervalMinutes)
+	log.Printf("[SUCCESS]: Worker webhook berjalan setiap %d detik", cfg.Webhooks.PollIntervalSeconds)
+	wg.Wait()
+
+	log.Printf("[SUCCESS]: Worker berhenti")
+}
@@ -0,0 +1,266 @@
+// Package app wires the API's repositories, usecases and handlers together.
+// cmd/api serves it and the integration tests drive it, so both exercise the
+// same router.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/config"
+	"github.com/srgjo27/agora/internal/eventbus"
+	"github.com/srgjo27/agora/internal/handler/http"
+	"github.com/srgjo27/agora/internal/mailer"
+	"github.com/srgjo27/agora/internal/metrics"
+	"github.com/srgjo27/agora/internal/realtime"
+	"github.com/srgjo27/agora/internal/repository/instrumented"
+	"github.com/srgjo27/agora/internal/repository/postgres"
+	"github.com/srgjo27/agora/internal/service"
+	"github.com/srgjo27/agora/internal/storage"
+	"github.com/srgjo27/agora/internal/usecase"
+	"github.com/srgjo27/agora/internal/usecase/traced"
+	"github.com/srgjo27/agora/internal/worker"
+	"go.opentelemetry.io/otel"
+)
+
+type App struct {
+	Router *gin.Engine
+
+	eventHandler     *http.EventHandler
+	webSocketHandler *http.WebSocketHandler
+	healthHandler    *http.HealthHandler
+	background       sync.WaitGroup
+}
+
+// New builds the API on db, logging requests and handler errors to logger.
+// The event hub, the outbox relay and the thread purge job run in the
+// background until ctx is
+// cancelled; Wait blocks until they have returned.
+func New(ctx context.Context, cfg *config.Config, db *sqlx.DB, logger *slog.Logger) (*App, error) {
+	m := metrics.New()
+	m.RegisterDB(db.DB, cfg.DB.Name)
+
+	userRepo := postgres.NewPostgresUserRepo(db)
+	categoryRepo := postgres.NewPostgresCategoryRepo(db)
+	threadRepo := instrumented.NewInstrumentedThreadRepo(postgres.NewPostgresThreadRepo(db), m)
+	postRepo := instrumented.NewInstrumentedPostRepo(postgres.NewPostgresPostRepo(db), m)
+	voteRepo := instrumented.NewInstrumentedVoteRepo(postgres.NewPostgresVoteRepo(db), m)
+	auditLogRepo := postgres.NewPostgresAuditLogRepo(db)
+	notificationRepo := postgres.NewPostgresNotificationRepo(db)
+	tagRepo := postgres.NewPostgresTagRepo(db)
+	mentionRepo := postgres.NewPostgresMentionRepo(db)
+	attachmentRepo := postgres.NewPostgresAttachmentRepo(db)
+	subscriptionRepo := postgres.NewPostgresSubscriptionRepo(db)
+	bookmarkRepo := postgres.NewPostgresBookmarkRepo(db)
+	digestRepo := postgres.NewPostgresDigestRepo(db)
+	webhookRepo := postgres.NewPostgresWebhookRepo(db)
+	outboxRepo := postgres.NewPostgresOutboxRepo(db)
+	processedEventRepo := postgres.NewPostgresProcessedEventRepo(db)
+	txManager := postgres.NewPostgresTxManager(db)
+
+	tokenSvc := service.NewTokenService(cfg)
+	contentRenderer := service.NewMarkdownRenderer()
+	digestRenderer := service.NewDigestRenderer(cfg.App.BaseURL, cfg.App.APIBaseURL)
+
+	var blobStore usecase.BlobStore
+	var err error
+	switch cfg.Storage.Driver {
+	case "s3":
+		blobStore, err = storage.NewS3BlobStore(cfg)
+	default:
+		blobStore, err = storage.NewLocalBlobStore(cfg.Storage.LocalDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyiapkan penyimpanan lampiran: %w", err)
+	}
+
+	mailSender, err := mailer.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyiapkan pengirim email: %w", err)
+	}
+
+	corsMiddleware, err := http.NewCORSMiddleware(cfg.CORS)
+	if err != nil {
+		return nil, fmt.Errorf("konfigurasi CORS tidak aman atau tidak valid: %w", err)
+	}
+
+	var broker realtime.Broker
+	switch cfg.Events.Broker {
+	case "local":
+		broker = realtime.NewLocalBroker(256)
+	default:
+		broker = postgres.NewPostgresEventBroker(db, cfg.DB.DSN(), logger)
+	}
+
+	a := &App{}
+
+	eventHub := realtime.NewHub(broker, logger)
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+
+		if err := eventHub.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.ErrorContext(ctx, "event hub stopped", "error", err)
+		}
+	}()
+
+	auditLogUsecase := usecase.NewAuditLogUsecase(auditLogRepo, userRepo)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepo, userRepo, eventHub, logger)
+	userUsecase := usecase.NewUserUsecase(txManager, userRepo, tokenSvc, auditLogUsecase)
+	categoryUsecase := usecase.NewCategoryUsecase(txManager, categoryRepo, auditLogUsecase)
+	subscriptionUsecase := usecase.NewSubscriptionUsecase(subscriptionRepo, threadRepo, postRepo, userRepo, categoryRepo, logger)
+	bookmarkUsecase := usecase.NewBookmarkUsecase(bookmarkRepo, threadRepo, postRepo, userRepo, categoryRepo)
+	webhookUsecase := usecase.NewWebhookUsecase(txManager, webhookRepo, service.NewWebhookSender(time.Duration(cfg.Webhooks.TimeoutSeconds)*time.Second), auditLogUsecase, logger)
+	digestUsecase := usecase.NewDigestUsecase(digestRepo, categoryRepo, threadRepo, subscriptionRepo, digestRenderer, mailSender, logger)
+	threadUsecase := usecase.NewThreadUsecase(txManager, threadRepo, categoryRepo, userRepo, tagRepo, mentionRepo, attachmentRepo, outboxRepo, auditLogUsecase, subscriptionUsecase, contentRenderer, logger)
+	postUsecase := usecase.NewPostUsecase(txManager, postRepo, threadRepo, userRepo, tagRepo, mentionRepo, attachmentRepo, outboxRepo, subscriptionUsecase, auditLogUsecase, contentRenderer, logger)
+	tagUsecase := usecase.NewTagUsecase(txManager, tagRepo, auditLogUsecase)
+	attachmentUsecase := usecase.NewAttachmentUsecase(
+		txManager,
+		attachmentRepo,
+		threadRepo,
+		postRepo,
+		blobStore,
+		auditLogUsecase,
+		int64(cfg.Storage.AttachmentMaxSizeMB)<<20,
+		int64(cfg.Storage.AttachmentQuotaMB)<<20,
+		logger,
+	)
+	voteUsecase := usecase.NewVoteUsecase(txManager, voteRepo, threadRepo, postRepo, outboxRepo)
+
+	// The relay runs here because the hub and its subscribers live in this
+	// process. Events can arrive more than once, so every consumer is
+	// wrapped with usecase.Deduplicate.
+	eventBus := eventbus.NewLocalBus()
+	eventBus.Subscribe(eventbus.AllEvents, usecase.RealtimeConsumer, usecase.Deduplicate(usecase.RealtimeConsumer, processedEventRepo, usecase.NewRealtimeHandler(eventHub)))
+	eventBus.Subscribe(eventbus.AllEvents, usecase.NotificationConsumer, usecase.Deduplicate(usecase.NotificationConsumer, processedEventRepo, usecase.NewNotificationHandler(notificationUsecase, threadRepo, postRepo)))
+	eventBus.Subscribe(eventbus.AllEvents, usecase.WebhookConsumer, usecase.Deduplicate(usecase.WebhookConsumer, processedEventRepo, usecase.NewWebhookHandler(webhookUsecase)))
+
+	outboxUsecase := usecase.NewOutboxUsecase(outboxRepo, processedEventRepo, eventBus, logger)
+	outboxJob := worker.NewOutboxRelayJob(
+		traced.NewTracedOutboxUsecase(outboxUsecase, otel.GetTracerProvider()),
+		time.Duration(cfg.Outbox.RetentionDays)*24*time.Hour,
+		time.Duration(cfg.Outbox.PollIntervalSeconds)*time.Second,
+		logger,
+	)
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		outboxJob.Run(ctx)
+	}()
+
+	purgeJob := worker.NewThreadPurgeJob(
+		threadUsecase,
+		attachmentUsecase,
+		time.Duration(cfg.Threads.RetentionDays)*24*time.Hour,
+		time.Duration(cfg.Threads.PurgeIntervalMinutes)*time.Minute,
+		logger,
+	)
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		purgeJob.Run(ctx)
+	}()
+
+	// Usecases are traced where handlers call them; calls between usecases
+	// show up as SQL spans under the caller's span.
+	tp := otel.GetTracerProvider()
+	tracedThreadUsecase := traced.NewTracedThreadUsecase(threadUsecase, tp)
+	tracedBookmarkUsecase := traced.NewTracedBookmarkUsecase(bookmarkUsecase, tp)
+
+	userHandler := http.NewUserHandler(traced.NewTracedUserUsecase(userUsecase, tp), cfg)
+	categoryHandler := http.NewCategoryHandler(traced.NewTracedCategoryUsecase(categoryUsecase, tp))
+	threadHandler := http.NewThreadHandler(tracedThreadUsecase, tracedBookmarkUsecase, logger)
+	postHandler := http.NewPostHandler(traced.NewTracedPostUsecase(postUsecase, tp), tracedBookmarkUsecase, logger)
+	voteHandler := http.NewVoteHandler(traced.NewTracedVoteUsecase(voteUsecase, tp))
+	auditLogHandler := http.NewAuditLogHandler(traced.NewTracedAuditLogUsecase(auditLogUsecase, tp))
+	notificationHandler := http.NewNotificationHandler(traced.NewTracedNotificationUsecase(notificationUsecase, tp))
+	eventHandler := http.NewEventHandler(tracedThreadUsecase, eventHub)
+	webSocketHandler := http.NewWebSocketHandler(tokenSvc, eventHub, eventHub, corsMiddleware, logger)
+	tagHandler := http.NewTagHandler(traced.NewTracedTagUsecase(tagUsecase, tp))
+	subscriptionHandler := http.NewSubscriptionHandler(traced.NewTracedSubscriptionUsecase(subscriptionUsecase, tp))
+	bookmarkHandler := http.NewBookmarkHandler(tracedBookmarkUsecase, logger)
+	digestHandler := http.NewDigestHandler(traced.NewTracedDigestUsecase(digestUsecase, tp))
+	webhookHandler := http.NewWebhookHandler(traced.NewTracedWebhookUsecase(webhookUsecase, tp))
+	attachmentHandler := http.NewAttachmentHandler(traced.NewTracedAttachmentUsecase(attachmentUsecase, tp), int64(cfg.Storage.AttachmentMaxSizeMB)<<20)
+
+	healthHandler := http.NewHealthHandler(
+		http.ReadinessCheck{Name: "database", Check: db.PingContext},
+		http.ReadinessCheck{Name: "schema", Check: func(ctx context.Context) error {
+			return postgres.CheckSchemaVersion(ctx, db)
+		}},
+	)
+
+	authMiddleware := http.NewAuthMiddleware(tokenSvc)
+
+	router := http.NewRouter(
+		userHandler,
+		authMiddleware,
+		corsMiddleware,
+		categoryHandler,
+		threadHandler,
+		postHandler,
+		voteHandler,
+		auditLogHandler,
+		notificationHandler,
+		eventHandler,
+		webSocketHandler,
+		tagHandler,
+		attachmentHandler,
+		subscriptionHandler,
+		bookmarkHandler,
+		digestHandler,
+		webhookHandler,
+		healthHandler,
+		logger,
+		m,
+	)
+
+	a.Router = router
+	a.eventHandler = eventHandler
+	a.webSocketHandler = webSocketHandler
+	a.healthHandler = healthHandler
+
+	return a, nil
+}
+
+// Drain makes /readyz fail so load balancers stop sending new requests,
+// while requests already in flight are still served.
+func (a *App) Drain() {
+	a.healthHandler.Drain()
+}
+
+// Wait blocks until the background jobs started by New have returned after
+// its context was cancelled, or until ctx expires.
+func (a *App) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		a.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CloseStreams ends open SSE streams so the HTTP server can drain. It is
+// meant for http.Server.RegisterOnShutdown.
+func (a *App) CloseStreams() {
+	a.eventHandler.Shutdown()
+}
+
+// CloseWebSockets sends a going-away frame to every WebSocket client and
+// waits for them to disconnect, or for ctx to expire.
+func (a *App) CloseWebSockets(ctx context.Context) error {
+	return a.webSocketHandler.Shutdown(ctx)
+}
@@ -1,47 +1,180 @@
+// Package config loads the settings shared by the API and the worker.
+//
+// Every setting has a fixed environment variable name. Values are resolved
+// from, lowest precedence first: built-in defaults, the defaults of the
+// active profile (APP_ENV), the .env file, the .env.<profile> file and the
+// process environment. A setting can instead be read from a file named by
+// <NAME>_FILE, e.g. DB_PASSWORD_FILE=/run/secrets/db_password, which keeps
+// secrets out of the environment.
 package config
 
-import (
-	"fmt"
+import "fmt"
 
-	"github.com/spf13/viper"
+// Profiles select environment-specific defaults and validation rules.
+const (
+	ProfileDev     = "dev"
+	ProfileStaging = "staging"
+	ProfileProd    = "prod"
 )
 
 type Config struct {
-	DBHost     string `mapstructure:"DB_HOST"`
-	DBPort     string `mapstructure:"DB_PORT"`
-	DBUser     string `mapstructure:"DB_USER"`
-	DBPassword string `mapstructure:"DB_PASSWORD"`
-	DBName     string `mapstructure:"DB_NAME"`
-	DBSslMode  string `mapstructure:"DB_SSLMODE"`
+	// Env is the active profile: dev, staging or prod.
+	Env string
 
-	APIPort string `mapstructure:"API_PORT"`
+	App      AppConfig
+	DB       DBConfig
+	HTTP     HTTPConfig
+	Log      LogConfig
+	Tracing  TracingConfig
+	Auth     AuthConfig
+	CORS     CORSConfig
+	Threads  ThreadsConfig
+	Events   EventsConfig
+	Storage  StorageConfig
+	Mail     MailConfig
+	Digest   DigestConfig
+	Webhooks WebhooksConfig
+	Outbox   OutboxConfig
+}
+
+// AppConfig holds the public URLs used in links inside emails.
+type AppConfig struct {
+	// BaseURL is the web app that thread links point to.
+	BaseURL string
+	// APIBaseURL is this API, used for one-click unsubscribe links.
+	APIBaseURL string
+}
 
-	JWTSecretKey               string `mapstructure:"JWT_SECRET_KEY"`
-	AccessTokenDurationMinutes int    `mapstructure:"JWT_ACCESS_TOKEN_DURATION_MINUTES"`
-	RefreshTokenDurationHours  int    `mapstructure:"JWT_REFRESH_TOKEN_DURATION_HOURS"`
+type DBConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
 
-	CookieDomain string `mapstructure:"COOKIE_DOMAIN"`
-	CookieSecure bool   `mapstructure:"COOKIE_SECURE"`
+	// ConnectTimeoutSeconds is how long startup keeps retrying while the
+	// database is unreachable.
+	ConnectTimeoutSeconds int
 }
 
-func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigFile(".env")
-	viper.AutomaticEnv()
+func (c *DBConfig) DSN() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s", c.User, c.Password, c.Host, c.Port, c.Name, c.SSLMode)
+}
 
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return config, err
-		}
-	}
+type HTTPConfig struct {
+	Port string
 
-	if err := viper.Unmarshal(&config); err != nil {
-		return config, err
-	}
+	ReadTimeoutSeconds  int
+	WriteTimeoutSeconds int
+	IdleTimeoutSeconds  int
+
+	// ShutdownDrainSeconds is how long /readyz fails before the server stops
+	// accepting requests, so load balancers can route traffic away.
+	ShutdownDrainSeconds   int
+	ShutdownTimeoutSeconds int
+}
+
+type LogConfig struct {
+	Level  string
+	Format string
+}
+
+type TracingConfig struct {
+	Exporter     string
+	File         string
+	OTLPEndpoint string
+	SampleRatio  float64
+}
+
+type AuthConfig struct {
+	JWTSecretKey               string
+	AccessTokenDurationMinutes int
+	RefreshTokenDurationHours  int
+
+	CookieDomain string
+	CookieSecure bool
+}
+
+type CORSConfig struct {
+	// Default applies to every route.
+	Default CORSPolicy
+	// PublicRead applies to the routes anyone may read, for origins the
+	// default policy does not allow. It is off while it has no origins.
+	PublicRead CORSPolicy
+}
+
+// CORSPolicy is one set of CORS rules. An origin is either exact, such as
+// https://app.example.com, "*", or a pattern with a wildcard subdomain
+// (https://*.example.com) or port (http://localhost:*).
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAgeSeconds    int
+}
+
+type ThreadsConfig struct {
+	// RetentionDays is how long soft-deleted threads are kept before they
+	// are purged permanently.
+	RetentionDays        int
+	PurgeIntervalMinutes int
+}
+
+type EventsConfig struct {
+	Broker string
+}
+
+type StorageConfig struct {
+	Driver   string
+	LocalDir string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+
+	AttachmentMaxSizeMB int
+	AttachmentQuotaMB   int
+}
 
-	return config, nil
+type MailConfig struct {
+	Driver  string
+	From    string
+	FileDir string
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+type DigestConfig struct {
+	IntervalMinutes int
+}
+
+type WebhooksConfig struct {
+	PollIntervalSeconds   int
+	TimeoutSeconds        int
+	DeliveryRetentionDays int
 }
 
-func (c *Config) DSN() string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s", c.DBUser, c.DBPassword, c.DBHost, c.DBPort, c.DBName, c.DBSslMode)
+type OutboxConfig struct {
+	PollIntervalSeconds int
+	RetentionDays       int
+}
+
+// LoadConfig resolves the configuration, reading .env files from the
+// directory path, and validates it.
+func LoadConfig(path string) (Config, error) {
+	cfg, _, err := load(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.Validate()
 }
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadConfigLayersAndSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".env", "APP_ENV=production\nDB_USER=agora\nDB_NAME=agora\nDB_HOST=from-dotenv\nLOG_LEVEL=debug\n")
+	writeFile(t, dir, ".env.prod", "DB_HOST=from-profile-file\n")
+	secret := writeFile(t, dir, "jwt", strings.Repeat("k", 40)+"\n")
+
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("JWT_SECRET_KEY_FILE", secret)
+	t.Setenv("CORS_ALLOWED_ORIGINS", " https://app.example.com, ,https://*.example.com")
+
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	if cfg.Env != ProfileProd {
+		t.Errorf("Env = %q, want %q", cfg.Env, ProfileProd)
+	}
+	if cfg.DB.Host != "from-profile-file" {
+		t.Errorf("DB.Host = %q, want the value from .env.prod", cfg.DB.Host)
+	}
+	if cfg.Log.Level != "warn" {
+		t.Errorf("Log.Level = %q, want the environment to win", cfg.Log.Level)
+	}
+	if cfg.Auth.JWTSecretKey != strings.Repeat("k", 40) {
+		t.Errorf("JWTSecretKey was not read from JWT_SECRET_KEY_FILE without its newline")
+	}
+	if !cfg.Auth.CookieSecure {
+		t.Error("CookieSecure is not on by default in prod")
+	}
+	if got := cfg.CORS.Default.AllowedOrigins; !slices.Equal(got, []string{"https://app.example.com", "https://*.example.com"}) {
+		t.Errorf("CORS.Default.AllowedOrigins = %q, want the trimmed list without blanks", got)
+	}
+	if cfg.Auth.AccessTokenDurationMinutes != 15 {
+		t.Errorf("AccessTokenDurationMinutes = %d, want the default 15", cfg.Auth.AccessTokenDurationMinutes)
+	}
+}
+
+func TestLoadConfigReportsEveryProblem(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".env", "DB_USER=agora\nDB_NAME=agora\nJWT_ACCESS_TOKEN_DURATION_MINUTES=0\nSTORAGE_DRIVER=s3\n")
+
+	_, err := LoadConfig(dir)
+	if err == nil {
+		t.Fatal("LoadConfig accepted an invalid configuration")
+	}
+
+	for _, want := range []string{
+		"JWT_SECRET_KEY is required",
+		"JWT_ACCESS_TOKEN_DURATION_MINUTES must be between 1 and 1440, got 0",
+		"S3_BUCKET is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadConfigRejectsValueAndFileTogether(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DB_PASSWORD", "inline")
+	t.Setenv("DB_PASSWORD_FILE", writeFile(t, dir, "db_password", "from-file"))
+
+	if _, err := LoadConfig(dir); err == nil || !strings.Contains(err.Error(), "DB_PASSWORD and DB_PASSWORD_FILE are both set") {
+		t.Errorf("got %v, want an error about both being set", err)
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".env", "DB_USER=agora\nDB_NAME=agora\nDB_PASSWORD=hunter2\nJWT_SECRET_KEY=dev-secret\n")
+
+	var out bytes.Buffer
+	if err := Print(&out, dir, true); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+
+	if strings.Contains(out.String(), "hunter2") || strings.Contains(out.String(), "dev-secret") {
+		t.Errorf("redacted output contains a secret:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "DB_USER=agora") {
+		t.Errorf("output does not contain DB_USER:\n%s", out.String())
+	}
+}
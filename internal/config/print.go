@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+const redactedValue = "<redacted>"
+
+// Print writes the resolved configuration in .env form, each line followed
+// by where the value came from. With redacted, secrets that are set are
+// replaced. The configuration is printed even when it does not validate, so
+// the output helps find the cause; the validation error is returned after.
+func Print(w io.Writer, path string, redacted bool) error {
+	cfg, values, loadErr := load(path)
+	if values == nil {
+		return loadErr
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range values {
+		value := r.value
+		if redacted && r.secret && value != "" {
+			value = redactedValue
+		}
+
+		fmt.Fprintf(tw, "%s=%s\t# %s\n", r.env, quote(value), r.source)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if loadErr != nil {
+		return loadErr
+	}
+
+	return cfg.Validate()
+}
+
+// quote wraps values that a dotenv parser would otherwise split or strip.
+func quote(value string) string {
+	for _, r := range value {
+		if r == ' ' || r == '#' || r == '"' || r == '\'' || r == '\\' {
+			return fmt.Sprintf("%q", value)
+		}
+	}
+
+	return value
+}
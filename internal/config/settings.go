@@ -0,0 +1,317 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// setting binds one environment variable to the Config field it fills.
+type setting struct {
+	env    string
+	target any // *string, *[]string, *int, *bool or *float64
+	def    string
+	secret bool
+}
+
+// settings lists every setting in the order they are printed.
+func (c *Config) settings() []setting {
+	return []setting{
+		{env: "APP_ENV", target: &c.Env, def: ProfileDev},
+		{env: "APP_BASE_URL", target: &c.App.BaseURL, def: "http://localhost:3000"},
+		{env: "API_BASE_URL", target: &c.App.APIBaseURL, def: "http://localhost:8080"},
+
+		{env: "DB_HOST", target: &c.DB.Host, def: "localhost"},
+		{env: "DB_PORT", target: &c.DB.Port, def: "5432"},
+		{env: "DB_USER", target: &c.DB.User},
+		{env: "DB_PASSWORD", target: &c.DB.Password, secret: true},
+		{env: "DB_NAME", target: &c.DB.Name},
+		{env: "DB_SSLMODE", target: &c.DB.SSLMode, def: "prefer"},
+		{env: "DB_CONNECT_TIMEOUT_SECONDS", target: &c.DB.ConnectTimeoutSeconds, def: "30"},
+
+		{env: "API_PORT", target: &c.HTTP.Port, def: "8080"},
+		{env: "HTTP_READ_TIMEOUT_SECONDS", target: &c.HTTP.ReadTimeoutSeconds, def: "30"},
+		{env: "HTTP_WRITE_TIMEOUT_SECONDS", target: &c.HTTP.WriteTimeoutSeconds, def: "30"},
+		{env: "HTTP_IDLE_TIMEOUT_SECONDS", target: &c.HTTP.IdleTimeoutSeconds, def: "120"},
+		{env: "SHUTDOWN_DRAIN_SECONDS", target: &c.HTTP.ShutdownDrainSeconds, def: "0"},
+		{env: "SHUTDOWN_TIMEOUT_SECONDS", target: &c.HTTP.ShutdownTimeoutSeconds, def: "15"},
+
+		{env: "LOG_LEVEL", target: &c.Log.Level, def: "info"},
+		{env: "LOG_FORMAT", target: &c.Log.Format, def: "json"},
+
+		{env: "TRACING_EXPORTER", target: &c.Tracing.Exporter, def: "none"},
+		{env: "TRACING_FILE", target: &c.Tracing.File, def: "./traces.jsonl"},
+		{env: "TRACING_OTLP_ENDPOINT", target: &c.Tracing.OTLPEndpoint, def: "http://localhost:4318"},
+		{env: "TRACING_SAMPLE_RATIO", target: &c.Tracing.SampleRatio, def: "1.0"},
+
+		{env: "JWT_SECRET_KEY", target: &c.Auth.JWTSecretKey, secret: true},
+		{env: "JWT_ACCESS_TOKEN_DURATION_MINUTES", target: &c.Auth.AccessTokenDurationMinutes, def: "15"},
+		{env: "JWT_REFRESH_TOKEN_DURATION_HOURS", target: &c.Auth.RefreshTokenDurationHours, def: "168"},
+		{env: "COOKIE_DOMAIN", target: &c.Auth.CookieDomain},
+		{env: "COOKIE_SECURE", target: &c.Auth.CookieSecure, def: "false"},
+
+		{env: "CORS_ALLOWED_ORIGINS", target: &c.CORS.Default.AllowedOrigins, def: "http://localhost:3000,http://localhost:5173,http://localhost:4200,http://localhost:8080"},
+		{env: "CORS_ALLOWED_METHODS", target: &c.CORS.Default.AllowedMethods, def: "GET,POST,PUT,PATCH,DELETE,OPTIONS"},
+		{env: "CORS_ALLOWED_HEADERS", target: &c.CORS.Default.AllowedHeaders, def: "Origin,Content-Type,Accept,Authorization,X-Requested-With,X-CSRF-Token,X-Request-ID,Content-Length"},
+		{env: "CORS_EXPOSED_HEADERS", target: &c.CORS.Default.ExposedHeaders, def: "Content-Length,X-Total-Count,X-Page,X-Per-Page,X-Request-ID"},
+		{env: "CORS_ALLOW_CREDENTIALS", target: &c.CORS.Default.AllowCredentials, def: "true"},
+		{env: "CORS_MAX_AGE_SECONDS", target: &c.CORS.Default.MaxAgeSeconds, def: "43200"},
+		{env: "CORS_PUBLIC_ALLOWED_ORIGINS", target: &c.CORS.PublicRead.AllowedOrigins},
+		{env: "CORS_PUBLIC_ALLOWED_METHODS", target: &c.CORS.PublicRead.AllowedMethods, def: "GET,HEAD,OPTIONS"},
+		{env: "CORS_PUBLIC_ALLOWED_HEADERS", target: &c.CORS.PublicRead.AllowedHeaders, def: "Accept,Content-Type,X-Request-ID"},
+		{env: "CORS_PUBLIC_EXPOSED_HEADERS", target: &c.CORS.PublicRead.ExposedHeaders, def: "Content-Length,X-Total-Count,X-Page,X-Per-Page,X-Request-ID"},
+		{env: "CORS_PUBLIC_ALLOW_CREDENTIALS", target: &c.CORS.PublicRead.AllowCredentials, def: "false"},
+		{env: "CORS_PUBLIC_MAX_AGE_SECONDS", target: &c.CORS.PublicRead.MaxAgeSeconds, def: "43200"},
+
+		{env: "THREAD_RETENTION_DAYS", target: &c.Threads.RetentionDays, def: "30"},
+		{env: "THREAD_PURGE_INTERVAL_MINUTES", target: &c.Threads.PurgeIntervalMinutes, def: "60"},
+
+		{env: "EVENT_BROKER", target: &c.Events.Broker, def: "postgres"},
+
+		{env: "STORAGE_DRIVER", target: &c.Storage.Driver, def: "local"},
+		{env: "STORAGE_LOCAL_DIR", target: &c.Storage.LocalDir, def: "./uploads"},
+		{env: "S3_ENDPOINT", target: &c.Storage.S3Endpoint},
+		{env: "S3_REGION", target: &c.Storage.S3Region, def: "us-east-1"},
+		{env: "S3_BUCKET", target: &c.Storage.S3Bucket},
+		{env: "S3_ACCESS_KEY", target: &c.Storage.S3AccessKey, secret: true},
+		{env: "S3_SECRET_KEY", target: &c.Storage.S3SecretKey, secret: true},
+		{env: "S3_USE_SSL", target: &c.Storage.S3UseSSL, def: "true"},
+		{env: "ATTACHMENT_MAX_SIZE_MB", target: &c.Storage.AttachmentMaxSizeMB, def: "10"},
+		{env: "ATTACHMENT_QUOTA_MB", target: &c.Storage.AttachmentQuotaMB, def: "100"},
+
+		{env: "MAIL_DRIVER", target: &c.Mail.Driver, def: "file"},
+		{env: "MAIL_FROM", target: &c.Mail.From, def: "Agora <no-reply@agora.local>"},
+		{env: "MAIL_FILE_DIR", target: &c.Mail.FileDir, def: "./mail"},
+		{env: "SMTP_HOST", target: &c.Mail.SMTPHost},
+		{env: "SMTP_PORT", target: &c.Mail.SMTPPort, def: "587"},
+		{env: "SMTP_USERNAME", target: &c.Mail.SMTPUsername},
+		{env: "SMTP_PASSWORD", target: &c.Mail.SMTPPassword, secret: true},
+
+		{env: "DIGEST_INTERVAL_MINUTES", target: &c.Digest.IntervalMinutes, def: "60"},
+
+		{env: "WEBHOOK_POLL_INTERVAL_SECONDS", target: &c.Webhooks.PollIntervalSeconds, def: "10"},
+		{env: "WEBHOOK_TIMEOUT_SECONDS", target: &c.Webhooks.TimeoutSeconds, def: "10"},
+		{env: "WEBHOOK_DELIVERY_RETENTION_DAYS", target: &c.Webhooks.DeliveryRetentionDays, def: "30"},
+
+		{env: "OUTBOX_POLL_INTERVAL_SECONDS", target: &c.Outbox.PollIntervalSeconds, def: "2"},
+		{env: "OUTBOX_RETENTION_DAYS", target: &c.Outbox.RetentionDays, def: "7"},
+	}
+}
+
+// profileDefaults override the built-in defaults for a profile. Anything
+// set explicitly still wins.
+var profileDefaults = map[string]map[string]string{
+	ProfileDev: {
+		"LOG_FORMAT": "text",
+	},
+	ProfileStaging: {
+		"COOKIE_SECURE": "true",
+	},
+	ProfileProd: {
+		"COOKIE_SECURE": "true",
+	},
+}
+
+// profileAliases accepts the long names used by older .env files.
+var profileAliases = map[string]string{
+	"development": ProfileDev,
+	"production":  ProfileProd,
+}
+
+// resolved is the value a setting ended up with and where it came from.
+type resolved struct {
+	setting
+	value  string
+	source string
+}
+
+// load resolves every setting without validating the result.
+func load(path string) (Config, []resolved, error) {
+	var cfg Config
+
+	dotenv, err := readEnvFile(filepath.Join(path, ".env"))
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	environ := readEnviron()
+
+	profile, profileSource, err := activeProfile(dotenv, environ)
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	profileFile, err := readEnvFile(filepath.Join(path, ".env."+profile))
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	layers := []layer{dotenv, profileFile, environ}
+
+	var values []resolved
+	var errs []string
+
+	for _, s := range cfg.settings() {
+		r := resolved{setting: s, value: s.def, source: "default"}
+		if v, ok := profileDefaults[profile][s.env]; ok {
+			r.value, r.source = v, "profile "+profile
+		}
+
+		if s.env == "APP_ENV" {
+			cfg.Env = profile
+			values = append(values, resolved{setting: s, value: profile, source: profileSource})
+
+			continue
+		}
+
+		for _, l := range layers {
+			v, hasValue := l.values[s.env]
+			file, hasFile := l.values[s.env+"_FILE"]
+
+			switch {
+			case hasValue && hasFile:
+				errs = append(errs, fmt.Sprintf("%s and %s_FILE are both set in %s", s.env, s.env, l.name))
+			case hasValue:
+				r.value, r.source = v, l.name
+			case hasFile:
+				content, err := os.ReadFile(file)
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("%s_FILE: %v", s.env, err))
+
+					continue
+				}
+				r.value, r.source = strings.TrimRight(string(content), "\r\n"), s.env+"_FILE in "+l.name
+			}
+		}
+
+		if err := assign(s.target, r.value); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", s.env, err))
+		}
+
+		values = append(values, r)
+	}
+
+	if len(errs) > 0 {
+		return cfg, values, fmt.Errorf("invalid configuration:\n  %s", strings.Join(errs, "\n  "))
+	}
+
+	return cfg, values, nil
+}
+
+// layer is one source of raw values, keyed by environment variable name.
+type layer struct {
+	name   string
+	values map[string]string
+}
+
+// readEnviron returns the process environment as a layer.
+func readEnviron() layer {
+	l := layer{name: "environment", values: map[string]string{}}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		// As before, an empty variable counts as unset.
+		if value != "" {
+			l.values[name] = value
+		}
+	}
+
+	return l
+}
+
+// readEnvFile reads a dotenv file; a missing file is an empty layer.
+func readEnvFile(file string) (layer, error) {
+	l := layer{name: filepath.Base(file), values: map[string]string{}}
+
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return l, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	v.SetConfigType("env")
+	if err := v.ReadInConfig(); err != nil {
+		return l, fmt.Errorf("%s: %w", l.name, err)
+	}
+
+	for key, value := range v.AllSettings() {
+		if s := fmt.Sprint(value); s != "" {
+			l.values[strings.ToUpper(key)] = s
+		}
+	}
+
+	return l, nil
+}
+
+// activeProfile reads APP_ENV, which can only come from .env or the
+// environment since it picks the profile file.
+func activeProfile(dotenv, environ layer) (string, string, error) {
+	profile, source := ProfileDev, "default"
+	for _, l := range []layer{dotenv, environ} {
+		if v, ok := l.values["APP_ENV"]; ok {
+			profile, source = strings.ToLower(v), l.name
+		}
+	}
+
+	if alias, ok := profileAliases[profile]; ok {
+		profile = alias
+	}
+
+	if _, ok := profileDefaults[profile]; !ok {
+		return "", "", fmt.Errorf("APP_ENV must be one of dev, staging, prod, got %q", profile)
+	}
+
+	return profile, source, nil
+}
+
+func assign(target any, value string) error {
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *[]string:
+		// A comma-separated list; blank items are dropped.
+		*t = nil
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*t = append(*t, item)
+			}
+		}
+	case *int:
+		if value == "" {
+			*t = 0
+
+			return nil
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		*t = n
+	case *bool:
+		if value == "" {
+			*t = false
+
+			return nil
+		}
+
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		*t = b
+	case *float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		*t = f
+	default:
+		panic(fmt.Sprintf("config: unsupported setting type %T", target))
+	}
+
+	return nil
+}
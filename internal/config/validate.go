@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// minProductionSecretLength is the shortest JWT secret accepted outside
+// dev; HS256 keys should be at least as long as the hash.
+const minProductionSecretLength = 32
+
+// Validate reports every missing or out of range setting at once, named by
+// its environment variable, so a broken deployment fails at startup rather
+// than on the first request.
+func (c *Config) Validate() error {
+	v := &validation{}
+
+	v.url("APP_BASE_URL", c.App.BaseURL)
+	v.url("API_BASE_URL", c.App.APIBaseURL)
+
+	v.required("DB_HOST", c.DB.Host)
+	v.port("DB_PORT", c.DB.Port)
+	v.required("DB_USER", c.DB.User)
+	v.required("DB_NAME", c.DB.Name)
+	v.oneOf("DB_SSLMODE", c.DB.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	v.atLeast("DB_CONNECT_TIMEOUT_SECONDS", c.DB.ConnectTimeoutSeconds, 0)
+
+	v.port("API_PORT", c.HTTP.Port)
+	v.atLeast("HTTP_READ_TIMEOUT_SECONDS", c.HTTP.ReadTimeoutSeconds, 1)
+	v.atLeast("HTTP_WRITE_TIMEOUT_SECONDS", c.HTTP.WriteTimeoutSeconds, 1)
+	v.atLeast("HTTP_IDLE_TIMEOUT_SECONDS", c.HTTP.IdleTimeoutSeconds, 1)
+	v.atLeast("SHUTDOWN_DRAIN_SECONDS", c.HTTP.ShutdownDrainSeconds, 0)
+	v.atLeast("SHUTDOWN_TIMEOUT_SECONDS", c.HTTP.ShutdownTimeoutSeconds, 1)
+
+	v.oneOf("LOG_LEVEL", strings.ToLower(c.Log.Level), "debug", "info", "warn", "error")
+	v.oneOf("LOG_FORMAT", strings.ToLower(c.Log.Format), "json", "text")
+
+	v.oneOf("TRACING_EXPORTER", c.Tracing.Exporter, "none", "stdout", "file", "otlp")
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.fail("TRACING_SAMPLE_RATIO", "must be between 0 and 1")
+	}
+	switch c.Tracing.Exporter {
+	case "file":
+		v.required("TRACING_FILE", c.Tracing.File)
+	case "otlp":
+		v.url("TRACING_OTLP_ENDPOINT", c.Tracing.OTLPEndpoint)
+	}
+
+	v.required("JWT_SECRET_KEY", c.Auth.JWTSecretKey)
+	v.between("JWT_ACCESS_TOKEN_DURATION_MINUTES", c.Auth.AccessTokenDurationMinutes, 1, 24*60)
+	v.between("JWT_REFRESH_TOKEN_DURATION_HOURS", c.Auth.RefreshTokenDurationHours, 1, 365*24)
+	if c.Env != ProfileDev {
+		if c.Auth.JWTSecretKey != "" && len(c.Auth.JWTSecretKey) < minProductionSecretLength {
+			v.fail("JWT_SECRET_KEY", fmt.Sprintf("must be at least %d characters outside dev", minProductionSecretLength))
+		}
+		if !c.Auth.CookieSecure {
+			v.fail("COOKIE_SECURE", "must be true outside dev")
+		}
+	}
+
+	v.atLeast("CORS_MAX_AGE_SECONDS", c.CORS.Default.MaxAgeSeconds, 0)
+	v.atLeast("CORS_PUBLIC_MAX_AGE_SECONDS", c.CORS.PublicRead.MaxAgeSeconds, 0)
+
+	v.atLeast("THREAD_RETENTION_DAYS", c.Threads.RetentionDays, 1)
+	v.atLeast("THREAD_PURGE_INTERVAL_MINUTES", c.Threads.PurgeIntervalMinutes, 1)
+
+	v.oneOf("EVENT_BROKER", c.Events.Broker, "postgres", "local")
+
+	v.oneOf("STORAGE_DRIVER", c.Storage.Driver, "local", "s3")
+	switch c.Storage.Driver {
+	case "local":
+		v.required("STORAGE_LOCAL_DIR", c.Storage.LocalDir)
+	case "s3":
+		v.required("S3_ENDPOINT", c.Storage.S3Endpoint)
+		v.required("S3_BUCKET", c.Storage.S3Bucket)
+		v.required("S3_ACCESS_KEY", c.Storage.S3AccessKey)
+		v.required("S3_SECRET_KEY", c.Storage.S3SecretKey)
+	}
+	v.atLeast("ATTACHMENT_MAX_SIZE_MB", c.Storage.AttachmentMaxSizeMB, 1)
+	v.atLeast("ATTACHMENT_QUOTA_MB", c.Storage.AttachmentQuotaMB, c.Storage.AttachmentMaxSizeMB)
+
+	v.oneOf("MAIL_DRIVER", c.Mail.Driver, "file", "smtp")
+	v.required("MAIL_FROM", c.Mail.From)
+	switch c.Mail.Driver {
+	case "file":
+		v.required("MAIL_FILE_DIR", c.Mail.FileDir)
+	case "smtp":
+		v.required("SMTP_HOST", c.Mail.SMTPHost)
+		v.port("SMTP_PORT", c.Mail.SMTPPort)
+	}
+
+	v.atLeast("DIGEST_INTERVAL_MINUTES", c.Digest.IntervalMinutes, 1)
+
+	v.atLeast("WEBHOOK_POLL_INTERVAL_SECONDS", c.Webhooks.PollIntervalSeconds, 1)
+	v.atLeast("WEBHOOK_TIMEOUT_SECONDS", c.Webhooks.TimeoutSeconds, 1)
+	v.atLeast("WEBHOOK_DELIVERY_RETENTION_DAYS", c.Webhooks.DeliveryRetentionDays, 1)
+
+	v.atLeast("OUTBOX_POLL_INTERVAL_SECONDS", c.Outbox.PollIntervalSeconds, 1)
+	v.atLeast("OUTBOX_RETENTION_DAYS", c.Outbox.RetentionDays, 1)
+
+	return v.err()
+}
+
+type validation struct {
+	problems []string
+}
+
+func (v *validation) fail(name, problem string) {
+	v.problems = append(v.problems, name+" "+problem)
+}
+
+func (v *validation) required(name, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.fail(name, "is required")
+	}
+}
+
+func (v *validation) atLeast(name string, value, min int) {
+	if value < min {
+		v.fail(name, fmt.Sprintf("must be at least %d, got %d", min, value))
+	}
+}
+
+func (v *validation) between(name string, value, min, max int) {
+	if value < min || value > max {
+		v.fail(name, fmt.Sprintf("must be between %d and %d, got %d", min, max, value))
+	}
+}
+
+func (v *validation) oneOf(name, value string, allowed ...string) {
+	if !slices.Contains(allowed, value) {
+		v.fail(name, fmt.Sprintf("must be one of %s, got %q", strings.Join(allowed, ", "), value))
+	}
+}
+
+func (v *validation) port(name, value string) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 65535 {
+		v.fail(name, fmt.Sprintf("must be a port number, got %q", value))
+	}
+}
+
+func (v *validation) url(name, value string) {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.fail(name, fmt.Sprintf("must be an http or https URL, got %q", value))
+	}
+}
+
+func (v *validation) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("invalid configuration:\n  %s", strings.Join(v.problems, "\n  "))
+}
@@ -0,0 +1,296 @@
+// Package contracttest checks HTTP responses against the OpenAPI document in
+// api/openapi. Tests that drive the router pass every response through a
+// Validator, so a handler that changes a response without the document
+// changing with it fails the build.
+package contracttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/srgjo27/agora/api/openapi"
+)
+
+// specURL names the document for the schema compiler; it is never fetched.
+const specURL = "openapi.json"
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Operation is a documented method and path template, such as GET
+// /api/v1/threads/{thread_id}.
+type Operation struct {
+	Method string
+	Path   string
+}
+
+// Validator matches responses to the operations of the document and
+// validates JSON bodies against their schemas. It is safe for concurrent
+// use.
+type Validator struct {
+	doc      map[string]any
+	compiler *jsonschema.Compiler
+
+	mu      sync.Mutex
+	schemas map[string]*jsonschema.Schema
+}
+
+// New loads the document and compiles every response schema in it, so a
+// broken reference fails here rather than in the test that first hits it.
+func New() (*Validator, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(openapi.Spec, &doc); err != nil {
+		return nil, fmt.Errorf("decode OpenAPI document: %w", err)
+	}
+
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, "3.1.") {
+		return nil, fmt.Errorf("OpenAPI version %q, want 3.1.x", version)
+	}
+
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(openapi.Spec))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	compiler.AssertFormat()
+	if err := compiler.AddResource(specURL, schemaDoc); err != nil {
+		return nil, err
+	}
+
+	v := &Validator{doc: doc, compiler: compiler, schemas: make(map[string]*jsonschema.Schema)}
+
+	for _, op := range v.Operations() {
+		responses, _ := v.operation(op.Path, op.Method)["responses"].(map[string]any)
+		if len(responses) == 0 {
+			return nil, fmt.Errorf("%s %s documents no responses", op.Method, op.Path)
+		}
+
+		for status := range responses {
+			response, pointer, err := v.response(op, status)
+			if err != nil {
+				return nil, err
+			}
+
+			content, _ := response["content"].(map[string]any)
+			for mediaType, media := range content {
+				if _, ok := media.(map[string]any)["schema"]; ok && isJSON(mediaType) {
+					if _, err := v.schema(pointer + "/content/" + escape(mediaType) + "/schema"); err != nil {
+						return nil, fmt.Errorf("%s %s %s: %w", op.Method, op.Path, status, err)
+					}
+				}
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// Operations lists every documented operation, sorted by path and method.
+func (v *Validator) Operations() []Operation {
+	var ops []Operation
+
+	paths, _ := v.doc["paths"].(map[string]any)
+	for path, item := range paths {
+		item, _ := item.(map[string]any)
+		for _, method := range methods {
+			if _, ok := item[method]; ok {
+				ops = append(ops, Operation{Method: strings.ToUpper(method), Path: path})
+			}
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+
+		return ops[i].Method < ops[j].Method
+	})
+
+	return ops
+}
+
+// ValidateResponse reports how a response to method and path (with or
+// without a query) departs from the document: an undocumented operation or
+// status, a content type the operation does not produce, or a JSON body
+// that does not match the schema. Error statuses may fall back to the
+// operation's default response; success statuses must be listed.
+func (v *Validator) ValidateResponse(method, path string, status int, header http.Header, body []byte) error {
+	path, _, _ = strings.Cut(path, "?")
+
+	template, ok := v.match(path)
+	if !ok {
+		return fmt.Errorf("%s %s: no documented path matches", method, path)
+	}
+
+	op := Operation{Method: strings.ToUpper(method), Path: template}
+	if v.operation(op.Path, op.Method) == nil {
+		return fmt.Errorf("%s %s: method not documented", op.Method, op.Path)
+	}
+
+	key := strconv.Itoa(status)
+	response, pointer, err := v.response(op, key)
+	if err != nil && status >= 400 {
+		response, pointer, err = v.response(op, "default")
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s: status %d not documented", op.Method, op.Path, status)
+	}
+
+	content, _ := response["content"].(map[string]any)
+	if len(content) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("%s %s %d: documented without a body, got %q", op.Method, op.Path, status, body)
+		}
+
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("%s %s %d: Content-Type %q: %v", op.Method, op.Path, status, header.Get("Content-Type"), err)
+	}
+
+	documented := mediaTypeKey(content, mediaType)
+	if documented == "" {
+		return fmt.Errorf("%s %s %d: Content-Type %s not documented", op.Method, op.Path, status, mediaType)
+	}
+
+	if _, ok := content[documented].(map[string]any)["schema"]; !ok || !isJSON(mediaType) {
+		return nil
+	}
+
+	schema, err := v.schema(pointer + "/content/" + escape(documented) + "/schema")
+	if err != nil {
+		return err
+	}
+
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s %s %d: body is not JSON: %v", op.Method, op.Path, status, err)
+	}
+
+	if err := schema.Validate(instance); err != nil {
+		return fmt.Errorf("%s %s %d: body does not match the document: %v\nbody: %s", op.Method, op.Path, status, err, body)
+	}
+
+	return nil
+}
+
+// match finds the documented path template for path. Literal segments win
+// over parameters, as in the router.
+func (v *Validator) match(path string) (string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	best, bestLiterals := "", -1
+	paths, _ := v.doc["paths"].(map[string]any)
+	for template := range paths {
+		parts := strings.Split(strings.Trim(template, "/"), "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+
+		literals := 0
+		matched := true
+		for i, part := range parts {
+			switch {
+			case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+				matched = matched && segments[i] != ""
+			case part == segments[i]:
+				literals++
+			default:
+				matched = false
+			}
+		}
+
+		if matched && literals > bestLiterals {
+			best, bestLiterals = template, literals
+		}
+	}
+
+	return best, bestLiterals >= 0
+}
+
+func (v *Validator) operation(path, method string) map[string]any {
+	paths, _ := v.doc["paths"].(map[string]any)
+	item, _ := paths[path].(map[string]any)
+	op, _ := item[strings.ToLower(method)].(map[string]any)
+
+	return op
+}
+
+// response returns the response object for status and its JSON pointer,
+// following a reference into components.
+func (v *Validator) response(op Operation, status string) (map[string]any, string, error) {
+	responses, _ := v.operation(op.Path, op.Method)["responses"].(map[string]any)
+	response, ok := responses[status].(map[string]any)
+	if !ok {
+		return nil, "", fmt.Errorf("%s %s: no %s response", op.Method, op.Path, status)
+	}
+
+	pointer := "#/paths/" + escape(op.Path) + "/" + strings.ToLower(op.Method) + "/responses/" + status
+
+	if ref, ok := response["$ref"].(string); ok {
+		name, found := strings.CutPrefix(ref, "#/components/responses/")
+		components, _ := v.doc["components"].(map[string]any)
+		shared, _ := components["responses"].(map[string]any)
+		response, ok = shared[name].(map[string]any)
+		if !found || !ok {
+			return nil, "", fmt.Errorf("%s %s %s: unresolved reference %s", op.Method, op.Path, status, ref)
+		}
+		pointer = ref
+	}
+
+	return response, pointer, nil
+}
+
+func (v *Validator) schema(pointer string) (*jsonschema.Schema, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if schema, ok := v.schemas[pointer]; ok {
+		return schema, nil
+	}
+
+	schema, err := v.compiler.Compile(specURL + pointer)
+	if err != nil {
+		return nil, err
+	}
+	v.schemas[pointer] = schema
+
+	return schema, nil
+}
+
+// mediaTypeKey picks the content entry for mediaType: an exact match, then
+// type/*, then */*.
+func mediaTypeKey(content map[string]any, mediaType string) string {
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, key := range []string{mediaType, major + "/*", "*/*"} {
+		if _, ok := content[key]; ok {
+			return key
+		}
+	}
+
+	return ""
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// escape encodes s as one JSON pointer token inside a URL fragment.
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	s = strings.ReplaceAll(s, "/", "~1")
+
+	return strings.NewReplacer("{", "%7B", "}", "%7D", "*", "%2A").Replace(s)
+}
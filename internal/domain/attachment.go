@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AllowedAttachmentTypes are the sniffed media types accepted for upload.
+var AllowedAttachmentTypes = map[string]bool{
+	"image/png":          true,
+	"image/jpeg":         true,
+	"image/gif":          true,
+	"image/webp":         true,
+	"application/pdf":    true,
+	"text/plain":         true,
+	"application/zip":    true,
+	"application/x-gzip": true,
+}
+
+type Attachment struct {
+	ID          uuid.UUID  `db:"id"`
+	UserID      uuid.UUID  `db:"user_id"`
+	ThreadID    *uuid.UUID `db:"thread_id"`
+	PostID      *uuid.UUID `db:"post_id"`
+	StorageKey  string     `db:"storage_key"`
+	Filename    string     `db:"filename"`
+	ContentType string     `db:"content_type"`
+	SizeBytes   int64      `db:"size_bytes"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
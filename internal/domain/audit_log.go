@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionThreadDelete     = "thread.delete"
+	AuditActionThreadUpdate     = "thread.update"
+	AuditActionThreadRestore    = "thread.restore"
+	AuditActionThreadLock       = "thread.lock"
+	AuditActionThreadUnlock     = "thread.unlock"
+	AuditActionPostUpdate       = "post.update"
+	AuditActionCategoryCreate   = "category.create"
+	AuditActionUserRoleUpdate   = "user.role_update"
+	AuditActionTagRename        = "tag.rename"
+	AuditActionTagMerge         = "tag.merge"
+	AuditActionWebhookCreate    = "webhook.create"
+	AuditActionWebhookUpdate    = "webhook.update"
+	AuditActionWebhookDelete    = "webhook.delete"
+	AuditActionAttachmentDelete = "attachment.delete"
+)
+
+const (
+	AuditTargetThread     = "thread"
+	AuditTargetPost       = "post"
+	AuditTargetCategory   = "category"
+	AuditTargetUser       = "user"
+	AuditTargetTag        = "tag"
+	AuditTargetWebhook    = "webhook"
+	AuditTargetAttachment = "attachment"
+)
+
+type AuditLog struct {
+	ID         uuid.UUID `db:"id"`
+	ActorID    uuid.UUID `db:"actor_id"`
+	ActorRole  string    `db:"actor_role"`
+	Action     string    `db:"action"`
+	TargetType string    `db:"target_type"`
+	TargetID   uuid.UUID `db:"target_id"`
+	Reason     *string   `db:"reason"`
+	Details    *string   `db:"details"`
+	CreatedAt  time.Time `db:"created_at"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BookmarkTypeThread = "thread"
+	BookmarkTypePost   = "post"
+
+	MaxBookmarkFolders = 50
+)
+
+type BookmarkFolder struct {
+	ID            uuid.UUID `db:"id"`
+	UserID        uuid.UUID `db:"user_id"`
+	Name          string    `db:"name"`
+	CreatedAt     time.Time `db:"created_at"`
+	BookmarkCount int       `db:"bookmark_count"`
+}
+
+type Bookmark struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	ThreadID  uuid.UUID  `db:"thread_id"`
+	PostID    *uuid.UUID `db:"post_id"`
+	FolderID  *uuid.UUID `db:"folder_id"`
+	Note      *string    `db:"note"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+	Thread    *Thread    `db:"-"`
+	Post      *Post      `db:"-"`
+}
+
+func (b *Bookmark) Type() string {
+	if b.PostID != nil {
+		return BookmarkTypePost
+	}
+
+	return BookmarkTypeThread
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+
+	DefaultDigestFrequency = DigestWeekly
+)
+
+// DigestPeriod is how much activity one digest covers, and how long after
+// the previous one the next is due. It is zero for DigestOff.
+func DigestPeriod(frequency string) time.Duration {
+	switch frequency {
+	case DigestDaily:
+		return 24 * time.Hour
+	case DigestWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+type DigestPreferences struct {
+	UserID           uuid.UUID  `db:"user_id"`
+	Frequency        string     `db:"frequency"`
+	UnsubscribeToken string     `db:"unsubscribe_token"`
+	LastSentAt       *time.Time `db:"last_sent_at"`
+	UpdatedAt        time.Time  `db:"updated_at"`
+}
+
+type DigestRecipient struct {
+	UserID     uuid.UUID  `db:"user_id"`
+	Username   string     `db:"username"`
+	Email      string     `db:"email"`
+	Frequency  string     `db:"frequency"`
+	LastSentAt *time.Time `db:"last_sent_at"`
+}
+
+// Digest is the content of one email. Threads in Unread carry their Thread.
+type Digest struct {
+	Recipient        *DigestRecipient
+	Since            time.Time
+	TopThreads       []*Thread
+	Unread           []*ThreadReadState
+	Categories       map[uuid.UUID]*Category
+	UnsubscribeToken string
+}
+
+func (d *Digest) IsEmpty() bool {
+	return len(d.TopThreads) == 0 && len(d.Unread) == 0
+}
+
+type EmailMessage struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+	Headers map[string]string
+}
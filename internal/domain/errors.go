@@ -1,12 +1,108 @@
 package domain
 
-import "errors"
+import "strings"
+
+// Code identifies the kind of an Error. Codes are sent to API clients, so an
+// existing code must never change meaning.
+type Code string
+
+const (
+	CodeInvalid              Code = "invalid"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeConflict             Code = "conflict"
+	CodeThreadLocked         Code = "thread_locked"
+	CodeFileTooLarge         Code = "file_too_large"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodeQuotaExceeded        Code = "quota_exceeded"
+	CodeLimitReached         Code = "limit_reached"
+	CodeUnavailable          Code = "unavailable"
+	CodeInternal             Code = "internal"
+)
+
+// Error is the error type returned across the usecase boundary. Two Errors
+// match under errors.Is when their codes are equal, so a detailed error such
+// as one built by Invalid still matches its sentinel:
+//
+//	errors.Is(domain.Invalid(domain.FieldError{Field: "title"}), domain.ErrInvalid) // true
+type Error struct {
+	Code Code
+	// Message describes the error in English for logs. Clients get a
+	// localized message chosen by Code instead.
+	Message string
+	// Fields lists the inputs that were rejected, if any.
+	Fields []FieldError
+	// Err is the underlying cause, if any.
+	Err error
+}
+
+// FieldError describes one rejected input.
+type FieldError struct {
+	// Field is the name the client used: a JSON field, query parameter or
+	// path parameter.
+	Field string
+	// Rule is the check that failed, e.g. "required", "uuid" or "max_length".
+	Rule string
+	// Param is the rule's argument, e.g. "255" for max_length.
+	Param string
+}
 
 var (
-	ErrConflict     = errors.New("data sudah ada")
-	ErrNotFound     = errors.New("data tidak ditemukan")
-	ErrInvalid      = errors.New("data tidak valid")
-	ErrUnauthorized = errors.New("autentikasi gagal")
-	ErrThreadLocked = errors.New("thread is locked")
-	ErrForbidden    = errors.New("forbidden")
+	ErrInvalid      = &Error{Code: CodeInvalid, Message: "invalid input"}
+	ErrUnauthorized = &Error{Code: CodeUnauthorized, Message: "authentication failed"}
+	ErrForbidden    = &Error{Code: CodeForbidden, Message: "forbidden"}
+	ErrNotFound     = &Error{Code: CodeNotFound, Message: "not found"}
+	ErrConflict     = &Error{Code: CodeConflict, Message: "already exists"}
+	ErrThreadLocked = &Error{Code: CodeThreadLocked, Message: "thread is locked"}
+
+	ErrFileTooLarge         = &Error{Code: CodeFileTooLarge, Message: "file is too large"}
+	ErrUnsupportedMediaType = &Error{Code: CodeUnsupportedMediaType, Message: "unsupported media type"}
+	ErrQuotaExceeded        = &Error{Code: CodeQuotaExceeded, Message: "storage quota exceeded"}
+	ErrLimitReached         = &Error{Code: CodeLimitReached, Message: "limit reached"}
+
+	ErrUnavailable = &Error{Code: CodeUnavailable, Message: "service unavailable"}
 )
+
+// Invalid returns an ErrInvalid naming the rejected fields.
+func Invalid(fields ...FieldError) *Error {
+	return &Error{Code: CodeInvalid, Message: ErrInvalid.Message, Fields: fields}
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Message)
+
+	for i, f := range e.Fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(f.Field)
+		b.WriteString(" fails ")
+		b.WriteString(f.Rule)
+		if f.Param != "" {
+			b.WriteString("=")
+			b.WriteString(f.Param)
+		}
+	}
+
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+
+	return ok && t.Code == e.Code
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventPostCreated      = "post.created"
+	EventPostUpdated      = "post.updated"
+	EventThreadUpdated    = "thread.updated"
+	EventThreadVoted      = "thread.voted"
+	EventPostVoted        = "post.voted"
+	EventThreadLockChange = "thread.lock_changed"
+	EventThreadCreated    = "thread.created"
+	EventThreadDeleted    = "thread.deleted"
+	EventThreadRestored   = "thread.restored"
+	EventNotification     = "notification.created"
+	EventTyping           = "typing"
+)
+
+// Event is a real-time notification fanned out to subscribers of a topic.
+// Data is kept small so it fits in broker payload limits (e.g. NOTIFY's 8000 bytes).
+type Event struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       string          `json:"type"`
+	Topic      string          `json:"topic"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	Truncated  bool            `json:"truncated,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+const (
+	TopicThread   = "thread"
+	TopicCategory = "category"
+	TopicUser     = "user"
+)
+
+func ThreadTopic(threadID uuid.UUID) string {
+	return TopicThread + ":" + threadID.String()
+}
+
+func CategoryTopic(categoryID uuid.UUID) string {
+	return TopicCategory + ":" + categoryID.String()
+}
+
+func UserTopic(userID uuid.UUID) string {
+	return TopicUser + ":" + userID.String()
+}
+
+type PostEventData struct {
+	ID           uuid.UUID  `json:"id"`
+	ThreadID     uuid.UUID  `json:"thread_id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	ParentPostID *uuid.UUID `json:"parent_post_id,omitempty"`
+	Content      string     `json:"content"`
+	ContentHTML  string     `json:"content_html"`
+	VoteCount    int        `json:"vote_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+type ThreadEventData struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Content     string     `json:"content"`
+	ContentHTML string     `json:"content_html"`
+	IsPinned    bool       `json:"is_pinned"`
+	IsLocked    bool       `json:"is_locked"`
+	VoteCount   int        `json:"vote_count"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+type ThreadCreatedEventData struct {
+	ID         uuid.UUID   `json:"id"`
+	Title      string      `json:"title"`
+	Slug       string      `json:"slug"`
+	UserID     uuid.UUID   `json:"user_id"`
+	CategoryID uuid.UUID   `json:"category_id"`
+	Mentioned  []uuid.UUID `json:"mentioned,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// ThreadRestoredEventData is a trashed thread brought back by an admin.
+type ThreadRestoredEventData struct {
+	ID         uuid.UUID `json:"id"`
+	RestoredBy uuid.UUID `json:"restored_by"`
+	RestoredAt time.Time `json:"restored_at"`
+}
+
+// PostCreatedEventData is a new post with the users it mentions.
+type PostCreatedEventData struct {
+	PostEventData
+	Mentioned []uuid.UUID `json:"mentioned,omitempty"`
+}
+
+type NotificationEventData struct {
+	ID        uuid.UUID  `json:"id"`
+	Type      string     `json:"type"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+	ThreadID  *uuid.UUID `json:"thread_id,omitempty"`
+	PostID    *uuid.UUID `json:"post_id,omitempty"`
+	Milestone *int       `json:"milestone,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type TypingEventData struct {
+	UserID   uuid.UUID `json:"user_id"`
+	ThreadID uuid.UUID `json:"thread_id"`
+}
+
+type VoteEventData struct {
+	ThreadID  uuid.UUID  `json:"thread_id"`
+	PostID    *uuid.UUID `json:"post_id,omitempty"`
+	VoteCount int        `json:"vote_count"`
+}
+
+func NewPostEventData(p *Post) *PostEventData {
+	return &PostEventData{
+		ID:           p.ID,
+		ThreadID:     p.ThreadID,
+		UserID:       p.UserID,
+		ParentPostID: p.ParentPostID,
+		Content:      p.Content,
+		ContentHTML:  p.ContentHTML,
+		VoteCount:    p.VoteCount,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+func NewThreadEventData(t *Thread) *ThreadEventData {
+	return &ThreadEventData{
+		ID:          t.ID,
+		Title:       t.Title,
+		Slug:        t.Slug,
+		Content:     t.Content,
+		ContentHTML: t.ContentHTML,
+		IsPinned:    t.IsPinned,
+		IsLocked:    t.IsLocked,
+		VoteCount:   t.VoteCount,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NotificationThreadReply   = "thread_reply"
+	NotificationPostReply     = "post_reply"
+	NotificationMention       = "mention"
+	NotificationVoteMilestone = "vote_milestone"
+)
+
+// VoteMilestones are the vote counts at which the content owner is notified.
+var VoteMilestones = []int{10, 25, 50, 100, 250, 500, 1000}
+
+// Notification is sent to UserID. EventID is the outbox event a reply or
+// mention notification was created for.
+type Notification struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	EventID   *uuid.UUID `db:"event_id"`
+	ActorID   *uuid.UUID `db:"actor_id"`
+	Type      string     `db:"type"`
+	ThreadID  *uuid.UUID `db:"thread_id"`
+	PostID    *uuid.UUID `db:"post_id"`
+	Milestone *int       `db:"milestone"`
+	IsRead    bool       `db:"is_read"`
+	CreatedAt time.Time  `db:"created_at"`
+	ReadAt    *time.Time `db:"read_at"`
+}
+
+type NotificationPreferences struct {
+	UserID        uuid.UUID `db:"user_id"`
+	ThreadReply   bool      `db:"thread_reply"`
+	PostReply     bool      `db:"post_reply"`
+	Mention       bool      `db:"mention"`
+	VoteMilestone bool      `db:"vote_milestone"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func DefaultNotificationPreferences(userID uuid.UUID) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:        userID,
+		ThreadReply:   true,
+		PostReply:     true,
+		Mention:       true,
+		VoteMilestone: true,
+	}
+}
+
+func (p *NotificationPreferences) Allows(notificationType string) bool {
+	switch notificationType {
+	case NotificationThreadReply:
+		return p.ThreadReply
+	case NotificationPostReply:
+		return p.PostReply
+	case NotificationMention:
+		return p.Mention
+	case NotificationVoteMilestone:
+		return p.VoteMilestone
+	default:
+		return false
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AggregateThread = "thread"
+	AggregatePost   = "post"
+)
+
+// OutboxEvent is a domain event recorded in the same transaction as the
+// change that raised it. It is published at least once; consumers use ID
+// to drop duplicates.
+type OutboxEvent struct {
+	ID            uuid.UUID  `db:"id"`
+	Seq           int64      `db:"seq"`
+	AggregateType string     `db:"aggregate_type"`
+	AggregateID   uuid.UUID  `db:"aggregate_id"`
+	EventType     string     `db:"event_type"`
+	Payload       []byte     `db:"payload"`
+	Attempts      int        `db:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	LastError     *string    `db:"last_error"`
+	CreatedAt     time.Time  `db:"created_at"`
+	PublishedAt   *time.Time `db:"published_at"`
+}
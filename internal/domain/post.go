@@ -7,12 +7,14 @@ import (
 )
 
 type Post struct {
-	ID           uuid.UUID  `db:"id"`
-	Content      string     `db:"content"`
-	UserID       uuid.UUID  `db:"user_id"`
-	ThreadID     uuid.UUID  `db:"thread_id"`
-	ParentPostID *uuid.UUID `db:"parent_post_id"`
-	VoteCount    int        `db:"vote_count"`
-	CreatedAt    time.Time  `db:"created_at"`
-	UpdatedAt    *time.Time `db:"updated_at"`
+	ID           uuid.UUID     `db:"id"`
+	Content      string        `db:"content"`
+	ContentHTML  string        `db:"content_html"`
+	UserID       uuid.UUID     `db:"user_id"`
+	ThreadID     uuid.UUID     `db:"thread_id"`
+	ParentPostID *uuid.UUID    `db:"parent_post_id"`
+	VoteCount    int           `db:"vote_count"`
+	CreatedAt    time.Time     `db:"created_at"`
+	UpdatedAt    *time.Time    `db:"updated_at"`
+	Attachments  []*Attachment `db:"-"`
 }
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ThreadReadPosition struct {
+	UserID         uuid.UUID  `db:"user_id"`
+	ThreadID       uuid.UUID  `db:"thread_id"`
+	LastReadPostID *uuid.UUID `db:"last_read_post_id"`
+	LastReadAt     time.Time  `db:"last_read_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+type ThreadReadState struct {
+	ThreadID          uuid.UUID  `db:"thread_id"`
+	SubscribedAt      *time.Time `db:"subscribed_at"`
+	LastReadPostID    *uuid.UUID `db:"last_read_post_id"`
+	LastReadAt        *time.Time `db:"last_read_at"`
+	UnreadCount       int        `db:"unread_count"`
+	FirstUnreadPostID *uuid.UUID `db:"first_unread_post_id"`
+	LastActivityAt    time.Time  `db:"last_activity_at"`
+	Thread            *Thread    `db:"-"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TagSourceExplicit = "explicit"
+	TagSourceContent  = "content"
+
+	MaxTagsPerThread = 10
+)
+
+var tagNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,49}$`)
+
+type Tag struct {
+	ID          uuid.UUID `db:"id"`
+	Name        string    `db:"name"`
+	CreatedAt   time.Time `db:"created_at"`
+	ThreadCount int       `db:"thread_count"`
+}
+
+type Mention struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	ActorID   uuid.UUID  `db:"actor_id"`
+	ThreadID  uuid.UUID  `db:"thread_id"`
+	PostID    *uuid.UUID `db:"post_id"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// NormalizeTagName lowercases name and strips a leading '#'. It reports
+// false if the result is not a valid tag name, using the same rules as
+// #tags written in content.
+func NormalizeTagName(name string) (string, bool) {
+	normalized := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+
+	return normalized, tagNamePattern.MatchString(normalized)
+}
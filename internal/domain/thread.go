@@ -7,15 +7,19 @@ import (
 )
 
 type Thread struct {
-	ID         uuid.UUID  `db:"id"`
-	Title      string     `db:"title"`
-	Slug       string     `db:"slug"`
-	Content    string     `db:"content"`
-	UserID     uuid.UUID  `db:"user_id"`
-	CategoryID uuid.UUID  `db:"category_id"`
-	IsPinned   bool       `db:"is_pinned"`
-	IsLocked   bool       `db:"is_locked"`
-	VoteCount  int        `db:"vote_count"`
-	CreatedAt  time.Time  `db:"created_at"`
-	UpdatedAt  *time.Time `db:"updated_at"`
+	ID          uuid.UUID     `db:"id"`
+	Title       string        `db:"title"`
+	Slug        string        `db:"slug"`
+	Content     string        `db:"content"`
+	ContentHTML string        `db:"content_html"`
+	UserID      uuid.UUID     `db:"user_id"`
+	CategoryID  uuid.UUID     `db:"category_id"`
+	IsPinned    bool          `db:"is_pinned"`
+	IsLocked    bool          `db:"is_locked"`
+	VoteCount   int           `db:"vote_count"`
+	CreatedAt   time.Time     `db:"created_at"`
+	UpdatedAt   *time.Time    `db:"updated_at"`
+	DeletedAt   *time.Time    `db:"deleted_at"`
+	Tags        []*Tag        `db:"-"`
+	Attachments []*Attachment `db:"-"`
 }
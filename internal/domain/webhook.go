@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookEventThreadCreated = "thread.created"
+	WebhookEventPostCreated   = "post.created"
+	WebhookEventThreadDeleted = "thread.deleted"
+	WebhookEventVoteCast      = "vote.cast"
+)
+
+// WebhookEventTypes are the events a webhook can subscribe to.
+var WebhookEventTypes = []string{
+	WebhookEventThreadCreated,
+	WebhookEventPostCreated,
+	WebhookEventThreadDeleted,
+	WebhookEventVoteCast,
+}
+
+func IsWebhookEventType(eventType string) bool {
+	for _, t := range WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nonPublicPrefixes are ranges IsPublicWebhookAddr refuses on top of the
+// private, loopback and link-local ones netip already knows about.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// IsPublicWebhookAddr reports whether a webhook may be delivered to addr.
+// Private, loopback and link-local addresses, including the cloud metadata
+// address 169.254.169.254, are refused so a webhook cannot reach internal
+// services.
+func IsPublicWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID          uuid.UUID  `db:"id"`
+	URL         string     `db:"url"`
+	Secret      string     `db:"secret"`
+	Description *string    `db:"description"`
+	IsActive    bool       `db:"is_active"`
+	CreatedBy   *uuid.UUID `db:"created_by"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+	Events      []string   `db:"-"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID  `db:"id"`
+	WebhookID      uuid.UUID  `db:"webhook_id"`
+	EventID        uuid.UUID  `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        []byte     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastAttemptAt  *time.Time `db:"last_attempt_at"`
+	ResponseStatus *int       `db:"response_status"`
+	ResponseBody   *string    `db:"response_body"`
+	LastError      *string    `db:"last_error"`
+	RedeliveryOf   *uuid.UUID `db:"redelivery_of"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+}
+
+// WebhookPayload is the JSON body posted to a webhook.
+type WebhookPayload struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type WebhookResponse struct {
+	StatusCode int
+	Body       string
+}
+
+type ThreadDeletedEventData struct {
+	ID        uuid.UUID `json:"id"`
+	DeletedBy uuid.UUID `json:"deleted_by"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// VoteCastEventData reports a vote that changed the target's vote count
+// from PreviousCount to VoteCount.
+type VoteCastEventData struct {
+	ThreadID      uuid.UUID  `json:"thread_id"`
+	PostID        *uuid.UUID `json:"post_id,omitempty"`
+	UserID        uuid.UUID  `json:"user_id"`
+	VoteType      int        `json:"vote_type"`
+	VoteCount     int        `json:"vote_count"`
+	PreviousCount int        `json:"previous_count"`
+}
@@ -0,0 +1,58 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// AllEvents subscribes a handler to every event type.
+const AllEvents = "*"
+
+// LocalBus is an in-process usecase.EventBus that hands each event to the
+// handlers subscribed to its type, synchronously. If any handler fails the
+// whole event is retried, so handlers that already succeeded see it again;
+// wrap them with usecase.Deduplicate.
+type LocalBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]namedHandler
+}
+
+type namedHandler struct {
+	name    string
+	handler usecase.EventHandler
+}
+
+func NewLocalBus() *LocalBus {
+	return &LocalBus{handlers: make(map[string][]namedHandler)}
+}
+
+// Subscribe registers handler under name for eventType, or for every event
+// when eventType is AllEvents. name identifies the consumer in errors.
+func (b *LocalBus) Subscribe(eventType, name string, handler usecase.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], namedHandler{name: name, handler: handler})
+}
+
+func (b *LocalBus) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	b.mu.RLock()
+	handlers := make([]namedHandler, 0, len(b.handlers[event.EventType])+len(b.handlers[AllEvents]))
+	handlers = append(handlers, b.handlers[event.EventType]...)
+	handlers = append(handlers, b.handlers[AllEvents]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
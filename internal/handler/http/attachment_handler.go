@@ -0,0 +1,157 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// multipartOverhead allows for the multipart boundaries and headers that
+// surround the file in an upload request.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	attachmentUsecase usecase.AttachmentUsecase
+	maxUploadSize     int64
+}
+
+func NewAttachmentHandler(au usecase.AttachmentUsecase, maxUploadSize int64) *AttachmentHandler {
+	return &AttachmentHandler{attachmentUsecase: au, maxUploadSize: maxUploadSize}
+}
+
+func (h *AttachmentHandler) UploadToThread(c *gin.Context) {
+	threadID, err := uuid.Parse(c.Param("thread_id"))
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	h.upload(c, func(userID uuid.UUID, role string, params usecase.UploadAttachmentParams) (*domain.Attachment, error) {
+		return h.attachmentUsecase.UploadToThread(c.Request.Context(), threadID, userID, role, params)
+	})
+}
+
+func (h *AttachmentHandler) UploadToPost(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("post_id"))
+	if err != nil {
+		c.Error(invalidParam("post_id", "uuid"))
+
+		return
+	}
+
+	h.upload(c, func(userID uuid.UUID, role string, params usecase.UploadAttachmentParams) (*domain.Attachment, error) {
+		return h.attachmentUsecase.UploadToPost(c.Request.Context(), postID, userID, role, params)
+	})
+}
+
+func (h *AttachmentHandler) upload(c *gin.Context, store func(uuid.UUID, string, usecase.UploadAttachmentParams) (*domain.Attachment, error)) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	role, _ := getUserRoleFromCtx(c)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.Error(domain.ErrFileTooLarge)
+
+			return
+		}
+
+		c.Error(invalidParam("file", "required"))
+
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+	defer file.Close()
+
+	attachment, err := store(userID, role, usecase.UploadAttachmentParams{
+		Filename: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		Content:  file,
+	})
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewAttachmentResponse(attachment))
+}
+
+// Download streams the attachment. Only images are shown inline; anything
+// else is served as a download so it cannot run in the site's origin.
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		c.Error(invalidParam("attachment_id", "uuid"))
+
+		return
+	}
+
+	attachment, content, err := h.attachmentUsecase.Open(c.Request.Context(), attachmentID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+	defer content.Close()
+
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	c.DataFromReader(http.StatusOK, attachment.SizeBytes, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":     mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options":  "nosniff",
+		"Content-Security-Policy": "sandbox",
+	})
+}
+
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		c.Error(invalidParam("attachment_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	role, _ := getUserRoleFromCtx(c)
+
+	err = h.attachmentUsecase.Delete(c.Request.Context(), attachmentID, userID, role)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "attachment deleted successfully"})
+}
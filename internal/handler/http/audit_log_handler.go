@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type AuditLogHandler struct {
+	auditLogUsecase usecase.AuditLogUsecase
+}
+
+func NewAuditLogHandler(au usecase.AuditLogUsecase) *AuditLogHandler {
+	return &AuditLogHandler{auditLogUsecase: au}
+}
+
+func (h *AuditLogHandler) GetAll(c *gin.Context) {
+	params, err := getPaginationParams(c)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	var filter usecase.AuditLogFilter
+
+	if v := c.Query("actor_id"); v != "" {
+		actorID, err := uuid.Parse(v)
+		if err != nil {
+			c.Error(invalidParam("actor_id", "uuid"))
+
+			return
+		}
+		filter.ActorID = &actorID
+	}
+
+	if v := c.Query("target_id"); v != "" {
+		targetID, err := uuid.Parse(v)
+		if err != nil {
+			c.Error(invalidParam("target_id", "uuid"))
+
+			return
+		}
+		filter.TargetID = &targetID
+	}
+
+	if v := c.Query("target_type"); v != "" {
+		filter.TargetType = &v
+	}
+
+	if v := c.Query("action"); v != "" {
+		filter.Action = &v
+	}
+
+	entries, userMap, totalItems, err := h.auditLogUsecase.GetAll(c.Request.Context(), filter, params)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*AuditLogResponse, len(entries))
+	for i, e := range entries {
+		dtos[i] = NewAuditLogResponse(e, userMap[e.ActorID])
+	}
+
+	response := gin.H{
+		"data": dtos,
+		"meta": newPaginationMeta(totalItems, params),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
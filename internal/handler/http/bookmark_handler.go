@@ -0,0 +1,316 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type BookmarkHandler struct {
+	bookmarkUsecase usecase.BookmarkUsecase
+	logger          *slog.Logger
+}
+
+func NewBookmarkHandler(bu usecase.BookmarkUsecase, logger *slog.Logger) *BookmarkHandler {
+	return &BookmarkHandler{bookmarkUsecase: bu, logger: logger}
+}
+
+func (h *BookmarkHandler) BookmarkThread(c *gin.Context) {
+	threadID, err := getThreadIDFromParam(c)
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	h.save(c, func(userID uuid.UUID, params usecase.SaveBookmarkParams) (*domain.Bookmark, error) {
+		return h.bookmarkUsecase.BookmarkThread(c.Request.Context(), userID, threadID, params)
+	})
+}
+
+func (h *BookmarkHandler) BookmarkPost(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("post_id"))
+	if err != nil {
+		c.Error(invalidParam("post_id", "uuid"))
+
+		return
+	}
+
+	h.save(c, func(userID uuid.UUID, params usecase.SaveBookmarkParams) (*domain.Bookmark, error) {
+		return h.bookmarkUsecase.BookmarkPost(c.Request.Context(), userID, postID, params)
+	})
+}
+
+func (h *BookmarkHandler) save(c *gin.Context, save func(uuid.UUID, usecase.SaveBookmarkParams) (*domain.Bookmark, error)) {
+	var req SaveBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	bookmark, err := save(userID, usecase.SaveBookmarkParams{FolderID: req.FolderID, Note: req.Note})
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewBookmarkResponse(bookmark, nil, nil))
+}
+
+func (h *BookmarkHandler) RemoveThreadBookmark(c *gin.Context) {
+	threadID, err := getThreadIDFromParam(c)
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	h.respondRemoved(c, h.bookmarkUsecase.RemoveThreadBookmark(c.Request.Context(), userID, threadID))
+}
+
+func (h *BookmarkHandler) RemovePostBookmark(c *gin.Context) {
+	postID, err := uuid.Parse(c.Param("post_id"))
+	if err != nil {
+		c.Error(invalidParam("post_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	h.respondRemoved(c, h.bookmarkUsecase.RemovePostBookmark(c.Request.Context(), userID, postID))
+}
+
+func (h *BookmarkHandler) respondRemoved(c *gin.Context, err error) {
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "bookmark removed successfully"})
+}
+
+// GetMine lists the caller's bookmarks, newest first. folder_id narrows the
+// list to one folder, or to unfiled bookmarks when it is "none"; type is
+// "thread" or "post".
+func (h *BookmarkHandler) GetMine(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	params, err := getPaginationParams(c)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	filter := usecase.BookmarkFilter{Type: c.Query("type")}
+	if folder := c.Query("folder_id"); folder == "none" {
+		filter.Unfiled = true
+	} else if folder != "" {
+		folderID, err := uuid.Parse(folder)
+		if err != nil {
+			c.Error(invalidParam("folder_id", "uuid"))
+
+			return
+		}
+		filter.FolderID = &folderID
+	}
+
+	bookmarks, userMap, catMap, totalItems, err := h.bookmarkUsecase.GetByUserID(c.Request.Context(), userID, filter, params)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*BookmarkResponse, len(bookmarks))
+	for i, b := range bookmarks {
+		dtos[i] = NewBookmarkResponse(b, userMap, catMap)
+	}
+
+	response := gin.H{
+		"data": dtos,
+		"meta": newPaginationMeta(totalItems, params),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BookmarkHandler) GetFolders(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	folders, err := h.bookmarkUsecase.GetFolders(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*BookmarkFolderResponse, len(folders))
+	for i, f := range folders {
+		dtos[i] = NewBookmarkFolderResponse(f)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dtos})
+}
+
+func (h *BookmarkHandler) CreateFolder(c *gin.Context) {
+	var req BookmarkFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	folder, err := h.bookmarkUsecase.CreateFolder(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewBookmarkFolderResponse(folder))
+}
+
+func (h *BookmarkHandler) RenameFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folder_id"))
+	if err != nil {
+		c.Error(invalidParam("folder_id", "uuid"))
+
+		return
+	}
+
+	var req BookmarkFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	folder, err := h.bookmarkUsecase.RenameFolder(c.Request.Context(), userID, folderID, req.Name)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewBookmarkFolderResponse(folder))
+}
+
+func (h *BookmarkHandler) DeleteFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folder_id"))
+	if err != nil {
+		c.Error(invalidParam("folder_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	if err := h.bookmarkUsecase.DeleteFolder(c.Request.Context(), userID, folderID); err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "folder deleted successfully"})
+}
+
+// bookmarkedThreads reports which of threadIDs the caller has bookmarked. It
+// returns nil for anonymous callers, which leaves the flag out of responses.
+func bookmarkedThreads(c *gin.Context, bu usecase.BookmarkUsecase, logger *slog.Logger, threadIDs ...uuid.UUID) map[uuid.UUID]bool {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists || len(threadIDs) == 0 {
+		return nil
+	}
+
+	flags, err := bu.GetBookmarkedThreadIDs(c.Request.Context(), userID, threadIDs)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "failed to load thread bookmarks", "user_id", userID, "error", err)
+
+		return nil
+	}
+
+	return flags
+}
+
+func bookmarkedPosts(c *gin.Context, bu usecase.BookmarkUsecase, logger *slog.Logger, postIDs ...uuid.UUID) map[uuid.UUID]bool {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists || len(postIDs) == 0 {
+		return nil
+	}
+
+	flags, err := bu.GetBookmarkedPostIDs(c.Request.Context(), userID, postIDs)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "failed to load post bookmarks", "user_id", userID, "error", err)
+
+		return nil
+	}
+
+	return flags
+}
+
+func bookmarkFlag(flags map[uuid.UUID]bool, id uuid.UUID) *bool {
+	if flags == nil {
+		return nil
+	}
+
+	bookmarked := flags[id]
+
+	return &bookmarked
+}
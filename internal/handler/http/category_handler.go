@@ -1,10 +1,10 @@
 package http
 
 import (
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/srgjo27/agora/internal/domain"
 	"github.com/srgjo27/agora/internal/usecase"
 )
@@ -20,21 +20,23 @@ func NewCategoryHandler(cu usecase.CategoryUsecase) *CategoryHandler {
 func (h *CategoryHandler) Create(c *gin.Context) {
 	var req CreateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
 
 		return
 	}
 
-	cat, err := h.categoryUsecase.Create(c.Request.Context(), req.Name, req.Description)
-	if err != nil {
-		if err == domain.ErrConflict {
-			c.JSON(http.StatusConflict, gin.H{"error": "category already exists"})
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
 
-			return
-		}
+	role, _ := getUserRoleFromCtx(c)
 
-		log.Fatalf("[ERROR]: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	cat, err := h.categoryUsecase.Create(c.Request.Context(), userID, role, req.Name, req.Description)
+	if err != nil {
+		c.Error(err)
 
 		return
 	}
@@ -45,9 +47,73 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 func (h *CategoryHandler) GetAll(c *gin.Context) {
 	cats, err := h.categoryUsecase.GetAll(c.Request.Context())
 	if err != nil {
-		log.Fatalf("[ERROR]: %v", err)
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewCategoryListResponse(cats))
+}
+
+func (h *CategoryHandler) Follow(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("category_id"))
+	if err != nil {
+		c.Error(invalidParam("category_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	if err := h.categoryUsecase.Follow(c.Request.Context(), userID, categoryID); err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category followed successfully"})
+}
+
+func (h *CategoryHandler) Unfollow(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("category_id"))
+	if err != nil {
+		c.Error(invalidParam("category_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
 
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	if err := h.categoryUsecase.Unfollow(c.Request.Context(), userID, categoryID); err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "category unfollowed successfully"})
+}
+
+func (h *CategoryHandler) GetFollowed(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	cats, err := h.categoryUsecase.GetFollowed(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
 
 		return
 	}
@@ -1,69 +1,231 @@
 package http
 
 import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/config"
 )
 
-// SetupCORS mengonfigurasi CORS middleware untuk aplikasi
-func SetupCORS() gin.HandlerFunc {
-	return cors.New(cors.Config{
-		// Daftar origin yang diizinkan
-		AllowOrigins: []string{
-			"http://localhost:3000",  // React development server
-			"http://localhost:5173",  // Vite development server
-			"http://localhost:8080",  // Local backend
-			"http://localhost:4200",  // Angular development server
-			"https://yourdomain.com", // Production domain
-		},
-
-		// Method HTTP yang diizinkan
-		AllowMethods: []string{
-			"GET",
-			"POST",
-			"PUT",
-			"PATCH",
-			"DELETE",
-			"OPTIONS",
-		},
-
-		// Header yang diizinkan
-		AllowHeaders: []string{
-			"Origin",
-			"Content-Type",
-			"Accept",
-			"Authorization",
-			"X-Requested-With",
-			"X-CSRF-Token",
-			"Content-Length",
-		},
-
-		// Header yang di-expose ke client
-		ExposeHeaders: []string{
-			"Content-Length",
-			"X-Total-Count",
-			"X-Page",
-			"X-Per-Page",
-		},
-
-		// Mengizinkan credentials (cookies, authorization headers)
-		AllowCredentials: true,
-
-		// Cache preflight request selama 12 jam
-		MaxAge: 12 * time.Hour,
+// CORSMiddleware applies the configured CORS policies. The default policy
+// covers every route; the public read policy, when configured, lets more
+// origins read publicReadRoutes without credentials. An origin the default
+// policy allows always gets the default policy, so the web app keeps sending
+// its token to public routes.
+type CORSMiddleware struct {
+	trusted    *corsPolicy
+	publicRead *corsPolicy
+	routes     [][]string
+}
+
+type corsPolicy struct {
+	allowAll bool
+	origins  []originPattern
+	handler  gin.HandlerFunc
+}
+
+// originPattern is an allowed origin. With subdomains, host is the parent
+// domain and only its subdomains match; port "*" matches any port.
+type originPattern struct {
+	scheme     string
+	host       string
+	port       string
+	subdomains bool
+}
+
+// NewCORSMiddleware builds the middleware from configuration. Invalid
+// origins and combinations browsers would reject or that expose
+// credentials to any site, such as "*" with credentials, are startup
+// errors rather than requests failing in the browser later.
+func NewCORSMiddleware(cfg config.CORSConfig) (*CORSMiddleware, error) {
+	trusted, err := newCORSPolicy(cfg.Default)
+	if err != nil {
+		return nil, fmt.Errorf("CORS_ALLOWED_*: %w", err)
+	}
+
+	m := &CORSMiddleware{trusted: trusted}
+
+	if len(cfg.PublicRead.AllowedOrigins) > 0 {
+		if m.publicRead, err = newCORSPolicy(cfg.PublicRead); err != nil {
+			return nil, fmt.Errorf("CORS_PUBLIC_*: %w", err)
+		}
+		if slices.ContainsFunc(cfg.PublicRead.AllowedMethods, func(method string) bool {
+			method = strings.ToUpper(method)
+
+			return method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions
+		}) {
+			return nil, fmt.Errorf("CORS_PUBLIC_ALLOWED_METHODS: the public read policy only covers GET, HEAD and OPTIONS")
+		}
+	}
+
+	for _, route := range publicReadRoutes {
+		m.routes = append(m.routes, strings.Split(strings.Trim(route, "/"), "/"))
+	}
+
+	return m, nil
+}
+
+func newCORSPolicy(cfg config.CORSPolicy) (*corsPolicy, error) {
+	if len(cfg.AllowedOrigins) == 0 {
+		return nil, fmt.Errorf("no allowed origins")
+	}
+
+	p := &corsPolicy{}
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			if cfg.AllowCredentials {
+				return nil, fmt.Errorf(`origin "*" cannot be combined with credentials; list the trusted origins instead`)
+			}
+			p.allowAll = true
+
+			continue
+		}
+
+		pattern, err := parseOriginPattern(origin)
+		if err != nil {
+			return nil, err
+		}
+		p.origins = append(p.origins, pattern)
+	}
+
+	if cfg.AllowCredentials {
+		for _, list := range [][]string{cfg.AllowedMethods, cfg.AllowedHeaders, cfg.ExposedHeaders} {
+			if slices.Contains(list, "*") {
+				return nil, fmt.Errorf(`"*" in methods or headers cannot be combined with credentials; browsers read it literally`)
+			}
+		}
+	}
+
+	corsConfig := cors.Config{
+		AllowMethods:     cfg.AllowedMethods,
+		AllowHeaders:     cfg.AllowedHeaders,
+		ExposeHeaders:    cfg.ExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           time.Duration(cfg.MaxAgeSeconds) * time.Second,
+	}
+	if p.allowAll {
+		corsConfig.AllowAllOrigins = true
+	} else {
+		corsConfig.AllowOriginFunc = p.allows
+	}
+	if err := corsConfig.Validate(); err != nil {
+		return nil, err
+	}
+	p.handler = cors.New(corsConfig)
+
+	return p, nil
+}
+
+// parseOriginPattern accepts scheme://host[:port], where host may start
+// with "*." and port may be "*".
+func parseOriginPattern(origin string) (originPattern, error) {
+	scheme, hostport, ok := strings.Cut(origin, "://")
+	if !ok || (scheme != "http" && scheme != "https") {
+		return originPattern{}, fmt.Errorf("origin %q must start with http:// or https://", origin)
+	}
+	if hostport == "" || strings.ContainsAny(hostport, "/?#@") {
+		return originPattern{}, fmt.Errorf("origin %q must be only scheme, host and port, without a path", origin)
+	}
+
+	p := originPattern{scheme: scheme, host: hostport}
+	if strings.LastIndex(hostport, ":") > strings.LastIndex(hostport, "]") {
+		host, port, err := net.SplitHostPort(hostport)
+		if err != nil {
+			return originPattern{}, fmt.Errorf("origin %q: %w", origin, err)
+		}
+		if n, err := strconv.Atoi(port); port != "*" && (err != nil || n < 1 || n > 65535) {
+			return originPattern{}, fmt.Errorf("origin %q has an invalid port", origin)
+		}
+		p.host, p.port = host, port
+	}
+	p.host = strings.ToLower(strings.Trim(p.host, "[]"))
+
+	if parent, ok := strings.CutPrefix(p.host, "*."); ok {
+		p.host, p.subdomains = parent, true
+		// A wildcard directly under a top-level domain would match sites
+		// run by anyone.
+		if !strings.Contains(parent, ".") && parent != "localhost" {
+			return originPattern{}, fmt.Errorf("origin %q matches every site under a top-level domain", origin)
+		}
+	}
+	if p.host == "" || strings.Contains(p.host, "*") {
+		return originPattern{}, fmt.Errorf(`origin %q: only a leading "*." subdomain wildcard is supported`, origin)
+	}
+
+	return p, nil
+}
+
+func (p originPattern) matches(scheme, host, port string) bool {
+	if scheme != p.scheme || (p.port != "*" && port != p.port) {
+		return false
+	}
+	if p.subdomains {
+		return strings.HasSuffix(host, "."+p.host)
+	}
+
+	return host == p.host
+}
+
+func (p *corsPolicy) allows(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	scheme, host, port := u.Scheme, strings.ToLower(u.Hostname()), u.Port()
+
+	return slices.ContainsFunc(p.origins, func(pattern originPattern) bool {
+		return pattern.matches(scheme, host, port)
 	})
 }
 
-// SetupCORSForDevelopment mengonfigurasi CORS dengan pengaturan lebih permisif untuk development
-func SetupCORSForDevelopment() gin.HandlerFunc {
-	return cors.New(cors.Config{
-		AllowAllOrigins:  true, // HANYA untuk development!
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"*"}, // Allow all headers
-		ExposeHeaders:    []string{"*"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
+// AllowsOrigin reports whether the default policy allows origin. The
+// WebSocket handshake uses it, since browsers do not apply CORS to it.
+func (m *CORSMiddleware) AllowsOrigin(origin string) bool {
+	return m.trusted.allows(origin)
+}
+
+// Apply picks the policy for each request. Preflight requests match no
+// route, so public read routes are recognised by path and by the method the
+// preflight asks for rather than by the matched route.
+func (m *CORSMiddleware) Apply() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.publicRead != nil && m.isPublicRead(c.Request) && !m.trusted.allows(c.GetHeader("Origin")) {
+			m.publicRead.handler(c)
+
+			return
+		}
+
+		m.trusted.handler(c)
+	}
+}
+
+func (m *CORSMiddleware) isPublicRead(r *http.Request) bool {
+	method := r.Method
+	if method == http.MethodOptions {
+		method = r.Header.Get("Access-Control-Request-Method")
+	}
+	if method != http.MethodGet && method != http.MethodHead {
+		return false
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	return slices.ContainsFunc(m.routes, func(route []string) bool {
+		return slices.EqualFunc(route, segments, func(want, got string) bool {
+			return want == got || (strings.HasPrefix(want, ":") && got != "")
+		})
 	})
 }
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/config"
+	"github.com/srgjo27/agora/internal/metrics"
+)
+
+func testCORSConfig() config.CORSConfig {
+	return config.CORSConfig{
+		Default: config.CORSPolicy{
+			AllowedOrigins:   []string{"https://app.example.com", "https://*.preview.example.com", "http://localhost:*"},
+			AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
+			AllowedHeaders:   []string{"Authorization", "Content-Type"},
+			AllowCredentials: true,
+		},
+		PublicRead: config.CORSPolicy{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET", "HEAD", "OPTIONS"},
+			AllowedHeaders: []string{"Accept"},
+		},
+	}
+}
+
+func newCORSTestRouter(t *testing.T, cfg config.CORSConfig) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	m, err := NewCORSMiddleware(cfg)
+	if err != nil {
+		t.Fatalf("NewCORSMiddleware: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(m.Apply())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/threads/:thread_id", ok)
+	router.POST("/api/v1/threads", ok)
+	router.GET("/api/v1/users/me", ok)
+
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, path, origin, preflightMethod string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Origin", origin)
+	if preflightMethod != "" {
+		req.Header.Set("Access-Control-Request-Method", preflightMethod)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestCORSMatchesConfiguredOriginPatterns(t *testing.T) {
+	router := newCORSTestRouter(t, testCORSConfig())
+
+	for _, tc := range []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://pr-12.preview.example.com", true},
+		{"http://localhost:5173", true},
+		{"https://preview.example.com", false},
+		{"https://evilpreview.example.com", false},
+		{"http://app.example.com", false},
+		{"https://app.example.com.evil.test", false},
+	} {
+		rec := corsRequest(router, http.MethodGet, "/api/v1/users/me", tc.origin, "")
+
+		got := rec.Header().Get("Access-Control-Allow-Origin") == tc.origin
+		if got != tc.allowed {
+			t.Errorf("origin %s: allowed = %v, want %v (status %d)", tc.origin, got, tc.allowed, rec.Code)
+		}
+	}
+}
+
+func TestCORSPublicReadPolicyOnlyCoversPublicReads(t *testing.T) {
+	router := newCORSTestRouter(t, testCORSConfig())
+	const stranger = "https://forum-widget.test"
+
+	rec := corsRequest(router, http.MethodOptions, "/api/v1/threads/42", stranger, http.MethodGet)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("preflight for a public read: Allow-Origin = %q, want *", rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Error("the public read policy allows credentials")
+	}
+
+	rec = corsRequest(router, http.MethodGet, "/api/v1/threads/42", stranger, "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("public read: status %d, Allow-Origin %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	for _, tc := range []struct{ method, path, preflight string }{
+		{http.MethodOptions, "/api/v1/threads", http.MethodPost},
+		{http.MethodPost, "/api/v1/threads", ""},
+		{http.MethodGet, "/api/v1/users/me", ""},
+	} {
+		if rec := corsRequest(router, tc.method, tc.path, stranger, tc.preflight); rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s from an untrusted origin: status %d, want 403", tc.method, tc.path, rec.Code)
+		}
+	}
+
+	// The web app keeps its credentialed policy on public routes.
+	rec = corsRequest(router, http.MethodGet, "/api/v1/threads/42", "https://app.example.com", "")
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Error("a trusted origin lost credentials on a public read route")
+	}
+}
+
+func TestCORSRejectsUnsafeConfiguration(t *testing.T) {
+	for name, tc := range map[string]struct {
+		edit func(*config.CORSConfig)
+		want string
+	}{
+		"any origin with credentials": {
+			func(c *config.CORSConfig) { c.Default.AllowedOrigins = []string{"*"} },
+			"cannot be combined with credentials",
+		},
+		"any header with credentials": {
+			func(c *config.CORSConfig) { c.Default.AllowedHeaders = []string{"*"} },
+			"cannot be combined with credentials",
+		},
+		"wildcard under a top-level domain": {
+			func(c *config.CORSConfig) { c.Default.AllowedOrigins = []string{"https://*.com"} },
+			"top-level domain",
+		},
+		"wildcard inside a label": {
+			func(c *config.CORSConfig) { c.Default.AllowedOrigins = []string{"https://app-*.example.com"} },
+			"subdomain wildcard",
+		},
+		"origin with a path": {
+			func(c *config.CORSConfig) { c.Default.AllowedOrigins = []string{"https://app.example.com/"} },
+			"without a path",
+		},
+		"null origin": {
+			func(c *config.CORSConfig) { c.Default.AllowedOrigins = []string{"null"} },
+			"must start with http",
+		},
+		"writes in the public policy": {
+			func(c *config.CORSConfig) { c.PublicRead.AllowedMethods = []string{"GET", "POST"} },
+			"CORS_PUBLIC_ALLOWED_METHODS",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := testCORSConfig()
+			tc.edit(&cfg)
+
+			_, err := NewCORSMiddleware(cfg)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("NewCORSMiddleware error = %v, want it to mention %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestPublicReadRoutesAreRegistered(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := NewRouter(
+		&UserHandler{}, &AuthMiddleware{}, &CORSMiddleware{}, &CategoryHandler{}, &ThreadHandler{},
+		&PostHandler{}, &VoteHandler{}, &AuditLogHandler{}, &NotificationHandler{}, &EventHandler{},
+		&WebSocketHandler{}, &TagHandler{}, &AttachmentHandler{}, &SubscriptionHandler{},
+		&BookmarkHandler{}, &DigestHandler{}, &WebhookHandler{}, &HealthHandler{},
+		slog.Default(), metrics.New(),
+	)
+
+	routes := router.Routes()
+	for _, path := range publicReadRoutes {
+		if !slices.ContainsFunc(routes, func(r gin.RouteInfo) bool { return r.Method == http.MethodGet && r.Path == path }) {
+			t.Errorf("public read route GET %s is not registered", path)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// unsubscribePage asks for confirmation before a link click changes
+// anything; link scanners and prefetchers only ever issue the GET.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Unsubscribe from Agora digests</title>
+</head>
+<body>
+  <h1>Unsubscribe from Agora digests?</h1>
+  <p>You will no longer receive digest emails. You can turn them back on in your settings.</p>
+  <form method="post" action="?token={{.}}">
+    <button type="submit">Unsubscribe</button>
+  </form>
+</body>
+</html>
+`))
+
+const unsubscribedPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Unsubscribed from Agora digests</title>
+</head>
+<body>
+  <h1>You have been unsubscribed from email digests</h1>
+</body>
+</html>
+`
+
+type DigestHandler struct {
+	digestUsecase usecase.DigestUsecase
+}
+
+func NewDigestHandler(du usecase.DigestUsecase) *DigestHandler {
+	return &DigestHandler{digestUsecase: du}
+}
+
+func (h *DigestHandler) GetPreferences(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	prefs, err := h.digestUsecase.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewDigestPreferencesResponse(prefs))
+}
+
+func (h *DigestHandler) UpdatePreferences(c *gin.Context) {
+	var req UpdateDigestPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	prefs, err := h.digestUsecase.UpdateFrequency(c.Request.Context(), userID, req.Frequency)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewDigestPreferencesResponse(prefs))
+}
+
+// ConfirmUnsubscribe answers a click on the link in a digest email with a
+// page that posts back to Unsubscribe. It changes nothing itself.
+func (h *DigestHandler) ConfirmUnsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.Error(domain.ErrNotFound)
+
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := unsubscribePage.Execute(c.Writer, token); err != nil {
+		c.Error(err)
+	}
+}
+
+// Unsubscribe turns digests off for the owner of the token in the link. It
+// answers both the confirmation page and the RFC 8058 one-click POST that
+// mail clients send on the user's behalf; only browsers are sent HTML back.
+func (h *DigestHandler) Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+
+	if err := h.digestUsecase.Unsubscribe(c.Request.Context(), token); err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(unsubscribedPage))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "you have been unsubscribed from email digests"})
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// unsubscribeRecorder is a DigestUsecase that records unsubscribe tokens.
+type unsubscribeRecorder struct {
+	usecase.DigestUsecase
+	tokens []string
+}
+
+func (u *unsubscribeRecorder) Unsubscribe(ctx context.Context, token string) error {
+	u.tokens = append(u.tokens, token)
+
+	return nil
+}
+
+func TestDigestUnsubscribeChangesStateOnlyOnPost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	digests := &unsubscribeRecorder{}
+	handler := NewDigestHandler(digests)
+
+	router := gin.New()
+	router.GET("/digest/unsubscribe", handler.ConfirmUnsubscribe)
+	router.POST("/digest/unsubscribe", handler.Unsubscribe)
+
+	serve := func(method, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/digest/unsubscribe?token=a%22b%3Cc", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "text/html")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("GET = %d %s, want a 200 HTML page", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if len(digests.tokens) != 0 {
+		t.Fatalf("GET unsubscribed %v", digests.tokens)
+	}
+	if !strings.Contains(rec.Body.String(), `<form method="post" action="?token=a%22b%3cc">`) {
+		t.Errorf("GET page does not post the escaped token back:\n%s", rec.Body)
+	}
+
+	// Mail clients sending the one-click POST get JSON, browsers get a page.
+	if rec := serve(http.MethodPost, ""); rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("one-click POST = %d %s, want 200 JSON", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if rec := serve(http.MethodPost, "text/html,application/xhtml+xml,*/*;q=0.8"); rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("browser POST = %d %s, want 200 HTML", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	if len(digests.tokens) != 2 || digests.tokens[0] != `a"b<c` {
+		t.Errorf("unsubscribed %q, want the token twice", digests.tokens)
+	}
+}
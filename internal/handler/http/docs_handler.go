@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/api/openapi"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// The Swagger UI assets are embedded from github.com/swaggo/files/v2, which
+// pins the swagger-ui-dist release (v2.0.2 carries 5.18.2). Upgrading that
+// module upgrades the docs page.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Agora API</title>
+  <link rel="stylesheet" href="/docs/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="/docs/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+var swaggerUIAssets = http.FS(swaggerFiles.FS)
+
+// OpenAPISpec serves the OpenAPI document describing every route.
+func OpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openapi.Spec)
+}
+
+// SwaggerUI serves a page for browsing and trying the API. It loads its
+// assets from SwaggerUIAsset, so the page works without reaching a CDN.
+func SwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
+
+// SwaggerUIAsset serves the embedded asset named by the last element of the
+// request path. Only the routes registered for it can reach it.
+func SwaggerUIAsset(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.FileFromFS(path.Base(c.Request.URL.Path), swaggerUIAssets)
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestSwaggerUILoadsOnlyServedAssets(t *testing.T) {
+	router := newDocumentedRouter(t)
+
+	assets := regexp.MustCompile(`(?:src|href)="([^"]+)"`).FindAllStringSubmatch(swaggerUIPage, -1)
+	if len(assets) == 0 {
+		t.Fatal("the docs page references no assets")
+	}
+
+	for _, m := range assets {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, m[1], nil))
+
+		if rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+			t.Errorf("GET %s = %d with %d bytes, want the asset served from the binary", m[1], rec.Code, rec.Body.Len())
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+const sseHeartbeatInterval = 25 * time.Second
+
+type EventHandler struct {
+	threadUsecase usecase.ThreadUsecase
+	subscriber    usecase.EventSubscriber
+	heartbeat     time.Duration
+
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+func NewEventHandler(tu usecase.ThreadUsecase, es usecase.EventSubscriber) *EventHandler {
+	return &EventHandler{
+		threadUsecase: tu,
+		subscriber:    es,
+		heartbeat:     sseHeartbeatInterval,
+		shutdown:      make(chan struct{}),
+	}
+}
+
+// Shutdown ends every open stream so that the HTTP server can drain;
+// clients reconnect automatically per the SSE spec.
+func (h *EventHandler) Shutdown() {
+	h.shutdownOnce.Do(func() {
+		close(h.shutdown)
+	})
+}
+
+func (h *EventHandler) StreamThread(c *gin.Context) {
+	idParam := c.Param("thread_id")
+	threadID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	if _, _, _, err := h.threadUsecase.GetByID(c.Request.Context(), threadID); err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	sub := h.subscriber.Subscribe(domain.ThreadTopic(threadID))
+	defer sub.Close()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	// A stream is meant to outlive the server's write timeout. The error
+	// only means the writer cannot set deadlines, e.g. in tests.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	// Send the headers now so the client sees the stream open before the
+	// first event or heartbeat.
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-h.shutdown:
+			return false
+		case event, ok := <-sub.Events():
+			if !ok {
+				return false
+			}
+
+			c.Render(-1, sse.Event{
+				Id:    event.ID.String(),
+				Event: event.Type,
+				Data:  event,
+			})
+
+			return true
+		case <-heartbeat.C:
+			// SSE comment line; keeps idle connections open through proxies.
+			fmt.Fprint(w, ": heartbeat\n\n")
+
+			return true
+		}
+	})
+}
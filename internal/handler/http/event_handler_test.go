@@ -0,0 +1,219 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// threadLookup is a ThreadUsecase that only knows the given threads.
+type threadLookup struct {
+	usecase.ThreadUsecase
+	threads map[uuid.UUID]*domain.Thread
+}
+
+func (u threadLookup) GetByID(ctx context.Context, id uuid.UUID) (*domain.Thread, *domain.User, *domain.Category, error) {
+	thread, ok := u.threads[id]
+	if !ok {
+		return nil, nil, nil, domain.ErrNotFound
+	}
+
+	return thread, nil, nil, nil
+}
+
+// channelSubscription is an EventSubscription fed by the test.
+type channelSubscription struct {
+	topic  string
+	events chan *domain.Event
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (s *channelSubscription) Events() <-chan *domain.Event { return s.events }
+
+func (s *channelSubscription) Close() { s.once.Do(func() { close(s.closed) }) }
+
+// channelSubscriber hands out one channelSubscription per Subscribe call.
+type channelSubscriber struct {
+	mu     sync.Mutex
+	topics []string
+	subs   chan *channelSubscription
+}
+
+func newChannelSubscriber() *channelSubscriber {
+	return &channelSubscriber{subs: make(chan *channelSubscription, 8)}
+}
+
+func (s *channelSubscriber) Subscribe(topic string) usecase.EventSubscription {
+	s.mu.Lock()
+	s.topics = append(s.topics, topic)
+	s.mu.Unlock()
+
+	sub := &channelSubscription{topic: topic, events: make(chan *domain.Event, 1), closed: make(chan struct{})}
+	s.subs <- sub
+
+	return sub
+}
+
+func newEventServer(t *testing.T, handler *EventHandler) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Errors())
+	router.GET("/threads/:thread_id/events", handler.StreamThread)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// readFrame reads lines up to the blank line that ends an SSE frame.
+func readFrame(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+
+		lines = append(lines, line)
+	}
+}
+
+func TestStreamThreadFramesEventsAndHeartbeats(t *testing.T) {
+	thread := &domain.Thread{ID: uuid.New()}
+	subscriber := newChannelSubscriber()
+	handler := NewEventHandler(threadLookup{threads: map[uuid.UUID]*domain.Thread{thread.ID: thread}}, subscriber)
+	handler.heartbeat = 20 * time.Millisecond
+	server := newEventServer(t, handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/threads/"+thread.ID.String()+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" || resp.Header.Get("Cache-Control") != "no-cache" {
+		t.Fatalf("response = %d %q %q, want an uncached event stream", resp.StatusCode, resp.Header.Get("Content-Type"), resp.Header.Get("Cache-Control"))
+	}
+
+	sub := <-subscriber.subs
+	if want := domain.ThreadTopic(thread.ID); sub.topic != want {
+		t.Errorf("subscribed to %q, want %q", sub.topic, want)
+	}
+
+	body := bufio.NewReader(resp.Body)
+
+	// With no events the stream sends heartbeat comments.
+	if frame := readFrame(t, body); len(frame) != 1 || frame[0] != ": heartbeat" {
+		t.Fatalf("idle frame = %q, want a heartbeat comment", frame)
+	}
+
+	event := &domain.Event{ID: uuid.New(), Type: domain.EventPostCreated, Topic: domain.ThreadTopic(thread.ID), OccurredAt: time.Now().UTC()}
+	sub.events <- event
+
+	frame := readFrame(t, body)
+	for frame[0] == ": heartbeat" {
+		frame = readFrame(t, body)
+	}
+
+	if len(frame) != 3 || frame[0] != "id:"+event.ID.String() || frame[1] != "event:"+event.Type || !strings.HasPrefix(frame[2], "data:") {
+		t.Fatalf("event frame = %q, want id, event and data lines", frame)
+	}
+
+	var data domain.Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(frame[2], "data:")), &data); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+
+	if data.ID != event.ID || data.Type != event.Type || data.Topic != event.Topic {
+		t.Errorf("data = %+v, want the published event", data)
+	}
+
+	// Disconnecting ends the stream and releases the subscription.
+	cancel()
+
+	select {
+	case <-sub.closed:
+	case <-time.After(time.Second):
+		t.Error("subscription not closed after the client disconnected")
+	}
+}
+
+func TestStreamThreadEndsOnShutdown(t *testing.T) {
+	thread := &domain.Thread{ID: uuid.New()}
+	subscriber := newChannelSubscriber()
+	handler := NewEventHandler(threadLookup{threads: map[uuid.UUID]*domain.Thread{thread.ID: thread}}, subscriber)
+	server := newEventServer(t, handler)
+
+	resp, err := http.Get(server.URL + "/threads/" + thread.ID.String() + "/events")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	sub := <-subscriber.subs
+	handler.Shutdown()
+
+	select {
+	case <-sub.closed:
+	case <-time.After(time.Second):
+		t.Error("subscription not closed after Shutdown")
+	}
+}
+
+func TestStreamThreadRejectsUnknownThreads(t *testing.T) {
+	subscriber := newChannelSubscriber()
+	server := newEventServer(t, NewEventHandler(threadLookup{}, subscriber))
+
+	tests := []struct {
+		name     string
+		threadID string
+		want     int
+	}{
+		{"invalid id", "not-a-uuid", http.StatusBadRequest},
+		{"missing thread", uuid.NewString(), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/threads/" + tt.threadID + "/events")
+			if err != nil {
+				t.Fatalf("GET: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	if len(subscriber.topics) != 0 {
+		t.Errorf("subscribed to %q, want no subscription", subscriber.topics)
+	}
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds all readiness checks together, so a hung
+// dependency fails the probe instead of stalling it.
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck is one dependency the API needs to serve traffic.
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// HealthHandler serves the probes used by orchestrators and load balancers.
+// They live outside /api/v1, need no authentication and answer plain JSON
+// rather than problem details, since they are read by machines.
+type HealthHandler struct {
+	checks   []ReadinessCheck
+	draining atomic.Bool
+}
+
+func NewHealthHandler(checks ...ReadinessCheck) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+// Drain makes readiness fail from now on, so traffic is routed away while
+// in-flight requests finish. Liveness is not affected.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live answers GET /healthz: the process is up and serving HTTP. It checks
+// no dependency, so a database outage does not get the API restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Ready answers GET /readyz with the result of every readiness check, and
+// 503 if any of them failed or the server is shutting down.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, HealthResponse{Status: "draining"})
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	resp := HealthResponse{Status: "ready", Checks: make(map[string]string, len(h.checks))}
+	status := http.StatusOK
+
+	for _, check := range h.checks {
+		if err := check.Check(ctx); err != nil {
+			resp.Checks[check.Name] = err.Error()
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+
+			continue
+		}
+
+		resp.Checks[check.Name] = "ok"
+	}
+
+	c.JSON(status, resp)
+}
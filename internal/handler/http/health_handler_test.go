@@ -0,0 +1,63 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadinessReportsEveryCheck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	schemaErr := errors.New("schema is at version 12, want 13")
+	health := NewHealthHandler(
+		ReadinessCheck{Name: "database", Check: func(context.Context) error { return nil }},
+		ReadinessCheck{Name: "schema", Check: func(context.Context) error { return schemaErr }},
+	)
+
+	router := gin.New()
+	router.GET("/healthz", health.Live)
+	router.GET("/readyz", health.Ready)
+
+	get := func(path string) (int, HealthResponse) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var body HealthResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: decode body: %v", path, err)
+		}
+
+		return rec.Code, body
+	}
+
+	if status, _ := get("/healthz"); status != http.StatusOK {
+		t.Errorf("/healthz status = %d, want 200", status)
+	}
+
+	status, body := get("/readyz")
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("/readyz status = %d, want 503", status)
+	}
+	if body.Checks["database"] != "ok" || body.Checks["schema"] != schemaErr.Error() {
+		t.Errorf("/readyz checks = %v", body.Checks)
+	}
+
+	health.checks[1].Check = func(context.Context) error { return nil }
+	if status, _ := get("/readyz"); status != http.StatusOK {
+		t.Errorf("/readyz status = %d once every check passes, want 200", status)
+	}
+
+	health.Drain()
+	if status, body := get("/readyz"); status != http.StatusServiceUnavailable || body.Status != "draining" {
+		t.Errorf("/readyz while draining = %d %q, want 503 draining", status, body.Status)
+	}
+	if status, _ := get("/healthz"); status != http.StatusOK {
+		t.Errorf("/healthz status while draining = %d, want 200", status)
+	}
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/metrics"
+)
+
+// unmatchedRoute labels requests that matched no route, so that scans of
+// random paths cannot grow the number of series.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of every request by its route
+// template, e.g. /api/v1/threads/:thread_id rather than the concrete path.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		method := c.Request.Method
+		m.HTTPRequests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		m.HTTPDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
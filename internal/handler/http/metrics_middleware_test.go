@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/metrics"
+)
+
+func TestMetricsLabelsRequestsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := metrics.New()
+
+	router := gin.New()
+	router.Use(Metrics(m))
+	router.GET("/threads/:thread_id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/metrics", gin.WrapH(m.Handler()))
+
+	for _, path := range []string{"/threads/1", "/threads/2", "/no/such/path"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`agora_http_requests_total{method="GET",route="/threads/:thread_id",status="204"} 2`,
+		`agora_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`agora_http_request_duration_seconds_count{method="GET",route="/threads/:thread_id"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+}
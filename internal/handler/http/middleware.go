@@ -1,12 +1,11 @@
 package http
 
 import (
-	"log"
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
 	"github.com/srgjo27/agora/internal/usecase"
 )
 
@@ -27,14 +26,14 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+			abortWithError(c, domain.ErrUnauthorized)
 
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+			abortWithError(c, &domain.Error{Code: domain.CodeUnauthorized, Message: "invalid authorization header format"})
 
 			return
 		}
@@ -43,8 +42,7 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 
 		userID, role, err := m.tokenSvc.ValidateToken(c.Request.Context(), tokenString)
 		if err != nil {
-			log.Fatalf("[ERROR]: %v", err)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			abortWithError(c, &domain.Error{Code: domain.CodeUnauthorized, Message: "invalid or expired token", Err: err})
 
 			return
 		}
@@ -54,6 +52,27 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthenticate identifies the caller when a valid bearer token is
+// sent, for public routes that add per-user fields to their responses. A
+// missing or invalid token leaves the request anonymous instead of
+// rejecting it.
+func (m *AuthMiddleware) OptionalAuthenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			return
+		}
+
+		userID, role, err := m.tokenSvc.ValidateToken(c.Request.Context(), parts[1])
+		if err != nil {
+			return
+		}
+
+		c.Set(userCtxKey, userID)
+		c.Set(roleCtxKey, role)
+	}
+}
+
 func getUserIDFromCtx(ctx *gin.Context) (uuid.UUID, bool) {
 	val, ok := ctx.Get(userCtxKey)
 	if !ok {
@@ -80,13 +99,13 @@ func (m *AuthMiddleware) AdminOnly() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := getUserRoleFromCtx(c)
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "role not found in context"})
+			abortWithError(c, domain.ErrForbidden)
 
 			return
 		}
 
 		if role != "admin" {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			abortWithError(c, &domain.Error{Code: domain.CodeForbidden, Message: "admin access required"})
 
 			return
 		}
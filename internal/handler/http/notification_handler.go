@@ -0,0 +1,180 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type NotificationHandler struct {
+	notificationUsecase usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(nu usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{notificationUsecase: nu}
+}
+
+func (h *NotificationHandler) GetAll(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	params, err := getPaginationParams(c)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+
+	notifications, userMap, totalItems, err := h.notificationUsecase.GetByUserID(c.Request.Context(), userID, unreadOnly, params)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	unreadCount, err := h.notificationUsecase.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*NotificationResponse, len(notifications))
+	for i, n := range notifications {
+		var actor *domain.User
+		if n.ActorID != nil {
+			actor = userMap[*n.ActorID]
+		}
+		dtos[i] = NewNotificationResponse(n, actor)
+	}
+
+	response := gin.H{
+		"data":         dtos,
+		"meta":         newPaginationMeta(totalItems, params),
+		"unread_count": unreadCount,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	count, err := h.notificationUsecase.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	idParam := c.Param("notification_id")
+	notificationID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.Error(invalidParam("notification_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	err = h.notificationUsecase.MarkRead(c.Request.Context(), userID, notificationID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	updated, err := h.notificationUsecase.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "all notifications marked as read", "updated": updated})
+}
+
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	prefs, err := h.notificationUsecase.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewNotificationPreferencesResponse(prefs))
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	params := usecase.UpdateNotificationPreferencesParams{
+		ThreadReply:   req.ThreadReply,
+		PostReply:     req.PostReply,
+		Mention:       req.Mention,
+		VoteMilestone: req.VoteMilestone,
+	}
+
+	prefs, err := h.notificationUsecase.UpdatePreferences(c.Request.Context(), userID, params)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewNotificationPreferencesResponse(prefs))
+}
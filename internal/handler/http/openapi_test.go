@@ -0,0 +1,127 @@
+package http
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/contracttest"
+	"github.com/srgjo27/agora/internal/metrics"
+)
+
+var ginParam = regexp.MustCompile(`:([^/]+)`)
+
+func newDocumentedRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cors, err := NewCORSMiddleware(testCORSConfig())
+	if err != nil {
+		t.Fatalf("NewCORSMiddleware: %v", err)
+	}
+
+	return NewRouter(
+		&UserHandler{}, &AuthMiddleware{}, cors, &CategoryHandler{}, &ThreadHandler{},
+		&PostHandler{}, &VoteHandler{}, &AuditLogHandler{}, &NotificationHandler{}, &EventHandler{},
+		&WebSocketHandler{}, &TagHandler{}, &AttachmentHandler{}, &SubscriptionHandler{},
+		&BookmarkHandler{}, &DigestHandler{}, &WebhookHandler{}, NewHealthHandler(),
+		slog.New(slog.NewTextHandler(io.Discard, nil)), metrics.New(),
+	)
+}
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	v, err := contracttest.New()
+	if err != nil {
+		t.Fatalf("load OpenAPI document: %v", err)
+	}
+
+	var registered []contracttest.Operation
+	for _, r := range newDocumentedRouter(t).Routes() {
+		registered = append(registered, contracttest.Operation{Method: r.Method, Path: ginParam.ReplaceAllString(r.Path, "{$1}")})
+	}
+	documented := v.Operations()
+
+	for _, op := range registered {
+		if !slices.Contains(documented, op) {
+			t.Errorf("%s %s is registered but not documented in api/openapi/openapi.json", op.Method, op.Path)
+		}
+	}
+	for _, op := range documented {
+		if !slices.Contains(registered, op) {
+			t.Errorf("%s %s is documented but not registered", op.Method, op.Path)
+		}
+	}
+}
+
+func TestResponsesMatchOpenAPIDocument(t *testing.T) {
+	v, err := contracttest.New()
+	if err != nil {
+		t.Fatalf("load OpenAPI document: %v", err)
+	}
+	router := newDocumentedRouter(t)
+
+	for _, tc := range []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodGet, "/healthz", "", http.StatusOK},
+		{http.MethodGet, "/readyz", "", http.StatusOK},
+		{http.MethodGet, "/openapi.json", "", http.StatusOK},
+		{http.MethodGet, "/docs", "", http.StatusOK},
+		{http.MethodGet, "/docs/swagger-ui.css", "", http.StatusOK},
+		{http.MethodGet, "/docs/swagger-ui-bundle.js", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/digest/unsubscribe?token=abc", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/digest/unsubscribe", "", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/users/me", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/threads/not-a-uuid", "", http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/auth/register", `{"username": 1}`, http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		if tc.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("%s %s: status %d, want %d", tc.method, tc.path, rec.Code, tc.status)
+		}
+		if err := v.ValidateResponse(tc.method, tc.path, rec.Code, rec.Header(), rec.Body.Bytes()); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestValidatorRejectsUndocumentedResponses(t *testing.T) {
+	v, err := contracttest.New()
+	if err != nil {
+		t.Fatalf("load OpenAPI document: %v", err)
+	}
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+
+	for name, tc := range map[string]struct {
+		method, path string
+		status       int
+		header       http.Header
+		body         string
+	}{
+		"missing field":        {http.MethodGet, "/healthz", http.StatusOK, jsonHeader, `{}`},
+		"undocumented status":  {http.MethodGet, "/healthz", http.StatusCreated, jsonHeader, `{"status":"ok"}`},
+		"undocumented path":    {http.MethodGet, "/api/v1/nowhere/else/at/all", http.StatusOK, jsonHeader, `{}`},
+		"undocumented method":  {http.MethodDelete, "/healthz", http.StatusOK, jsonHeader, `{}`},
+		"wrong content type":   {http.MethodGet, "/healthz", http.StatusOK, http.Header{"Content-Type": []string{"text/plain"}}, `ok`},
+		"problem without type": {http.MethodGet, "/api/v1/users/me", http.StatusUnauthorized, http.Header{"Content-Type": []string{"application/problem+json"}}, `{"status":401}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := v.ValidateResponse(tc.method, tc.path, tc.status, tc.header, []byte(tc.body)); err == nil {
+				t.Error("ValidateResponse accepted a response the document does not describe")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"math"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -35,3 +36,17 @@ func getPaginationParams(c *gin.Context) (usecase.PaginationParams, error) {
 		Offset: offset,
 	}, nil
 }
+
+func newPaginationMeta(totalItems int, params usecase.PaginationParams) PaginationMeta {
+	totalPages := 0
+	if params.Limit > 0 {
+		totalPages = int(math.Ceil(float64(totalItems) / float64(params.Limit)))
+	}
+
+	return PaginationMeta{
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		CurrentPage: (params.Offset / params.Limit) + 1,
+		Limit:       params.Limit,
+	}
+}
@@ -1,8 +1,7 @@
 package http
 
 import (
-	"log"
-	"math"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,11 +11,13 @@ import (
 )
 
 type PostHandler struct {
-	postUsecase usecase.PostUsecase
+	postUsecase     usecase.PostUsecase
+	bookmarkUsecase usecase.BookmarkUsecase
+	logger          *slog.Logger
 }
 
-func NewPostHandler(pu usecase.PostUsecase) *PostHandler {
-	return &PostHandler{postUsecase: pu}
+func NewPostHandler(pu usecase.PostUsecase, bu usecase.BookmarkUsecase, logger *slog.Logger) *PostHandler {
+	return &PostHandler{postUsecase: pu, bookmarkUsecase: bu, logger: logger}
 }
 
 func getThreadIDFromParam(c *gin.Context) (uuid.UUID, error) {
@@ -28,41 +29,28 @@ func getThreadIDFromParam(c *gin.Context) (uuid.UUID, error) {
 func (h *PostHandler) Create(c *gin.Context) {
 	threadID, err := getThreadIDFromParam(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid thread ID"})
+		c.Error(invalidParam("thread_id", "uuid"))
 
 		return
 	}
 
 	var req CreatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
 
 		return
 	}
 
 	userID, exists := getUserIDFromCtx(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		c.Error(domain.ErrUnauthorized)
 
 		return
 	}
 
 	post, err := h.postUsecase.Create(c.Request.Context(), req.Content, userID, threadID, req.ParentPostID)
 	if err != nil {
-		if err == domain.ErrInvalid {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input or thread ID"})
-
-			return
-		}
-
-		if err == domain.ErrThreadLocked {
-			c.JSON(http.StatusForbidden, gin.H{"error": "thread is locked"})
-
-			return
-		}
-
-		log.Fatalf("[ERROR]: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 
 		return
 	}
@@ -73,53 +61,78 @@ func (h *PostHandler) Create(c *gin.Context) {
 func (h *PostHandler) GetByThreadID(c *gin.Context) {
 	threadID, err := getThreadIDFromParam(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid thread ID"})
+		c.Error(invalidParam("thread_id", "uuid"))
 
 		return
 	}
 
 	params, err := getPaginationParams(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pagination parameters"})
+		c.Error(err)
 
 		return
 	}
 
 	posts, userMap, totalItems, err := h.postUsecase.GetByThreadID(c.Request.Context(), threadID, params)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "thread not found"})
-
-			return
-		}
-
-		log.Printf("[ERROR]: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 
 		return
 	}
 
+	ids := make([]uuid.UUID, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	flags := bookmarkedPosts(c, h.bookmarkUsecase, h.logger, ids...)
+
 	dtos := make([]*PostResponse, len(posts))
 	for i, p := range posts {
 		dtos[i] = NewPostResponse(p, userMap[p.UserID])
+		dtos[i].Bookmarked = bookmarkFlag(flags, p.ID)
 	}
 
-	totalPages := 0
-	if params.Limit > 0 {
-		totalPages = int(math.Ceil(float64(totalItems) / float64(params.Limit)))
+	response := gin.H{
+		"data": dtos,
+		"meta": newPaginationMeta(totalItems, params),
 	}
 
-	meta := PaginationMeta{
-		TotalItems:  totalItems,
-		TotalPages:  totalPages,
-		CurrentPage: (params.Offset / params.Limit) + 1,
-		Limit:       params.Limit,
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PostHandler) Update(c *gin.Context) {
+	idParam := c.Param("post_id")
+	postID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.Error(invalidParam("post_id", "uuid"))
+
+		return
 	}
 
-	response := gin.H{
-		"data": dtos,
-		"meta": meta,
+	var req UpdatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	role, _ := getUserRoleFromCtx(c)
+
+	post, author, err := h.postUsecase.Update(c.Request.Context(), postID, userID, role, req.Content, req.Reason)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dto := NewPostResponse(post, author)
+	dto.Bookmarked = bookmarkFlag(bookmarkedPosts(c, h.bookmarkUsecase, h.logger, post.ID), post.ID)
+	c.JSON(http.StatusOK, dto)
 }
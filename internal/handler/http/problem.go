@@ -0,0 +1,197 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/logging"
+)
+
+const problemContentType = "application/problem+json"
+
+// codeMalformedBody is for a request body that could not be decoded at all,
+// as opposed to one whose fields failed validation.
+const codeMalformedBody domain.Code = "malformed_body"
+
+// errInternal stands in for any error without a domain code, so the cause
+// is logged but never shown to the client.
+var errInternal = &domain.Error{Code: domain.CodeInternal, Message: "internal server error"}
+
+var problemStatus = map[domain.Code]int{
+	domain.CodeInvalid:              http.StatusBadRequest,
+	codeMalformedBody:               http.StatusBadRequest,
+	domain.CodeUnauthorized:         http.StatusUnauthorized,
+	domain.CodeForbidden:            http.StatusForbidden,
+	domain.CodeThreadLocked:         http.StatusForbidden,
+	domain.CodeNotFound:             http.StatusNotFound,
+	domain.CodeConflict:             http.StatusConflict,
+	domain.CodeFileTooLarge:         http.StatusRequestEntityTooLarge,
+	domain.CodeQuotaExceeded:        http.StatusRequestEntityTooLarge,
+	domain.CodeUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	domain.CodeLimitReached:         http.StatusUnprocessableEntity,
+	domain.CodeUnavailable:          http.StatusServiceUnavailable,
+	domain.CodeInternal:             http.StatusInternalServerError,
+}
+
+// Problem is the body of every error response, in the format of RFC 9457.
+// Code is stable and meant for programs; Title and Detail are localized from
+// the Accept-Language header and meant for people.
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	Code      domain.Code    `json:"code"`
+	Errors    []ProblemField `json:"errors,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+}
+
+type ProblemField struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors answers with a problem for the last error a handler attached with
+// c.Error, unless the handler has already written a response. Errors that
+// carry no domain code become a 500; RequestLogger still logs their cause.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		var derr *domain.Error
+		if !errors.As(c.Errors.Last().Err, &derr) {
+			derr = errInternal
+		}
+
+		writeProblem(c, derr)
+	}
+}
+
+// writeProblem writes err as a problem in the language the client prefers
+// and aborts the request.
+func writeProblem(c *gin.Context, err *domain.Error) {
+	status, ok := problemStatus[err.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	msgs := problemMessagesFor(c.GetHeader("Accept-Language"))
+	text := msgs.problem(err.Code)
+
+	problem := Problem{
+		Type:      "urn:agora:problem:" + string(err.Code),
+		Title:     text.title,
+		Status:    status,
+		Detail:    text.detail,
+		Instance:  c.Request.URL.Path,
+		Code:      err.Code,
+		RequestID: logging.RequestID(c.Request.Context()),
+	}
+
+	for _, f := range err.Fields {
+		problem.Errors = append(problem.Errors, ProblemField{
+			Field:   f.Field,
+			Rule:    f.Rule,
+			Param:   f.Param,
+			Message: msgs.field(f),
+		})
+	}
+
+	body, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	c.Header("Content-Language", msgs.lang)
+	c.Data(status, problemContentType, body)
+	c.Abort()
+}
+
+// abortWithError attaches err for Errors to render and stops the handler
+// chain, for middleware that rejects a request.
+func abortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
+
+// invalidParam reports a path or query parameter that failed rule.
+func invalidParam(field, rule string) error {
+	return domain.Invalid(domain.FieldError{Field: field, Rule: rule})
+}
+
+// invalidBody turns a ShouldBindJSON error into a domain error naming the
+// fields that failed validation.
+func invalidBody(err error) error {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		fields := make([]domain.FieldError, len(verrs))
+		for i, fe := range verrs {
+			fields[i] = validationFieldError(fe)
+		}
+
+		return domain.Invalid(fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return domain.Invalid(domain.FieldError{Field: typeErr.Field, Rule: "type", Param: typeErr.Type.String()})
+	}
+
+	return &domain.Error{Code: codeMalformedBody, Message: "malformed request body", Err: err}
+}
+
+// validationFieldError names a failed length check by what was measured, so
+// that "min" on a string reads as min_length and on a list as min_items.
+func validationFieldError(fe validator.FieldError) domain.FieldError {
+	rule := fe.Tag()
+
+	switch rule {
+	case "min", "max", "len":
+		switch fe.Kind() {
+		case reflect.String:
+			rule += "_length"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			rule += "_items"
+		}
+	}
+
+	return domain.FieldError{Field: fe.Field(), Rule: rule, Param: fe.Param()}
+}
+
+// useJSONFieldNames makes validation errors name fields as clients send
+// them rather than by their Go names.
+func useJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+
+		if name == "" {
+			return f.Name
+		}
+
+		return name
+	})
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/srgjo27/agora/internal/domain"
+	"golang.org/x/text/language"
+)
+
+type problemText struct {
+	title  string
+	detail string
+}
+
+// problemMessages holds the client-facing messages of one language. Every
+// domain code and validation rule should have an entry in each language;
+// a missing one falls back to the generic message.
+type problemMessages struct {
+	lang     string
+	problems map[domain.Code]problemText
+	// rules holds one message per validation rule, with {param} replaced by
+	// the rule's argument.
+	rules map[string]string
+}
+
+func (m *problemMessages) problem(code domain.Code) problemText {
+	if text, ok := m.problems[code]; ok {
+		return text
+	}
+
+	return m.problems[domain.CodeInternal]
+}
+
+func (m *problemMessages) field(f domain.FieldError) string {
+	msg, ok := m.rules[f.Rule]
+	if !ok {
+		msg = m.rules[""]
+	}
+
+	return strings.ReplaceAll(msg, "{param}", strings.ReplaceAll(f.Param, " ", ", "))
+}
+
+// problemLanguages lists the supported languages, the default first.
+var problemLanguages = []*problemMessages{englishProblems, indonesianProblems}
+
+var problemLanguageMatcher = language.NewMatcher([]language.Tag{language.English, language.Indonesian})
+
+// problemMessagesFor picks the supported language that best matches an
+// Accept-Language header, English if none does.
+func problemMessagesFor(acceptLanguage string) *problemMessages {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return problemLanguages[0]
+	}
+
+	_, i, confidence := problemLanguageMatcher.Match(tags...)
+	if confidence == language.No {
+		return problemLanguages[0]
+	}
+
+	return problemLanguages[i]
+}
+
+var englishProblems = &problemMessages{
+	lang: "en",
+	problems: map[domain.Code]problemText{
+		domain.CodeInvalid:              {"Invalid request", "One or more inputs are invalid."},
+		codeMalformedBody:               {"Malformed request body", "The request body is not valid JSON."},
+		domain.CodeUnauthorized:         {"Unauthorized", "Authentication is required or has failed."},
+		domain.CodeForbidden:            {"Forbidden", "You are not allowed to perform this action."},
+		domain.CodeNotFound:             {"Not found", "The requested resource does not exist."},
+		domain.CodeConflict:             {"Conflict", "The resource already exists."},
+		domain.CodeThreadLocked:         {"Thread locked", "The thread is locked and cannot be changed."},
+		domain.CodeFileTooLarge:         {"File too large", "The file exceeds the maximum upload size."},
+		domain.CodeUnsupportedMediaType: {"Unsupported media type", "This file type is not allowed."},
+		domain.CodeQuotaExceeded:        {"Quota exceeded", "Your attachment storage quota is used up."},
+		domain.CodeLimitReached:         {"Limit reached", "You have reached the maximum number allowed."},
+		domain.CodeUnavailable:          {"Service unavailable", "The service is temporarily unavailable. Try again later."},
+		domain.CodeInternal:             {"Internal server error", "Something went wrong on our side."},
+	},
+	rules: map[string]string{
+		"":            "is invalid",
+		"required":    "is required",
+		"email":       "must be a valid email address",
+		"url":         "must be a valid URL",
+		"uuid":        "must be a valid UUID",
+		"oneof":       "must be one of: {param}",
+		"type":        "has the wrong type, expected {param}",
+		"min":         "must be at least {param}",
+		"max":         "must be at most {param}",
+		"len":         "must be exactly {param}",
+		"min_length":  "must be at least {param} characters long",
+		"max_length":  "must be at most {param} characters long",
+		"len_length":  "must be exactly {param} characters long",
+		"min_items":   "must contain at least {param} items",
+		"max_items":   "must contain at most {param} items",
+		"len_items":   "must contain exactly {param} items",
+		"not_empty":   "must not be empty",
+		"exists":      "does not refer to an existing record",
+		"same_thread": "must belong to the same thread",
+		"different":   "must differ from the tag being merged",
+		"tag_name":    "must be a valid tag name",
+		"http_url":    "must be an http or https URL",
+		"public_host": "must point to a public host",
+		"event_type":  "contains an unknown event type",
+	},
+}
+
+var indonesianProblems = &problemMessages{
+	lang: "id",
+	problems: map[domain.Code]problemText{
+		domain.CodeInvalid:              {"Permintaan tidak valid", "Satu atau lebih input tidak valid."},
+		codeMalformedBody:               {"Body permintaan rusak", "Body permintaan bukan JSON yang valid."},
+		domain.CodeUnauthorized:         {"Tidak terautentikasi", "Autentikasi diperlukan atau gagal."},
+		domain.CodeForbidden:            {"Akses ditolak", "Anda tidak diizinkan melakukan tindakan ini."},
+		domain.CodeNotFound:             {"Tidak ditemukan", "Data yang diminta tidak ditemukan."},
+		domain.CodeConflict:             {"Konflik", "Data sudah ada."},
+		domain.CodeThreadLocked:         {"Thread terkunci", "Thread terkunci dan tidak dapat diubah."},
+		domain.CodeFileTooLarge:         {"File terlalu besar", "File melebihi ukuran unggahan maksimum."},
+		domain.CodeUnsupportedMediaType: {"Jenis file tidak didukung", "Jenis file ini tidak diizinkan."},
+		domain.CodeQuotaExceeded:        {"Kuota terlampaui", "Kuota penyimpanan lampiran Anda sudah habis."},
+		domain.CodeLimitReached:         {"Batas tercapai", "Anda telah mencapai jumlah maksimum yang diizinkan."},
+		domain.CodeUnavailable:          {"Layanan tidak tersedia", "Layanan sedang tidak tersedia. Coba lagi nanti."},
+		domain.CodeInternal:             {"Kesalahan server", "Terjadi kesalahan di sisi server."},
+	},
+	rules: map[string]string{
+		"":            "tidak valid",
+		"required":    "wajib diisi",
+		"email":       "harus berupa alamat email yang valid",
+		"url":         "harus berupa URL yang valid",
+		"uuid":        "harus berupa UUID yang valid",
+		"oneof":       "harus salah satu dari: {param}",
+		"type":        "tipenya salah, seharusnya {param}",
+		"min":         "minimal {param}",
+		"max":         "maksimal {param}",
+		"len":         "harus tepat {param}",
+		"min_length":  "minimal {param} karakter",
+		"max_length":  "maksimal {param} karakter",
+		"len_length":  "harus tepat {param} karakter",
+		"min_items":   "minimal berisi {param} item",
+		"max_items":   "maksimal berisi {param} item",
+		"len_items":   "harus berisi tepat {param} item",
+		"not_empty":   "tidak boleh kosong",
+		"exists":      "tidak merujuk ke data yang ada",
+		"same_thread": "harus berada di thread yang sama",
+		"different":   "harus berbeda dari tag yang digabungkan",
+		"tag_name":    "harus berupa nama tag yang valid",
+		"http_url":    "harus berupa URL http atau https",
+		"public_host": "harus mengarah ke host publik",
+		"event_type":  "berisi jenis event yang tidak dikenal",
+	},
+}
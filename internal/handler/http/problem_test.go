@@ -0,0 +1,153 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+func newProblemRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	useJSONFieldNames()
+
+	router := gin.New()
+	router.Use(RequestID(), Errors())
+
+	router.POST("/bind", func(c *gin.Context) {
+		var req struct {
+			Title string   `json:"title" binding:"required,min=5"`
+			Tags  []string `json:"tags" binding:"omitempty,max=2"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(invalidBody(err))
+
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/wrapped", func(c *gin.Context) {
+		c.Error(fmt.Errorf("load thread: %w", domain.ErrNotFound))
+	})
+	router.GET("/unknown", func(c *gin.Context) {
+		c.Error(errors.New("connection refused"))
+	})
+
+	return router
+}
+
+func TestErrorsWritesProblems(t *testing.T) {
+	router := newProblemRouter()
+
+	tests := []struct {
+		name           string
+		method, path   string
+		body           string
+		acceptLanguage string
+		status         int
+		code           domain.Code
+		lang           string
+		fields         []ProblemField
+	}{
+		{
+			name: "validation errors name JSON fields", method: http.MethodPost, path: "/bind",
+			body: `{"title":"abc","tags":["a","b","c"]}`, status: http.StatusBadRequest, code: domain.CodeInvalid, lang: "en",
+			fields: []ProblemField{
+				{Field: "title", Rule: "min_length", Param: "5", Message: "must be at least 5 characters long"},
+				{Field: "tags", Rule: "max_items", Param: "2", Message: "must contain at most 2 items"},
+			},
+		},
+		{
+			name: "messages follow Accept-Language", method: http.MethodPost, path: "/bind",
+			body: `{}`, acceptLanguage: "id-ID,id;q=0.9,en;q=0.8", status: http.StatusBadRequest, code: domain.CodeInvalid, lang: "id",
+			fields: []ProblemField{{Field: "title", Rule: "required", Message: "wajib diisi"}},
+		},
+		{
+			name: "malformed body", method: http.MethodPost, path: "/bind",
+			body: `{"title":`, status: http.StatusBadRequest, code: codeMalformedBody, lang: "en",
+		},
+		{
+			name: "wrapped domain error", method: http.MethodGet, path: "/wrapped",
+			acceptLanguage: "fr", status: http.StatusNotFound, code: domain.CodeNotFound, lang: "en",
+		},
+		{
+			name: "error without a code", method: http.MethodGet, path: "/unknown",
+			status: http.StatusInternalServerError, code: domain.CodeInternal, lang: "en",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+
+			if ct := rec.Header().Get("Content-Type"); ct != problemContentType {
+				t.Errorf("got Content-Type %q, want %q", ct, problemContentType)
+			}
+
+			if lang := rec.Header().Get("Content-Language"); lang != tt.lang {
+				t.Errorf("got Content-Language %q, want %q", lang, tt.lang)
+			}
+
+			var problem Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+
+			if problem.Status != tt.status || problem.Code != tt.code || problem.Title == "" {
+				t.Errorf("got %+v, want status %d and code %q with a title", problem, tt.status, tt.code)
+			}
+
+			if problem.RequestID == "" || problem.RequestID != rec.Header().Get(requestIDHeader) {
+				t.Errorf("got request_id %q, want the X-Request-ID header", problem.RequestID)
+			}
+
+			if strings.Contains(rec.Body.String(), "connection refused") {
+				t.Errorf("problem leaks the cause of an internal error: %s", rec.Body)
+			}
+
+			if len(problem.Errors) != len(tt.fields) {
+				t.Fatalf("got field errors %+v, want %+v", problem.Errors, tt.fields)
+			}
+
+			for i, f := range tt.fields {
+				if problem.Errors[i] != f {
+					t.Errorf("field error %d: got %+v, want %+v", i, problem.Errors[i], f)
+				}
+			}
+		})
+	}
+}
+
+func TestEveryCodeHasMessages(t *testing.T) {
+	for _, msgs := range problemLanguages {
+		for code := range problemStatus {
+			if _, ok := msgs.problems[code]; !ok {
+				t.Errorf("%s: no message for code %q", msgs.lang, code)
+			}
+		}
+
+		for rule := range englishProblems.rules {
+			if _, ok := msgs.rules[rule]; !ok {
+				t.Errorf("%s: no message for rule %q", msgs.lang, rule)
+			}
+		}
+	}
+}
@@ -22,6 +22,7 @@ type CreateThreadRequest struct {
 	Title      string    `json:"title" binding:"required,min=5"`
 	Content    string    `json:"content" binding:"required,min=10"`
 	CategoryID uuid.UUID `json:"category_id" binding:"required"`
+	Tags       []string  `json:"tags" binding:"omitempty,max=10"`
 }
 
 type CreatePostRequest struct {
@@ -29,11 +30,71 @@ type CreatePostRequest struct {
 	ParentPostID *uuid.UUID `json:"parent_post_id"`
 }
 
+type UpdatePostRequest struct {
+	Content string  `json:"content" binding:"required"`
+	Reason  *string `json:"reason" binding:"omitempty,max=500"`
+}
+
 type VoteRequest struct {
 	VoteType int `json:"vote_type" binding:"min=-1,max=1"`
 }
 
 type UpdateThreadRequest struct {
-	Title   *string `json:"title" binding:"omitempty,min=5"`
-	Content *string `json:"content" binding:"omitempty,min=10"`
+	Title   *string   `json:"title" binding:"omitempty,min=5"`
+	Content *string   `json:"content" binding:"omitempty,min=10"`
+	Tags    *[]string `json:"tags" binding:"omitempty,max=10"`
+	Reason  *string   `json:"reason" binding:"omitempty,max=500"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role   string  `json:"role" binding:"required,oneof=admin member"`
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	ThreadReply   *bool `json:"thread_reply"`
+	PostReply     *bool `json:"post_reply"`
+	Mention       *bool `json:"mention"`
+	VoteMilestone *bool `json:"vote_milestone"`
+}
+
+type RenameTagRequest struct {
+	Name   string  `json:"name" binding:"required"`
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type MergeTagRequest struct {
+	Into   string  `json:"into" binding:"required"`
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type MarkThreadReadRequest struct {
+	PostID *uuid.UUID `json:"post_id"`
+}
+
+type SaveBookmarkRequest struct {
+	FolderID *uuid.UUID `json:"folder_id"`
+	Note     *string    `json:"note" binding:"omitempty,max=1000"`
+}
+
+type BookmarkFolderRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+type UpdateDigestPreferencesRequest struct {
+	Frequency string `json:"frequency" binding:"required,oneof=off daily weekly"`
+}
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+}
+
+type UpdateWebhookRequest struct {
+	URL          *string   `json:"url" binding:"omitempty,url,max=2048"`
+	Events       *[]string `json:"events" binding:"omitempty,min=1"`
+	Description  *string   `json:"description" binding:"omitempty,max=255"`
+	IsActive     *bool     `json:"is_active"`
+	RotateSecret bool      `json:"rotate_secret"`
 }
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -68,44 +69,54 @@ func NewCategoryListResponse(cats []*domain.Category) []*CategoryResponse {
 }
 
 type ThreadSummaryResponse struct {
-	ID        uuid.UUID             `json:"id"`
-	Title     string                `json:"title"`
-	Slug      string                `json:"slug"`
-	Author    *AuthorResponse       `json:"author"`
-	Category  *CategoryInfoResponse `json:"category"`
-	IsPinned  bool                  `json:"is_pinned"`
-	IsLocked  bool                  `json:"is_locked"`
-	VoteCount int                   `json:"vote_count"`
-	CreatedAt time.Time             `json:"created_at"`
+	ID         uuid.UUID             `json:"id"`
+	Title      string                `json:"title"`
+	Slug       string                `json:"slug"`
+	Author     *AuthorResponse       `json:"author"`
+	Category   *CategoryInfoResponse `json:"category"`
+	IsPinned   bool                  `json:"is_pinned"`
+	IsLocked   bool                  `json:"is_locked"`
+	VoteCount  int                   `json:"vote_count"`
+	Tags       []*TagInfoResponse    `json:"tags"`
+	Bookmarked *bool                 `json:"bookmarked,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+	DeletedAt  *time.Time            `json:"deleted_at,omitempty"`
 }
 
 type ThreadDetailResponse struct {
-	ID        uuid.UUID             `json:"id"`
-	Title     string                `json:"title"`
-	Slug      string                `json:"slug"`
-	Content   string                `json:"content"`
-	Author    *AuthorResponse       `json:"author"`
-	Category  *CategoryInfoResponse `json:"category"`
-	IsPinned  bool                  `json:"is_pinned"`
-	IsLocked  bool                  `json:"is_locked"`
-	VoteCount int                   `json:"vote_count"`
-	CreatedAt time.Time             `json:"created_at"`
-	UpdatedAt *time.Time            `json:"updated_at,omitempty"`
+	ID          uuid.UUID             `json:"id"`
+	Title       string                `json:"title"`
+	Slug        string                `json:"slug"`
+	Content     string                `json:"content"`
+	ContentHTML string                `json:"content_html"`
+	Author      *AuthorResponse       `json:"author"`
+	Category    *CategoryInfoResponse `json:"category"`
+	IsPinned    bool                  `json:"is_pinned"`
+	IsLocked    bool                  `json:"is_locked"`
+	VoteCount   int                   `json:"vote_count"`
+	Tags        []*TagInfoResponse    `json:"tags"`
+	Attachments []*AttachmentResponse `json:"attachments"`
+	Bookmarked  *bool                 `json:"bookmarked,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   *time.Time            `json:"updated_at,omitempty"`
 }
 
 func NewThreadDetailResponse(t *domain.Thread, author *domain.User, cat *domain.Category) *ThreadDetailResponse {
 	return &ThreadDetailResponse{
-		ID:        t.ID,
-		Title:     t.Title,
-		Slug:      t.Slug,
-		Content:   t.Content,
-		Author:    NewAuthorResponse(author),
-		Category:  NewCategoryInfoResponse(cat),
-		IsPinned:  t.IsPinned,
-		IsLocked:  t.IsLocked,
-		VoteCount: t.VoteCount,
-		CreatedAt: t.CreatedAt,
-		UpdatedAt: t.UpdatedAt,
+		ID:          t.ID,
+		Title:       t.Title,
+		Slug:        t.Slug,
+		Content:     t.Content,
+		ContentHTML: t.ContentHTML,
+		Author:      NewAuthorResponse(author),
+		Category:    NewCategoryInfoResponse(cat),
+		IsPinned:    t.IsPinned,
+		IsLocked:    t.IsLocked,
+		VoteCount:   t.VoteCount,
+		Tags:        NewTagInfoResponses(t.Tags),
+		Attachments: NewAttachmentResponses(t.Attachments),
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
 	}
 }
 
@@ -119,34 +130,121 @@ func NewThreadSummaryResponse(t *domain.Thread, author *domain.User, cat *domain
 		IsPinned:  t.IsPinned,
 		IsLocked:  t.IsLocked,
 		VoteCount: t.VoteCount,
+		Tags:      NewTagInfoResponses(t.Tags),
 		CreatedAt: t.CreatedAt,
+		DeletedAt: t.DeletedAt,
 	}
 }
 
 type PostResponse struct {
-	ID           uuid.UUID       `json:"id"`
-	Content      string          `json:"content"`
-	Author       *AuthorResponse `json:"author"`
-	ThreadID     uuid.UUID       `json:"thread_id"`
-	ParentPostID *uuid.UUID      `json:"parent_post_id,omitempty"`
-	VoteCount    int             `json:"vote_count"`
-	CreatedAt    time.Time       `json:"created_at"`
-	UpdatedAt    *time.Time      `json:"updated_at,omitempty"`
+	ID           uuid.UUID             `json:"id"`
+	Content      string                `json:"content"`
+	ContentHTML  string                `json:"content_html"`
+	Author       *AuthorResponse       `json:"author"`
+	ThreadID     uuid.UUID             `json:"thread_id"`
+	ParentPostID *uuid.UUID            `json:"parent_post_id,omitempty"`
+	VoteCount    int                   `json:"vote_count"`
+	Attachments  []*AttachmentResponse `json:"attachments"`
+	Bookmarked   *bool                 `json:"bookmarked,omitempty"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    *time.Time            `json:"updated_at,omitempty"`
 }
 
 func NewPostResponse(p *domain.Post, author *domain.User) *PostResponse {
 	return &PostResponse{
 		ID:           p.ID,
 		Content:      p.Content,
+		ContentHTML:  p.ContentHTML,
 		Author:       NewAuthorResponse(author),
 		ThreadID:     p.ThreadID,
 		ParentPostID: p.ParentPostID,
 		VoteCount:    p.VoteCount,
+		Attachments:  NewAttachmentResponses(p.Attachments),
 		CreatedAt:    p.CreatedAt,
 		UpdatedAt:    p.UpdatedAt,
 	}
 }
 
+type AuditLogResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	Actor      *AuthorResponse `json:"actor"`
+	ActorRole  string          `json:"actor_role"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   uuid.UUID       `json:"target_id"`
+	Reason     *string         `json:"reason,omitempty"`
+	Details    *string         `json:"details,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func NewAuditLogResponse(entry *domain.AuditLog, actor *domain.User) *AuditLogResponse {
+	return &AuditLogResponse{
+		ID:         entry.ID,
+		Actor:      NewAuthorResponse(actor),
+		ActorRole:  entry.ActorRole,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Reason:     entry.Reason,
+		Details:    entry.Details,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
+
+type NotificationResponse struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	Actor     *AuthorResponse `json:"actor,omitempty"`
+	ThreadID  *uuid.UUID      `json:"thread_id,omitempty"`
+	PostID    *uuid.UUID      `json:"post_id,omitempty"`
+	Milestone *int            `json:"milestone,omitempty"`
+	IsRead    bool            `json:"is_read"`
+	CreatedAt time.Time       `json:"created_at"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+}
+
+func NewNotificationResponse(n *domain.Notification, actor *domain.User) *NotificationResponse {
+	return &NotificationResponse{
+		ID:        n.ID,
+		Type:      n.Type,
+		Actor:     NewAuthorResponse(actor),
+		ThreadID:  n.ThreadID,
+		PostID:    n.PostID,
+		Milestone: n.Milestone,
+		IsRead:    n.IsRead,
+		CreatedAt: n.CreatedAt,
+		ReadAt:    n.ReadAt,
+	}
+}
+
+type DigestPreferencesResponse struct {
+	Frequency  string     `json:"frequency"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+}
+
+func NewDigestPreferencesResponse(p *domain.DigestPreferences) *DigestPreferencesResponse {
+	return &DigestPreferencesResponse{
+		Frequency:  p.Frequency,
+		LastSentAt: p.LastSentAt,
+	}
+}
+
+type NotificationPreferencesResponse struct {
+	ThreadReply   bool `json:"thread_reply"`
+	PostReply     bool `json:"post_reply"`
+	Mention       bool `json:"mention"`
+	VoteMilestone bool `json:"vote_milestone"`
+}
+
+func NewNotificationPreferencesResponse(p *domain.NotificationPreferences) *NotificationPreferencesResponse {
+	return &NotificationPreferencesResponse{
+		ThreadReply:   p.ThreadReply,
+		PostReply:     p.PostReply,
+		Mention:       p.Mention,
+		VoteMilestone: p.VoteMilestone,
+	}
+}
+
 type PaginationMeta struct {
 	TotalItems  int `json:"total_items"`
 	TotalPages  int `json:"total_pages"`
@@ -189,3 +287,221 @@ func NewCategoryInfoResponse(cat *domain.Category) *CategoryInfoResponse {
 		Slug: cat.Slug,
 	}
 }
+
+type TagInfoResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func NewTagInfoResponses(tags []*domain.Tag) []*TagInfoResponse {
+	dtos := make([]*TagInfoResponse, len(tags))
+	for i, t := range tags {
+		dtos[i] = &TagInfoResponse{ID: t.ID, Name: t.Name}
+	}
+
+	return dtos
+}
+
+type TagResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	ThreadCount int       `json:"thread_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewTagResponse(t *domain.Tag) *TagResponse {
+	return &TagResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		ThreadCount: t.ThreadCount,
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+type AttachmentResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewAttachmentResponse(a *domain.Attachment) *AttachmentResponse {
+	return &AttachmentResponse{
+		ID:          a.ID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		SizeBytes:   a.SizeBytes,
+		URL:         "/api/v1/attachments/" + a.ID.String(),
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func NewAttachmentResponses(attachments []*domain.Attachment) []*AttachmentResponse {
+	dtos := make([]*AttachmentResponse, len(attachments))
+	for i, a := range attachments {
+		dtos[i] = NewAttachmentResponse(a)
+	}
+
+	return dtos
+}
+
+type ThreadReadStateResponse struct {
+	ThreadID          uuid.UUID              `json:"thread_id"`
+	Thread            *ThreadSummaryResponse `json:"thread,omitempty"`
+	IsSubscribed      bool                   `json:"is_subscribed"`
+	SubscribedAt      *time.Time             `json:"subscribed_at,omitempty"`
+	LastReadPostID    *uuid.UUID             `json:"last_read_post_id"`
+	LastReadAt        *time.Time             `json:"last_read_at"`
+	UnreadCount       int                    `json:"unread_count"`
+	FirstUnreadPostID *uuid.UUID             `json:"first_unread_post_id"`
+	LastActivityAt    time.Time              `json:"last_activity_at"`
+}
+
+func NewThreadReadStateResponse(s *domain.ThreadReadState, author *domain.User, cat *domain.Category) *ThreadReadStateResponse {
+	var thread *ThreadSummaryResponse
+	if s.Thread != nil {
+		thread = NewThreadSummaryResponse(s.Thread, author, cat)
+	}
+
+	return &ThreadReadStateResponse{
+		ThreadID:          s.ThreadID,
+		Thread:            thread,
+		IsSubscribed:      s.SubscribedAt != nil,
+		SubscribedAt:      s.SubscribedAt,
+		LastReadPostID:    s.LastReadPostID,
+		LastReadAt:        s.LastReadAt,
+		UnreadCount:       s.UnreadCount,
+		FirstUnreadPostID: s.FirstUnreadPostID,
+		LastActivityAt:    s.LastActivityAt,
+	}
+}
+
+type BookmarkFolderResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	BookmarkCount int       `json:"bookmark_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func NewBookmarkFolderResponse(f *domain.BookmarkFolder) *BookmarkFolderResponse {
+	return &BookmarkFolderResponse{
+		ID:            f.ID,
+		Name:          f.Name,
+		BookmarkCount: f.BookmarkCount,
+		CreatedAt:     f.CreatedAt,
+	}
+}
+
+type BookmarkResponse struct {
+	ID        uuid.UUID              `json:"id"`
+	Type      string                 `json:"type"`
+	ThreadID  uuid.UUID              `json:"thread_id"`
+	PostID    *uuid.UUID             `json:"post_id,omitempty"`
+	FolderID  *uuid.UUID             `json:"folder_id"`
+	Note      *string                `json:"note"`
+	Thread    *ThreadSummaryResponse `json:"thread,omitempty"`
+	Post      *PostResponse          `json:"post,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+func NewBookmarkResponse(b *domain.Bookmark, userMap map[uuid.UUID]*domain.User, catMap map[uuid.UUID]*domain.Category) *BookmarkResponse {
+	dto := &BookmarkResponse{
+		ID:        b.ID,
+		Type:      b.Type(),
+		ThreadID:  b.ThreadID,
+		PostID:    b.PostID,
+		FolderID:  b.FolderID,
+		Note:      b.Note,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+
+	if b.Thread != nil {
+		dto.Thread = NewThreadSummaryResponse(b.Thread, userMap[b.Thread.UserID], catMap[b.Thread.CategoryID])
+	}
+
+	if b.Post != nil {
+		dto.Post = NewPostResponse(b.Post, userMap[b.Post.UserID])
+	}
+
+	return dto
+}
+
+type WebhookResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	URL         string     `json:"url"`
+	Events      []string   `json:"events"`
+	Description *string    `json:"description"`
+	IsActive    bool       `json:"is_active"`
+	Secret      string     `json:"secret,omitempty"`
+	CreatedBy   *uuid.UUID `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+// NewWebhookResponse leaves the signing secret out unless withSecret is
+// set, which is only the case right after it was generated.
+func NewWebhookResponse(w *domain.Webhook, withSecret bool) *WebhookResponse {
+	dto := &WebhookResponse{
+		ID:          w.ID,
+		URL:         w.URL,
+		Events:      w.Events,
+		Description: w.Description,
+		IsActive:    w.IsActive,
+		CreatedBy:   w.CreatedBy,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+
+	if withSecret {
+		dto.Secret = w.Secret
+	}
+
+	return dto
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	ResponseStatus *int            `json:"response_status"`
+	ResponseBody   *string         `json:"response_body"`
+	LastError      *string         `json:"last_error"`
+	RedeliveryOf   *uuid.UUID      `json:"redelivery_of"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+func NewWebhookDeliveryResponse(d *domain.WebhookDelivery) *WebhookDeliveryResponse {
+	dto := &WebhookDeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		ResponseBody:   d.ResponseBody,
+		LastError:      d.LastError,
+		RedeliveryOf:   d.RedeliveryOf,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+
+	if d.Status == domain.WebhookDeliveryPending {
+		dto.NextAttemptAt = &d.NextAttemptAt
+	}
+
+	return dto
+}
@@ -1,18 +1,66 @@
 package http
 
-import "github.com/gin-gonic/gin"
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/metrics"
+)
+
+// publicReadRoutes are the GET routes anyone may call without a token. The
+// public read CORS policy applies to them, so keep this list in step with
+// the optional group and the public reads registered in NewRouter.
+var publicReadRoutes = []string{
+	"/api/v1/threads",
+	"/api/v1/threads/:thread_id",
+	"/api/v1/threads/:thread_id/posts",
+	"/api/v1/threads/:thread_id/events",
+	"/api/v1/tags",
+	"/api/v1/tags/:tag/threads",
+	"/api/v1/categories",
+	"/api/v1/attachments/:attachment_id",
+}
 
 func NewRouter(
 	userHandler *UserHandler,
 	authMiddleware *AuthMiddleware,
+	corsMiddleware *CORSMiddleware,
 	categoryHandler *CategoryHandler,
 	threadHandler *ThreadHandler,
 	postHandler *PostHandler,
 	voteHandler *VoteHandler,
+	auditLogHandler *AuditLogHandler,
+	notificationHandler *NotificationHandler,
+	eventHandler *EventHandler,
+	webSocketHandler *WebSocketHandler,
+	tagHandler *TagHandler,
+	attachmentHandler *AttachmentHandler,
+	subscriptionHandler *SubscriptionHandler,
+	bookmarkHandler *BookmarkHandler,
+	digestHandler *DigestHandler,
+	webhookHandler *WebhookHandler,
+	healthHandler *HealthHandler,
+	logger *slog.Logger,
+	m *metrics.Metrics,
 ) *gin.Engine {
-	router := gin.Default()
+	useJSONFieldNames()
+
+	router := gin.New()
+
+	router.Use(RequestID(), Tracing(), RequestLogger(logger), Metrics(m), Recovery(logger), Errors(), corsMiddleware.Apply())
+
+	router.NoRoute(func(c *gin.Context) {
+		c.Error(domain.ErrNotFound)
+	})
 
-	router.Use(SetupCORS())
+	router.GET("/metrics", gin.WrapH(m.Handler()))
+	router.GET("/healthz", healthHandler.Live)
+	router.GET("/readyz", healthHandler.Ready)
+	router.GET("/openapi.json", OpenAPISpec)
+	router.GET("/docs", SwaggerUI)
+	router.GET("/docs/swagger-ui.css", SwaggerUIAsset)
+	router.GET("/docs/swagger-ui-bundle.js", SwaggerUIAsset)
 
 	api := router.Group("/api/v1")
 	{
@@ -30,6 +78,26 @@ func NewRouter(
 			users := protected.Group("/users")
 			{
 				users.GET("/me", userHandler.GetMyProfile)
+				users.GET("/me/mentions", threadHandler.GetMentioningMe)
+				users.GET("/me/subscriptions", subscriptionHandler.GetMine)
+				users.GET("/me/bookmarks", bookmarkHandler.GetMine)
+				users.GET("/me/bookmark-folders", bookmarkHandler.GetFolders)
+				users.POST("/me/bookmark-folders", bookmarkHandler.CreateFolder)
+				users.PATCH("/me/bookmark-folders/:folder_id", bookmarkHandler.RenameFolder)
+				users.DELETE("/me/bookmark-folders/:folder_id", bookmarkHandler.DeleteFolder)
+				users.GET("/me/followed-categories", categoryHandler.GetFollowed)
+				users.GET("/me/digest", digestHandler.GetPreferences)
+				users.PUT("/me/digest", digestHandler.UpdatePreferences)
+			}
+
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", notificationHandler.GetAll)
+				notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+				notifications.POST("/:notification_id/read", notificationHandler.MarkRead)
+				notifications.POST("/read-all", notificationHandler.MarkAllRead)
+				notifications.GET("/preferences", notificationHandler.GetPreferences)
+				notifications.PUT("/preferences", notificationHandler.UpdatePreferences)
 			}
 
 			admin := protected.Group("/admin")
@@ -37,27 +105,74 @@ func NewRouter(
 			{
 				admin.POST("/categories", categoryHandler.Create)
 				admin.GET("/users", userHandler.GetUsers)
+				admin.PATCH("/users/:user_id/role", userHandler.UpdateRole)
+				admin.GET("/audit-logs", auditLogHandler.GetAll)
+				admin.GET("/threads/trash", threadHandler.GetTrash)
+				admin.POST("/threads/:thread_id/restore", threadHandler.Restore)
+				admin.POST("/threads/:thread_id/lock", threadHandler.Lock)
+				admin.POST("/threads/:thread_id/unlock", threadHandler.Unlock)
+				admin.PATCH("/tags/:tag", tagHandler.Rename)
+				admin.POST("/tags/:tag/merge", tagHandler.Merge)
+				admin.POST("/webhooks", webhookHandler.Create)
+				admin.GET("/webhooks", webhookHandler.GetAll)
+				admin.GET("/webhooks/:webhook_id", webhookHandler.GetByID)
+				admin.PATCH("/webhooks/:webhook_id", webhookHandler.Update)
+				admin.DELETE("/webhooks/:webhook_id", webhookHandler.Delete)
+				admin.GET("/webhooks/:webhook_id/deliveries", webhookHandler.GetDeliveries)
+				admin.POST("/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
 			}
 
+			protected.POST("/categories/:category_id/follow", categoryHandler.Follow)
+			protected.DELETE("/categories/:category_id/follow", categoryHandler.Unfollow)
+
 			protected.POST("/threads", threadHandler.Create)
 			protected.DELETE("/threads/:thread_id", threadHandler.Delete)
 			protected.PATCH("/threads/:thread_id", threadHandler.Update)
 
+			protected.POST("/threads/:thread_id/subscription", subscriptionHandler.Subscribe)
+			protected.DELETE("/threads/:thread_id/subscription", subscriptionHandler.Unsubscribe)
+			protected.GET("/threads/:thread_id/read", subscriptionHandler.GetReadState)
+			protected.PUT("/threads/:thread_id/read", subscriptionHandler.MarkRead)
+
+			protected.PUT("/threads/:thread_id/bookmark", bookmarkHandler.BookmarkThread)
+			protected.DELETE("/threads/:thread_id/bookmark", bookmarkHandler.RemoveThreadBookmark)
+
 			protected.POST("/threads/:thread_id/posts", postHandler.Create)
+			protected.PATCH("/posts/:post_id", postHandler.Update)
+
+			protected.PUT("/posts/:post_id/bookmark", bookmarkHandler.BookmarkPost)
+			protected.DELETE("/posts/:post_id/bookmark", bookmarkHandler.RemovePostBookmark)
+
+			protected.POST("/threads/:thread_id/attachments", attachmentHandler.UploadToThread)
+			protected.POST("/posts/:post_id/attachments", attachmentHandler.UploadToPost)
+			protected.DELETE("/attachments/:attachment_id", attachmentHandler.Delete)
 
 			protected.POST("/threads/:thread_id/vote", voteHandler.VoteOnThread)
 			protected.POST("/posts/:post_id/vote", voteHandler.VoteOnPost)
+		}
 
-			postsGroup := api.Group("")
-			{
-				postsGroup.GET("/threads/:thread_id/posts", postHandler.GetByThreadID)
-			}
+		// Public reads that also mark the caller's bookmarks when a token
+		// is sent.
+		optional := api.Group("")
+		optional.Use(authMiddleware.OptionalAuthenticate())
+		{
+			optional.GET("/threads", threadHandler.GetAll)
+			optional.GET("/threads/:thread_id", threadHandler.GetByID)
+			optional.GET("/threads/:thread_id/posts", postHandler.GetByThreadID)
+			optional.GET("/tags/:tag/threads", threadHandler.GetByTag)
 		}
 
 		api.GET("/categories", categoryHandler.GetAll)
 
-		api.GET("/threads", threadHandler.GetAll)
-		api.GET("/threads/:thread_id", threadHandler.GetByID)
+		api.GET("/digest/unsubscribe", digestHandler.ConfirmUnsubscribe)
+		api.POST("/digest/unsubscribe", digestHandler.Unsubscribe)
+		api.GET("/threads/:thread_id/events", eventHandler.StreamThread)
+
+		api.GET("/attachments/:attachment_id", attachmentHandler.Download)
+
+		api.GET("/tags", tagHandler.Search)
+
+		api.GET("/ws", webSocketHandler.Connect)
 	}
 
 	return router
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type SubscriptionHandler struct {
+	subscriptionUsecase usecase.SubscriptionUsecase
+}
+
+func NewSubscriptionHandler(su usecase.SubscriptionUsecase) *SubscriptionHandler {
+	return &SubscriptionHandler{subscriptionUsecase: su}
+}
+
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	threadID, err := getThreadIDFromParam(c)
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	state, err := h.subscriptionUsecase.Subscribe(c.Request.Context(), userID, threadID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewThreadReadStateResponse(state, nil, nil))
+}
+
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	threadID, err := getThreadIDFromParam(c)
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	if err := h.subscriptionUsecase.Unsubscribe(c.Request.Context(), userID, threadID); err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "unsubscribed successfully"})
+}
+
+func (h *SubscriptionHandler) GetMine(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	params, err := getPaginationParams(c)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	states, userMap, catMap, totalItems, err := h.subscriptionUsecase.GetByUserID(c.Request.Context(), userID, params)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*ThreadReadStateResponse, len(states))
+	for i, s := range states {
+		var author *domain.User
+		var cat *domain.Category
+		if s.Thread != nil {
+			author = userMap[s.Thread.UserID]
+			cat = catMap[s.Thread.CategoryID]
+		}
+		dtos[i] = NewThreadReadStateResponse(s, author, cat)
+	}
+
+	response := gin.H{
+		"data": dtos,
+		"meta": newPaginationMeta(totalItems, params),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *SubscriptionHandler) GetReadState(c *gin.Context) {
+	threadID, err := getThreadIDFromParam(c)
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	state, err := h.subscriptionUsecase.GetReadState(c.Request.Context(), userID, threadID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewThreadReadStateResponse(state, nil, nil))
+}
+
+// MarkRead accepts an optional post_id; without one the whole thread is
+// marked read.
+func (h *SubscriptionHandler) MarkRead(c *gin.Context) {
+	threadID, err := getThreadIDFromParam(c)
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	var req MarkThreadReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	state, err := h.subscriptionUsecase.MarkRead(c.Request.Context(), userID, threadID, req.PostID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewThreadReadStateResponse(state, nil, nil))
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type TagHandler struct {
+	tagUsecase usecase.TagUsecase
+}
+
+func NewTagHandler(tu usecase.TagUsecase) *TagHandler {
+	return &TagHandler{tagUsecase: tu}
+}
+
+// Search serves tag autocomplete: GET /tags?q=go&limit=10.
+func (h *TagHandler) Search(c *gin.Context) {
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.Error(domain.Invalid(domain.FieldError{Field: "limit", Rule: "min", Param: "1"}))
+
+			return
+		}
+		limit = n
+	}
+
+	tags, err := h.tagUsecase.Search(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*TagResponse, len(tags))
+	for i, t := range tags {
+		dtos[i] = NewTagResponse(t)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dtos})
+}
+
+func (h *TagHandler) Rename(c *gin.Context) {
+	var req RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	actorID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	actorRole, _ := getUserRoleFromCtx(c)
+
+	tag, err := h.tagUsecase.Rename(c.Request.Context(), actorID, actorRole, c.Param("tag"), req.Name, req.Reason)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewTagResponse(tag))
+}
+
+func (h *TagHandler) Merge(c *gin.Context) {
+	var req MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	actorID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	actorRole, _ := getUserRoleFromCtx(c)
+
+	tag, err := h.tagUsecase.Merge(c.Request.Context(), actorID, actorRole, c.Param("tag"), req.Into, req.Reason)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewTagResponse(tag))
+}
@@ -1,9 +1,9 @@
 package http
 
 import (
-	"log"
-	"math"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,37 +12,33 @@ import (
 )
 
 type ThreadHandler struct {
-	threadUsecase usecase.ThreadUsecase
+	threadUsecase   usecase.ThreadUsecase
+	bookmarkUsecase usecase.BookmarkUsecase
+	logger          *slog.Logger
 }
 
-func NewThreadHandler(tu usecase.ThreadUsecase) *ThreadHandler {
-	return &ThreadHandler{threadUsecase: tu}
+func NewThreadHandler(tu usecase.ThreadUsecase, bu usecase.BookmarkUsecase, logger *slog.Logger) *ThreadHandler {
+	return &ThreadHandler{threadUsecase: tu, bookmarkUsecase: bu, logger: logger}
 }
 
 func (h *ThreadHandler) Create(c *gin.Context) {
 	var req CreateThreadRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
 
 		return
 	}
 
 	userID, exists := getUserIDFromCtx(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		c.Error(domain.ErrUnauthorized)
 
 		return
 	}
 
-	thread, user, category, err := h.threadUsecase.Create(c.Request.Context(), req.Title, req.Content, userID, req.CategoryID)
+	thread, user, category, err := h.threadUsecase.Create(c.Request.Context(), req.Title, req.Content, req.Tags, userID, req.CategoryID)
 	if err != nil {
-		if err == domain.ErrInvalid {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input or category ID"})
-
-			return
-		}
-		log.Fatalf("[ERROR]: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 
 		return
 	}
@@ -53,15 +49,26 @@ func (h *ThreadHandler) Create(c *gin.Context) {
 func (h *ThreadHandler) GetAll(c *gin.Context) {
 	params, err := getPaginationParams(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pagination parameters"})
+		c.Error(err)
 
 		return
 	}
 
-	threads, userMap, catMap, totalItems, err := h.threadUsecase.GetAll(c.Request.Context(), params)
+	filter := usecase.ThreadFilter{
+		MatchAll: c.Query("tag_match") == "all",
+	}
+
+	for _, v := range c.QueryArray("tags") {
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+
+	threads, userMap, catMap, totalItems, err := h.threadUsecase.GetAll(c.Request.Context(), filter, params)
 	if err != nil {
-		log.Fatalf("[ERROR]: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 
 		return
 	}
@@ -71,21 +78,11 @@ func (h *ThreadHandler) GetAll(c *gin.Context) {
 		dtos[i] = NewThreadSummaryResponse(t, userMap[t.UserID], catMap[t.CategoryID])
 	}
 
-	totalPages := 0
-	if params.Limit > 0 {
-		totalPages = int(math.Ceil(float64(totalItems) / float64(params.Limit)))
-	}
-
-	meta := PaginationMeta{
-		TotalItems:  totalItems,
-		TotalPages:  totalPages,
-		CurrentPage: (params.Offset / params.Limit) + 1,
-		Limit:       params.Limit,
-	}
+	h.markBookmarked(c, dtos)
 
 	response := gin.H{
 		"data": dtos,
-		"meta": meta,
+		"meta": newPaginationMeta(totalItems, params),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -95,26 +92,20 @@ func (h *ThreadHandler) GetByID(c *gin.Context) {
 	idParam := c.Param("thread_id")
 	threadID, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid thread ID"})
+		c.Error(invalidParam("thread_id", "uuid"))
 
 		return
 	}
 
 	thread, user, cat, err := h.threadUsecase.GetByID(c.Request.Context(), threadID)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "thread not found"})
-
-			return
-		}
-
-		log.Fatalf("[ERROR]: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 
 		return
 	}
 
 	dto := NewThreadDetailResponse(thread, user, cat)
+	dto.Bookmarked = bookmarkFlag(bookmarkedThreads(c, h.bookmarkUsecase, h.logger, thread.ID), thread.ID)
 	c.JSON(http.StatusOK, dto)
 }
 
@@ -122,31 +113,28 @@ func (h *ThreadHandler) Delete(c *gin.Context) {
 	idParam := c.Param("thread_id")
 	threadID, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid thread ID"})
+		c.Error(invalidParam("thread_id", "uuid"))
 
 		return
 	}
 
 	userID, exists := getUserIDFromCtx(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		c.Error(domain.ErrUnauthorized)
 
 		return
 	}
 
 	role, _ := getUserRoleFromCtx(c)
 
-	err = h.threadUsecase.Delete(c.Request.Context(), threadID, userID, role)
+	var reason *string
+	if r := c.Query("reason"); r != "" {
+		reason = &r
+	}
+
+	err = h.threadUsecase.Delete(c.Request.Context(), threadID, userID, role, reason)
 	if err != nil {
-		switch err {
-		case domain.ErrNotFound:
-			c.JSON(http.StatusNotFound, gin.H{"error": "thread not found"})
-		case domain.ErrForbidden:
-			c.JSON(http.StatusForbidden, gin.H{"error": "you are not authorized to delete this thread"})
-		default:
-			log.Printf("[ERROR]: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		c.Error(err)
 
 		return
 	}
@@ -158,21 +146,21 @@ func (h *ThreadHandler) Update(c *gin.Context) {
 	idParam := c.Param("thread_id")
 	threadID, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid thread ID"})
+		c.Error(invalidParam("thread_id", "uuid"))
 
 		return
 	}
 
 	var req UpdateThreadRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
 
 		return
 	}
 
 	userID, exists := getUserIDFromCtx(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		c.Error(domain.ErrUnauthorized)
 
 		return
 	}
@@ -182,22 +170,199 @@ func (h *ThreadHandler) Update(c *gin.Context) {
 	params := usecase.UpdateThreadParams{
 		Title:   req.Title,
 		Content: req.Content,
+		Tags:    req.Tags,
+		Reason:  req.Reason,
 	}
 
 	thread, user, cat, err := h.threadUsecase.Update(c.Request.Context(), threadID, userID, role, params)
 	if err != nil {
-		switch err {
-		case domain.ErrNotFound:
-			c.JSON(http.StatusNotFound, gin.H{"error": "thread not found"})
-		case domain.ErrForbidden:
-			c.JSON(http.StatusForbidden, gin.H{"error": "you are not authorized to update this thread"})
-		default:
-			log.Fatalf("[ERROR]: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		c.Error(err)
+
+		return
+	}
+
+	dto := NewThreadDetailResponse(thread, user, cat)
+	dto.Bookmarked = bookmarkFlag(bookmarkedThreads(c, h.bookmarkUsecase, h.logger, thread.ID), thread.ID)
+	c.JSON(http.StatusOK, dto)
+}
+
+func (h *ThreadHandler) GetTrash(c *gin.Context) {
+	params, err := getPaginationParams(c)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	threads, userMap, catMap, totalItems, err := h.threadUsecase.GetDeleted(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*ThreadSummaryResponse, len(threads))
+	for i, t := range threads {
+		dtos[i] = NewThreadSummaryResponse(t, userMap[t.UserID], catMap[t.CategoryID])
+	}
+
+	response := gin.H{
+		"data": dtos,
+		"meta": newPaginationMeta(totalItems, params),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ThreadHandler) Restore(c *gin.Context) {
+	idParam := c.Param("thread_id")
+	threadID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	role, _ := getUserRoleFromCtx(c)
+
+	var reason *string
+	if r := c.Query("reason"); r != "" {
+		reason = &r
+	}
+
+	thread, user, cat, err := h.threadUsecase.Restore(c.Request.Context(), threadID, userID, role, reason)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewThreadDetailResponse(thread, user, cat))
+}
+
+func (h *ThreadHandler) Lock(c *gin.Context) {
+	h.setLocked(c, true)
+}
+
+func (h *ThreadHandler) Unlock(c *gin.Context) {
+	h.setLocked(c, false)
+}
+
+func (h *ThreadHandler) setLocked(c *gin.Context, locked bool) {
+	idParam := c.Param("thread_id")
+	threadID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.Error(invalidParam("thread_id", "uuid"))
+
+		return
+	}
+
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	role, _ := getUserRoleFromCtx(c)
+
+	var reason *string
+	if r := c.Query("reason"); r != "" {
+		reason = &r
+	}
+
+	thread, user, cat, err := h.threadUsecase.SetLocked(c.Request.Context(), threadID, userID, role, locked, reason)
+	if err != nil {
+		c.Error(err)
 
 		return
 	}
 
 	c.JSON(http.StatusOK, NewThreadDetailResponse(thread, user, cat))
 }
+
+func (h *ThreadHandler) GetByTag(c *gin.Context) {
+	params, err := getPaginationParams(c)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	threads, userMap, catMap, totalItems, err := h.threadUsecase.GetByTag(c.Request.Context(), c.Param("tag"), params)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*ThreadSummaryResponse, len(threads))
+	for i, t := range threads {
+		dtos[i] = NewThreadSummaryResponse(t, userMap[t.UserID], catMap[t.CategoryID])
+	}
+
+	h.markBookmarked(c, dtos)
+
+	response := gin.H{
+		"data": dtos,
+		"meta": newPaginationMeta(totalItems, params),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ThreadHandler) GetMentioningMe(c *gin.Context) {
+	userID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	params, err := getPaginationParams(c)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	threads, userMap, catMap, totalItems, err := h.threadUsecase.GetMentioning(c.Request.Context(), userID, params)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*ThreadSummaryResponse, len(threads))
+	for i, t := range threads {
+		dtos[i] = NewThreadSummaryResponse(t, userMap[t.UserID], catMap[t.CategoryID])
+	}
+
+	h.markBookmarked(c, dtos)
+
+	response := gin.H{
+		"data": dtos,
+		"meta": newPaginationMeta(totalItems, params),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ThreadHandler) markBookmarked(c *gin.Context, dtos []*ThreadSummaryResponse) {
+	ids := make([]uuid.UUID, len(dtos))
+	for i, dto := range dtos {
+		ids[i] = dto.ID
+	}
+
+	flags := bookmarkedThreads(c, h.bookmarkUsecase, h.logger, ids...)
+	for _, dto := range dtos {
+		dto.Bookmarked = bookmarkFlag(flags, dto.ID)
+	}
+}
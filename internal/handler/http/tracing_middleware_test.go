@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/srgjo27/agora/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingContinuesIncomingTraceAndIgnoresClientErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { provider.Shutdown(t.Context()) })
+
+	router := gin.New()
+	router.Use(Tracing(), Errors())
+	router.GET("/threads/:thread_id", func(c *gin.Context) {
+		c.Error(domain.ErrNotFound)
+	})
+	router.GET("/boom", func(c *gin.Context) {
+		c.Error(errInternal)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/threads/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+
+	notFound := spans[0]
+	if notFound.Name() != "GET /threads/:thread_id" {
+		t.Errorf("span name = %q", notFound.Name())
+	}
+	if got := notFound.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the one from traceparent", got)
+	}
+	if notFound.Status().Code == codes.Error {
+		t.Error("a 404 marked the span as failed")
+	}
+
+	if spans[1].Status().Code != codes.Error {
+		t.Error("a 500 did not mark the span as failed")
+	}
+}
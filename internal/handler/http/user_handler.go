@@ -1,10 +1,10 @@
 package http
 
 import (
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/srgjo27/agora/internal/config"
 	"github.com/srgjo27/agora/internal/domain"
 	"github.com/srgjo27/agora/internal/usecase"
@@ -23,20 +23,15 @@ func (h *UserHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
+
+		return
 	}
 
 	user, err := h.userUsecase.Register(c.Request.Context(), req.Username, req.Email, req.Password)
 
 	if err != nil {
-		switch err {
-		case domain.ErrConflict:
-			c.JSON(http.StatusConflict, gin.H{"error": "email already exists"})
-		case domain.ErrInvalid:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		c.Error(err)
 
 		return
 	}
@@ -48,21 +43,14 @@ func (h *UserHandler) Login(c *gin.Context) {
 	var req LoginRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
 		return
 	}
 
 	accessToken, refreshToken, err := h.userUsecase.Login(c.Request.Context(), req.Email, req.Password)
 
 	if err != nil {
-		switch err {
-		case domain.ErrUnauthorized:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
-		case domain.ErrInvalid:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		c.Error(err)
 
 		return
 	}
@@ -70,10 +58,10 @@ func (h *UserHandler) Login(c *gin.Context) {
 	c.SetCookie(
 		"refresh_token",
 		refreshToken,
-		int(h.cfg.RefreshTokenDurationHours*3600),
+		int(h.cfg.Auth.RefreshTokenDurationHours*3600),
 		"/api/v1/auth",
-		h.cfg.CookieDomain,
-		h.cfg.CookieSecure,
+		h.cfg.Auth.CookieDomain,
+		h.cfg.Auth.CookieSecure,
 		true,
 	)
 
@@ -85,13 +73,13 @@ func (h *UserHandler) Login(c *gin.Context) {
 func (h *UserHandler) Refresh(c *gin.Context) {
 	refreshToken, err := c.Cookie("refresh_token")
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token not found"})
+		c.Error(domain.ErrUnauthorized)
 		return
 	}
 
 	newAccessToken, err := h.userUsecase.Refresh(c.Request.Context(), refreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		c.Error(&domain.Error{Code: domain.CodeUnauthorized, Message: "invalid refresh token", Err: err})
 		return
 	}
 
@@ -106,8 +94,8 @@ func (h *UserHandler) Logout(c *gin.Context) {
 		"",
 		-1,
 		"/api/v1/auth",
-		h.cfg.CookieDomain,
-		h.cfg.CookieSecure,
+		h.cfg.Auth.CookieDomain,
+		h.cfg.Auth.CookieSecure,
 		true,
 	)
 
@@ -117,21 +105,14 @@ func (h *UserHandler) Logout(c *gin.Context) {
 func (h *UserHandler) GetMyProfile(c *gin.Context) {
 	userID, exists := getUserIDFromCtx(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found in context"})
+		c.Error(domain.ErrUnauthorized)
 
 		return
 	}
 
 	user, err := h.userUsecase.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-
-			return
-		}
-
-		log.Fatalf("[ERROR]: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 		return
 	}
 
@@ -141,11 +122,44 @@ func (h *UserHandler) GetMyProfile(c *gin.Context) {
 func (h *UserHandler) GetUsers(c *gin.Context) {
 	users, err := h.userUsecase.GetUsers(c.Request.Context())
 	if err != nil {
-		log.Fatalf("[ERROR]: %v", err)
-
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, NewUserListResponse(users))
 }
+
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	idParam := c.Param("user_id")
+	targetID, err := uuid.Parse(idParam)
+	if err != nil {
+		c.Error(invalidParam("user_id", "uuid"))
+
+		return
+	}
+
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	actorID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	actorRole, _ := getUserRoleFromCtx(c)
+
+	user, err := h.userUsecase.UpdateRole(c.Request.Context(), actorID, actorRole, targetID, req.Role, req.Reason)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewUserResponse(user))
+}
@@ -1,7 +1,6 @@
 package http
 
 import (
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,35 +21,28 @@ func (h *VoteHandler) VoteOnThread(c *gin.Context) {
 	idParam := c.Param("thread_id")
 	threadID, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid thread ID"})
+		c.Error(invalidParam("thread_id", "uuid"))
 
 		return
 	}
 
 	var req VoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vote_type, must be 1, 0, or -1"})
+		c.Error(invalidBody(err))
 
 		return
 	}
 
 	userID, exists := getUserIDFromCtx(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		c.Error(domain.ErrUnauthorized)
 
 		return
 	}
 
 	err = h.voteUsecase.VoteOnThread(c.Request.Context(), userID, threadID, req.VoteType)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "thread not found"})
-
-			return
-		}
-
-		log.Fatalf("[ERROR]: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 
 		return
 	}
@@ -62,35 +54,28 @@ func (h *VoteHandler) VoteOnPost(c *gin.Context) {
 	idParam := c.Param("post_id")
 	postID, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post ID"})
+		c.Error(invalidParam("post_id", "uuid"))
 
 		return
 	}
 
 	var req VoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vote_type, must be 1, 0, or -1"})
+		c.Error(invalidBody(err))
 
 		return
 	}
 
 	userID, exists := getUserIDFromCtx(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		c.Error(domain.ErrUnauthorized)
 
 		return
 	}
 
 	err = h.voteUsecase.VoteOnPost(c.Request.Context(), userID, postID, req.VoteType)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
-
-			return
-		}
-
-		log.Fatalf("[ERROR]: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 
 		return
 	}
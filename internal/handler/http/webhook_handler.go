@@ -0,0 +1,215 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type WebhookHandler struct {
+	webhookUsecase usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(wu usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{webhookUsecase: wu}
+}
+
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	actorID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	actorRole, _ := getUserRoleFromCtx(c)
+
+	webhook, err := h.webhookUsecase.Create(c.Request.Context(), actorID, actorRole, usecase.CreateWebhookParams{
+		URL:         req.URL,
+		Events:      req.Events,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.respondError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewWebhookResponse(webhook, true))
+}
+
+func (h *WebhookHandler) GetAll(c *gin.Context) {
+	webhooks, err := h.webhookUsecase.GetAll(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	dtos := make([]*WebhookResponse, len(webhooks))
+	for i, w := range webhooks {
+		dtos[i] = NewWebhookResponse(w, false)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dtos})
+}
+
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		c.Error(invalidParam("webhook_id", "uuid"))
+
+		return
+	}
+
+	webhook, err := h.webhookUsecase.GetByID(c.Request.Context(), webhookID)
+	if err != nil {
+		h.respondError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewWebhookResponse(webhook, false))
+}
+
+// Update changes a webhook. Setting rotate_secret generates a new signing
+// secret, which is returned once in the response.
+func (h *WebhookHandler) Update(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		c.Error(invalidParam("webhook_id", "uuid"))
+
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+
+		return
+	}
+
+	actorID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	actorRole, _ := getUserRoleFromCtx(c)
+
+	webhook, err := h.webhookUsecase.Update(c.Request.Context(), actorID, actorRole, webhookID, usecase.UpdateWebhookParams{
+		URL:          req.URL,
+		Events:       req.Events,
+		Description:  req.Description,
+		IsActive:     req.IsActive,
+		RotateSecret: req.RotateSecret,
+	})
+	if err != nil {
+		h.respondError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, NewWebhookResponse(webhook, req.RotateSecret))
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		c.Error(invalidParam("webhook_id", "uuid"))
+
+		return
+	}
+
+	actorID, exists := getUserIDFromCtx(c)
+	if !exists {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	actorRole, _ := getUserRoleFromCtx(c)
+
+	if err := h.webhookUsecase.Delete(c.Request.Context(), actorID, actorRole, webhookID); err != nil {
+		h.respondError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "webhook deleted successfully"})
+}
+
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		c.Error(invalidParam("webhook_id", "uuid"))
+
+		return
+	}
+
+	params, err := getPaginationParams(c)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	deliveries, totalItems, err := h.webhookUsecase.GetDeliveries(c.Request.Context(), webhookID, params)
+	if err != nil {
+		h.respondError(c, err)
+
+		return
+	}
+
+	dtos := make([]*WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		dtos[i] = NewWebhookDeliveryResponse(d)
+	}
+
+	response := gin.H{
+		"data": dtos,
+		"meta": newPaginationMeta(totalItems, params),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		c.Error(invalidParam("webhook_id", "uuid"))
+
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		c.Error(invalidParam("delivery_id", "uuid"))
+
+		return
+	}
+
+	delivery, err := h.webhookUsecase.Redeliver(c.Request.Context(), webhookID, deliveryID)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	c.JSON(http.StatusAccepted, NewWebhookDeliveryResponse(delivery))
+}
+
+func (h *WebhookHandler) respondError(c *gin.Context, err error) {
+	c.Error(err)
+}
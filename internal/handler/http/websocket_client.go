@@ -0,0 +1,335 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+const (
+	wsWriteWait        = 10 * time.Second
+	wsPongWait         = 60 * time.Second
+	wsPingPeriod       = (wsPongWait * 9) / 10
+	wsMaxMessageSize   = 4096
+	wsSendBuffer       = 64
+	wsMaxSubscriptions = 50
+	wsTypingInterval   = 3 * time.Second
+)
+
+const (
+	wsMessageSubscribe   = "subscribe"
+	wsMessageUnsubscribe = "unsubscribe"
+	wsMessageTyping      = "typing"
+	wsMessagePing        = "ping"
+
+	wsMessageSubscribed   = "subscribed"
+	wsMessageUnsubscribed = "unsubscribed"
+	wsMessageEvent        = "event"
+	wsMessagePong         = "pong"
+	wsMessageError        = "error"
+)
+
+type wsClientMessage struct {
+	Type  string `json:"type"`
+	Topic string `json:"topic"`
+}
+
+type wsServerMessage struct {
+	Type  string        `json:"type"`
+	Topic string        `json:"topic,omitempty"`
+	Event *domain.Event `json:"event,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+type wsClient struct {
+	handler *WebSocketHandler
+	conn    *websocket.Conn
+	userID  uuid.UUID
+	send    chan *wsServerMessage
+	logger  *slog.Logger
+
+	mu         sync.Mutex
+	subs       map[string]usecase.EventSubscription
+	lastTyping map[string]time.Time
+
+	done      chan struct{}
+	closeOnce sync.Once
+	closeCode int
+	closeText string
+}
+
+// newWSClient logs under the ID of the request that opened the connection,
+// so everything the client does can be traced back to its handshake.
+func newWSClient(h *WebSocketHandler, conn *websocket.Conn, userID uuid.UUID, requestID string) *wsClient {
+	return &wsClient{
+		handler:    h,
+		conn:       conn,
+		userID:     userID,
+		send:       make(chan *wsServerMessage, wsSendBuffer),
+		logger:     h.logger.With("user_id", userID, "request_id", requestID),
+		subs:       make(map[string]usecase.EventSubscription),
+		lastTyping: make(map[string]time.Time),
+		done:       make(chan struct{}),
+	}
+}
+
+// run serves the connection until either side closes it. Every client is
+// implicitly subscribed to its own notification topic.
+func (c *wsClient) run() {
+	writerDone := make(chan struct{})
+	go func() {
+		c.writePump()
+		close(writerDone)
+	}()
+
+	c.subscribe(domain.UserTopic(c.userID))
+	c.readPump()
+
+	c.close(websocket.CloseNormalClosure, "")
+	<-writerDone
+}
+
+func (c *wsClient) readPump() {
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				c.logger.Warn("websocket read error", "error", err)
+			}
+
+			return
+		}
+
+		var msg wsClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.enqueue(&wsServerMessage{Type: wsMessageError, Error: "invalid message"})
+
+			continue
+		}
+
+		c.handleMessage(&msg)
+	}
+}
+
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+
+				return
+			}
+		case <-c.done:
+			if c.closeCode != websocket.CloseAbnormalClosure {
+				msg := websocket.FormatCloseMessage(c.closeCode, c.closeText)
+				c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait))
+			}
+
+			return
+		}
+	}
+}
+
+func (c *wsClient) handleMessage(msg *wsClientMessage) {
+	switch msg.Type {
+	case wsMessageSubscribe:
+		if err := c.authorizeTopic(msg.Topic); err != "" {
+			c.enqueue(&wsServerMessage{Type: wsMessageError, Topic: msg.Topic, Error: err})
+
+			return
+		}
+
+		if !c.subscribe(msg.Topic) {
+			c.enqueue(&wsServerMessage{Type: wsMessageError, Topic: msg.Topic, Error: "too many subscriptions"})
+
+			return
+		}
+
+		c.enqueue(&wsServerMessage{Type: wsMessageSubscribed, Topic: msg.Topic})
+	case wsMessageUnsubscribe:
+		c.unsubscribe(msg.Topic)
+		c.enqueue(&wsServerMessage{Type: wsMessageUnsubscribed, Topic: msg.Topic})
+	case wsMessageTyping:
+		c.publishTyping(msg.Topic)
+	case wsMessagePing:
+		c.enqueue(&wsServerMessage{Type: wsMessagePong})
+	default:
+		c.enqueue(&wsServerMessage{Type: wsMessageError, Error: "unknown message type"})
+	}
+}
+
+// authorizeTopic returns an error message if the client may not subscribe
+// to topic. Thread and category topics are public; user topics are private.
+func (c *wsClient) authorizeTopic(topic string) string {
+	kind, id, ok := parseTopic(topic)
+	if !ok {
+		return "invalid topic"
+	}
+
+	if kind == domain.TopicUser && id != c.userID {
+		return "forbidden topic"
+	}
+
+	return ""
+}
+
+func (c *wsClient) subscribe(topic string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	if _, ok := c.subs[topic]; ok {
+		return true
+	}
+
+	if len(c.subs) >= wsMaxSubscriptions {
+		return false
+	}
+
+	sub := c.handler.subscriber.Subscribe(topic)
+	c.subs[topic] = sub
+
+	go func() {
+		for event := range sub.Events() {
+			c.enqueue(&wsServerMessage{Type: wsMessageEvent, Topic: topic, Event: event})
+		}
+	}()
+
+	return true
+}
+
+func (c *wsClient) unsubscribe(topic string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if sub, ok := c.subs[topic]; ok {
+		sub.Close()
+		delete(c.subs, topic)
+	}
+}
+
+func (c *wsClient) publishTyping(topic string) {
+	kind, threadID, ok := parseTopic(topic)
+	if !ok || kind != domain.TopicThread {
+		c.enqueue(&wsServerMessage{Type: wsMessageError, Topic: topic, Error: "typing is only supported on thread topics"})
+
+		return
+	}
+
+	c.mu.Lock()
+	last := c.lastTyping[topic]
+	throttled := time.Since(last) < wsTypingInterval
+	if !throttled {
+		c.lastTyping[topic] = time.Now()
+	}
+	c.mu.Unlock()
+
+	if throttled {
+		return
+	}
+
+	data, _ := json.Marshal(&domain.TypingEventData{UserID: c.userID, ThreadID: threadID})
+	event := &domain.Event{
+		ID:         uuid.New(),
+		Type:       domain.EventTyping,
+		Topic:      topic,
+		Data:       data,
+		OccurredAt: time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), wsWriteWait)
+	defer cancel()
+
+	if err := c.handler.publisher.Publish(ctx, event); err != nil {
+		c.logger.Error("failed to publish typing event", "error", err)
+	}
+}
+
+// enqueue never blocks. A client that cannot keep up with its event stream
+// is disconnected rather than allowed to stall the hub or grow unbounded.
+func (c *wsClient) enqueue(msg *wsServerMessage) {
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+	default:
+		c.logger.Warn("disconnecting slow websocket consumer")
+		c.close(websocket.ClosePolicyViolation, "slow consumer")
+	}
+}
+
+func (c *wsClient) close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.done)
+
+		c.mu.Lock()
+		for topic, sub := range c.subs {
+			sub.Close()
+			delete(c.subs, topic)
+		}
+		c.mu.Unlock()
+
+		// Unblock readPump, which may be waiting on the network.
+		c.conn.SetReadDeadline(time.Now().Add(wsWriteWait))
+	})
+}
+
+func parseTopic(topic string) (string, uuid.UUID, bool) {
+	kind, rawID, found := strings.Cut(topic, ":")
+	if !found {
+		return "", uuid.Nil, false
+	}
+
+	switch kind {
+	case domain.TopicThread, domain.TopicCategory, domain.TopicUser:
+	default:
+		return "", uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return "", uuid.Nil, false
+	}
+
+	return kind, id, true
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/logging"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// WebSocketHandler is a multiplexed gateway: one connection per client,
+// carrying notifications, thread/category events and typing indicators.
+type WebSocketHandler struct {
+	tokenSvc   usecase.TokenService
+	publisher  usecase.EventPublisher
+	subscriber usecase.EventSubscriber
+	upgrader   websocket.Upgrader
+	logger     *slog.Logger
+
+	mu      sync.Mutex
+	clients map[*wsClient]struct{}
+	closing bool
+	wg      sync.WaitGroup
+}
+
+func NewWebSocketHandler(ts usecase.TokenService, ep usecase.EventPublisher, es usecase.EventSubscriber, corsMiddleware *CORSMiddleware, logger *slog.Logger) *WebSocketHandler {
+	return &WebSocketHandler{
+		tokenSvc:   ts,
+		publisher:  ep,
+		subscriber: es,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				// Non-browser clients do not send an Origin header.
+				origin := r.Header.Get("Origin")
+
+				return origin == "" || corsMiddleware.AllowsOrigin(origin)
+			},
+		},
+		clients: make(map[*wsClient]struct{}),
+		logger:  logger,
+	}
+}
+
+// Connect authenticates with the same access token used for the REST API.
+// Browsers cannot set headers on a WebSocket handshake, so the token may also
+// be passed as the access_token query parameter.
+func (h *WebSocketHandler) Connect(c *gin.Context) {
+	tokenString := c.Query("access_token")
+	if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+			tokenString = parts[1]
+		}
+	}
+
+	if tokenString == "" {
+		c.Error(domain.ErrUnauthorized)
+
+		return
+	}
+
+	userID, _, err := h.tokenSvc.ValidateToken(c.Request.Context(), tokenString)
+	if err != nil {
+		c.Error(&domain.Error{Code: domain.CodeUnauthorized, Message: "invalid or expired token", Err: err})
+
+		return
+	}
+
+	h.mu.Lock()
+	if h.closing {
+		h.mu.Unlock()
+		c.Error(domain.ErrUnavailable)
+
+		return
+	}
+	h.wg.Add(1)
+	h.mu.Unlock()
+	defer h.wg.Done()
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already written an HTTP error response.
+		return
+	}
+
+	client := newWSClient(h, conn, userID, logging.RequestID(c.Request.Context()))
+
+	h.mu.Lock()
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, client)
+		h.mu.Unlock()
+	}()
+
+	client.run()
+}
+
+// Shutdown sends a going-away close frame to every client and waits for
+// their connections to finish, or for ctx to expire.
+func (h *WebSocketHandler) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	for client := range h.clients {
+		client.close(websocket.CloseGoingAway, "server shutting down")
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
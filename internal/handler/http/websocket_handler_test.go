@@ -0,0 +1,255 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// tokenTable is a TokenService that accepts only the given tokens.
+type tokenTable struct {
+	usecase.TokenService
+	users map[string]uuid.UUID
+}
+
+func (t tokenTable) ValidateToken(ctx context.Context, tokenString string) (uuid.UUID, string, error) {
+	userID, ok := t.users[tokenString]
+	if !ok {
+		return uuid.Nil, "", errors.New("unknown token")
+	}
+
+	return userID, "member", nil
+}
+
+type wsFixture struct {
+	userID     uuid.UUID
+	token      string
+	subscriber *channelSubscriber
+	url        string
+}
+
+func newWSFixture(t *testing.T) *wsFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	f := &wsFixture{userID: uuid.New(), token: "valid-token", subscriber: newChannelSubscriber()}
+
+	cors, err := NewCORSMiddleware(testCORSConfig())
+	if err != nil {
+		t.Fatalf("NewCORSMiddleware: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewWebSocketHandler(tokenTable{users: map[string]uuid.UUID{f.token: f.userID}}, nil, f.subscriber, cors, logger)
+
+	router := gin.New()
+	router.Use(Errors())
+	router.GET("/ws", handler.Connect)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	f.url = "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	return f
+}
+
+// dial opens a connection with the given query and headers, returning the
+// handshake status when it is refused.
+func (f *wsFixture) dial(t *testing.T, query string, header http.Header) (*websocket.Conn, int) {
+	t.Helper()
+
+	conn, resp, err := websocket.DefaultDialer.Dial(f.url+query, header)
+	if err != nil {
+		if !errors.Is(err, websocket.ErrBadHandshake) {
+			t.Fatalf("Dial: %v", err)
+		}
+
+		return nil, resp.StatusCode
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, resp.StatusCode
+}
+
+// connect opens an authenticated connection and returns it with the
+// subscription to the user's own topic.
+func (f *wsFixture) connect(t *testing.T) (*websocket.Conn, *channelSubscription) {
+	t.Helper()
+
+	conn, status := f.dial(t, "?access_token="+f.token, nil)
+	if conn == nil {
+		t.Fatalf("handshake refused with %d", status)
+	}
+
+	sub := <-f.subscriber.subs
+	if want := domain.UserTopic(f.userID); sub.topic != want {
+		t.Fatalf("subscribed to %q on connect, want %q", sub.topic, want)
+	}
+
+	return conn, sub
+}
+
+func readServerMessage(t *testing.T, conn *websocket.Conn) *wsServerMessage {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	var msg wsServerMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+
+	return &msg
+}
+
+func TestWebSocketChecksOrigin(t *testing.T) {
+	f := newWSFixture(t)
+
+	tests := []struct {
+		name   string
+		origin string
+		want   int
+	}{
+		{"no origin", "", http.StatusSwitchingProtocols},
+		{"allowed origin", "https://app.example.com", http.StatusSwitchingProtocols},
+		{"allowed wildcard origin", "https://pr-1.preview.example.com", http.StatusSwitchingProtocols},
+		{"other origin", "https://evil.example.com", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+
+			if _, status := f.dial(t, "?access_token="+f.token, header); status != tt.want {
+				t.Errorf("handshake = %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebSocketRequiresAValidToken(t *testing.T) {
+	f := newWSFixture(t)
+
+	tests := []struct {
+		name   string
+		query  string
+		header http.Header
+		want   int
+	}{
+		{"no token", "", nil, http.StatusUnauthorized},
+		{"unknown token", "?access_token=forged", nil, http.StatusUnauthorized},
+		{"unknown bearer token", "", http.Header{"Authorization": {"Bearer forged"}}, http.StatusUnauthorized},
+		{"query token", "?access_token=" + f.token, nil, http.StatusSwitchingProtocols},
+		{"bearer token", "", http.Header{"Authorization": {"Bearer " + f.token}}, http.StatusSwitchingProtocols},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, status := f.dial(t, tt.query, tt.header); status != tt.want {
+				t.Errorf("handshake = %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebSocketForbidsOtherUsersTopics(t *testing.T) {
+	f := newWSFixture(t)
+	conn, own := f.connect(t)
+
+	other := domain.UserTopic(uuid.New())
+	if err := conn.WriteJSON(&wsClientMessage{Type: wsMessageSubscribe, Topic: other}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if msg := readServerMessage(t, conn); msg.Type != wsMessageError || msg.Topic != other || msg.Error != "forbidden topic" {
+		t.Fatalf("subscribing to another user's topic = %+v, want a forbidden topic error", msg)
+	}
+
+	// Thread topics are public.
+	thread := domain.ThreadTopic(uuid.New())
+	if err := conn.WriteJSON(&wsClientMessage{Type: wsMessageSubscribe, Topic: thread}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if msg := readServerMessage(t, conn); msg.Type != wsMessageSubscribed || msg.Topic != thread {
+		t.Fatalf("subscribing to a thread = %+v, want subscribed", msg)
+	}
+
+	if sub := <-f.subscriber.subs; sub.topic != thread {
+		t.Errorf("subscribed to %q, want only %q", sub.topic, thread)
+	}
+
+	// Events on the user's own topic are delivered.
+	event := &domain.Event{ID: uuid.New(), Type: domain.EventNotification, Topic: own.topic, OccurredAt: time.Now()}
+	own.events <- event
+
+	if msg := readServerMessage(t, conn); msg.Type != wsMessageEvent || msg.Topic != own.topic || msg.Event == nil || msg.Event.ID != event.ID {
+		t.Fatalf("event message = %+v, want the notification event", msg)
+	}
+
+	// Disconnecting releases every subscription.
+	conn.Close()
+
+	select {
+	case <-own.closed:
+	case <-time.After(time.Second):
+		t.Error("subscription not closed after the client disconnected")
+	}
+}
+
+func TestWebSocketDisconnectsSlowConsumers(t *testing.T) {
+	f := newWSFixture(t)
+	conn, own := f.connect(t)
+
+	// Events larger than the socket buffers back up the writer once the
+	// client stops reading, until the send queue overflows.
+	data, _ := json.Marshal(strings.Repeat("x", 32<<10))
+	go func() {
+		for {
+			event := &domain.Event{ID: uuid.New(), Type: domain.EventNotification, Topic: own.topic, Data: data, OccurredAt: time.Now()}
+
+			select {
+			case own.events <- event:
+			case <-own.closed:
+				close(own.events)
+
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-own.closed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("slow consumer was not disconnected")
+	}
+
+	// Draining what was queued ends with the policy violation close frame.
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation || closeErr.Text != "slow consumer" {
+				t.Errorf("connection ended with %v, want a slow consumer policy violation", err)
+			}
+
+			return
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/srgjo27/agora/internal/app"
+	"github.com/srgjo27/agora/internal/config"
+	"github.com/srgjo27/agora/internal/contracttest"
+)
+
+// contract checks every response the tests receive against the OpenAPI
+// document.
+var contract = sync.OnceValues(contracttest.New)
+
+func checkContract(t *testing.T, method, path string, res *http.Response, body []byte) {
+	t.Helper()
+
+	v, err := contract()
+	if err != nil {
+		t.Fatalf("load OpenAPI document: %v", err)
+	}
+
+	if err := v.ValidateResponse(method, path, res.StatusCode, res.Header, body); err != nil {
+		t.Errorf("response does not match api/openapi/openapi.json: %v", err)
+	}
+}
+
+// newServer serves the API over the test database, emptied first.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	db := requireDB(t)
+
+	cfg := &config.Config{
+		Env: config.ProfileDev,
+		App: config.AppConfig{
+			BaseURL:    "http://localhost:3000",
+			APIBaseURL: "http://localhost:8080",
+		},
+		Auth: config.AuthConfig{
+			JWTSecretKey:               "integration-test-secret",
+			AccessTokenDurationMinutes: 15,
+			RefreshTokenDurationHours:  24,
+		},
+		CORS: config.CORSConfig{
+			Default: config.CORSPolicy{
+				AllowedOrigins:   []string{"http://localhost:3000"},
+				AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+				AllowedHeaders:   []string{"Content-Type", "Authorization"},
+				AllowCredentials: true,
+			},
+		},
+		Threads: config.ThreadsConfig{
+			RetentionDays:        30,
+			PurgeIntervalMinutes: 60,
+		},
+		Events: config.EventsConfig{Broker: "local"},
+		Storage: config.StorageConfig{
+			Driver:              "local",
+			LocalDir:            t.TempDir(),
+			AttachmentMaxSizeMB: 1,
+			AttachmentQuotaMB:   10,
+		},
+		Mail: config.MailConfig{
+			Driver:  "file",
+			From:    "Agora <no-reply@agora.local>",
+			FileDir: t.TempDir(),
+		},
+		Webhooks: config.WebhooksConfig{TimeoutSeconds: 1},
+		Outbox:   config.OutboxConfig{PollIntervalSeconds: 1, RetentionDays: 7},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	api, err := app.New(ctx, cfg, db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("app.New: %v", err)
+	}
+
+	srv := httptest.NewServer(api.Router)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// client is one API user: it keeps the refresh cookie and the access token
+// it was last given.
+type client struct {
+	t      *testing.T
+	base   string
+	http   *http.Client
+	token  string
+	userID string
+}
+
+func newClient(t *testing.T, srv *httptest.Server) *client {
+	t.Helper()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("cookiejar: %v", err)
+	}
+
+	return &client{t: t, base: srv.URL + "/api/v1", http: &http.Client{Jar: jar}}
+}
+
+// do sends body as JSON and returns the status and the raw response body.
+func (c *client) do(method, path string, body interface{}) (int, []byte) {
+	c.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatalf("marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.base+path, reader)
+	if err != nil {
+		c.t.Fatalf("new request: %v", err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		c.t.Fatalf("read response: %v", err)
+	}
+
+	checkContract(c.t, method, req.URL.Path, res, data)
+
+	return res.StatusCode, data
+}
+
+// expect sends a request, fails the test unless the response has the given
+// status, and decodes the response into out when it is not nil.
+func (c *client) expect(method, path string, body interface{}, status int, out interface{}) {
+	c.t.Helper()
+
+	got, data := c.do(method, path, body)
+	if got != status {
+		c.t.Fatalf("%s %s: got status %d, want %d: %s", method, path, got, status, data)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			c.t.Fatalf("%s %s: decode response: %v: %s", method, path, err, data)
+		}
+	}
+}
+
+func (c *client) register(username string) {
+	c.t.Helper()
+
+	var user struct {
+		ID string `json:"id"`
+	}
+	c.expect(http.MethodPost, "/auth/register", map[string]string{
+		"username": username,
+		"email":    username + "@example.com",
+		"password": "password123",
+	}, http.StatusCreated, &user)
+
+	c.userID = user.ID
+}
+
+func (c *client) login(username string) {
+	c.t.Helper()
+
+	var res struct {
+		AccessToken string `json:"access_token"`
+	}
+	c.expect(http.MethodPost, "/auth/login", map[string]string{
+		"email":    username + "@example.com",
+		"password": "password123",
+	}, http.StatusOK, &res)
+
+	if res.AccessToken == "" {
+		c.t.Fatalf("login returned no access token")
+	}
+
+	c.token = res.AccessToken
+}
+
+type idResponse struct {
+	ID string `json:"id"`
+}
+
+type threadResponse struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	VoteCount int    `json:"vote_count"`
+	Author    *struct {
+		ID string `json:"id"`
+	} `json:"author"`
+}
+
+func TestAuthFlow(t *testing.T) {
+	srv := newServer(t)
+	alice := newClient(t, srv)
+
+	alice.register("alice")
+
+	if status, body := alice.do(http.MethodPost, "/auth/register", map[string]string{
+		"username": "alice2",
+		"email":    "alice@example.com",
+		"password": "password123",
+	}); status != http.StatusConflict {
+		t.Errorf("register with a taken email: got %d, want %d: %s", status, http.StatusConflict, body)
+	}
+
+	if status, body := alice.do(http.MethodPost, "/auth/login", map[string]string{
+		"email":    "alice@example.com",
+		"password": "wrong-password",
+	}); status != http.StatusUnauthorized {
+		t.Errorf("login with a wrong password: got %d, want %d: %s", status, http.StatusUnauthorized, body)
+	}
+
+	if status, _ := alice.do(http.MethodGet, "/users/me", nil); status != http.StatusUnauthorized {
+		t.Errorf("profile without a token: got %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	alice.login("alice")
+
+	var me struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Role     string `json:"role"`
+	}
+	alice.expect(http.MethodGet, "/users/me", nil, http.StatusOK, &me)
+
+	if me.ID != alice.userID || me.Username != "alice" || me.Role != "member" {
+		t.Errorf("profile: got %+v, want member alice %s", me, alice.userID)
+	}
+
+	// The refresh token travels only as the cookie set by login.
+	alice.token = ""
+
+	var refreshed struct {
+		AccessToken string `json:"access_token"`
+	}
+	alice.expect(http.MethodPost, "/auth/refresh", nil, http.StatusOK, &refreshed)
+
+	alice.token = refreshed.AccessToken
+	alice.expect(http.MethodGet, "/users/me", nil, http.StatusOK, nil)
+
+	stranger := newClient(t, srv)
+	if status, _ := stranger.do(http.MethodPost, "/auth/refresh", nil); status != http.StatusUnauthorized {
+		t.Errorf("refresh without the cookie: got %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	alice.expect(http.MethodPost, "/auth/logout", nil, http.StatusOK, nil)
+
+	if status, _ := alice.do(http.MethodPost, "/auth/refresh", nil); status != http.StatusUnauthorized {
+		t.Errorf("refresh after logout: got %d, want %d", status, http.StatusUnauthorized)
+	}
+}
+
+func TestThreadLifecycle(t *testing.T) {
+	srv := newServer(t)
+
+	alice := newClient(t, srv)
+	alice.register("alice")
+
+	// Categories are created by admins, and there is no API to make the
+	// first one.
+	if _, err := testDB.Exec(`UPDATE users SET role = 'admin' WHERE id = $1`, alice.userID); err != nil {
+		t.Fatalf("promote alice: %v", err)
+	}
+
+	alice.login("alice")
+
+	bob := newClient(t, srv)
+	bob.register("bob")
+	bob.login("bob")
+
+	var category idResponse
+	alice.expect(http.MethodPost, "/admin/categories", map[string]string{"name": "General"}, http.StatusOK, &category)
+
+	if status, _ := bob.do(http.MethodPost, "/admin/categories", map[string]string{"name": "Off topic"}); status != http.StatusForbidden {
+		t.Errorf("member creating a category: got %d, want %d", status, http.StatusForbidden)
+	}
+
+	var thread threadResponse
+	bob.expect(http.MethodPost, "/threads", map[string]interface{}{
+		"title":       "Integration testing",
+		"content":     "How do we test the whole stack?",
+		"category_id": category.ID,
+		"tags":        []string{"testing"},
+	}, http.StatusCreated, &thread)
+
+	if thread.Author == nil || thread.Author.ID != bob.userID {
+		t.Errorf("created thread author: got %+v, want %s", thread.Author, bob.userID)
+	}
+
+	var got threadResponse
+	alice.expect(http.MethodGet, "/threads/"+thread.ID, nil, http.StatusOK, &got)
+
+	if got.Title != "Integration testing" {
+		t.Errorf("thread title: got %q", got.Title)
+	}
+
+	var list struct {
+		Data []threadResponse `json:"data"`
+	}
+	alice.expect(http.MethodGet, "/threads?tags=testing", nil, http.StatusOK, &list)
+
+	if len(list.Data) != 1 || list.Data[0].ID != thread.ID {
+		t.Errorf("threads tagged testing: got %+v, want only %s", list.Data, thread.ID)
+	}
+
+	var post idResponse
+	alice.expect(http.MethodPost, "/threads/"+thread.ID+"/posts", map[string]string{"content": "With @bob and a real database."}, http.StatusOK, &post)
+
+	bob.expect(http.MethodPost, "/threads/"+thread.ID+"/posts", map[string]interface{}{
+		"content":        "Agreed.",
+		"parent_post_id": post.ID,
+	}, http.StatusOK, nil)
+
+	var posts struct {
+		Data []idResponse `json:"data"`
+		Meta struct {
+			TotalItems int `json:"total_items"`
+		} `json:"meta"`
+	}
+	bob.expect(http.MethodGet, "/threads/"+thread.ID+"/posts", nil, http.StatusOK, &posts)
+
+	if posts.Meta.TotalItems != 2 || len(posts.Data) != 2 || posts.Data[0].ID != post.ID {
+		t.Errorf("posts: got %+v, want alice's post first of 2", posts)
+	}
+
+	alice.expect(http.MethodPost, "/threads/"+thread.ID+"/vote", map[string]int{"vote_type": 1}, http.StatusOK, nil)
+	bob.expect(http.MethodPost, "/posts/"+post.ID+"/vote", map[string]int{"vote_type": -1}, http.StatusOK, nil)
+
+	alice.expect(http.MethodGet, "/threads/"+thread.ID, nil, http.StatusOK, &got)
+
+	if got.VoteCount != 1 {
+		t.Errorf("thread vote count: got %d, want 1", got.VoteCount)
+	}
+
+	var mentions struct {
+		Data []idResponse `json:"data"`
+	}
+	bob.expect(http.MethodGet, "/users/me/mentions", nil, http.StatusOK, &mentions)
+
+	if len(mentions.Data) != 1 || mentions.Data[0].ID != thread.ID {
+		t.Errorf("bob's mentions: got %+v, want the thread", mentions.Data)
+	}
+
+	stranger := newClient(t, srv)
+	stranger.register("carol")
+	stranger.login("carol")
+
+	if status, _ := stranger.do(http.MethodDelete, "/threads/"+thread.ID, nil); status != http.StatusForbidden {
+		t.Errorf("deleting someone else's thread: got %d, want %d", status, http.StatusForbidden)
+	}
+
+	bob.expect(http.MethodDelete, "/threads/"+thread.ID, nil, http.StatusOK, nil)
+
+	if status, _ := bob.do(http.MethodGet, "/threads/"+thread.ID, nil); status != http.StatusNotFound {
+		t.Errorf("reading a deleted thread: got %d, want %d", status, http.StatusNotFound)
+	}
+
+	if status, _ := bob.do(http.MethodPost, "/threads/"+thread.ID+"/posts", map[string]string{"content": "Too late"}); status != http.StatusBadRequest {
+		t.Errorf("posting to a deleted thread: got %d, want %d", status, http.StatusBadRequest)
+	}
+
+	var trash struct {
+		Data []idResponse `json:"data"`
+	}
+	alice.expect(http.MethodGet, "/admin/threads/trash", nil, http.StatusOK, &trash)
+
+	if len(trash.Data) != 1 || trash.Data[0].ID != thread.ID {
+		t.Errorf("trash: got %+v, want the deleted thread", trash.Data)
+	}
+}
+
+// TestMalformedRequests sends requests that handlers must reject without
+// reaching the database.
+func TestMalformedRequests(t *testing.T) {
+	srv := newServer(t)
+	alice := newClient(t, srv)
+	alice.register("alice")
+	alice.login("alice")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		status int
+		code   string
+		field  string
+	}{
+		{"register without fields", http.MethodPost, "/auth/register", map[string]string{}, http.StatusBadRequest, "invalid", "username"},
+		{"login without fields", http.MethodPost, "/auth/login", map[string]string{}, http.StatusBadRequest, "invalid", "email"},
+		{"thread with invalid ID", http.MethodGet, "/threads/not-a-uuid", nil, http.StatusBadRequest, "invalid", "thread_id"},
+		{"thread without a category", http.MethodPost, "/threads", map[string]string{"title": "Title", "content": "Long enough content"}, http.StatusBadRequest, "invalid", "category_id"},
+		{"post to an unknown thread", http.MethodPost, "/threads/00000000-0000-0000-0000-000000000000/posts", map[string]string{"content": "Hello"}, http.StatusBadRequest, "invalid", "thread_id"},
+		{"vote out of range", http.MethodPost, "/threads/00000000-0000-0000-0000-000000000000/vote", map[string]int{"vote_type": 2}, http.StatusBadRequest, "invalid", "vote_type"},
+		{"delete an unknown thread", http.MethodDelete, "/threads/00000000-0000-0000-0000-000000000000", nil, http.StatusNotFound, "not_found", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alice.t = t
+
+			status, body := alice.do(tt.method, tt.path, tt.body)
+			if status != tt.status {
+				t.Fatalf("got status %d, want %d: %s", status, tt.status, body)
+			}
+
+			var problem struct {
+				Status int    `json:"status"`
+				Code   string `json:"code"`
+				Errors []struct {
+					Field string `json:"field"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(body, &problem); err != nil {
+				t.Fatalf("decode problem: %v: %s", err, body)
+			}
+
+			if problem.Status != tt.status || problem.Code != tt.code {
+				t.Errorf("got problem status %d code %q, want %d %q", problem.Status, problem.Code, tt.status, tt.code)
+			}
+
+			if tt.field != "" && (len(problem.Errors) == 0 || problem.Errors[0].Field != tt.field) {
+				t.Errorf("got field errors %+v, want the first for %q", problem.Errors, tt.field)
+			}
+		})
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	srv := newServer(t)
+
+	resp, err := http.Get(srv.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("GET /readyz: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	checkContract(t, http.MethodGet, "/readyz", resp, body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+
+	var ready struct {
+		Checks map[string]string `json:"checks"`
+	}
+	if err := json.Unmarshal(body, &ready); err != nil {
+		t.Fatalf("decode readiness: %v: %s", err, body)
+	}
+
+	for _, name := range []string{"database", "schema"} {
+		if ready.Checks[name] != "ok" {
+			t.Errorf("check %s = %q, want ok", name, ready.Checks[name])
+		}
+	}
+}
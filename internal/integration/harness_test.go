@@ -0,0 +1,366 @@
+// Package integration drives the API end to end: the real router from
+// app.New, backed by a throwaway Postgres with the schema from migrations.
+//
+// The database is, in order of preference:
+//   - a fresh schema in the database named by AGORA_TEST_DATABASE_URL,
+//     dropped when the tests finish;
+//   - a temporary cluster started with the initdb and postgres binaries from
+//     AGORA_TEST_PG_BIN, PATH or /usr/lib/postgresql/*/bin.
+//
+// The tests are skipped when neither is available.
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	testDatabaseURLEnv = "AGORA_TEST_DATABASE_URL"
+	testPGBinEnv       = "AGORA_TEST_PG_BIN"
+
+	migrationsDir = "../../migrations"
+)
+
+// errPostgresUnavailable means there is no database to test against, which
+// skips the tests rather than failing them.
+var errPostgresUnavailable = errors.New("postgres is not available")
+
+var (
+	testDB     *sqlx.DB
+	skipReason string
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	db, cleanup, err := openDatabase()
+	if errors.Is(err, errPostgresUnavailable) {
+		skipReason = err.Error()
+
+		return m.Run()
+	}
+
+	if err != nil {
+		log.Printf("[ERROR]: Gagal menyiapkan database integrasi: %v", err)
+
+		return 1
+	}
+	defer cleanup()
+
+	if err := applyMigrations(db); err != nil {
+		log.Printf("[ERROR]: Gagal menjalankan migrasi: %v", err)
+
+		return 1
+	}
+
+	testDB = db
+
+	return m.Run()
+}
+
+// requireDB returns the test database, emptied of data left by earlier tests.
+func requireDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	if testDB == nil {
+		t.Skip(skipReason)
+	}
+
+	var tables []string
+	if err := testDB.Select(&tables, `SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'`); err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+
+	if len(tables) > 0 {
+		if _, err := testDB.Exec(`TRUNCATE ` + strings.Join(tables, ", ") + ` CASCADE`); err != nil {
+			t.Fatalf("reset database: %v", err)
+		}
+	}
+
+	return testDB
+}
+
+func openDatabase() (*sqlx.DB, func(), error) {
+	if dsn := os.Getenv(testDatabaseURLEnv); dsn != "" {
+		return openSchema(dsn)
+	}
+
+	return startCluster()
+}
+
+// openSchema creates a schema of its own in an existing database, so the
+// tests leave the rest of it alone.
+func openSchema(dsn string) (*sqlx.DB, func(), error) {
+	admin, err := sqlx.Connect("pgx", dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema := "agora_it_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		admin.Close()
+
+		return nil, nil, err
+	}
+
+	dropSchema := func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			log.Printf("[ERROR]: Gagal menghapus schema %s: %v", schema, err)
+		}
+
+		admin.Close()
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		dropSchema()
+
+		return nil, nil, err
+	}
+
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	db, err := sqlx.Connect("pgx", u.String())
+	if err != nil {
+		dropSchema()
+
+		return nil, nil, err
+	}
+
+	return db, func() {
+		db.Close()
+		dropSchema()
+	}, nil
+}
+
+// startCluster runs a temporary Postgres cluster listening on a free local
+// port. It is removed, data and all, by the returned cleanup.
+func startCluster() (*sqlx.DB, func(), error) {
+	initdb, postgres, err := findPostgresBinaries()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if os.Geteuid() == 0 {
+		return nil, nil, fmt.Errorf("%w: initdb refuses to run as root", errPostgresUnavailable)
+	}
+
+	dir, err := os.MkdirTemp("", "agora-pg-")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataDir := filepath.Join(dir, "data")
+	out, err := exec.Command(initdb, "-D", dataDir, "-U", "agora", "-A", "trust", "-E", "UTF8", "--no-locale", "--no-sync").CombinedOutput()
+	if err != nil {
+		os.RemoveAll(dir)
+
+		return nil, nil, fmt.Errorf("initdb: %v: %s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+
+		return nil, nil, err
+	}
+
+	logFile, err := os.Create(filepath.Join(dir, "postgres.log"))
+	if err != nil {
+		os.RemoveAll(dir)
+
+		return nil, nil, err
+	}
+
+	cmd := exec.Command(postgres,
+		"-D", dataDir,
+		"-p", strconv.Itoa(port),
+		"-k", dir,
+		"-c", "listen_addresses=127.0.0.1",
+		"-c", "fsync=off",
+		"-c", "synchronous_commit=off",
+		"-c", "full_page_writes=off",
+	)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		os.RemoveAll(dir)
+
+		return nil, nil, err
+	}
+
+	stop := func() {
+		cmd.Process.Signal(os.Interrupt)
+
+		done := make(chan struct{})
+		go func() {
+			cmd.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			cmd.Process.Kill()
+			<-done
+		}
+
+		logFile.Close()
+		os.RemoveAll(dir)
+	}
+
+	base := fmt.Sprintf("postgres://agora@127.0.0.1:%d", port)
+
+	admin, err := waitForPostgres(base+"/postgres?sslmode=disable", 30*time.Second)
+	if err != nil {
+		logs, _ := os.ReadFile(logFile.Name())
+		stop()
+
+		return nil, nil, fmt.Errorf("%v\n%s", err, logs)
+	}
+
+	_, err = admin.Exec(`CREATE DATABASE agora_test`)
+	admin.Close()
+	if err != nil {
+		stop()
+
+		return nil, nil, err
+	}
+
+	db, err := sqlx.Connect("pgx", base+"/agora_test?sslmode=disable")
+	if err != nil {
+		stop()
+
+		return nil, nil, err
+	}
+
+	return db, func() {
+		db.Close()
+		stop()
+	}, nil
+}
+
+func findPostgresBinaries() (string, string, error) {
+	var dirs []string
+	if dir := os.Getenv(testPGBinEnv); dir != "" {
+		dirs = append(dirs, dir)
+	}
+
+	if path, err := exec.LookPath("initdb"); err == nil {
+		dirs = append(dirs, filepath.Dir(path))
+	}
+
+	installed, _ := filepath.Glob("/usr/lib/postgresql/*/bin")
+	sort.Sort(sort.Reverse(sort.StringSlice(installed)))
+	dirs = append(dirs, installed...)
+
+	for _, dir := range dirs {
+		initdb := filepath.Join(dir, "initdb")
+		postgres := filepath.Join(dir, "postgres")
+		if isExecutable(initdb) && isExecutable(postgres) {
+			return initdb, postgres, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: set %s or %s, or install initdb and postgres", errPostgresUnavailable, testDatabaseURLEnv, testPGBinEnv)
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && !info.IsDir() && info.Mode()&0o111 != 0
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func waitForPostgres(dsn string, timeout time.Duration) (*sqlx.DB, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		db, err := sqlx.ConnectContext(ctx, "pgx", dsn)
+		if err == nil {
+			return db, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("postgres did not start: %w", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// applyMigrations runs every up migration in order and records the newest
+// version in schema_migrations, as the migrate CLI would on an empty database.
+func applyMigrations(db *sqlx.DB) error {
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.up.sql"))
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("no migrations found in %s", migrationsDir)
+	}
+
+	sort.Strings(files)
+
+	for _, file := range files {
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.Exec(string(sql)); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+	}
+
+	prefix, _, _ := strings.Cut(filepath.Base(files[len(files)-1]), "_")
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(files[len(files)-1]), err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, version)
+
+	return err
+}
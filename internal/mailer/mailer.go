@@ -0,0 +1,17 @@
+package mailer
+
+import (
+	"github.com/srgjo27/agora/internal/config"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// New returns the Mailer selected by MAIL_DRIVER. It is shared by the API,
+// which needs one to build the digest usecase, and the worker that sends.
+func New(cfg *config.Config) (usecase.Mailer, error) {
+	switch cfg.Mail.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	default:
+		return NewFileMailer(cfg.Mail.FileDir, cfg.Mail.From)
+	}
+}
@@ -0,0 +1,90 @@
+// Package metrics holds the Prometheus collectors the API exposes at
+// /metrics. HTTP metrics are recorded by a Gin middleware and domain counters
+// by repository decorators, so business code never touches a collector.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "agora"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	// HTTPRequests counts served requests by method, route template and
+	// status code.
+	HTTPRequests *prometheus.CounterVec
+	// HTTPDuration observes request latency by method and route template.
+	HTTPDuration *prometheus.HistogramVec
+
+	ThreadsCreated prometheus.Counter
+	PostsCreated   prometheus.Counter
+	// VotesCast counts votes cast or changed, by target (thread or post) and
+	// direction (up or down). Retracted votes are not counted.
+	VotesCast *prometheus.CounterVec
+}
+
+// New returns metrics registered on a registry of their own, along with the
+// Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests served, by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency, by method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		ThreadsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "threads_created_total",
+			Help:      "Threads inserted.",
+		}),
+		PostsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "posts_created_total",
+			Help:      "Posts inserted.",
+		}),
+		VotesCast: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "votes_cast_total",
+			Help:      "Votes cast or changed, by target and direction.",
+		}, []string{"target", "direction"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequests,
+		m.HTTPDuration,
+		m.ThreadsCreated,
+		m.PostsCreated,
+		m.VotesCast,
+	)
+
+	return m
+}
+
+// RegisterDB exports the connection pool statistics of db (sql.DB.Stats) as
+// go_sql_* metrics labelled with name.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
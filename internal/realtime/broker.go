@@ -0,0 +1,46 @@
+package realtime
+
+import (
+	"context"
+
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+// Broker carries events between API instances. Every event published on any
+// instance must be delivered to the handler of every subscribed instance,
+// including the publisher itself.
+type Broker interface {
+	Publish(ctx context.Context, event *domain.Event) error
+	// Subscribe blocks, invoking handler for each received event, until ctx is
+	// cancelled or the broker fails irrecoverably.
+	Subscribe(ctx context.Context, handler func(*domain.Event)) error
+}
+
+// LocalBroker is an in-process Broker for single-instance deployments.
+type LocalBroker struct {
+	events chan *domain.Event
+}
+
+func NewLocalBroker(buffer int) *LocalBroker {
+	return &LocalBroker{events: make(chan *domain.Event, buffer)}
+}
+
+func (b *LocalBroker) Publish(ctx context.Context, event *domain.Event) error {
+	select {
+	case b.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *LocalBroker) Subscribe(ctx context.Context, handler func(*domain.Event)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-b.events:
+			handler(event)
+		}
+	}
+}
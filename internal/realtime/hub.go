@@ -0,0 +1,105 @@
+package realtime
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+const defaultSubscriptionBuffer = 32
+
+// Hub is an in-process pub/sub that fans broker events out to local
+// subscribers by topic. Publishing goes through the broker so that every
+// instance, including this one, receives the event exactly once.
+type Hub struct {
+	broker Broker
+	logger *slog.Logger
+
+	mu          sync.RWMutex
+	subscribers map[string]map[*subscription]struct{}
+}
+
+func NewHub(broker Broker, logger *slog.Logger) *Hub {
+	return &Hub{
+		broker:      broker,
+		logger:      logger,
+		subscribers: make(map[string]map[*subscription]struct{}),
+	}
+}
+
+func (h *Hub) Publish(ctx context.Context, event *domain.Event) error {
+	return h.broker.Publish(ctx, event)
+}
+
+func (h *Hub) Subscribe(topic string) usecase.EventSubscription {
+	sub := &subscription{
+		hub:    h,
+		topic:  topic,
+		events: make(chan *domain.Event, defaultSubscriptionBuffer),
+	}
+
+	h.mu.Lock()
+	if h.subscribers[topic] == nil {
+		h.subscribers[topic] = make(map[*subscription]struct{})
+	}
+	h.subscribers[topic][sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+// Run consumes the broker until ctx is cancelled.
+func (h *Hub) Run(ctx context.Context) error {
+	return h.broker.Subscribe(ctx, h.dispatch)
+}
+
+func (h *Hub) dispatch(event *domain.Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers[event.Topic] {
+		select {
+		case sub.events <- event:
+		default:
+			// A slow subscriber must not stall delivery to everyone else.
+			h.logger.Warn("dropping event for slow subscriber", "event_type", event.Type, "topic", event.Topic)
+		}
+	}
+}
+
+func (h *Hub) unsubscribe(sub *subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := h.subscribers[sub.topic]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.topic)
+	}
+
+	close(sub.events)
+}
+
+type subscription struct {
+	hub    *Hub
+	topic  string
+	events chan *domain.Event
+	once   sync.Once
+}
+
+func (s *subscription) Events() <-chan *domain.Event {
+	return s.events
+}
+
+func (s *subscription) Close() {
+	s.once.Do(func() {
+		s.hub.unsubscribe(s)
+	})
+}
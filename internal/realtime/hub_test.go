@@ -0,0 +1,191 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// runHub starts h over its broker and stops it when the test ends.
+func runHub(t *testing.T, h *Hub) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- h.Run(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Run = %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+func newEvent(topic string) *domain.Event {
+	return &domain.Event{ID: uuid.New(), Type: domain.EventPostCreated, Topic: topic, OccurredAt: time.Now()}
+}
+
+// receive waits for the next event on sub.
+func receive(t *testing.T, sub usecase.EventSubscription) *domain.Event {
+	t.Helper()
+
+	select {
+	case event, ok := <-sub.Events():
+		if !ok {
+			t.Fatal("subscription closed, want an event")
+		}
+
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+
+		return nil
+	}
+}
+
+// assertIdle fails if sub receives an event shortly.
+func assertIdle(t *testing.T, sub usecase.EventSubscription) {
+	t.Helper()
+
+	select {
+	case event := <-sub.Events():
+		t.Errorf("received %s on %s, want nothing", event.Type, event.Topic)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestHubFansOutByTopic(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(NewLocalBroker(8), discardLogger)
+	runHub(t, hub)
+
+	topic := domain.ThreadTopic(uuid.New())
+	first := hub.Subscribe(topic)
+	defer first.Close()
+	second := hub.Subscribe(topic)
+	defer second.Close()
+	other := hub.Subscribe(domain.ThreadTopic(uuid.New()))
+	defer other.Close()
+
+	event := newEvent(topic)
+	if err := hub.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	for _, sub := range []usecase.EventSubscription{first, second} {
+		if got := receive(t, sub); got.ID != event.ID {
+			t.Errorf("received %s, want %s", got.ID, event.ID)
+		}
+	}
+
+	assertIdle(t, other)
+}
+
+func TestHubCloseUnsubscribes(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(NewLocalBroker(8), discardLogger)
+	runHub(t, hub)
+
+	topic := domain.ThreadTopic(uuid.New())
+	leaving := hub.Subscribe(topic)
+	staying := hub.Subscribe(topic)
+	defer staying.Close()
+
+	leaving.Close()
+	leaving.Close()
+
+	if _, ok := <-leaving.Events(); ok {
+		t.Fatal("Events of a closed subscription is still open")
+	}
+
+	if err := hub.Publish(ctx, newEvent(topic)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	receive(t, staying)
+
+	staying.Close()
+
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+
+	if n := len(hub.subscribers); n != 0 {
+		t.Errorf("hub keeps %d topics after every subscriber left, want 0", n)
+	}
+}
+
+func TestHubDropsEventsForSlowSubscribers(t *testing.T) {
+	hub := NewHub(NewLocalBroker(0), discardLogger)
+
+	topic := domain.ThreadTopic(uuid.New())
+	slow := hub.Subscribe(topic)
+	defer slow.Close()
+	fast := hub.Subscribe(topic)
+	defer fast.Close()
+
+	// Nobody reads slow, so its buffer fills and later events are dropped
+	// instead of blocking delivery to fast.
+	for i := 0; i < defaultSubscriptionBuffer+5; i++ {
+		hub.dispatch(newEvent(topic))
+		receive(t, fast)
+	}
+
+	if n := len(slow.Events()); n != defaultSubscriptionBuffer {
+		t.Errorf("slow subscriber holds %d events, want a full buffer of %d", n, defaultSubscriptionBuffer)
+	}
+}
+
+func TestLocalBrokerDeliversToItsSubscriber(t *testing.T) {
+	broker := NewLocalBroker(1)
+
+	// Publishing before anyone subscribes is buffered.
+	event := newEvent(domain.UserTopic(uuid.New()))
+	if err := broker.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan *domain.Event, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- broker.Subscribe(ctx, func(e *domain.Event) { received <- e })
+	}()
+
+	select {
+	case got := <-received:
+		if got.ID != event.ID {
+			t.Errorf("received %s, want %s", got.ID, event.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Subscribe = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestLocalBrokerPublishGivesUpWhenFull(t *testing.T) {
+	broker := NewLocalBroker(1)
+
+	if err := broker.Publish(context.Background(), newEvent("t")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := broker.Publish(ctx, newEvent("t")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Publish to a full broker = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
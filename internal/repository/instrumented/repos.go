@@ -0,0 +1,90 @@
+// Package instrumented decorates repositories with Prometheus counters. Each
+// decorator embeds the repository it wraps and overrides only the methods it
+// counts.
+//
+// Counters are incremented when the insert succeeds, so a row whose
+// transaction is later rolled back is still counted.
+package instrumented
+
+import (
+	"context"
+
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/metrics"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type instrumentedThreadRepo struct {
+	usecase.ThreadRepository
+	metrics *metrics.Metrics
+}
+
+func NewInstrumentedThreadRepo(next usecase.ThreadRepository, m *metrics.Metrics) usecase.ThreadRepository {
+	return &instrumentedThreadRepo{ThreadRepository: next, metrics: m}
+}
+
+func (r *instrumentedThreadRepo) Create(ctx context.Context, thread *domain.Thread) error {
+	if err := r.ThreadRepository.Create(ctx, thread); err != nil {
+		return err
+	}
+
+	r.metrics.ThreadsCreated.Inc()
+
+	return nil
+}
+
+type instrumentedPostRepo struct {
+	usecase.PostRepository
+	metrics *metrics.Metrics
+}
+
+func NewInstrumentedPostRepo(next usecase.PostRepository, m *metrics.Metrics) usecase.PostRepository {
+	return &instrumentedPostRepo{PostRepository: next, metrics: m}
+}
+
+func (r *instrumentedPostRepo) Create(ctx context.Context, post *domain.Post) error {
+	if err := r.PostRepository.Create(ctx, post); err != nil {
+		return err
+	}
+
+	r.metrics.PostsCreated.Inc()
+
+	return nil
+}
+
+type instrumentedVoteRepo struct {
+	usecase.VoteRepository
+	metrics *metrics.Metrics
+}
+
+func NewInstrumentedVoteRepo(next usecase.VoteRepository, m *metrics.Metrics) usecase.VoteRepository {
+	return &instrumentedVoteRepo{VoteRepository: next, metrics: m}
+}
+
+func (r *instrumentedVoteRepo) UpsertThreadVote(ctx context.Context, vote *domain.ThreadVote) error {
+	if err := r.VoteRepository.UpsertThreadVote(ctx, vote); err != nil {
+		return err
+	}
+
+	r.metrics.VotesCast.WithLabelValues("thread", voteDirection(vote.VoteType)).Inc()
+
+	return nil
+}
+
+func (r *instrumentedVoteRepo) UpsertPostVote(ctx context.Context, vote *domain.ThreadVote) error {
+	if err := r.VoteRepository.UpsertPostVote(ctx, vote); err != nil {
+		return err
+	}
+
+	r.metrics.VotesCast.WithLabelValues("post", voteDirection(vote.VoteType)).Inc()
+
+	return nil
+}
+
+func voteDirection(voteType int) string {
+	if voteType < 0 {
+		return "down"
+	}
+
+	return "up"
+}
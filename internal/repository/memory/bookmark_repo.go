@@ -0,0 +1,304 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryBookmarkRepo struct {
+	store *Store
+}
+
+func NewMemoryBookmarkRepo(s *Store) usecase.BookmarkRepository {
+	return &memoryBookmarkRepo{store: s}
+}
+
+// Upsert saves the bookmark, replacing the folder and note of an existing
+// bookmark on the same thread or post, and writes the stored row back into
+// bookmark as the postgres RETURNING does.
+func (r *memoryBookmarkRepo) Upsert(ctx context.Context, bookmark *domain.Bookmark) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing := r.find(bookmark.UserID, bookmark.ThreadID, bookmark.PostID)
+	if existing == nil {
+		existing = copyBookmark(bookmark)
+		r.store.bookmarks[existing.ID] = existing
+	} else {
+		existing.FolderID = copyUUID(bookmark.FolderID)
+		existing.Note = copyString(bookmark.Note)
+		existing.UpdatedAt = bookmark.UpdatedAt
+	}
+
+	*bookmark = *copyBookmark(existing)
+
+	return nil
+}
+
+func (r *memoryBookmarkRepo) Delete(ctx context.Context, userID, threadID uuid.UUID, postID *uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	b := r.find(userID, threadID, postID)
+	if b == nil {
+		return domain.ErrNotFound
+	}
+
+	delete(r.store.bookmarks, b.ID)
+
+	return nil
+}
+
+// find returns the user's bookmark of the thread, or of the post when
+// postID is set. The caller must hold the store lock.
+func (r *memoryBookmarkRepo) find(userID, threadID uuid.UUID, postID *uuid.UUID) *domain.Bookmark {
+	for _, b := range r.store.bookmarks {
+		if b.UserID != userID {
+			continue
+		}
+
+		if postID == nil && b.PostID == nil && b.ThreadID == threadID {
+			return b
+		}
+
+		if postID != nil && b.PostID != nil && *b.PostID == *postID {
+			return b
+		}
+	}
+
+	return nil
+}
+
+func (r *memoryBookmarkRepo) GetByUserID(ctx context.Context, userID uuid.UUID, filter usecase.BookmarkFilter, params usecase.PaginationParams) ([]*domain.Bookmark, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	bookmarks := r.matching(userID, filter)
+	sort.Slice(bookmarks, func(i, j int) bool {
+		if !bookmarks[i].CreatedAt.Equal(bookmarks[j].CreatedAt) {
+			return bookmarks[i].CreatedAt.After(bookmarks[j].CreatedAt)
+		}
+
+		return bookmarks[i].ID.String() > bookmarks[j].ID.String()
+	})
+
+	return paginate(bookmarks, params), nil
+}
+
+func (r *memoryBookmarkRepo) CountByUserID(ctx context.Context, userID uuid.UUID, filter usecase.BookmarkFilter) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.matching(userID, filter)), nil
+}
+
+// matching returns copies of the user's bookmarks that pass the filter,
+// leaving out those on trashed threads. The caller must hold the store lock.
+func (r *memoryBookmarkRepo) matching(userID uuid.UUID, filter usecase.BookmarkFilter) []*domain.Bookmark {
+	bookmarks := []*domain.Bookmark{}
+	for _, b := range r.store.bookmarks {
+		if b.UserID != userID || !r.store.isLiveThread(b.ThreadID) {
+			continue
+		}
+
+		if filter.Unfiled && b.FolderID != nil {
+			continue
+		}
+
+		if !filter.Unfiled && filter.FolderID != nil && (b.FolderID == nil || *b.FolderID != *filter.FolderID) {
+			continue
+		}
+
+		if filter.Type != "" && b.Type() != filter.Type {
+			continue
+		}
+
+		bookmarks = append(bookmarks, copyBookmark(b))
+	}
+
+	return bookmarks
+}
+
+func (r *memoryBookmarkRepo) GetBookmarkedThreadIDs(ctx context.Context, userID uuid.UUID, threadIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	bookmarked := make(map[uuid.UUID]bool)
+	for _, threadID := range threadIDs {
+		if r.find(userID, threadID, nil) != nil {
+			bookmarked[threadID] = true
+		}
+	}
+
+	return bookmarked, nil
+}
+
+func (r *memoryBookmarkRepo) GetBookmarkedPostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	bookmarked := make(map[uuid.UUID]bool)
+	for _, postID := range postIDs {
+		if r.find(userID, uuid.Nil, &postID) != nil {
+			bookmarked[postID] = true
+		}
+	}
+
+	return bookmarked, nil
+}
+
+// CreateFolder enforces the unique index on (user_id, LOWER(name)).
+func (r *memoryBookmarkRepo) CreateFolder(ctx context.Context, folder *domain.BookmarkFolder) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.bookmarkFolders[folder.ID]; ok || r.hasFolderNamed(folder.UserID, folder.Name, uuid.Nil) {
+		return domain.ErrConflict
+	}
+
+	c := *folder
+	c.BookmarkCount = 0
+	r.store.bookmarkFolders[folder.ID] = &c
+
+	return nil
+}
+
+func (r *memoryBookmarkRepo) GetFolderByID(ctx context.Context, id uuid.UUID) (*domain.BookmarkFolder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	folder, ok := r.store.bookmarkFolders[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	return r.withCount(folder), nil
+}
+
+func (r *memoryBookmarkRepo) GetFoldersByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.BookmarkFolder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	folders := []*domain.BookmarkFolder{}
+	for _, folder := range r.store.bookmarkFolders {
+		if folder.UserID == userID {
+			folders = append(folders, r.withCount(folder))
+		}
+	}
+
+	sort.Slice(folders, func(i, j int) bool {
+		return strings.ToLower(folders[i].Name) < strings.ToLower(folders[j].Name)
+	})
+
+	return folders, nil
+}
+
+func (r *memoryBookmarkRepo) CountFoldersByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, folder := range r.store.bookmarkFolders {
+		if folder.UserID == userID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *memoryBookmarkRepo) RenameFolder(ctx context.Context, id uuid.UUID, name string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	folder, ok := r.store.bookmarkFolders[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	if r.hasFolderNamed(folder.UserID, name, id) {
+		return domain.ErrConflict
+	}
+
+	folder.Name = name
+
+	return nil
+}
+
+// DeleteFolder leaves its bookmarks unfiled, as ON DELETE SET NULL does.
+func (r *memoryBookmarkRepo) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.bookmarkFolders[id]; !ok {
+		return domain.ErrNotFound
+	}
+
+	delete(r.store.bookmarkFolders, id)
+
+	for _, b := range r.store.bookmarks {
+		if b.FolderID != nil && *b.FolderID == id {
+			b.FolderID = nil
+		}
+	}
+
+	return nil
+}
+
+func (r *memoryBookmarkRepo) hasFolderNamed(userID uuid.UUID, name string, except uuid.UUID) bool {
+	for _, folder := range r.store.bookmarkFolders {
+		if folder.UserID == userID && folder.ID != except && strings.EqualFold(folder.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withCount copies the folder with the number of its bookmarks on live
+// threads. The caller must hold the store lock.
+func (r *memoryBookmarkRepo) withCount(folder *domain.BookmarkFolder) *domain.BookmarkFolder {
+	c := *folder
+	c.BookmarkCount = 0
+	for _, b := range r.store.bookmarks {
+		if b.FolderID != nil && *b.FolderID == folder.ID && r.store.isLiveThread(b.ThreadID) {
+			c.BookmarkCount++
+		}
+	}
+
+	return &c
+}
+
+// isLiveThread reports whether the thread exists and is not in the trash.
+// The caller must hold the store lock.
+func (s *Store) isLiveThread(id uuid.UUID) bool {
+	thread, ok := s.threads[id]
+
+	return ok && thread.DeletedAt == nil
+}
+
+func copyBookmark(b *domain.Bookmark) *domain.Bookmark {
+	c := *b
+	c.PostID = copyUUID(b.PostID)
+	c.FolderID = copyUUID(b.FolderID)
+	c.Note = copyString(b.Note)
+	c.Thread = nil
+	c.Post = nil
+
+	return &c
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	c := *s
+
+	return &c
+}
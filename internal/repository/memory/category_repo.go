@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryCategoryRepo struct {
+	store *Store
+}
+
+func NewMemoryCategoryRepo(s *Store) usecase.CategoryRepository {
+	return &memoryCategoryRepo{store: s}
+}
+
+func (r *memoryCategoryRepo) Create(ctx context.Context, category *domain.Category) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.categories[category.ID]; ok {
+		return domain.ErrConflict
+	}
+
+	for _, existing := range r.store.categories {
+		if existing.Name == category.Name || existing.Slug == category.Slug {
+			return domain.ErrConflict
+		}
+	}
+
+	r.store.categories[category.ID] = copyCategory(category)
+
+	return nil
+}
+
+func (r *memoryCategoryRepo) GetBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, category := range r.store.categories {
+		if category.Slug == slug {
+			return copyCategory(category), nil
+		}
+	}
+
+	return nil, domain.ErrNotFound
+}
+
+func (r *memoryCategoryRepo) GetAll(ctx context.Context) ([]*domain.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var categories []*domain.Category
+	for _, category := range r.store.categories {
+		categories = append(categories, copyCategory(category))
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].CreatedAt.Before(categories[j].CreatedAt)
+	})
+
+	return categories, nil
+}
+
+func (r *memoryCategoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	category, ok := r.store.categories[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	return copyCategory(category), nil
+}
+
+func (r *memoryCategoryRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	catMap := make(map[uuid.UUID]*domain.Category)
+	for _, id := range ids {
+		if category, ok := r.store.categories[id]; ok {
+			catMap[id] = copyCategory(category)
+		}
+	}
+
+	return catMap, nil
+}
+
+func (r *memoryCategoryRepo) Follow(ctx context.Context, userID, categoryID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	followed, ok := r.store.follows[userID]
+	if !ok {
+		followed = make(map[uuid.UUID]struct{})
+		r.store.follows[userID] = followed
+	}
+
+	followed[categoryID] = struct{}{}
+
+	return nil
+}
+
+func (r *memoryCategoryRepo) Unfollow(ctx context.Context, userID, categoryID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.follows[userID], categoryID)
+
+	return nil
+}
+
+func (r *memoryCategoryRepo) GetFollowedByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	categories := []*domain.Category{}
+	for categoryID := range r.store.follows[userID] {
+		if category, ok := r.store.categories[categoryID]; ok {
+			categories = append(categories, copyCategory(category))
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+
+	return categories, nil
+}
+
+func copyCategory(category *domain.Category) *domain.Category {
+	c := *category
+
+	return &c
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryDigestRepo struct {
+	store *Store
+}
+
+func NewMemoryDigestRepo(s *Store) usecase.DigestRepository {
+	return &memoryDigestRepo{store: s}
+}
+
+func (r *memoryDigestRepo) EnsurePreferences(ctx context.Context, userID uuid.UUID, token string) (*domain.DigestPreferences, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	prefs, ok := r.store.digestPreferences[userID]
+	if !ok {
+		if _, ok := r.store.users[userID]; !ok {
+			return nil, domain.ErrNotFound
+		}
+
+		for _, p := range r.store.digestPreferences {
+			if p.UnsubscribeToken == token {
+				return nil, domain.ErrConflict
+			}
+		}
+
+		prefs = &domain.DigestPreferences{
+			UserID:           userID,
+			Frequency:        domain.DefaultDigestFrequency,
+			UnsubscribeToken: token,
+			UpdatedAt:        time.Now(),
+		}
+		r.store.digestPreferences[userID] = prefs
+	}
+
+	return copyDigestPreferences(prefs), nil
+}
+
+func (r *memoryDigestRepo) UpdateFrequency(ctx context.Context, userID uuid.UUID, frequency string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	prefs, ok := r.store.digestPreferences[userID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	prefs.Frequency = frequency
+	prefs.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (r *memoryDigestRepo) UnsubscribeByToken(ctx context.Context, token string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, prefs := range r.store.digestPreferences {
+		if prefs.UnsubscribeToken == token {
+			prefs.Frequency = domain.DigestOff
+			prefs.UpdatedAt = time.Now()
+
+			return nil
+		}
+	}
+
+	return domain.ErrNotFound
+}
+
+// GetDueRecipients treats users without preferences as getting the default
+// digest, never sent, as the postgres query does.
+func (r *memoryDigestRepo) GetDueRecipients(ctx context.Context, cutoffs map[string]time.Time, after uuid.UUID, limit int) ([]*domain.DigestRecipient, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recipients := []*domain.DigestRecipient{}
+	for _, user := range r.store.users {
+		if bytes.Compare(user.ID[:], after[:]) <= 0 {
+			continue
+		}
+
+		recipient := &domain.DigestRecipient{
+			UserID:    user.ID,
+			Username:  user.Username,
+			Email:     user.Email,
+			Frequency: domain.DefaultDigestFrequency,
+		}
+		if prefs, ok := r.store.digestPreferences[user.ID]; ok {
+			recipient.Frequency = prefs.Frequency
+			recipient.LastSentAt = copyTime(prefs.LastSentAt)
+		}
+
+		cutoff, ok := cutoffs[recipient.Frequency]
+		if !ok || (recipient.LastSentAt != nil && recipient.LastSentAt.After(cutoff)) {
+			continue
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	sort.Slice(recipients, func(i, j int) bool {
+		return bytes.Compare(recipients[i].UserID[:], recipients[j].UserID[:]) < 0
+	})
+
+	if len(recipients) > limit {
+		recipients = recipients[:limit]
+	}
+
+	return recipients, nil
+}
+
+func (r *memoryDigestRepo) MarkSent(ctx context.Context, userID uuid.UUID, sentAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if prefs, ok := r.store.digestPreferences[userID]; ok {
+		prefs.LastSentAt = &sentAt
+	}
+
+	return nil
+}
+
+func copyDigestPreferences(prefs *domain.DigestPreferences) *domain.DigestPreferences {
+	c := *prefs
+	c.LastSentAt = copyTime(prefs.LastSentAt)
+
+	return &c
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/srgjo27/agora/internal/repository/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		s := NewStore()
+
+		return repotest.Repos{
+			Users:      NewMemoryUserRepo(s),
+			Categories: NewMemoryCategoryRepo(s),
+			Threads:    NewMemoryThreadRepo(s),
+			Posts:      NewMemoryPostRepo(s),
+			Votes:      NewMemoryVoteRepo(s),
+			Tx:         NewTxManager(s),
+
+			Notifications:   NewMemoryNotificationRepo(s),
+			Outbox:          NewMemoryOutboxRepo(s),
+			ProcessedEvents: NewMemoryProcessedEventRepo(s),
+			Webhooks:        NewMemoryWebhookRepo(s),
+			Tags:            NewMemoryTagRepo(s),
+			Subscriptions:   NewMemorySubscriptionRepo(s),
+			Bookmarks:       NewMemoryBookmarkRepo(s),
+			Digests:         NewMemoryDigestRepo(s),
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryNotificationRepo struct {
+	store *Store
+}
+
+func NewMemoryNotificationRepo(s *Store) usecase.NotificationRepository {
+	return &memoryNotificationRepo{store: s}
+}
+
+// Create skips a vote milestone that was already announced for the same
+// target, and a second notification to a user about the same event, as the
+// unique indexes on notifications do.
+func (r *memoryNotificationRepo) Create(ctx context.Context, n *domain.Notification) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.notifications[n.ID]; ok {
+		return false, nil
+	}
+
+	for _, existing := range r.store.notifications {
+		if n.Type == domain.NotificationVoteMilestone && sameMilestone(existing, n) {
+			return false, nil
+		}
+
+		if n.EventID != nil && existing.EventID != nil && *existing.EventID == *n.EventID && existing.UserID == n.UserID {
+			return false, nil
+		}
+	}
+
+	c := *n
+	r.store.notifications[n.ID] = &c
+
+	return true, nil
+}
+
+func (r *memoryNotificationRepo) GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, params usecase.PaginationParams) ([]*domain.Notification, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	notifications := r.byUser(userID, unreadOnly)
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+
+	return paginate(notifications, params), nil
+}
+
+func (r *memoryNotificationRepo) CountByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.byUser(userID, unreadOnly)), nil
+}
+
+func (r *memoryNotificationRepo) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n, ok := r.store.notifications[id]
+	if !ok || n.UserID != userID {
+		return domain.ErrNotFound
+	}
+
+	markRead(n, time.Now())
+
+	return nil
+}
+
+func (r *memoryNotificationRepo) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	marked := 0
+
+	for _, n := range r.store.notifications {
+		if n.UserID == userID && !n.IsRead {
+			markRead(n, now)
+			marked++
+		}
+	}
+
+	return marked, nil
+}
+
+func (r *memoryNotificationRepo) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	prefs, ok := r.store.notificationPreferences[userID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	p := *prefs
+
+	return &p, nil
+}
+
+func (r *memoryNotificationRepo) UpsertPreferences(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	p := *prefs
+	r.store.notificationPreferences[prefs.UserID] = &p
+
+	return nil
+}
+
+// byUser returns copies of the user's notifications. The caller must hold the
+// store lock.
+func (r *memoryNotificationRepo) byUser(userID uuid.UUID, unreadOnly bool) []*domain.Notification {
+	notifications := make([]*domain.Notification, 0)
+	for _, n := range r.store.notifications {
+		if n.UserID != userID || (unreadOnly && n.IsRead) {
+			continue
+		}
+
+		c := *n
+		notifications = append(notifications, &c)
+	}
+
+	return notifications
+}
+
+func markRead(n *domain.Notification, at time.Time) {
+	n.IsRead = true
+	if n.ReadAt == nil {
+		n.ReadAt = &at
+	}
+}
+
+func sameMilestone(a, b *domain.Notification) bool {
+	return a.Type == domain.NotificationVoteMilestone &&
+		a.UserID == b.UserID &&
+		equalID(a.ThreadID, b.ThreadID) &&
+		equalID(a.PostID, b.PostID) &&
+		a.Milestone != nil && b.Milestone != nil && *a.Milestone == *b.Milestone
+}
+
+func equalID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
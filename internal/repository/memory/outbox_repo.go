@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type processedKey struct {
+	consumer string
+	eventID  uuid.UUID
+}
+
+type memoryOutboxRepo struct {
+	store *Store
+}
+
+func NewMemoryOutboxRepo(s *Store) usecase.OutboxRepository {
+	return &memoryOutboxRepo{store: s}
+}
+
+// Add assigns Seq in the order events are added, as the postgres sequence
+// does.
+func (r *memoryOutboxRepo) Add(ctx context.Context, events ...*domain.OutboxEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, e := range events {
+		c := *e
+		c.Seq = int64(len(r.store.outbox) + 1)
+		r.store.outbox = append(r.store.outbox, &c)
+	}
+
+	return nil
+}
+
+func (r *memoryOutboxRepo) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxEvent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	events := make([]*domain.OutboxEvent, 0)
+	for _, e := range r.store.outbox {
+		if len(events) == limit {
+			break
+		}
+
+		if e.PublishedAt != nil || e.NextAttemptAt.After(now) {
+			continue
+		}
+
+		e.NextAttemptAt = now.Add(lease)
+		c := *e
+		events = append(events, &c)
+	}
+
+	return events, nil
+}
+
+func (r *memoryOutboxRepo) MarkPublished(ctx context.Context, id uuid.UUID, publishedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if e := r.find(id); e != nil {
+		e.PublishedAt = &publishedAt
+		e.LastError = nil
+	}
+
+	return nil
+}
+
+func (r *memoryOutboxRepo) MarkFailed(ctx context.Context, event *domain.OutboxEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if e := r.find(event.ID); e != nil {
+		e.Attempts = event.Attempts
+		e.NextAttemptAt = event.NextAttemptAt
+		e.LastError = event.LastError
+	}
+
+	return nil
+}
+
+func (r *memoryOutboxRepo) PurgePublishedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	kept := r.store.outbox[:0]
+	for _, e := range r.store.outbox {
+		if e.PublishedAt == nil || !e.PublishedAt.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+
+	purged := len(r.store.outbox) - len(kept)
+	r.store.outbox = kept
+
+	return purged, nil
+}
+
+// find returns the stored event with the given ID. The caller must hold the
+// store lock.
+func (r *memoryOutboxRepo) find(id uuid.UUID) *domain.OutboxEvent {
+	for _, e := range r.store.outbox {
+		if e.ID == id {
+			return e
+		}
+	}
+
+	return nil
+}
+
+type memoryProcessedEventRepo struct {
+	store *Store
+}
+
+func NewMemoryProcessedEventRepo(s *Store) usecase.ProcessedEventRepository {
+	return &memoryProcessedEventRepo{store: s}
+}
+
+func (r *memoryProcessedEventRepo) IsProcessed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	_, ok := r.store.processedEvents[processedKey{consumer: consumer, eventID: eventID}]
+
+	return ok, nil
+}
+
+func (r *memoryProcessedEventRepo) MarkProcessed(ctx context.Context, consumer string, eventID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := processedKey{consumer: consumer, eventID: eventID}
+	if _, ok := r.store.processedEvents[key]; !ok {
+		r.store.processedEvents[key] = time.Now()
+	}
+
+	return nil
+}
+
+func (r *memoryProcessedEventRepo) PurgeBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	purged := 0
+	for key, processedAt := range r.store.processedEvents {
+		if processedAt.Before(cutoff) {
+			delete(r.store.processedEvents, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryPostRepo struct {
+	store *Store
+}
+
+func NewMemoryPostRepo(s *Store) usecase.PostRepository {
+	return &memoryPostRepo{store: s}
+}
+
+func (r *memoryPostRepo) CountByThreadID(ctx context.Context, threadID uuid.UUID) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, post := range r.store.posts {
+		if post.ThreadID == threadID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Create stores the columns the postgres implementation inserts; the vote
+// count and update time start out at their column defaults.
+func (r *memoryPostRepo) Create(ctx context.Context, post *domain.Post) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.posts[post.ID]; ok {
+		return domain.ErrConflict
+	}
+
+	r.store.posts[post.ID] = &domain.Post{
+		ID:           post.ID,
+		Content:      post.Content,
+		ContentHTML:  post.ContentHTML,
+		UserID:       post.UserID,
+		ThreadID:     post.ThreadID,
+		ParentPostID: copyUUID(post.ParentPostID),
+		CreatedAt:    post.CreatedAt,
+	}
+
+	return nil
+}
+
+func (r *memoryPostRepo) GetByThreadID(ctx context.Context, threadID uuid.UUID, params usecase.PaginationParams) ([]*domain.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var posts []*domain.Post
+	for _, post := range r.store.posts {
+		if post.ThreadID == threadID {
+			posts = append(posts, copyPost(post))
+		}
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].CreatedAt.Before(posts[j].CreatedAt)
+	})
+
+	return paginate(posts, params), nil
+}
+
+func (r *memoryPostRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	post, ok := r.store.posts[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	return copyPost(post), nil
+}
+
+func (r *memoryPostRepo) UpdateVoteCount(ctx context.Context, postID uuid.UUID, delta int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	post, ok := r.store.posts[postID]
+	if !ok {
+		return 0, domain.ErrNotFound
+	}
+
+	post.VoteCount += delta
+
+	return post.VoteCount, nil
+}
+
+func (r *memoryPostRepo) Update(ctx context.Context, post *domain.Post) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.posts[post.ID]
+	if !ok {
+		return nil
+	}
+
+	stored.Content = post.Content
+	stored.ContentHTML = post.ContentHTML
+	stored.UpdatedAt = copyTime(post.UpdatedAt)
+
+	return nil
+}
+
+func (r *memoryPostRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Post, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	postMap := make(map[uuid.UUID]*domain.Post)
+	for _, id := range ids {
+		if post, ok := r.store.posts[id]; ok {
+			postMap[id] = copyPost(post)
+		}
+	}
+
+	return postMap, nil
+}
+
+func copyPost(post *domain.Post) *domain.Post {
+	p := *post
+	p.ParentPostID = copyUUID(post.ParentPostID)
+	p.UpdatedAt = copyTime(post.UpdatedAt)
+	p.Attachments = nil
+
+	return &p
+}
+
+func copyUUID(id *uuid.UUID) *uuid.UUID {
+	if id == nil {
+		return nil
+	}
+
+	c := *id
+
+	return &c
+}
@@ -0,0 +1,266 @@
+// Package memory implements repositories on top of an in-process store. They
+// follow the postgres implementations' ordering and error semantics and are
+// meant for tests that exercise usecases without a database.
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type voteKey struct {
+	userID   uuid.UUID
+	targetID uuid.UUID
+}
+
+// threadTag is a row of thread_tags: a thread may carry the same tag from
+// more than one source.
+type threadTag struct {
+	tagID  uuid.UUID
+	source string
+}
+
+// Store holds the data shared by the repositories built on it. Repositories
+// that share a Store see each other's writes, like tables in one database.
+type Store struct {
+	mu sync.RWMutex
+	tables
+}
+
+// tables is the data of a Store, kept apart from its lock so a transaction
+// can copy it and put the copy back when it fails.
+type tables struct {
+	users      map[uuid.UUID]*domain.User
+	categories map[uuid.UUID]*domain.Category
+	follows    map[uuid.UUID]map[uuid.UUID]struct{}
+	threads    map[uuid.UUID]*domain.Thread
+	posts      map[uuid.UUID]*domain.Post
+
+	threadVotes map[voteKey]*domain.ThreadVote
+	postVotes   map[voteKey]*domain.ThreadVote
+
+	tags       map[uuid.UUID]*domain.Tag
+	threadTags map[uuid.UUID]map[threadTag]struct{}
+	postTags   map[uuid.UUID]map[uuid.UUID]struct{}
+	mentions   map[uuid.UUID]map[uuid.UUID]time.Time
+
+	subscriptions map[uuid.UUID]map[uuid.UUID]time.Time
+	readPositions map[readKey]*domain.ThreadReadPosition
+
+	bookmarks       map[uuid.UUID]*domain.Bookmark
+	bookmarkFolders map[uuid.UUID]*domain.BookmarkFolder
+
+	digestPreferences map[uuid.UUID]*domain.DigestPreferences
+
+	notifications           map[uuid.UUID]*domain.Notification
+	notificationPreferences map[uuid.UUID]*domain.NotificationPreferences
+
+	outbox          []*domain.OutboxEvent
+	processedEvents map[processedKey]time.Time
+
+	webhooks          map[uuid.UUID]*domain.Webhook
+	webhookDeliveries map[uuid.UUID]*domain.WebhookDelivery
+}
+
+func NewStore() *Store {
+	return &Store{tables: tables{
+		users:       make(map[uuid.UUID]*domain.User),
+		categories:  make(map[uuid.UUID]*domain.Category),
+		follows:     make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		threads:     make(map[uuid.UUID]*domain.Thread),
+		posts:       make(map[uuid.UUID]*domain.Post),
+		threadVotes: make(map[voteKey]*domain.ThreadVote),
+		postVotes:   make(map[voteKey]*domain.ThreadVote),
+		tags:        make(map[uuid.UUID]*domain.Tag),
+		threadTags:  make(map[uuid.UUID]map[threadTag]struct{}),
+		postTags:    make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		mentions:    make(map[uuid.UUID]map[uuid.UUID]time.Time),
+
+		subscriptions: make(map[uuid.UUID]map[uuid.UUID]time.Time),
+		readPositions: make(map[readKey]*domain.ThreadReadPosition),
+
+		bookmarks:       make(map[uuid.UUID]*domain.Bookmark),
+		bookmarkFolders: make(map[uuid.UUID]*domain.BookmarkFolder),
+
+		digestPreferences: make(map[uuid.UUID]*domain.DigestPreferences),
+
+		notifications:           make(map[uuid.UUID]*domain.Notification),
+		notificationPreferences: make(map[uuid.UUID]*domain.NotificationPreferences),
+
+		processedEvents: make(map[processedKey]time.Time),
+
+		webhooks:          make(map[uuid.UUID]*domain.Webhook),
+		webhookDeliveries: make(map[uuid.UUID]*domain.WebhookDelivery),
+	}}
+}
+
+// clone copies the tables deeply enough that no write through a repository
+// can reach the copy.
+func (t *tables) clone() tables {
+	outbox := make([]*domain.OutboxEvent, len(t.outbox))
+	for i, e := range t.outbox {
+		c := *e
+		outbox[i] = &c
+	}
+
+	return tables{
+		users:       cloneRows(t.users),
+		categories:  cloneRows(t.categories),
+		follows:     cloneNested(t.follows, func(v struct{}) struct{} { return v }),
+		threads:     cloneRows(t.threads),
+		posts:       cloneRows(t.posts),
+		threadVotes: cloneRows(t.threadVotes),
+		postVotes:   cloneRows(t.postVotes),
+		tags:        cloneRows(t.tags),
+		threadTags:  cloneNested(t.threadTags, func(v struct{}) struct{} { return v }),
+		postTags:    cloneNested(t.postTags, func(v struct{}) struct{} { return v }),
+		mentions:    cloneNested(t.mentions, func(v time.Time) time.Time { return v }),
+
+		subscriptions: cloneNested(t.subscriptions, func(v time.Time) time.Time { return v }),
+		readPositions: cloneRows(t.readPositions),
+
+		bookmarks:       cloneRows(t.bookmarks),
+		bookmarkFolders: cloneRows(t.bookmarkFolders),
+
+		digestPreferences: cloneRows(t.digestPreferences),
+
+		notifications:           cloneRows(t.notifications),
+		notificationPreferences: cloneRows(t.notificationPreferences),
+
+		outbox:          outbox,
+		processedEvents: maps.Clone(t.processedEvents),
+
+		webhooks:          cloneRows(t.webhooks),
+		webhookDeliveries: cloneRows(t.webhookDeliveries),
+	}
+}
+
+func cloneRow[T any](row *T) *T {
+	c := *row
+
+	return &c
+}
+
+func cloneRows[K comparable, T any](rows map[K]*T) map[K]*T {
+	c := make(map[K]*T, len(rows))
+	for k, row := range rows {
+		c[k] = cloneRow(row)
+	}
+
+	return c
+}
+
+func cloneNested[K, J comparable, V any](index map[K]map[J]V, cloneValue func(V) V) map[K]map[J]V {
+	c := make(map[K]map[J]V, len(index))
+	for k, inner := range index {
+		ci := make(map[J]V, len(inner))
+		for j, v := range inner {
+			ci[j] = cloneValue(v)
+		}
+		c[k] = ci
+	}
+
+	return c
+}
+
+// AddMention records that the user was mentioned in the thread at the given
+// time. A later mention in the same thread replaces an earlier one.
+func (s *Store) AddMention(userID, threadID uuid.UUID, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byThread, ok := s.mentions[userID]
+	if !ok {
+		byThread = make(map[uuid.UUID]time.Time)
+		s.mentions[userID] = byThread
+	}
+
+	if last, ok := byThread[threadID]; !ok || at.After(last) {
+		byThread[threadID] = at
+	}
+}
+
+// OutboxEvents returns a copy of every recorded outbox event, published or not, in the
+// order they were added.
+func (s *Store) OutboxEvents() []*domain.OutboxEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := make([]*domain.OutboxEvent, len(s.outbox))
+	for i, e := range s.outbox {
+		c := *e
+		events[i] = &c
+	}
+
+	return events
+}
+
+type txKey struct{}
+
+type memoryTxManager struct {
+	store *Store
+	mu    sync.Mutex
+}
+
+// NewTxManager returns a TxManager over s that runs one transaction at a
+// time, so reads inside fn see no other transaction's writes, as if every
+// row fn touched were locked. A nested call joins the outer transaction.
+// When fn fails or panics, s is put back as it was before fn ran.
+//
+// Only writes made inside a transaction are isolated: a repository call
+// made outside one while a transaction runs is kept even if that
+// transaction rolls back, which a database would not allow to happen.
+func NewTxManager(s *Store) usecase.TxManager {
+	return &memoryTxManager{store: s}
+}
+
+func (m *memoryTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.store.mu.RLock()
+	saved := m.store.tables.clone()
+	m.store.mu.RUnlock()
+
+	committed := false
+	defer func() {
+		if !committed {
+			m.store.mu.Lock()
+			m.store.tables = saved
+			m.store.mu.Unlock()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, m)); err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
+
+// paginate returns the page of items selected by params, as LIMIT and OFFSET
+// would.
+func paginate[T any](items []T, params usecase.PaginationParams) []T {
+	if params.Offset >= len(items) {
+		return nil
+	}
+
+	items = items[params.Offset:]
+	if params.Limit < len(items) {
+		items = items[:params.Limit]
+	}
+
+	return items
+}
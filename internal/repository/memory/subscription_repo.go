@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type readKey struct {
+	userID   uuid.UUID
+	threadID uuid.UUID
+}
+
+type memorySubscriptionRepo struct {
+	store *Store
+}
+
+func NewMemorySubscriptionRepo(s *Store) usecase.SubscriptionRepository {
+	return &memorySubscriptionRepo{store: s}
+}
+
+func (r *memorySubscriptionRepo) Subscribe(ctx context.Context, userID, threadID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	byThread, ok := r.store.subscriptions[userID]
+	if !ok {
+		byThread = make(map[uuid.UUID]time.Time)
+		r.store.subscriptions[userID] = byThread
+	}
+
+	if _, ok := byThread[threadID]; !ok {
+		byThread[threadID] = time.Now()
+	}
+
+	return nil
+}
+
+func (r *memorySubscriptionRepo) Unsubscribe(ctx context.Context, userID, threadID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.subscriptions[userID], threadID)
+
+	return nil
+}
+
+func (r *memorySubscriptionRepo) GetByUserID(ctx context.Context, userID uuid.UUID, params usecase.PaginationParams) ([]*domain.ThreadReadState, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return paginate(r.subscribed(userID), params), nil
+}
+
+func (r *memorySubscriptionRepo) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.subscribed(userID)), nil
+}
+
+// SaveReadPosition only ever moves a position forward, so a client that
+// reports an older post does not mark newer ones unread again.
+func (r *memorySubscriptionRepo) SaveReadPosition(ctx context.Context, pos *domain.ThreadReadPosition) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := readKey{userID: pos.UserID, threadID: pos.ThreadID}
+	if existing, ok := r.store.readPositions[key]; ok && !existing.LastReadAt.Before(pos.LastReadAt) {
+		return nil
+	}
+
+	c := *pos
+	c.LastReadPostID = copyUUID(pos.LastReadPostID)
+	r.store.readPositions[key] = &c
+
+	return nil
+}
+
+func (r *memorySubscriptionRepo) GetReadState(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadReadState, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	thread, ok := r.store.threads[threadID]
+	if !ok || thread.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+
+	return r.readState(userID, thread), nil
+}
+
+func (r *memorySubscriptionRepo) GetUnreadByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*domain.ThreadReadState, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	states := []*domain.ThreadReadState{}
+	for _, state := range r.subscribed(userID) {
+		if state.UnreadCount > 0 {
+			states = append(states, state)
+		}
+	}
+
+	if len(states) > limit {
+		states = states[:limit]
+	}
+
+	return states, nil
+}
+
+// subscribed returns the read state of every live thread the user is
+// subscribed to, most recently active first. The caller must hold the
+// store lock.
+func (r *memorySubscriptionRepo) subscribed(userID uuid.UUID) []*domain.ThreadReadState {
+	states := []*domain.ThreadReadState{}
+	for threadID := range r.store.subscriptions[userID] {
+		thread, ok := r.store.threads[threadID]
+		if !ok || thread.DeletedAt != nil {
+			continue
+		}
+
+		states = append(states, r.readState(userID, thread))
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].LastActivityAt.After(states[j].LastActivityAt)
+	})
+
+	return states
+}
+
+// readState counts the posts after the user's read position, leaving out
+// the user's own posts, as the postgres query does.
+func (r *memorySubscriptionRepo) readState(userID uuid.UUID, thread *domain.Thread) *domain.ThreadReadState {
+	state := &domain.ThreadReadState{ThreadID: thread.ID, LastActivityAt: thread.CreatedAt}
+
+	if subscribedAt, ok := r.store.subscriptions[userID][thread.ID]; ok {
+		state.SubscribedAt = &subscribedAt
+	}
+
+	pos, read := r.store.readPositions[readKey{userID: userID, threadID: thread.ID}]
+	if read {
+		lastReadAt := pos.LastReadAt
+		state.LastReadAt = &lastReadAt
+		state.LastReadPostID = copyUUID(pos.LastReadPostID)
+	}
+
+	var firstUnread *domain.Post
+	for _, post := range r.store.posts {
+		if post.ThreadID != thread.ID {
+			continue
+		}
+
+		if post.CreatedAt.After(state.LastActivityAt) {
+			state.LastActivityAt = post.CreatedAt
+		}
+
+		if post.UserID == userID || (read && !post.CreatedAt.After(pos.LastReadAt)) {
+			continue
+		}
+
+		state.UnreadCount++
+		if firstUnread == nil || post.CreatedAt.Before(firstUnread.CreatedAt) ||
+			(post.CreatedAt.Equal(firstUnread.CreatedAt) && post.ID.String() < firstUnread.ID.String()) {
+			firstUnread = post
+		}
+	}
+
+	if firstUnread != nil {
+		state.FirstUnreadPostID = copyUUID(&firstUnread.ID)
+	}
+
+	return state
+}
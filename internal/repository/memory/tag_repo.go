@@ -0,0 +1,231 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryTagRepo struct {
+	store *Store
+}
+
+func NewMemoryTagRepo(s *Store) usecase.TagRepository {
+	return &memoryTagRepo{store: s}
+}
+
+func (r *memoryTagRepo) UpsertByNames(ctx context.Context, names []string) ([]*domain.Tag, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tags := []*domain.Tag{}
+	now := time.Now()
+	for _, name := range names {
+		tag := r.byName(name)
+		if tag == nil {
+			tag = &domain.Tag{ID: uuid.New(), Name: name, CreatedAt: now}
+			r.store.tags[tag.ID] = tag
+		}
+
+		tags = append(tags, copyTag(tag))
+	}
+
+	return tags, nil
+}
+
+func (r *memoryTagRepo) GetByName(ctx context.Context, name string) (*domain.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tag := r.byName(name)
+	if tag == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	return copyTag(tag), nil
+}
+
+func (r *memoryTagRepo) byName(name string) *domain.Tag {
+	for _, tag := range r.store.tags {
+		if tag.Name == name {
+			return tag
+		}
+	}
+
+	return nil
+}
+
+// GetByThreadIDs lists a thread's tags once each, whatever their source,
+// ordered by name.
+func (r *memoryTagRepo) GetByThreadIDs(ctx context.Context, threadIDs []uuid.UUID) (map[uuid.UUID][]*domain.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tagMap := make(map[uuid.UUID][]*domain.Tag)
+	for _, threadID := range threadIDs {
+		if _, ok := tagMap[threadID]; ok {
+			continue
+		}
+
+		seen := make(map[uuid.UUID]bool)
+		for key := range r.store.threadTags[threadID] {
+			tag, ok := r.store.tags[key.tagID]
+			if !ok || seen[key.tagID] {
+				continue
+			}
+
+			seen[key.tagID] = true
+			tagMap[threadID] = append(tagMap[threadID], copyTag(tag))
+		}
+
+		sort.Slice(tagMap[threadID], func(i, j int) bool {
+			return tagMap[threadID][i].Name < tagMap[threadID][j].Name
+		})
+	}
+
+	return tagMap, nil
+}
+
+// Search counts the live threads carrying each tag themselves, as the
+// postgres query does.
+func (r *memoryTagRepo) Search(ctx context.Context, prefix string, limit int) ([]*domain.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uuid.UUID]map[uuid.UUID]struct{})
+	for threadID, keys := range r.store.threadTags {
+		thread, ok := r.store.threads[threadID]
+		if !ok || thread.DeletedAt != nil {
+			continue
+		}
+
+		for key := range keys {
+			if counts[key.tagID] == nil {
+				counts[key.tagID] = make(map[uuid.UUID]struct{})
+			}
+			counts[key.tagID][threadID] = struct{}{}
+		}
+	}
+
+	tags := []*domain.Tag{}
+	for _, tag := range r.store.tags {
+		if !strings.HasPrefix(tag.Name, prefix) {
+			continue
+		}
+
+		t := copyTag(tag)
+		t.ThreadCount = len(counts[tag.ID])
+		tags = append(tags, t)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].ThreadCount != tags[j].ThreadCount {
+			return tags[i].ThreadCount > tags[j].ThreadCount
+		}
+
+		return tags[i].Name < tags[j].Name
+	})
+
+	if len(tags) > limit {
+		tags = tags[:limit]
+	}
+
+	return tags, nil
+}
+
+func (r *memoryTagRepo) ReplaceThreadTags(ctx context.Context, threadID uuid.UUID, source string, tagIDs []uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	keys := r.store.threadTags[threadID]
+	if keys == nil {
+		keys = make(map[threadTag]struct{})
+		r.store.threadTags[threadID] = keys
+	}
+
+	for key := range keys {
+		if key.source == source {
+			delete(keys, key)
+		}
+	}
+
+	for _, tagID := range tagIDs {
+		keys[threadTag{tagID: tagID, source: source}] = struct{}{}
+	}
+
+	return nil
+}
+
+func (r *memoryTagRepo) ReplacePostTags(ctx context.Context, postID uuid.UUID, tagIDs []uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ids := make(map[uuid.UUID]struct{}, len(tagIDs))
+	for _, tagID := range tagIDs {
+		ids[tagID] = struct{}{}
+	}
+
+	r.store.postTags[postID] = ids
+
+	return nil
+}
+
+func (r *memoryTagRepo) Rename(ctx context.Context, id uuid.UUID, name string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tag, ok := r.store.tags[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	if other := r.byName(name); other != nil && other.ID != id {
+		return domain.ErrConflict
+	}
+
+	tag.Name = name
+
+	return nil
+}
+
+// Merge moves every thread and post tagged with the source tag over to the
+// target tag, then deletes the source tag.
+func (r *memoryTagRepo) Merge(ctx context.Context, sourceID, targetID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tags[sourceID]; !ok {
+		return domain.ErrNotFound
+	}
+
+	for _, keys := range r.store.threadTags {
+		for key := range keys {
+			if key.tagID == sourceID {
+				delete(keys, key)
+				keys[threadTag{tagID: targetID, source: key.source}] = struct{}{}
+			}
+		}
+	}
+
+	for _, ids := range r.store.postTags {
+		if _, ok := ids[sourceID]; ok {
+			delete(ids, sourceID)
+			ids[targetID] = struct{}{}
+		}
+	}
+
+	delete(r.store.tags, sourceID)
+
+	return nil
+}
+
+func copyTag(tag *domain.Tag) *domain.Tag {
+	t := *tag
+
+	return &t
+}
@@ -0,0 +1,466 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryThreadRepo struct {
+	store *Store
+}
+
+func NewMemoryThreadRepo(s *Store) usecase.ThreadRepository {
+	return &memoryThreadRepo{store: s}
+}
+
+// Create stores the columns the postgres implementation inserts; pinned,
+// locked, vote count and the update and delete times start out at their
+// column defaults.
+func (r *memoryThreadRepo) Create(ctx context.Context, thread *domain.Thread) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.threads[thread.ID]; ok {
+		return domain.ErrConflict
+	}
+
+	r.store.threads[thread.ID] = &domain.Thread{
+		ID:          thread.ID,
+		Title:       thread.Title,
+		Slug:        thread.Slug,
+		Content:     thread.Content,
+		ContentHTML: thread.ContentHTML,
+		UserID:      thread.UserID,
+		CategoryID:  thread.CategoryID,
+		CreatedAt:   thread.CreatedAt,
+	}
+
+	return nil
+}
+
+func (r *memoryThreadRepo) GetAll(ctx context.Context, filter usecase.ThreadFilter, params usecase.PaginationParams) ([]*domain.Thread, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	threads := r.filter(func(t *domain.Thread) bool {
+		return t.DeletedAt == nil && r.matchesTags(t.ID, filter)
+	})
+
+	sort.Slice(threads, func(i, j int) bool {
+		if threads[i].IsPinned != threads[j].IsPinned {
+			return threads[i].IsPinned
+		}
+
+		return threads[i].CreatedAt.After(threads[j].CreatedAt)
+	})
+
+	return paginate(threads, params), nil
+}
+
+func (r *memoryThreadRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Thread, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	thread, ok := r.store.threads[id]
+	if !ok || thread.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+
+	return copyThread(thread), nil
+}
+
+func (r *memoryThreadRepo) UpdateVoteCount(ctx context.Context, threadID uuid.UUID, delta int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	thread, ok := r.store.threads[threadID]
+	if !ok {
+		return 0, domain.ErrNotFound
+	}
+
+	thread.VoteCount += delta
+
+	return thread.VoteCount, nil
+}
+
+func (r *memoryThreadRepo) CountAll(ctx context.Context, filter usecase.ThreadFilter) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.count(func(t *domain.Thread) bool {
+		return t.DeletedAt == nil && r.matchesTags(t.ID, filter)
+	}), nil
+}
+
+func (r *memoryThreadRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	thread, ok := r.store.threads[id]
+	if !ok || thread.DeletedAt != nil {
+		return domain.ErrNotFound
+	}
+
+	now := time.Now()
+	thread.DeletedAt = &now
+
+	return nil
+}
+
+func (r *memoryThreadRepo) Update(ctx context.Context, thread *domain.Thread) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.threads[thread.ID]
+	if !ok || stored.DeletedAt != nil {
+		return nil
+	}
+
+	stored.Title = thread.Title
+	stored.Slug = thread.Slug
+	stored.Content = thread.Content
+	stored.ContentHTML = thread.ContentHTML
+	stored.UpdatedAt = copyTime(thread.UpdatedAt)
+
+	return nil
+}
+
+func (r *memoryThreadRepo) GetDeleted(ctx context.Context, params usecase.PaginationParams) ([]*domain.Thread, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	threads := r.filter(func(t *domain.Thread) bool {
+		return t.DeletedAt != nil
+	})
+
+	sort.Slice(threads, func(i, j int) bool {
+		return threads[i].DeletedAt.After(*threads[j].DeletedAt)
+	})
+
+	return paginate(threads, params), nil
+}
+
+func (r *memoryThreadRepo) CountDeleted(ctx context.Context) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.count(func(t *domain.Thread) bool {
+		return t.DeletedAt != nil
+	}), nil
+}
+
+func (r *memoryThreadRepo) Restore(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	thread, ok := r.store.threads[id]
+	if !ok || thread.DeletedAt == nil {
+		return domain.ErrNotFound
+	}
+
+	thread.DeletedAt = nil
+
+	return nil
+}
+
+func (r *memoryThreadRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Thread, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	threadMap := make(map[uuid.UUID]*domain.Thread)
+	for _, id := range ids {
+		if thread, ok := r.store.threads[id]; ok && thread.DeletedAt == nil {
+			threadMap[id] = copyThread(thread)
+		}
+	}
+
+	return threadMap, nil
+}
+
+func (r *memoryThreadRepo) GetTop(ctx context.Context, categoryIDs []uuid.UUID, since time.Time, limit int) ([]*domain.Thread, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	categories := make(map[uuid.UUID]struct{}, len(categoryIDs))
+	for _, id := range categoryIDs {
+		categories[id] = struct{}{}
+	}
+
+	threads := r.filter(func(t *domain.Thread) bool {
+		if t.DeletedAt != nil || t.CreatedAt.Before(since) {
+			return false
+		}
+
+		_, ok := categories[t.CategoryID]
+
+		return len(categories) == 0 || ok
+	})
+
+	sort.Slice(threads, func(i, j int) bool {
+		if threads[i].VoteCount != threads[j].VoteCount {
+			return threads[i].VoteCount > threads[j].VoteCount
+		}
+
+		return threads[i].CreatedAt.After(threads[j].CreatedAt)
+	})
+
+	threads = paginate(threads, usecase.PaginationParams{Limit: limit})
+	if threads == nil {
+		threads = []*domain.Thread{}
+	}
+
+	return threads, nil
+}
+
+// PurgeDeletedBefore permanently removes threads soft-deleted before cutoff,
+// along with their posts and votes.
+func (r *memoryThreadRepo) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	purged := 0
+	for id, thread := range r.store.threads {
+		if thread.DeletedAt == nil || !thread.DeletedAt.Before(cutoff) {
+			continue
+		}
+
+		r.store.deleteThread(id)
+		purged++
+	}
+
+	return purged, nil
+}
+
+func (r *memoryThreadRepo) SetLocked(ctx context.Context, id uuid.UUID, locked bool) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	thread, ok := r.store.threads[id]
+	if !ok || thread.DeletedAt != nil {
+		return domain.ErrNotFound
+	}
+
+	thread.IsLocked = locked
+
+	return nil
+}
+
+// GetByTagID returns threads tagged directly or through one of their posts.
+func (r *memoryThreadRepo) GetByTagID(ctx context.Context, tagID uuid.UUID, params usecase.PaginationParams) ([]*domain.Thread, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tagged := r.store.threadsTagged(tagID)
+	threads := r.filter(func(t *domain.Thread) bool {
+		_, ok := tagged[t.ID]
+
+		return t.DeletedAt == nil && ok
+	})
+
+	sort.Slice(threads, func(i, j int) bool {
+		return threads[i].CreatedAt.After(threads[j].CreatedAt)
+	})
+
+	return paginate(threads, params), nil
+}
+
+func (r *memoryThreadRepo) CountByTagID(ctx context.Context, tagID uuid.UUID) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tagged := r.store.threadsTagged(tagID)
+
+	return r.count(func(t *domain.Thread) bool {
+		_, ok := tagged[t.ID]
+
+		return t.DeletedAt == nil && ok
+	}), nil
+}
+
+// GetMentioning returns threads in which the user is mentioned, most recent
+// mention first.
+func (r *memoryThreadRepo) GetMentioning(ctx context.Context, userID uuid.UUID, params usecase.PaginationParams) ([]*domain.Thread, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	mentioned := r.store.mentions[userID]
+	threads := r.filter(func(t *domain.Thread) bool {
+		_, ok := mentioned[t.ID]
+
+		return t.DeletedAt == nil && ok
+	})
+
+	sort.Slice(threads, func(i, j int) bool {
+		return mentioned[threads[i].ID].After(mentioned[threads[j].ID])
+	})
+
+	return paginate(threads, params), nil
+}
+
+func (r *memoryThreadRepo) CountMentioning(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	mentioned := r.store.mentions[userID]
+
+	return r.count(func(t *domain.Thread) bool {
+		_, ok := mentioned[t.ID]
+
+		return t.DeletedAt == nil && ok
+	}), nil
+}
+
+// filter returns copies of the threads that match. The caller must hold the
+// store lock.
+func (r *memoryThreadRepo) filter(match func(*domain.Thread) bool) []*domain.Thread {
+	var threads []*domain.Thread
+	for _, thread := range r.store.threads {
+		if match(thread) {
+			threads = append(threads, copyThread(thread))
+		}
+	}
+
+	return threads
+}
+
+func (r *memoryThreadRepo) count(match func(*domain.Thread) bool) int {
+	count := 0
+	for _, thread := range r.store.threads {
+		if match(thread) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// matchesTags reports whether the thread's own tags satisfy the filter: any
+// of the filter's tags, or all of them when MatchAll is set.
+func (r *memoryThreadRepo) matchesTags(threadID uuid.UUID, filter usecase.ThreadFilter) bool {
+	if len(filter.Tags) == 0 {
+		return true
+	}
+
+	names := make(map[string]struct{})
+	for key := range r.store.threadTags[threadID] {
+		if tag, ok := r.store.tags[key.tagID]; ok {
+			names[tag.Name] = struct{}{}
+		}
+	}
+
+	wanted := make(map[string]struct{})
+	for _, name := range filter.Tags {
+		wanted[name] = struct{}{}
+	}
+
+	found := 0
+	for name := range wanted {
+		if _, ok := names[name]; ok {
+			found++
+		}
+	}
+
+	if filter.MatchAll {
+		return found == len(wanted)
+	}
+
+	return found > 0
+}
+
+// threadsTagged returns the IDs of threads carrying the tag themselves or on
+// one of their posts. The caller must hold the store lock.
+func (s *Store) threadsTagged(tagID uuid.UUID) map[uuid.UUID]struct{} {
+	ids := make(map[uuid.UUID]struct{})
+	for threadID, keys := range s.threadTags {
+		for key := range keys {
+			if key.tagID == tagID {
+				ids[threadID] = struct{}{}
+			}
+		}
+	}
+
+	for postID, tags := range s.postTags {
+		if _, ok := tags[tagID]; !ok {
+			continue
+		}
+
+		if post, ok := s.posts[postID]; ok {
+			ids[post.ThreadID] = struct{}{}
+		}
+	}
+
+	return ids
+}
+
+// deleteThread removes a thread and everything that cascades from it. The
+// caller must hold the store lock.
+func (s *Store) deleteThread(id uuid.UUID) {
+	delete(s.threads, id)
+	delete(s.threadTags, id)
+
+	for key := range s.threadVotes {
+		if key.targetID == id {
+			delete(s.threadVotes, key)
+		}
+	}
+
+	for _, byThread := range s.mentions {
+		delete(byThread, id)
+	}
+
+	for _, byThread := range s.subscriptions {
+		delete(byThread, id)
+	}
+
+	for key := range s.readPositions {
+		if key.threadID == id {
+			delete(s.readPositions, key)
+		}
+	}
+
+	for bookmarkID, b := range s.bookmarks {
+		if b.ThreadID == id {
+			delete(s.bookmarks, bookmarkID)
+		}
+	}
+
+	for postID, post := range s.posts {
+		if post.ThreadID != id {
+			continue
+		}
+
+		delete(s.posts, postID)
+		delete(s.postTags, postID)
+
+		for key := range s.postVotes {
+			if key.targetID == postID {
+				delete(s.postVotes, key)
+			}
+		}
+	}
+}
+
+func copyThread(thread *domain.Thread) *domain.Thread {
+	t := *thread
+	t.UpdatedAt = copyTime(thread.UpdatedAt)
+	t.DeletedAt = copyTime(thread.DeletedAt)
+	t.Tags = nil
+	t.Attachments = nil
+
+	return &t
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+
+	return &c
+}
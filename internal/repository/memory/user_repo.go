@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryUserRepo struct {
+	store *Store
+}
+
+func NewMemoryUserRepo(s *Store) usecase.UserRepository {
+	return &memoryUserRepo{store: s}
+}
+
+func (r *memoryUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Email == email {
+			return copyUser(user), nil
+		}
+	}
+
+	return nil, domain.ErrNotFound
+}
+
+func (r *memoryUserRepo) Create(ctx context.Context, user *domain.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[user.ID]; ok {
+		return domain.ErrConflict
+	}
+
+	for _, existing := range r.store.users {
+		if existing.Username == user.Username || existing.Email == user.Email {
+			return domain.ErrConflict
+		}
+	}
+
+	r.store.users[user.ID] = copyUser(user)
+
+	return nil
+}
+
+func (r *memoryUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	return copyUser(user), nil
+}
+
+// GetByIDs leaves out password hashes, as the postgres implementation does.
+func (r *memoryUserRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	userMap := make(map[uuid.UUID]*domain.User)
+	for _, id := range ids {
+		if user, ok := r.store.users[id]; ok {
+			userMap[id] = publicUser(user)
+		}
+	}
+
+	return userMap, nil
+}
+
+func (r *memoryUserRepo) GetUsers(ctx context.Context) ([]*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var users []*domain.User
+	for _, user := range r.store.users {
+		if user.Role != "admin" {
+			users = append(users, copyUser(user))
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.Before(users[j].CreatedAt)
+	})
+
+	return users, nil
+}
+
+func (r *memoryUserRepo) UpdateRole(ctx context.Context, id uuid.UUID, role string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	user.Role = role
+
+	return nil
+}
+
+// GetByUsernames matches usernames case-insensitively; usernames are expected
+// in lowercase and the map is keyed by lowercased username.
+func (r *memoryUserRepo) GetByUsernames(ctx context.Context, usernames []string) (map[string]*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[string]struct{}, len(usernames))
+	for _, username := range usernames {
+		wanted[username] = struct{}{}
+	}
+
+	userMap := make(map[string]*domain.User)
+	for _, user := range r.store.users {
+		key := strings.ToLower(user.Username)
+		if _, ok := wanted[key]; ok {
+			userMap[key] = publicUser(user)
+		}
+	}
+
+	return userMap, nil
+}
+
+func copyUser(user *domain.User) *domain.User {
+	u := *user
+
+	return &u
+}
+
+func publicUser(user *domain.User) *domain.User {
+	u := copyUser(user)
+	u.PasswordHash = ""
+
+	return u
+}
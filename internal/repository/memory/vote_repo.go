@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryVoteRepo struct {
+	store *Store
+}
+
+func NewMemoryVoteRepo(s *Store) usecase.VoteRepository {
+	return &memoryVoteRepo{store: s}
+}
+
+func (r *memoryVoteRepo) DeleteThreadVote(ctx context.Context, userID uuid.UUID, threadID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.threadVotes, voteKey{userID: userID, targetID: threadID})
+
+	return nil
+}
+
+// GetThreadVoteForUpdate relies on the memory TxManager running one
+// transaction at a time instead of locking the vote.
+func (r *memoryVoteRepo) GetThreadVoteForUpdate(ctx context.Context, userID uuid.UUID, threadID uuid.UUID) (*domain.ThreadVote, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return getVote(r.store.threadVotes, userID, threadID)
+}
+
+func (r *memoryVoteRepo) UpsertThreadVote(ctx context.Context, vote *domain.ThreadVote) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	upsertVote(r.store.threadVotes, vote)
+
+	return nil
+}
+
+func (r *memoryVoteRepo) DeletePostVote(ctx context.Context, userID uuid.UUID, postID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.postVotes, voteKey{userID: userID, targetID: postID})
+
+	return nil
+}
+
+// GetPostVoteForUpdate returns the vote with the post ID in ThreadID, as the
+// postgres implementation does.
+func (r *memoryVoteRepo) GetPostVoteForUpdate(ctx context.Context, userID uuid.UUID, postID uuid.UUID) (*domain.ThreadVote, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return getVote(r.store.postVotes, userID, postID)
+}
+
+func (r *memoryVoteRepo) UpsertPostVote(ctx context.Context, vote *domain.ThreadVote) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	upsertVote(r.store.postVotes, vote)
+
+	return nil
+}
+
+func getVote(votes map[voteKey]*domain.ThreadVote, userID, targetID uuid.UUID) (*domain.ThreadVote, error) {
+	vote, ok := votes[voteKey{userID: userID, targetID: targetID}]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	v := *vote
+
+	return &v, nil
+}
+
+// upsertVote records a vote or changes the type of an existing one. Like the
+// postgres upsert, it sets vote.CreatedAt but an existing vote keeps its
+// original time.
+func upsertVote(votes map[voteKey]*domain.ThreadVote, vote *domain.ThreadVote) {
+	vote.CreatedAt = time.Now()
+
+	key := voteKey{userID: vote.UserID, targetID: vote.ThreadID}
+	if existing, ok := votes[key]; ok {
+		existing.VoteType = vote.VoteType
+
+		return
+	}
+
+	v := *vote
+	votes[key] = &v
+}
@@ -0,0 +1,282 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type memoryWebhookRepo struct {
+	store *Store
+}
+
+func NewMemoryWebhookRepo(s *Store) usecase.WebhookRepository {
+	return &memoryWebhookRepo{store: s}
+}
+
+func (r *memoryWebhookRepo) Create(ctx context.Context, webhook *domain.Webhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhooks[webhook.ID]; ok {
+		return domain.ErrConflict
+	}
+
+	r.store.webhooks[webhook.ID] = copyWebhook(webhook)
+
+	return nil
+}
+
+func (r *memoryWebhookRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	webhook, ok := r.store.webhooks[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	return copyWebhook(webhook), nil
+}
+
+func (r *memoryWebhookRepo) GetAll(ctx context.Context) ([]*domain.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	webhooks := make([]*domain.Webhook, 0, len(r.store.webhooks))
+	for _, w := range r.store.webhooks {
+		webhooks = append(webhooks, copyWebhook(w))
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+
+	return webhooks, nil
+}
+
+// GetActiveByEvent leaves Events unset, as the postgres query does.
+func (r *memoryWebhookRepo) GetActiveByEvent(ctx context.Context, eventType string) ([]*domain.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	webhooks := make([]*domain.Webhook, 0)
+	for _, w := range r.store.webhooks {
+		if w.IsActive && slices.Contains(w.Events, eventType) {
+			c := *w
+			c.Events = nil
+			webhooks = append(webhooks, &c)
+		}
+	}
+
+	return webhooks, nil
+}
+
+func (r *memoryWebhookRepo) Update(ctx context.Context, webhook *domain.Webhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.webhooks[webhook.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	c := copyWebhook(webhook)
+	c.CreatedBy = existing.CreatedBy
+	c.CreatedAt = existing.CreatedAt
+	r.store.webhooks[webhook.ID] = c
+
+	return nil
+}
+
+// Delete removes the webhook's deliveries with it, as ON DELETE CASCADE does.
+func (r *memoryWebhookRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhooks[id]; !ok {
+		return domain.ErrNotFound
+	}
+
+	delete(r.store.webhooks, id)
+
+	for deliveryID, d := range r.store.webhookDeliveries {
+		if d.WebhookID == id {
+			r.deleteDelivery(deliveryID)
+		}
+	}
+
+	return nil
+}
+
+func (r *memoryWebhookRepo) CreateDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, d := range deliveries {
+		if d.RedeliveryOf == nil && r.hasDelivery(d.WebhookID, d.EventID) {
+			continue
+		}
+
+		c := *d
+		r.store.webhookDeliveries[d.ID] = &c
+	}
+
+	return nil
+}
+
+// hasDelivery reports whether the webhook already has an original delivery
+// of the event, as the unique index on webhook_deliveries does.
+func (r *memoryWebhookRepo) hasDelivery(webhookID, eventID uuid.UUID) bool {
+	for _, d := range r.store.webhookDeliveries {
+		if d.WebhookID == webhookID && d.EventID == eventID && d.RedeliveryOf == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *memoryWebhookRepo) GetDeliveryByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	d, ok := r.store.webhookDeliveries[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	c := *d
+
+	return &c, nil
+}
+
+func (r *memoryWebhookRepo) GetDeliveriesByWebhookID(ctx context.Context, webhookID uuid.UUID, params usecase.PaginationParams) ([]*domain.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	deliveries := r.byWebhook(webhookID)
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+
+		return deliveries[i].ID.String() > deliveries[j].ID.String()
+	})
+
+	return paginate(deliveries, params), nil
+}
+
+func (r *memoryWebhookRepo) CountDeliveriesByWebhookID(ctx context.Context, webhookID uuid.UUID) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return len(r.byWebhook(webhookID)), nil
+}
+
+func (r *memoryWebhookRepo) byWebhook(webhookID uuid.UUID) []*domain.WebhookDelivery {
+	deliveries := make([]*domain.WebhookDelivery, 0)
+	for _, d := range r.store.webhookDeliveries {
+		if d.WebhookID == webhookID {
+			c := *d
+			deliveries = append(deliveries, &c)
+		}
+	}
+
+	return deliveries
+}
+
+func (r *memoryWebhookRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	due := make([]*domain.WebhookDelivery, 0)
+	for _, d := range r.store.webhookDeliveries {
+		webhook, ok := r.store.webhooks[d.WebhookID]
+		if !ok || !webhook.IsActive || d.Status != domain.WebhookDeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+
+		due = append(due, d)
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*domain.WebhookDelivery, len(due))
+	for i, d := range due {
+		d.NextAttemptAt = now.Add(lease)
+		c := *d
+		claimed[i] = &c
+	}
+
+	return claimed, nil
+}
+
+func (r *memoryWebhookRepo) SaveDeliveryResult(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.webhookDeliveries[delivery.ID]
+	if !ok {
+		return nil
+	}
+
+	d.Status = delivery.Status
+	d.Attempts = delivery.Attempts
+	d.NextAttemptAt = delivery.NextAttemptAt
+	d.LastAttemptAt = delivery.LastAttemptAt
+	d.ResponseStatus = delivery.ResponseStatus
+	d.ResponseBody = delivery.ResponseBody
+	d.LastError = delivery.LastError
+	d.DeliveredAt = delivery.DeliveredAt
+
+	return nil
+}
+
+func (r *memoryWebhookRepo) PurgeDeliveriesBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	purged := 0
+	for id, d := range r.store.webhookDeliveries {
+		if d.Status != domain.WebhookDeliveryPending && d.CreatedAt.Before(cutoff) {
+			r.deleteDelivery(id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// deleteDelivery removes a delivery and clears RedeliveryOf on its copies,
+// as ON DELETE SET NULL does.
+func (r *memoryWebhookRepo) deleteDelivery(id uuid.UUID) {
+	delete(r.store.webhookDeliveries, id)
+
+	for _, d := range r.store.webhookDeliveries {
+		if d.RedeliveryOf != nil && *d.RedeliveryOf == id {
+			d.RedeliveryOf = nil
+		}
+	}
+}
+
+func copyWebhook(w *domain.Webhook) *domain.Webhook {
+	c := *w
+	c.Events = slices.Clone(w.Events)
+	if c.Events == nil {
+		c.Events = []string{}
+	}
+
+	return &c
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type postgresAttachmentRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresAttachmentRepo(db *sqlx.DB) usecase.AttachmentRepository {
+	return &postgresAttachmentRepo{db: db}
+}
+
+// CreateWithinQuota locks the owner's quota for the rest of the transaction,
+// so concurrent uploads sum what the previous one committed. Without the
+// lock, each would see only the rows committed before it started and
+// together they could exceed the quota.
+func (r *postgresAttachmentRepo) CreateWithinQuota(ctx context.Context, a *domain.Attachment, quota int64) error {
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		lock := `SELECT pg_advisory_xact_lock(hashtext('attachments'), hashtext($1::text))`
+		if _, err := conn(ctx, r.db).ExecContext(ctx, lock, a.UserID); err != nil {
+			return err
+		}
+
+		query := `INSERT INTO attachments (id, user_id, thread_id, post_id, storage_key, filename, content_type, size_bytes, created_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9
+		WHERE (SELECT COALESCE(SUM(size_bytes), 0) FROM attachments WHERE user_id = $2) + $8 <= $10`
+
+		res, err := conn(ctx, r.db).ExecContext(ctx, query, a.ID, a.UserID, a.ThreadID, a.PostID, a.StorageKey, a.Filename, a.ContentType, a.SizeBytes, a.CreatedAt, quota)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrQuotaExceeded
+		}
+
+		return nil
+	})
+}
+
+func (r *postgresAttachmentRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+
+	query := `SELECT * FROM attachments WHERE id = $1`
+
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &attachment, query, id)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (r *postgresAttachmentRepo) GetByThreadID(ctx context.Context, threadID uuid.UUID) ([]*domain.Attachment, error) {
+	attachments := []*domain.Attachment{}
+
+	query := `SELECT * FROM attachments WHERE thread_id = $1 ORDER BY created_at ASC`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &attachments, query, threadID)
+
+	return attachments, err
+}
+
+func (r *postgresAttachmentRepo) GetByPostIDs(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]*domain.Attachment, error) {
+	attachmentMap := make(map[uuid.UUID][]*domain.Attachment)
+	if len(postIDs) == 0 {
+		return attachmentMap, nil
+	}
+
+	attachments := []*domain.Attachment{}
+	query, args, err := sqlx.In(`SELECT * FROM attachments WHERE post_id IN (?) ORDER BY created_at ASC`, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.Rebind(query)
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &attachments, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range attachments {
+		attachmentMap[*a.PostID] = append(attachmentMap[*a.PostID], a)
+	}
+
+	return attachmentMap, nil
+}
+
+func (r *postgresAttachmentRepo) GetOrphaned(ctx context.Context, limit int) ([]*domain.Attachment, error) {
+	attachments := []*domain.Attachment{}
+
+	query := `SELECT * FROM attachments WHERE thread_id IS NULL AND post_id IS NULL ORDER BY created_at ASC LIMIT $1`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &attachments, query, limit)
+
+	return attachments, err
+}
+
+func (r *postgresAttachmentRepo) SumSizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var total int64
+	query := `SELECT COALESCE(SUM(size_bytes), 0) FROM attachments WHERE user_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &total, query, userID)
+	return total, err
+}
+
+func (r *postgresAttachmentRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM attachments WHERE id = $1`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
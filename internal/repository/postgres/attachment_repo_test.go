@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+func TestConcurrentUploadsStayWithinQuota(t *testing.T) {
+	dsn := os.Getenv(testDatabaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	db, err := sqlx.Connect("pgx", dsn)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if _, err := db.Exec(`TRUNCATE users, categories CASCADE`); err != nil {
+		t.Fatalf("reset test database: %v", err)
+	}
+
+	user := &domain.User{ID: uuid.New(), Username: "uploader", Email: "uploader@example.com", PasswordHash: "hash", Role: "user", CreatedAt: time.Now()}
+	if err := NewPostgresUserRepo(db).Create(ctx, user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	repo := NewPostgresAttachmentRepo(db)
+
+	const (
+		workers = 10
+		size    = 100
+		quota   = 3 * size
+	)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			id := uuid.New()
+			errs <- repo.CreateWithinQuota(ctx, &domain.Attachment{
+				ID:          id,
+				UserID:      user.ID,
+				StorageKey:  "attachments/" + id.String(),
+				Filename:    "file.png",
+				ContentType: "image/png",
+				SizeBytes:   size,
+				CreatedAt:   time.Now(),
+			}, quota)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, domain.ErrQuotaExceeded):
+			t.Fatalf("CreateWithinQuota: %v", err)
+		}
+	}
+
+	used, err := repo.SumSizeByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("SumSizeByUserID: %v", err)
+	}
+
+	if created != quota/size || used != quota {
+		t.Errorf("created %d attachments using %d bytes, want %d using %d", created, used, quota/size, quota)
+	}
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type postgresAuditLogRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresAuditLogRepo(db *sqlx.DB) usecase.AuditLogRepository {
+	return &postgresAuditLogRepo{db: db}
+}
+
+func (r *postgresAuditLogRepo) Create(ctx context.Context, entry *domain.AuditLog) error {
+	query := `INSERT INTO audit_logs (id, actor_id, actor_role, action, target_type, target_id, reason, details, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, entry.ID, entry.ActorID, entry.ActorRole, entry.Action, entry.TargetType, entry.TargetID, entry.Reason, entry.Details, entry.CreatedAt)
+
+	return err
+}
+
+func (r *postgresAuditLogRepo) GetAll(ctx context.Context, filter usecase.AuditLogFilter, params usecase.PaginationParams) ([]*domain.AuditLog, error) {
+	var entries []*domain.AuditLog
+
+	where, args := buildAuditLogWhere(filter)
+	args = append(args, params.Limit, params.Offset)
+
+	query := fmt.Sprintf(`SELECT * FROM audit_logs %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &entries, query, args...)
+
+	return entries, err
+}
+
+func (r *postgresAuditLogRepo) CountAll(ctx context.Context, filter usecase.AuditLogFilter) (int, error) {
+	var count int
+
+	where, args := buildAuditLogWhere(filter)
+
+	query := `SELECT COUNT(*) FROM audit_logs ` + where
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, args...)
+
+	return count, err
+}
+
+func buildAuditLogWhere(filter usecase.AuditLogFilter) (string, []interface{}) {
+	conditions := make([]string, 0, 4)
+	args := make([]interface{}, 0, 6)
+
+	if filter.ActorID != nil {
+		args = append(args, *filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+
+	if filter.TargetType != nil {
+		args = append(args, *filter.TargetType)
+		conditions = append(conditions, fmt.Sprintf("target_type = $%d", len(args)))
+	}
+
+	if filter.TargetID != nil {
+		args = append(args, *filter.TargetID)
+		conditions = append(conditions, fmt.Sprintf("target_id = $%d", len(args)))
+	}
+
+	if filter.Action != nil {
+		args = append(args, *filter.Action)
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// Bookmarks on soft-deleted threads are kept so they come back on restore,
+// but they are hidden from listings and folder counts in the meantime.
+const bookmarkFolderColumns = `f.*,
+	(SELECT COUNT(*) FROM bookmarks b JOIN threads t ON t.id = b.thread_id AND t.deleted_at IS NULL
+		WHERE b.folder_id = f.id) AS bookmark_count`
+
+type postgresBookmarkRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresBookmarkRepo(db *sqlx.DB) usecase.BookmarkRepository {
+	return &postgresBookmarkRepo{db: db}
+}
+
+// Upsert saves the bookmark, replacing the folder and note of an existing
+// bookmark on the same thread or post. The stored row is scanned back into
+// bookmark so callers see the original id and created_at.
+func (r *postgresBookmarkRepo) Upsert(ctx context.Context, bookmark *domain.Bookmark) error {
+	target := `(user_id, thread_id) WHERE post_id IS NULL`
+	if bookmark.PostID != nil {
+		target = `(user_id, post_id) WHERE post_id IS NOT NULL`
+	}
+
+	query := `INSERT INTO bookmarks (id, user_id, thread_id, post_id, folder_id, note, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT ` + target + ` DO UPDATE
+	SET folder_id = EXCLUDED.folder_id, note = EXCLUDED.note, updated_at = EXCLUDED.updated_at
+	RETURNING *`
+
+	return sqlx.GetContext(ctx, conn(ctx, r.db), bookmark, query,
+		bookmark.ID, bookmark.UserID, bookmark.ThreadID, bookmark.PostID,
+		bookmark.FolderID, bookmark.Note, bookmark.CreatedAt, bookmark.UpdatedAt)
+}
+
+func (r *postgresBookmarkRepo) Delete(ctx context.Context, userID, threadID uuid.UUID, postID *uuid.UUID) error {
+	query := `DELETE FROM bookmarks WHERE user_id = $1 AND thread_id = $2 AND post_id IS NULL`
+	args := []interface{}{userID, threadID}
+	if postID != nil {
+		query = `DELETE FROM bookmarks WHERE user_id = $1 AND thread_id = $2 AND post_id = $3`
+		args = append(args, *postID)
+	}
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresBookmarkRepo) GetByUserID(ctx context.Context, userID uuid.UUID, filter usecase.BookmarkFilter, params usecase.PaginationParams) ([]*domain.Bookmark, error) {
+	bookmarks := []*domain.Bookmark{}
+	where, args := buildBookmarkWhere(userID, filter)
+	args = append(args, params.Limit, params.Offset)
+
+	query := fmt.Sprintf(`SELECT b.*
+	FROM bookmarks b
+	JOIN threads t ON t.id = b.thread_id AND t.deleted_at IS NULL
+	%s
+	ORDER BY b.created_at DESC, b.id DESC
+	LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &bookmarks, query, args...)
+
+	return bookmarks, err
+}
+
+func (r *postgresBookmarkRepo) CountByUserID(ctx context.Context, userID uuid.UUID, filter usecase.BookmarkFilter) (int, error) {
+	var count int
+	where, args := buildBookmarkWhere(userID, filter)
+	query := `SELECT COUNT(*) FROM bookmarks b JOIN threads t ON t.id = b.thread_id AND t.deleted_at IS NULL ` + where
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, args...)
+	return count, err
+}
+
+func buildBookmarkWhere(userID uuid.UUID, filter usecase.BookmarkFilter) (string, []interface{}) {
+	conditions := []string{"b.user_id = $1"}
+	args := []interface{}{userID}
+
+	if filter.Unfiled {
+		conditions = append(conditions, "b.folder_id IS NULL")
+	} else if filter.FolderID != nil {
+		args = append(args, *filter.FolderID)
+		conditions = append(conditions, fmt.Sprintf("b.folder_id = $%d", len(args)))
+	}
+
+	switch filter.Type {
+	case domain.BookmarkTypeThread:
+		conditions = append(conditions, "b.post_id IS NULL")
+	case domain.BookmarkTypePost:
+		conditions = append(conditions, "b.post_id IS NOT NULL")
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *postgresBookmarkRepo) GetBookmarkedThreadIDs(ctx context.Context, userID uuid.UUID, threadIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	return r.getBookmarkedIDs(ctx, `SELECT thread_id FROM bookmarks WHERE user_id = ? AND post_id IS NULL AND thread_id IN (?)`, userID, threadIDs)
+}
+
+func (r *postgresBookmarkRepo) GetBookmarkedPostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	return r.getBookmarkedIDs(ctx, `SELECT post_id FROM bookmarks WHERE user_id = ? AND post_id IN (?)`, userID, postIDs)
+}
+
+func (r *postgresBookmarkRepo) getBookmarkedIDs(ctx context.Context, query string, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	bookmarked := make(map[uuid.UUID]bool)
+	if len(ids) == 0 {
+		return bookmarked, nil
+	}
+
+	query, args, err := sqlx.In(query, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []uuid.UUID
+	query = r.db.Rebind(query)
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &found, query, args...); err != nil {
+		return nil, err
+	}
+
+	for _, id := range found {
+		bookmarked[id] = true
+	}
+
+	return bookmarked, nil
+}
+
+func (r *postgresBookmarkRepo) CreateFolder(ctx context.Context, folder *domain.BookmarkFolder) error {
+	query := `INSERT INTO bookmark_folders (id, user_id, name, created_at) VALUES ($1, $2, $3, $4)`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, folder.ID, folder.UserID, folder.Name, folder.CreatedAt)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
+
+	return err
+}
+
+func (r *postgresBookmarkRepo) GetFolderByID(ctx context.Context, id uuid.UUID) (*domain.BookmarkFolder, error) {
+	var folder domain.BookmarkFolder
+
+	query := `SELECT ` + bookmarkFolderColumns + ` FROM bookmark_folders f WHERE f.id = $1`
+
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &folder, query, id)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+
+	return &folder, err
+}
+
+func (r *postgresBookmarkRepo) GetFoldersByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.BookmarkFolder, error) {
+	folders := []*domain.BookmarkFolder{}
+
+	query := `SELECT ` + bookmarkFolderColumns + ` FROM bookmark_folders f WHERE f.user_id = $1 ORDER BY LOWER(f.name) ASC`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &folders, query, userID)
+
+	return folders, err
+}
+
+func (r *postgresBookmarkRepo) CountFoldersByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM bookmark_folders WHERE user_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, userID)
+	return count, err
+}
+
+func (r *postgresBookmarkRepo) RenameFolder(ctx context.Context, id uuid.UUID, name string) error {
+	query := `UPDATE bookmark_folders SET name = $1 WHERE id = $2`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, name, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrConflict
+		}
+
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresBookmarkRepo) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM bookmark_folders WHERE id = $1`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
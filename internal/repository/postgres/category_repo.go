@@ -23,7 +23,7 @@ func (r *postgresCategoryRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) (m
 	}
 
 	query = r.db.Rebind(query)
-	err = r.db.SelectContext(ctx, &categories, query, args...)
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &categories, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +42,10 @@ func NewPostgresCategoryRepo(db *sqlx.DB) usecase.CategoryRepository {
 
 func (r *postgresCategoryRepo) Create(ctx context.Context, category *domain.Category) error {
 	query := `INSERT INTO categories (id, name, slug, description, created_at) VALUES ($1, $2, $3, $4, $5)`
-	_, err := r.db.ExecContext(ctx, query, category.ID, category.Name, category.Slug, category.Description, category.CreatedAt)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, category.ID, category.Name, category.Slug, category.Description, category.CreatedAt)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
 
 	return err
 }
@@ -52,7 +55,7 @@ func (r *postgresCategoryRepo) GetBySlug(ctx context.Context, slug string) (*dom
 
 	query := `SELECT id, name, slug, description, created_at FROM categories WHERE slug = $1`
 
-	err := r.db.GetContext(ctx, &category, query, slug)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &category, query, slug)
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
 	}
@@ -65,7 +68,7 @@ func (r *postgresCategoryRepo) GetAll(ctx context.Context) ([]*domain.Category,
 
 	query := `SELECT * FROM categories ORDER BY created_at ASC`
 
-	err := r.db.SelectContext(ctx, &categories, query)
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &categories, query)
 
 	return categories, err
 }
@@ -75,10 +78,39 @@ func (r *postgresCategoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*doma
 
 	query := `SELECT * FROM categories WHERE id = $1`
 
-	err := r.db.GetContext(ctx, &category, query, id)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &category, query, id)
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
 	}
 
 	return &category, err
 }
+
+func (r *postgresCategoryRepo) Follow(ctx context.Context, userID, categoryID uuid.UUID) error {
+	query := `INSERT INTO category_follows (user_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, categoryID)
+
+	return err
+}
+
+func (r *postgresCategoryRepo) Unfollow(ctx context.Context, userID, categoryID uuid.UUID) error {
+	query := `DELETE FROM category_follows WHERE user_id = $1 AND category_id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, categoryID)
+
+	return err
+}
+
+func (r *postgresCategoryRepo) GetFollowedByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Category, error) {
+	categories := []*domain.Category{}
+
+	query := `SELECT c.* FROM categories c
+	JOIN category_follows f ON f.category_id = c.id
+	WHERE f.user_id = $1
+	ORDER BY c.name ASC`
+
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &categories, query, userID)
+
+	return categories, err
+}
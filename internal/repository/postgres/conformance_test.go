@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/repository/repotest"
+)
+
+// testDatabaseURLEnv names a migrated database the conformance suite may
+// empty between tests. The suite is skipped when it is not set.
+const testDatabaseURLEnv = "AGORA_TEST_DATABASE_URL"
+
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv(testDatabaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	db, err := sqlx.Connect("pgx", dsn)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		// Tables without a foreign key to users or categories are listed
+		// themselves.
+		if _, err := db.Exec(`TRUNCATE users, categories, tags, webhooks, outbox_events, processed_events CASCADE`); err != nil {
+			t.Fatalf("reset test database: %v", err)
+		}
+
+		return repotest.Repos{
+			Users:      NewPostgresUserRepo(db),
+			Categories: NewPostgresCategoryRepo(db),
+			Threads:    NewPostgresThreadRepo(db),
+			Posts:      NewPostgresPostRepo(db),
+			Votes:      NewPostgresVoteRepo(db),
+			Tx:         NewPostgresTxManager(db),
+
+			Notifications:   NewPostgresNotificationRepo(db),
+			Outbox:          NewPostgresOutboxRepo(db),
+			ProcessedEvents: NewPostgresProcessedEventRepo(db),
+			Webhooks:        NewPostgresWebhookRepo(db),
+			Tags:            NewPostgresTagRepo(db),
+			Subscriptions:   NewPostgresSubscriptionRepo(db),
+			Bookmarks:       NewPostgresBookmarkRepo(db),
+			Digests:         NewPostgresDigestRepo(db),
+		}
+	})
+}
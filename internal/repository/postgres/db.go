@@ -1,31 +1,66 @@
 package postgres
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 	"github.com/srgjo27/agora/internal/config"
+	"github.com/srgjo27/agora/internal/tracing"
 )
 
-func ConnectDB(cfg *config.Config) *sqlx.DB {
-	dsn := cfg.DSN()
+const (
+	connectInitialBackoff = 500 * time.Millisecond
+	connectMaxBackoff     = 5 * time.Second
+)
 
-	db, err := sqlx.Connect("pgx", dsn)
+// ConnectDB opens the connection pool and waits for the database to answer,
+// retrying with exponential backoff for up to DB_CONNECT_TIMEOUT_SECONDS so
+// the API and the worker survive starting before Postgres is ready.
+func ConnectDB(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*sqlx.DB, error) {
+	connConfig, err := pgx.ParseConfig(cfg.DB.DSN())
 	if err != nil {
-		log.Fatalf("[ERROR]: Gagal terhubung ke database: %v", err)
+		return nil, fmt.Errorf("konfigurasi database tidak valid: %w", err)
 	}
 
+	connConfig.Tracer = tracing.NewQueryTracer()
+
+	db := sqlx.NewDb(stdlib.OpenDB(*connConfig), "pgx")
+
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(25)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
-	err = db.Ping()
-	if err != nil {
-		log.Fatalf("[ERROR]: Gagal ping database: %v", err)
+	deadline := time.Now().Add(time.Duration(cfg.DB.ConnectTimeoutSeconds) * time.Second)
+	backoff := connectInitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err = db.PingContext(ctx)
+		if err == nil {
+			break
+		}
+
+		if ctx.Err() != nil || time.Now().Add(backoff).After(deadline) {
+			db.Close()
+
+			return nil, fmt.Errorf("gagal terhubung ke database setelah %d percobaan: %w", attempt, err)
+		}
+
+		logger.InfoContext(ctx, "database not ready, retrying", "attempt", attempt, "backoff", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, connectMaxBackoff)
 	}
 
-	log.Printf("[SUCCESS]: Koneksi database berhasil!")
-	return db
+	logger.InfoContext(ctx, "connected to database")
+
+	return db, nil
 }
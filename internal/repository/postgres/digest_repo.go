@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type postgresDigestRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresDigestRepo(db *sqlx.DB) usecase.DigestRepository {
+	return &postgresDigestRepo{db: db}
+}
+
+func (r *postgresDigestRepo) EnsurePreferences(ctx context.Context, userID uuid.UUID, token string) (*domain.DigestPreferences, error) {
+	var prefs domain.DigestPreferences
+
+	query := `INSERT INTO digest_preferences (user_id, frequency, unsubscribe_token) VALUES ($1, $2, $3)
+	ON CONFLICT (user_id) DO NOTHING`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID, domain.DefaultDigestFrequency, token); err != nil {
+		return nil, err
+	}
+
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &prefs, `SELECT * FROM digest_preferences WHERE user_id = $1`, userID)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+
+	return &prefs, err
+}
+
+func (r *postgresDigestRepo) UpdateFrequency(ctx context.Context, userID uuid.UUID, frequency string) error {
+	query := `UPDATE digest_preferences SET frequency = $1, updated_at = $2 WHERE user_id = $3`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, frequency, time.Now(), userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresDigestRepo) UnsubscribeByToken(ctx context.Context, token string) error {
+	query := `UPDATE digest_preferences SET frequency = $1, updated_at = $2 WHERE unsubscribe_token = $3`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, domain.DigestOff, time.Now(), token)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresDigestRepo) GetDueRecipients(ctx context.Context, cutoffs map[string]time.Time, after uuid.UUID, limit int) ([]*domain.DigestRecipient, error) {
+	recipients := []*domain.DigestRecipient{}
+	if len(cutoffs) == 0 {
+		return recipients, nil
+	}
+
+	args := []interface{}{domain.DefaultDigestFrequency, after}
+	due := make([]string, 0, len(cutoffs))
+	for frequency, cutoff := range cutoffs {
+		args = append(args, frequency, cutoff)
+		due = append(due, fmt.Sprintf("(frequency = $%d AND (last_sent_at IS NULL OR last_sent_at <= $%d))", len(args)-1, len(args)))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`SELECT * FROM (
+		SELECT u.id AS user_id, u.username, u.email, COALESCE(d.frequency, $1) AS frequency, d.last_sent_at
+		FROM users u
+		LEFT JOIN digest_preferences d ON d.user_id = u.id
+		WHERE u.id > $2) recipients
+	WHERE %s
+	ORDER BY user_id ASC
+	LIMIT $%d`, strings.Join(due, " OR "), len(args))
+
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &recipients, query, args...)
+
+	return recipients, err
+}
+
+func (r *postgresDigestRepo) MarkSent(ctx context.Context, userID uuid.UUID, sentAt time.Time) error {
+	query := `UPDATE digest_preferences SET last_sent_at = $1 WHERE user_id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, sentAt, userID)
+
+	return err
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+const (
+	eventChannel = "agora_events"
+
+	// NOTIFY payloads are limited to 8000 bytes; leave headroom for encoding.
+	maxNotifyPayload = 7900
+
+	maxListenBackoff = 30 * time.Second
+)
+
+// PostgresEventBroker fans events out across API instances with
+// LISTEN/NOTIFY, so every instance connected to the same database sees
+// every event.
+type PostgresEventBroker struct {
+	db     *sqlx.DB
+	dsn    string
+	logger *slog.Logger
+}
+
+func NewPostgresEventBroker(db *sqlx.DB, dsn string, logger *slog.Logger) *PostgresEventBroker {
+	return &PostgresEventBroker{db: db, dsn: dsn, logger: logger}
+}
+
+func (b *PostgresEventBroker) Publish(ctx context.Context, event *domain.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if len(payload) > maxNotifyPayload {
+		trimmed := *event
+		trimmed.Data = nil
+		trimmed.Truncated = true
+
+		payload, err = json.Marshal(&trimmed)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, eventChannel, string(payload))
+
+	return err
+}
+
+// Subscribe listens on a dedicated connection and reconnects with
+// exponential backoff until ctx is cancelled.
+func (b *PostgresEventBroker) Subscribe(ctx context.Context, handler func(*domain.Event)) error {
+	backoff := time.Second
+
+	for {
+		connected, err := b.listen(ctx, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if connected {
+			backoff = time.Second
+		}
+
+		b.logger.ErrorContext(ctx, "event listener terminated, reconnecting", "backoff", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxListenBackoff {
+			backoff = maxListenBackoff
+		}
+	}
+}
+
+func (b *PostgresEventBroker) listen(ctx context.Context, handler func(*domain.Event)) (bool, error) {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+eventChannel); err != nil {
+		return false, err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+
+		var event domain.Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			b.logger.ErrorContext(ctx, "discarding malformed event payload", "error", err)
+
+			continue
+		}
+
+		handler(&event)
+	}
+}
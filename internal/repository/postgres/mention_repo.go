@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type postgresMentionRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresMentionRepo(db *sqlx.DB) usecase.MentionRepository {
+	return &postgresMentionRepo{db: db}
+}
+
+func (r *postgresMentionRepo) ReplaceForThread(ctx context.Context, threadID, actorID uuid.UUID, userIDs []uuid.UUID) error {
+	return r.replace(ctx, threadID, nil, actorID, userIDs)
+}
+
+func (r *postgresMentionRepo) ReplaceForPost(ctx context.Context, threadID, postID, actorID uuid.UUID, userIDs []uuid.UUID) error {
+	return r.replace(ctx, threadID, &postID, actorID, userIDs)
+}
+
+// replace drops mentions that are no longer in the content and adds new
+// ones. Mentions that remain keep their original created_at, so editing a
+// post does not move it back to the top of "threads mentioning me".
+func (r *postgresMentionRepo) replace(ctx context.Context, threadID uuid.UUID, postID *uuid.UUID, actorID uuid.UUID, userIDs []uuid.UUID) error {
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		scope := `thread_id = ? AND post_id IS NULL`
+		scopeArgs := []interface{}{threadID}
+		if postID != nil {
+			scope = `post_id = ?`
+			scopeArgs = []interface{}{*postID}
+		}
+
+		query, args := `DELETE FROM mentions WHERE `+scope, scopeArgs
+		if len(userIDs) > 0 {
+			var err error
+			query, args, err = sqlx.In(query+` AND user_id NOT IN (?)`, append(scopeArgs, userIDs)...)
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+			return err
+		}
+
+		insert := `INSERT INTO mentions (id, user_id, actor_id, thread_id, post_id, created_at) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT DO NOTHING`
+		now := time.Now()
+		for _, userID := range userIDs {
+			if _, err := tx.ExecContext(ctx, insert, uuid.New(), userID, actorID, threadID, postID, now); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
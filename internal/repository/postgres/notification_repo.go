@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type postgresNotificationRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresNotificationRepo(db *sqlx.DB) usecase.NotificationRepository {
+	return &postgresNotificationRepo{db: db}
+}
+
+func (r *postgresNotificationRepo) Create(ctx context.Context, n *domain.Notification) (bool, error) {
+	query := `INSERT INTO notifications (id, user_id, event_id, actor_id, type, thread_id, post_id, milestone, is_read, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT DO NOTHING`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, n.ID, n.UserID, n.EventID, n.ActorID, n.Type, n.ThreadID, n.PostID, n.Milestone, n.IsRead, n.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *postgresNotificationRepo) GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, params usecase.PaginationParams) ([]*domain.Notification, error) {
+	var notifications []*domain.Notification
+
+	query := `SELECT * FROM notifications
+	WHERE user_id = $1 AND (NOT $2 OR is_read = FALSE)
+	ORDER BY created_at DESC
+	LIMIT $3 OFFSET $4`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &notifications, query, userID, unreadOnly, params.Limit, params.Offset)
+
+	return notifications, err
+}
+
+func (r *postgresNotificationRepo) CountByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND (NOT $2 OR is_read = FALSE)`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, userID, unreadOnly)
+
+	return count, err
+}
+
+func (r *postgresNotificationRepo) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	query := `UPDATE notifications SET is_read = TRUE, read_at = COALESCE(read_at, $1) WHERE id = $2 AND user_id = $3`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresNotificationRepo) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `UPDATE notifications SET is_read = TRUE, read_at = $1 WHERE user_id = $2 AND is_read = FALSE`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), userID)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (r *postgresNotificationRepo) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	var prefs domain.NotificationPreferences
+
+	query := `SELECT * FROM notification_preferences WHERE user_id = $1`
+
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &prefs, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+
+	return &prefs, err
+}
+
+func (r *postgresNotificationRepo) UpsertPreferences(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	query := `
+	INSERT INTO notification_preferences (user_id, thread_reply, post_reply, mention, vote_milestone, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (user_id) DO UPDATE SET
+	thread_reply = EXCLUDED.thread_reply,
+	post_reply = EXCLUDED.post_reply,
+	mention = EXCLUDED.mention,
+	vote_milestone = EXCLUDED.vote_milestone,
+	updated_at = EXCLUDED.updated_at`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, prefs.UserID, prefs.ThreadReply, prefs.PostReply, prefs.Mention, prefs.VoteMilestone, prefs.UpdatedAt)
+
+	return err
+}
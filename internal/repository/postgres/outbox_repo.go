@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type postgresOutboxRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresOutboxRepo(db *sqlx.DB) usecase.OutboxRepository {
+	return &postgresOutboxRepo{db: db}
+}
+
+func (r *postgresOutboxRepo) Add(ctx context.Context, events ...*domain.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, next_attempt_at, created_at)
+	VALUES (:id, :aggregate_type, :aggregate_id, :event_type, :payload, :next_attempt_at, :created_at)`
+
+	_, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, events)
+
+	return err
+}
+
+func (r *postgresOutboxRepo) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxEvent, error) {
+	events := []*domain.OutboxEvent{}
+
+	query := `UPDATE outbox_events SET next_attempt_at = $1
+	WHERE id IN (
+		SELECT id FROM outbox_events
+		WHERE published_at IS NULL AND next_attempt_at <= $2
+		ORDER BY seq ASC
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED)
+	RETURNING *`
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &events, query, now.Add(lease), now, limit); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	sort.Slice(events, func(i, j int) bool { return events[i].Seq < events[j].Seq })
+
+	return events, nil
+}
+
+func (r *postgresOutboxRepo) MarkPublished(ctx context.Context, id uuid.UUID, publishedAt time.Time) error {
+	query := `UPDATE outbox_events SET published_at = $1, last_error = NULL WHERE id = $2`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, publishedAt, id)
+
+	return err
+}
+
+func (r *postgresOutboxRepo) MarkFailed(ctx context.Context, event *domain.OutboxEvent) error {
+	query := `UPDATE outbox_events SET attempts = :attempts, next_attempt_at = :next_attempt_at, last_error = :last_error WHERE id = :id`
+	_, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, event)
+
+	return err
+}
+
+func (r *postgresOutboxRepo) PurgePublishedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM outbox_events WHERE published_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+
+	return int(rowsAffected), err
+}
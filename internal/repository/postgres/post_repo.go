@@ -21,15 +21,15 @@ func NewPostgresPostRepo(db *sqlx.DB) usecase.PostRepository {
 func (r *postgresPostRepo) CountByThreadID(ctx context.Context, threadID uuid.UUID) (int, error) {
 	var count int
 	query := `SELECT COUNT(*) FROM posts WHERE thread_id = $1`
-	err := r.db.GetContext(ctx, &count, query, threadID)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, threadID)
 
 	return count, err
 }
 
 func (r *postgresPostRepo) Create(ctx context.Context, post *domain.Post) error {
-	query := `INSERT INTO posts (id, content, user_id, thread_id, parent_post_id, created_at) 
-	VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := r.db.ExecContext(ctx, query, post.ID, post.Content, post.UserID, post.ThreadID, post.ParentPostID, post.CreatedAt)
+	query := `INSERT INTO posts (id, content, content_html, user_id, thread_id, parent_post_id, created_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, post.ID, post.Content, post.ContentHTML, post.UserID, post.ThreadID, post.ParentPostID, post.CreatedAt)
 
 	return err
 }
@@ -42,7 +42,7 @@ func (r *postgresPostRepo) GetByThreadID(ctx context.Context, threadID uuid.UUID
 	thread_id = $1 
 	ORDER BY created_at ASC 
 	LIMIT $2 OFFSET $3`
-	err := r.db.SelectContext(ctx, &posts, query, threadID, params.Limit, params.Offset)
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &posts, query, threadID, params.Limit, params.Offset)
 
 	return posts, err
 }
@@ -50,7 +50,7 @@ func (r *postgresPostRepo) GetByThreadID(ctx context.Context, threadID uuid.UUID
 func (r *postgresPostRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Post, error) {
 	var post domain.Post
 	query := `SELECT * FROM posts WHERE id = $1`
-	err := r.db.GetContext(ctx, &post, query, id)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &post, query, id)
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
 	}
@@ -58,16 +58,43 @@ func (r *postgresPostRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.P
 	return &post, err
 }
 
-func (r *postgresPostRepo) UpdateVoteCount(ctx context.Context, tx *sqlx.Tx, postID uuid.UUID, delta int) error {
-	query := `UPDATE posts SET vote_count = vote_count + $1 WHERE id = $2`
-
-	if tx != nil {
-		_, err := tx.ExecContext(ctx, query, delta, postID)
+func (r *postgresPostRepo) UpdateVoteCount(ctx context.Context, postID uuid.UUID, delta int) (int, error) {
+	var count int
+	query := `UPDATE posts SET vote_count = vote_count + $1 WHERE id = $2 RETURNING vote_count`
 
-		return err
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, delta, postID)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrNotFound
 	}
 
-	_, err := r.db.ExecContext(ctx, query, delta, postID)
+	return count, err
+}
+
+func (r *postgresPostRepo) Update(ctx context.Context, post *domain.Post) error {
+	query := `UPDATE posts SET content = $1, content_html = $2, updated_at = $3 WHERE id = $4`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, post.Content, post.ContentHTML, post.UpdatedAt, post.ID)
 
 	return err
 }
+
+func (r *postgresPostRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Post, error) {
+	posts := []*domain.Post{}
+	query, args, err := sqlx.In(`SELECT * FROM posts WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.Rebind(query)
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &posts, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	postMap := make(map[uuid.UUID]*domain.Post)
+	for _, post := range posts {
+		postMap[post.ID] = post
+	}
+
+	return postMap, nil
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type postgresProcessedEventRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresProcessedEventRepo(db *sqlx.DB) usecase.ProcessedEventRepository {
+	return &postgresProcessedEventRepo{db: db}
+}
+
+func (r *postgresProcessedEventRepo) IsProcessed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM processed_events WHERE consumer = $1 AND event_id = $2)`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &exists, query, consumer, eventID)
+	return exists, err
+}
+
+func (r *postgresProcessedEventRepo) MarkProcessed(ctx context.Context, consumer string, eventID uuid.UUID) error {
+	query := `INSERT INTO processed_events (consumer, event_id, processed_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, consumer, eventID, time.Now())
+
+	return err
+}
+
+func (r *postgresProcessedEventRepo) PurgeBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM processed_events WHERE processed_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+
+	return int(rowsAffected), err
+}
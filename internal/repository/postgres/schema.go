@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExpectedSchemaVersion is the number of the newest file in migrations/.
+// Bump it together with every new migration; a test keeps the two in sync.
+const ExpectedSchemaVersion = 15
+
+// CheckSchemaVersion reports whether the database has been migrated to the
+// version this build expects, as recorded by the migrate CLI in
+// schema_migrations. A dirty or older schema is an error; a newer one is
+// accepted, since migrations are applied before new code is rolled out.
+func CheckSchemaVersion(ctx context.Context, db *sqlx.DB) error {
+	var state struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+
+	err := db.GetContext(ctx, &state, `SELECT version, dirty FROM schema_migrations LIMIT 1`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no migration has been applied, want version %d", ExpectedSchemaVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	if state.Dirty {
+		return fmt.Errorf("migration %d failed halfway (dirty)", state.Version)
+	}
+
+	if state.Version < ExpectedSchemaVersion {
+		return fmt.Errorf("schema is at version %d, want %d", state.Version, ExpectedSchemaVersion)
+	}
+
+	return nil
+}
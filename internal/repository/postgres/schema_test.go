@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestExpectedSchemaVersionMatchesNewestMigration(t *testing.T) {
+	files, err := filepath.Glob("../../../migrations/*.up.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+
+	newest := 0
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			t.Fatalf("%s: migration file names must start with a number", filepath.Base(file))
+		}
+
+		newest = max(newest, version)
+	}
+
+	if newest != ExpectedSchemaVersion {
+		t.Errorf("ExpectedSchemaVersion = %d, but the newest migration is %d", ExpectedSchemaVersion, newest)
+	}
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// readStateColumns expects threads t, an optional thread_subscriptions s and
+// an optional thread_read_positions r for the user in $1. The user's own
+// posts never count as unread.
+const readStateColumns = `t.id AS thread_id,
+	s.created_at AS subscribed_at,
+	r.last_read_post_id,
+	r.last_read_at,
+	(SELECT COUNT(*) FROM posts p
+		WHERE p.thread_id = t.id AND p.user_id <> $1 AND (r.last_read_at IS NULL OR p.created_at > r.last_read_at)) AS unread_count,
+	(SELECT p.id FROM posts p
+		WHERE p.thread_id = t.id AND p.user_id <> $1 AND (r.last_read_at IS NULL OR p.created_at > r.last_read_at)
+		ORDER BY p.created_at ASC, p.id ASC LIMIT 1) AS first_unread_post_id,
+	COALESCE((SELECT MAX(p.created_at) FROM posts p WHERE p.thread_id = t.id), t.created_at) AS last_activity_at`
+
+type postgresSubscriptionRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresSubscriptionRepo(db *sqlx.DB) usecase.SubscriptionRepository {
+	return &postgresSubscriptionRepo{db: db}
+}
+
+func (r *postgresSubscriptionRepo) Subscribe(ctx context.Context, userID, threadID uuid.UUID) error {
+	query := `INSERT INTO thread_subscriptions (user_id, thread_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, threadID)
+
+	return err
+}
+
+func (r *postgresSubscriptionRepo) Unsubscribe(ctx context.Context, userID, threadID uuid.UUID) error {
+	query := `DELETE FROM thread_subscriptions WHERE user_id = $1 AND thread_id = $2`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, threadID)
+
+	return err
+}
+
+func (r *postgresSubscriptionRepo) GetByUserID(ctx context.Context, userID uuid.UUID, params usecase.PaginationParams) ([]*domain.ThreadReadState, error) {
+	states := []*domain.ThreadReadState{}
+
+	query := `SELECT ` + readStateColumns + `
+	FROM thread_subscriptions s
+	JOIN threads t ON t.id = s.thread_id AND t.deleted_at IS NULL
+	LEFT JOIN thread_read_positions r ON r.user_id = s.user_id AND r.thread_id = s.thread_id
+	WHERE s.user_id = $1
+	ORDER BY last_activity_at DESC
+	LIMIT $2 OFFSET $3`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &states, query, userID, params.Limit, params.Offset)
+
+	return states, err
+}
+
+func (r *postgresSubscriptionRepo) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM thread_subscriptions s JOIN threads t ON t.id = s.thread_id AND t.deleted_at IS NULL WHERE s.user_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, userID)
+	return count, err
+}
+
+// SaveReadPosition only ever moves a position forward, so a client that
+// reports an older post does not mark newer ones unread again.
+func (r *postgresSubscriptionRepo) SaveReadPosition(ctx context.Context, pos *domain.ThreadReadPosition) error {
+	query := `INSERT INTO thread_read_positions (user_id, thread_id, last_read_post_id, last_read_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (user_id, thread_id) DO UPDATE
+	SET last_read_post_id = EXCLUDED.last_read_post_id, last_read_at = EXCLUDED.last_read_at, updated_at = EXCLUDED.updated_at
+	WHERE thread_read_positions.last_read_at < EXCLUDED.last_read_at`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, pos.UserID, pos.ThreadID, pos.LastReadPostID, pos.LastReadAt, pos.UpdatedAt)
+
+	return err
+}
+
+func (r *postgresSubscriptionRepo) GetReadState(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadReadState, error) {
+	var state domain.ThreadReadState
+
+	query := `SELECT ` + readStateColumns + `
+	FROM threads t
+	LEFT JOIN thread_subscriptions s ON s.thread_id = t.id AND s.user_id = $1
+	LEFT JOIN thread_read_positions r ON r.thread_id = t.id AND r.user_id = $1
+	WHERE t.id = $2 AND t.deleted_at IS NULL`
+
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &state, query, userID, threadID)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+func (r *postgresSubscriptionRepo) GetUnreadByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*domain.ThreadReadState, error) {
+	states := []*domain.ThreadReadState{}
+
+	query := `SELECT * FROM (SELECT ` + readStateColumns + `
+		FROM thread_subscriptions s
+		JOIN threads t ON t.id = s.thread_id AND t.deleted_at IS NULL
+		LEFT JOIN thread_read_positions r ON r.user_id = s.user_id AND r.thread_id = s.thread_id
+		WHERE s.user_id = $1) states
+	WHERE unread_count > 0
+	ORDER BY last_activity_at DESC
+	LIMIT $2`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &states, query, userID, limit)
+
+	return states, err
+}
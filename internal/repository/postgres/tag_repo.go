@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type postgresTagRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresTagRepo(db *sqlx.DB) usecase.TagRepository {
+	return &postgresTagRepo{db: db}
+}
+
+func (r *postgresTagRepo) UpsertByNames(ctx context.Context, names []string) ([]*domain.Tag, error) {
+	tags := []*domain.Tag{}
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	insert := `INSERT INTO tags (id, name, created_at) VALUES ($1, $2, $3) ON CONFLICT (name) DO NOTHING`
+	now := time.Now()
+	for _, name := range names {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, insert, uuid.New(), name, now); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM tags WHERE name IN (?)`, names)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.Rebind(query)
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &tags, query, args...)
+
+	return tags, err
+}
+
+func (r *postgresTagRepo) GetByName(ctx context.Context, name string) (*domain.Tag, error) {
+	var tag domain.Tag
+
+	query := `SELECT * FROM tags WHERE name = $1`
+
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &tag, query, name)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+type threadTagRow struct {
+	ThreadID uuid.UUID `db:"thread_id"`
+	domain.Tag
+}
+
+func (r *postgresTagRepo) GetByThreadIDs(ctx context.Context, threadIDs []uuid.UUID) (map[uuid.UUID][]*domain.Tag, error) {
+	tagMap := make(map[uuid.UUID][]*domain.Tag)
+	if len(threadIDs) == 0 {
+		return tagMap, nil
+	}
+
+	rows := []*threadTagRow{}
+	query, args, err := sqlx.In(`SELECT DISTINCT tt.thread_id, t.id, t.name, t.created_at
+	FROM thread_tags tt JOIN tags t ON t.id = tt.tag_id
+	WHERE tt.thread_id IN (?)
+	ORDER BY t.name`, threadIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.Rebind(query)
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		tag := row.Tag
+		tagMap[row.ThreadID] = append(tagMap[row.ThreadID], &tag)
+	}
+
+	return tagMap, nil
+}
+
+// Search returns tags starting with prefix, most used first.
+func (r *postgresTagRepo) Search(ctx context.Context, prefix string, limit int) ([]*domain.Tag, error) {
+	tags := []*domain.Tag{}
+
+	query := `SELECT t.id, t.name, t.created_at, COUNT(DISTINCT th.id) AS thread_count
+	FROM tags t
+	LEFT JOIN thread_tags tt ON tt.tag_id = t.id
+	LEFT JOIN threads th ON th.id = tt.thread_id AND th.deleted_at IS NULL
+	WHERE t.name LIKE $1
+	GROUP BY t.id
+	ORDER BY thread_count DESC, t.name ASC
+	LIMIT $2`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &tags, query, escapeLike(prefix)+"%", limit)
+
+	return tags, err
+}
+
+func (r *postgresTagRepo) ReplaceThreadTags(ctx context.Context, threadID uuid.UUID, source string, tagIDs []uuid.UUID) error {
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM thread_tags WHERE thread_id = $1 AND source = $2`, threadID, source); err != nil {
+			return err
+		}
+
+		insert := `INSERT INTO thread_tags (thread_id, tag_id, source) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+		for _, tagID := range tagIDs {
+			if _, err := tx.ExecContext(ctx, insert, threadID, tagID, source); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *postgresTagRepo) ReplacePostTags(ctx context.Context, postID uuid.UUID, tagIDs []uuid.UUID) error {
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE post_id = $1`, postID); err != nil {
+			return err
+		}
+
+		insert := `INSERT INTO post_tags (post_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+		for _, tagID := range tagIDs {
+			if _, err := tx.ExecContext(ctx, insert, postID, tagID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *postgresTagRepo) Rename(ctx context.Context, id uuid.UUID, name string) error {
+	query := `UPDATE tags SET name = $1 WHERE id = $2`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, name, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrConflict
+		}
+
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Merge moves every thread and post tagged with the source tag over to the
+// target tag, then deletes the source tag.
+func (r *postgresTagRepo) Merge(ctx context.Context, sourceID, targetID uuid.UUID) error {
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		statements := []string{
+			`INSERT INTO thread_tags (thread_id, tag_id, source) SELECT thread_id, $2, source FROM thread_tags WHERE tag_id = $1 ON CONFLICT DO NOTHING`,
+			`INSERT INTO post_tags (post_id, tag_id) SELECT post_id, $2 FROM post_tags WHERE tag_id = $1 ON CONFLICT DO NOTHING`,
+		}
+		for _, stmt := range statements {
+			if _, err := tx.ExecContext(ctx, stmt, sourceID, targetID); err != nil {
+				return err
+			}
+		}
+
+		res, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, sourceID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+
+		return nil
+	})
+}
@@ -3,6 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -19,17 +22,20 @@ func NewPostgresThreadRepo(db *sqlx.DB) usecase.ThreadRepository {
 }
 
 func (r *postgresThreadRepo) Create(ctx context.Context, thread *domain.Thread) error {
-	query := `INSERT INTO threads (id, title, slug, content, user_id, category_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
-	_, err := r.db.ExecContext(ctx, query, thread.ID, thread.Title, thread.Slug, thread.Content, thread.UserID, thread.CategoryID, thread.CreatedAt)
+	query := `INSERT INTO threads (id, title, slug, content, content_html, user_id, category_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, thread.ID, thread.Title, thread.Slug, thread.Content, thread.ContentHTML, thread.UserID, thread.CategoryID, thread.CreatedAt)
 
 	return err
 }
 
-func (r *postgresThreadRepo) GetAll(ctx context.Context, params usecase.PaginationParams) ([]*domain.Thread, error) {
+func (r *postgresThreadRepo) GetAll(ctx context.Context, filter usecase.ThreadFilter, params usecase.PaginationParams) ([]*domain.Thread, error) {
 	var threads []*domain.Thread
 
-	query := `SELECT * FROM threads ORDER BY is_pinned DESC, created_at DESC LIMIT $1 OFFSET $2`
-	err := r.db.SelectContext(ctx, &threads, query, params.Limit, params.Offset)
+	where, args := buildThreadWhere(filter)
+	args = append(args, params.Limit, params.Offset)
+
+	query := fmt.Sprintf(`SELECT * FROM threads %s ORDER BY is_pinned DESC, created_at DESC LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &threads, query, args...)
 
 	return threads, err
 }
@@ -37,9 +43,9 @@ func (r *postgresThreadRepo) GetAll(ctx context.Context, params usecase.Paginati
 func (r *postgresThreadRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Thread, error) {
 	var thread domain.Thread
 
-	query := `SELECT * FROM threads WHERE id = $1`
+	query := `SELECT * FROM threads WHERE id = $1 AND deleted_at IS NULL`
 
-	err := r.db.GetContext(ctx, &thread, query, id)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &thread, query, id)
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
 	}
@@ -47,29 +53,53 @@ func (r *postgresThreadRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain
 	return &thread, err
 }
 
-func (r *postgresThreadRepo) UpdateVoteCount(ctx context.Context, tx *sqlx.Tx, threadID uuid.UUID, delta int) error {
-	query := `UPDATE threads SET vote_count = vote_count + $1 WHERE id = $2`
-	if tx != nil {
-		_, err := tx.ExecContext(ctx, query, delta, threadID)
-		return err
-	}
+func (r *postgresThreadRepo) UpdateVoteCount(ctx context.Context, threadID uuid.UUID, delta int) (int, error) {
+	var count int
+	query := `UPDATE threads SET vote_count = vote_count + $1 WHERE id = $2 RETURNING vote_count`
 
-	_, err := r.db.ExecContext(ctx, query, delta, threadID)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, delta, threadID)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrNotFound
+	}
 
-	return err
+	return count, err
 }
 
-func (r *postgresThreadRepo) CountAll(ctx context.Context) (int, error) {
+func (r *postgresThreadRepo) CountAll(ctx context.Context, filter usecase.ThreadFilter) (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM threads`
-	err := r.db.GetContext(ctx, &count, query)
+	where, args := buildThreadWhere(filter)
+	query := `SELECT COUNT(*) FROM threads ` + where
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, args...)
 	return count, err
 }
 
+func buildThreadWhere(filter usecase.ThreadFilter) (string, []interface{}) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := make([]interface{}, 0, len(filter.Tags)+3)
+
+	if len(filter.Tags) > 0 {
+		placeholders := make([]string, len(filter.Tags))
+		for i, tag := range filter.Tags {
+			args = append(args, tag)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+
+		tagged := `FROM thread_tags tt JOIN tags tg ON tg.id = tt.tag_id WHERE tt.thread_id = threads.id AND tg.name IN (` + strings.Join(placeholders, ", ") + `)`
+		if filter.MatchAll {
+			args = append(args, len(filter.Tags))
+			conditions = append(conditions, fmt.Sprintf("(SELECT COUNT(DISTINCT tg.name) %s) = $%d", tagged, len(args)))
+		} else {
+			conditions = append(conditions, "EXISTS (SELECT 1 "+tagged+")")
+		}
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
 func (r *postgresThreadRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM threads WHERE id = $1`
+	query := `UPDATE threads SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
 
-	res, err := r.db.ExecContext(ctx, query, id)
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
 		return err
 	}
@@ -87,9 +117,172 @@ func (r *postgresThreadRepo) Delete(ctx context.Context, id uuid.UUID) error {
 }
 
 func (r *postgresThreadRepo) Update(ctx context.Context, thread *domain.Thread) error {
-	query := `UPDATE threads SET title = $1, slug = $2, content = $3, updated_at = $4 WHERE id = $5`
+	query := `UPDATE threads SET title = $1, slug = $2, content = $3, content_html = $4, updated_at = $5 WHERE id = $6 AND deleted_at IS NULL`
 
-	_, err := r.db.ExecContext(ctx, query, thread.Title, thread.Slug, thread.Content, thread.UpdatedAt, thread.ID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, thread.Title, thread.Slug, thread.Content, thread.ContentHTML, thread.UpdatedAt, thread.ID)
 
 	return err
 }
+
+func (r *postgresThreadRepo) GetDeleted(ctx context.Context, params usecase.PaginationParams) ([]*domain.Thread, error) {
+	var threads []*domain.Thread
+
+	query := `SELECT * FROM threads WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT $1 OFFSET $2`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &threads, query, params.Limit, params.Offset)
+
+	return threads, err
+}
+
+func (r *postgresThreadRepo) CountDeleted(ctx context.Context) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM threads WHERE deleted_at IS NOT NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query)
+	return count, err
+}
+
+func (r *postgresThreadRepo) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE threads SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresThreadRepo) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	query := `DELETE FROM threads WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (r *postgresThreadRepo) SetLocked(ctx context.Context, id uuid.UUID, locked bool) error {
+	query := `UPDATE threads SET is_locked = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, locked, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetByTagID returns threads tagged directly or through one of their posts.
+func (r *postgresThreadRepo) GetByTagID(ctx context.Context, tagID uuid.UUID, params usecase.PaginationParams) ([]*domain.Thread, error) {
+	var threads []*domain.Thread
+
+	query := `SELECT t.* FROM threads t
+	WHERE t.deleted_at IS NULL AND (
+		EXISTS (SELECT 1 FROM thread_tags tt WHERE tt.thread_id = t.id AND tt.tag_id = $1)
+		OR EXISTS (SELECT 1 FROM post_tags pt JOIN posts p ON p.id = pt.post_id WHERE p.thread_id = t.id AND pt.tag_id = $1)
+	)
+	ORDER BY t.created_at DESC
+	LIMIT $2 OFFSET $3`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &threads, query, tagID, params.Limit, params.Offset)
+
+	return threads, err
+}
+
+func (r *postgresThreadRepo) CountByTagID(ctx context.Context, tagID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM threads t
+	WHERE t.deleted_at IS NULL AND (
+		EXISTS (SELECT 1 FROM thread_tags tt WHERE tt.thread_id = t.id AND tt.tag_id = $1)
+		OR EXISTS (SELECT 1 FROM post_tags pt JOIN posts p ON p.id = pt.post_id WHERE p.thread_id = t.id AND pt.tag_id = $1)
+	)`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, tagID)
+	return count, err
+}
+
+// GetMentioning returns threads in which the user is mentioned, most recent
+// mention first.
+func (r *postgresThreadRepo) GetMentioning(ctx context.Context, userID uuid.UUID, params usecase.PaginationParams) ([]*domain.Thread, error) {
+	var threads []*domain.Thread
+
+	query := `SELECT t.* FROM threads t
+	JOIN (SELECT thread_id, MAX(created_at) AS mentioned_at FROM mentions WHERE user_id = $1 GROUP BY thread_id) m ON m.thread_id = t.id
+	WHERE t.deleted_at IS NULL
+	ORDER BY m.mentioned_at DESC
+	LIMIT $2 OFFSET $3`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &threads, query, userID, params.Limit, params.Offset)
+
+	return threads, err
+}
+
+func (r *postgresThreadRepo) CountMentioning(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(DISTINCT m.thread_id) FROM mentions m JOIN threads t ON t.id = m.thread_id WHERE m.user_id = $1 AND t.deleted_at IS NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, userID)
+	return count, err
+}
+
+func (r *postgresThreadRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Thread, error) {
+	threads := []*domain.Thread{}
+	query, args, err := sqlx.In(`SELECT * FROM threads WHERE id IN (?) AND deleted_at IS NULL`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.Rebind(query)
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &threads, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	threadMap := make(map[uuid.UUID]*domain.Thread)
+	for _, thread := range threads {
+		threadMap[thread.ID] = thread
+	}
+
+	return threadMap, nil
+}
+
+func (r *postgresThreadRepo) GetTop(ctx context.Context, categoryIDs []uuid.UUID, since time.Time, limit int) ([]*domain.Thread, error) {
+	threads := []*domain.Thread{}
+
+	query := `SELECT * FROM threads WHERE deleted_at IS NULL AND created_at >= ? ORDER BY vote_count DESC, created_at DESC LIMIT ?`
+	args := []interface{}{since, limit}
+	if len(categoryIDs) > 0 {
+		query = `SELECT * FROM threads WHERE deleted_at IS NULL AND created_at >= ? AND category_id IN (?) ORDER BY vote_count DESC, created_at DESC LIMIT ?`
+		args = []interface{}{since, categoryIDs, limit}
+	}
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.Rebind(query)
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &threads, query, args...)
+
+	return threads, err
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -22,7 +23,7 @@ func (r *postgresUserRepo) GetByEmail(ctx context.Context, email string) (*domai
 	var user domain.User
 	query := `SELECT id, username, email, password_hash, avatar_url, role, created_at FROM users WHERE email = $1`
 
-	err := r.db.GetContext(ctx, &user, query, email)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &user, query, email)
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
 	}
@@ -37,7 +38,10 @@ func (r *postgresUserRepo) GetByEmail(ctx context.Context, email string) (*domai
 func (r *postgresUserRepo) Create(ctx context.Context, user *domain.User) error {
 	query := `INSERT INTO users (id, username, email, password_hash, avatar_url, role, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
-	_, err := r.db.ExecContext(ctx, query, user.ID, user.Username, user.Email, user.PasswordHash, user.AvatarURL, user.Role, user.CreatedAt)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, user.ID, user.Username, user.Email, user.PasswordHash, user.AvatarURL, user.Role, user.CreatedAt)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
 
 	return err
 }
@@ -46,7 +50,7 @@ func (r *postgresUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.U
 	var user domain.User
 	query := `SELECT id, username, email, password_hash, avatar_url, role, created_at FROM users WHERE id = $1`
 
-	err := r.db.GetContext(ctx, &user, query, id)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &user, query, id)
 
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
@@ -67,7 +71,7 @@ func (r *postgresUserRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[u
 	}
 
 	query = r.db.Rebind(query)
-	err = r.db.SelectContext(ctx, &users, query, args...)
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &users, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +89,48 @@ func (r *postgresUserRepo) GetUsers(ctx context.Context) ([]*domain.User, error)
 
 	query := `SELECT * FROM users WHERE NOT role = 'admin' ORDER BY created_at ASC`
 
-	err := r.db.SelectContext(ctx, &users, query)
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &users, query)
 
 	return users, err
 }
+
+func (r *postgresUserRepo) UpdateRole(ctx context.Context, id uuid.UUID, role string) error {
+	query := `UPDATE users SET role = $1 WHERE id = $2`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, role, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresUserRepo) GetByUsernames(ctx context.Context, usernames []string) (map[string]*domain.User, error) {
+	users := []*domain.User{}
+	query, args, err := sqlx.In(`SELECT id, username, email, avatar_url, role, created_at FROM users WHERE LOWER(username) IN (?)`, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.Rebind(query)
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &users, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	userMap := make(map[string]*domain.User)
+	for _, user := range users {
+		userMap[strings.ToLower(user.Username)] = user
+	}
+
+	return userMap, nil
+}
@@ -19,24 +19,26 @@ func NewPostgresVoteRepo(db *sqlx.DB) usecase.VoteRepository {
 	return &postgresVoteRepo{db: db}
 }
 
-func (r *postgresVoteRepo) DeleteThreadVote(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, threadID uuid.UUID) error {
+func (r *postgresVoteRepo) DeleteThreadVote(ctx context.Context, userID uuid.UUID, threadID uuid.UUID) error {
 	query := `DELETE FROM thread_votes WHERE user_id = $1 AND thread_id = $2`
-	if tx != nil {
-		_, err := tx.ExecContext(ctx, query, userID, threadID)
-		return err
-	}
 
-	_, err := r.db.ExecContext(ctx, query, userID, threadID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, threadID)
 
 	return err
 }
 
-func (r *postgresVoteRepo) GetThreadVote(ctx context.Context, userID uuid.UUID, threadID uuid.UUID) (*domain.ThreadVote, error) {
+// GetThreadVoteForUpdate takes an advisory lock rather than a row lock, since
+// a vote that does not exist yet has no row to lock.
+func (r *postgresVoteRepo) GetThreadVoteForUpdate(ctx context.Context, userID uuid.UUID, threadID uuid.UUID) (*domain.ThreadVote, error) {
+	if err := lockVote(ctx, r.db, "thread_votes", userID, threadID); err != nil {
+		return nil, err
+	}
+
 	var vote domain.ThreadVote
 
 	query := `SELECT user_id, thread_id, vote_type, created_at FROM thread_votes WHERE user_id = $1 AND thread_id = $2`
 
-	err := r.db.GetContext(ctx, &vote, query, userID, threadID)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &vote, query, userID, threadID)
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
 	}
@@ -44,36 +46,30 @@ func (r *postgresVoteRepo) GetThreadVote(ctx context.Context, userID uuid.UUID,
 	return &vote, err
 }
 
-func (r *postgresVoteRepo) UpsertThreadVote(ctx context.Context, tx *sqlx.Tx, vote *domain.ThreadVote) error {
+func (r *postgresVoteRepo) UpsertThreadVote(ctx context.Context, vote *domain.ThreadVote) error {
 	query := `INSERT INTO thread_votes (user_id, thread_id, vote_type, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT (user_id, thread_id) DO UPDATE SET vote_type = EXCLUDED.vote_type`
 
 	vote.CreatedAt = time.Now()
 
-	if tx != nil {
-		_, err := tx.ExecContext(ctx, query, vote.UserID, vote.ThreadID, vote.VoteType, vote.CreatedAt)
-
-		return err
-	}
-
-	_, err := r.db.ExecContext(ctx, query, vote.UserID, vote.ThreadID, vote.VoteType, vote.CreatedAt)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, vote.UserID, vote.ThreadID, vote.VoteType, vote.CreatedAt)
 	return err
 }
 
-func (r *postgresVoteRepo) DeletePostVote(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, postID uuid.UUID) error {
+func (r *postgresVoteRepo) DeletePostVote(ctx context.Context, userID uuid.UUID, postID uuid.UUID) error {
 	query := `DELETE FROM post_votes WHERE user_id = $1 AND post_id = $2`
-	if tx != nil {
-		_, err := tx.ExecContext(ctx, query, userID, postID)
-		return err
-	}
 
-	_, err := r.db.ExecContext(ctx, query, userID, postID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, postID)
 	return err
 }
 
-func (r *postgresVoteRepo) GetPostVote(ctx context.Context, userID uuid.UUID, postID uuid.UUID) (*domain.ThreadVote, error) {
+func (r *postgresVoteRepo) GetPostVoteForUpdate(ctx context.Context, userID uuid.UUID, postID uuid.UUID) (*domain.ThreadVote, error) {
+	if err := lockVote(ctx, r.db, "post_votes", userID, postID); err != nil {
+		return nil, err
+	}
+
 	var vote domain.ThreadVote
 	query := `SELECT user_id, post_id as thread_id, vote_type, created_at FROM post_votes WHERE user_id = $1 AND post_id = $2`
-	err := r.db.GetContext(ctx, &vote, query, userID, postID)
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &vote, query, userID, postID)
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
 	}
@@ -81,7 +77,7 @@ func (r *postgresVoteRepo) GetPostVote(ctx context.Context, userID uuid.UUID, po
 	return &vote, err
 }
 
-func (r *postgresVoteRepo) UpsertPostVote(ctx context.Context, tx *sqlx.Tx, vote *domain.ThreadVote) error {
+func (r *postgresVoteRepo) UpsertPostVote(ctx context.Context, vote *domain.ThreadVote) error {
 	query := `
 	INSERT INTO post_votes (user_id, post_id, vote_type, created_at)
 	VALUES ($1, $2, $3, $4)
@@ -90,11 +86,17 @@ func (r *postgresVoteRepo) UpsertPostVote(ctx context.Context, tx *sqlx.Tx, vote
 
 	vote.CreatedAt = time.Now()
 
-	if tx != nil {
-		_, err := tx.ExecContext(ctx, query, vote.UserID, vote.ThreadID, vote.VoteType, vote.CreatedAt)
-		return err
-	}
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, vote.UserID, vote.ThreadID, vote.VoteType, vote.CreatedAt)
+	return err
+}
+
+// lockVote locks the user's vote on the target until the transaction in ctx
+// ends. It is taken in its own statement so the read that follows sees
+// whatever the previous holder committed.
+func lockVote(ctx context.Context, db *sqlx.DB, table string, userID, targetID uuid.UUID) error {
+	query := `SELECT pg_advisory_xact_lock(hashtext($1), hashtext($2::text || ':' || $3::text))`
+
+	_, err := conn(ctx, db).ExecContext(ctx, query, table, userID, targetID)
 
-	_, err := r.db.ExecContext(ctx, query, vote.UserID, vote.ThreadID, vote.VoteType, vote.CreatedAt)
 	return err
 }
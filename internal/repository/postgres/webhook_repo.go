@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type postgresWebhookRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresWebhookRepo(db *sqlx.DB) usecase.WebhookRepository {
+	return &postgresWebhookRepo{db: db}
+}
+
+func (r *postgresWebhookRepo) Create(ctx context.Context, webhook *domain.Webhook) error {
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		query := `INSERT INTO webhooks (id, url, secret, description, is_active, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		if _, err := tx.ExecContext(ctx, query, webhook.ID, webhook.URL, webhook.Secret, webhook.Description, webhook.IsActive, webhook.CreatedBy, webhook.CreatedAt); err != nil {
+			return err
+		}
+
+		if err := insertWebhookEvents(ctx, tx, webhook); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func insertWebhookEvents(ctx context.Context, tx sqlx.ExtContext, webhook *domain.Webhook) error {
+	query := `INSERT INTO webhook_events (webhook_id, event_type) VALUES ($1, $2)`
+	for _, eventType := range webhook.Events {
+		if _, err := tx.ExecContext(ctx, query, webhook.ID, eventType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *postgresWebhookRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &webhook, `SELECT * FROM webhooks WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachEvents(ctx, []*domain.Webhook{&webhook}); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *postgresWebhookRepo) GetAll(ctx context.Context) ([]*domain.Webhook, error) {
+	webhooks := []*domain.Webhook{}
+
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &webhooks, `SELECT * FROM webhooks ORDER BY created_at ASC`); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachEvents(ctx, webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *postgresWebhookRepo) GetActiveByEvent(ctx context.Context, eventType string) ([]*domain.Webhook, error) {
+	webhooks := []*domain.Webhook{}
+
+	query := `SELECT w.* FROM webhooks w
+	JOIN webhook_events e ON e.webhook_id = w.id
+	WHERE w.is_active AND e.event_type = $1`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &webhooks, query, eventType)
+
+	return webhooks, err
+}
+
+func (r *postgresWebhookRepo) attachEvents(ctx context.Context, webhooks []*domain.Webhook) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(webhooks))
+	byID := make(map[uuid.UUID]*domain.Webhook, len(webhooks))
+	for i, w := range webhooks {
+		ids[i] = w.ID
+		w.Events = []string{}
+		byID[w.ID] = w
+	}
+
+	var rows []struct {
+		WebhookID uuid.UUID `db:"webhook_id"`
+		EventType string    `db:"event_type"`
+	}
+
+	query, args, err := sqlx.In(`SELECT webhook_id, event_type FROM webhook_events WHERE webhook_id IN (?) ORDER BY event_type`, ids)
+	if err != nil {
+		return err
+	}
+
+	query = r.db.Rebind(query)
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &rows, query, args...); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		w := byID[row.WebhookID]
+		w.Events = append(w.Events, row.EventType)
+	}
+
+	return nil
+}
+
+func (r *postgresWebhookRepo) Update(ctx context.Context, webhook *domain.Webhook) error {
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+
+		query := `UPDATE webhooks SET url = $1, secret = $2, description = $3, is_active = $4, updated_at = $5 WHERE id = $6`
+		res, err := tx.ExecContext(ctx, query, webhook.URL, webhook.Secret, webhook.Description, webhook.IsActive, webhook.UpdatedAt, webhook.ID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_events WHERE webhook_id = $1`, webhook.ID); err != nil {
+			return err
+		}
+
+		if err := insertWebhookEvents(ctx, tx, webhook); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *postgresWebhookRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresWebhookRepo) CreateDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, next_attempt_at, redelivery_of, created_at)
+	VALUES (:id, :webhook_id, :event_id, :event_type, :payload, :status, :next_attempt_at, :redelivery_of, :created_at)
+	ON CONFLICT (webhook_id, event_id) WHERE redelivery_of IS NULL DO NOTHING`
+
+	_, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, deliveries)
+
+	return err
+}
+
+func (r *postgresWebhookRepo) GetDeliveryByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &delivery, `SELECT * FROM webhook_deliveries WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+
+	return &delivery, err
+}
+
+func (r *postgresWebhookRepo) GetDeliveriesByWebhookID(ctx context.Context, webhookID uuid.UUID, params usecase.PaginationParams) ([]*domain.WebhookDelivery, error) {
+	deliveries := []*domain.WebhookDelivery{}
+
+	query := `SELECT * FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &deliveries, query, webhookID, params.Limit, params.Offset)
+
+	return deliveries, err
+}
+
+func (r *postgresWebhookRepo) CountDeliveriesByWebhookID(ctx context.Context, webhookID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &count, query, webhookID)
+	return count, err
+}
+
+func (r *postgresWebhookRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	deliveries := []*domain.WebhookDelivery{}
+
+	query := `UPDATE webhook_deliveries SET next_attempt_at = $1
+	WHERE id IN (
+		SELECT d.id FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id AND w.is_active
+		WHERE d.status = 'pending' AND d.next_attempt_at <= $2
+		ORDER BY d.next_attempt_at ASC
+		LIMIT $3
+		FOR UPDATE OF d SKIP LOCKED)
+	RETURNING *`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &deliveries, query, now.Add(lease), now, limit)
+
+	return deliveries, err
+}
+
+func (r *postgresWebhookRepo) SaveDeliveryResult(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries
+	SET status = :status, attempts = :attempts, next_attempt_at = :next_attempt_at, last_attempt_at = :last_attempt_at,
+		response_status = :response_status, response_body = :response_body, last_error = :last_error, delivered_at = :delivered_at
+	WHERE id = :id`
+
+	_, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, delivery)
+
+	return err
+}
+
+func (r *postgresWebhookRepo) PurgeDeliveriesBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	query := `DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+
+	return int(rowsAffected), err
+}
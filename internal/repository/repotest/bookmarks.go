@@ -0,0 +1,281 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+func testBookmarks(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	thread := newThread(user, category, base)
+	trashed := newThread(user, category, base)
+	mustCreateThreads(t, r, thread, trashed)
+	post := newPost(user, thread, nil, base)
+	mustCreatePosts(t, r, post)
+	folder := newBookmarkFolder(user, "Reading", base)
+	mustCreateFolders(t, r, folder)
+
+	saved := newBookmark(user, thread, nil, base)
+	mustUpsertBookmark(t, r, saved)
+
+	// Saving the thread again keeps the original row and replaces the rest.
+	note := "read later"
+	again := newBookmark(user, thread, nil, base.Add(time.Second))
+	again.FolderID = &folder.ID
+	again.Note = &note
+	mustUpsertBookmark(t, r, again)
+
+	if again.ID != saved.ID || !again.CreatedAt.Equal(saved.CreatedAt) || !again.UpdatedAt.Equal(base.Add(time.Second)) ||
+		again.FolderID == nil || *again.FolderID != folder.ID || again.Note == nil || *again.Note != note {
+		t.Errorf("Upsert of a saved thread returned %+v, want %s with the new folder and note", again, saved.ID)
+	}
+
+	onPost := newBookmark(user, thread, &post.ID, base.Add(2*time.Second))
+	onTrashed := newBookmark(user, trashed, nil, base.Add(3*time.Second))
+	mustUpsertBookmark(t, r, onPost)
+	mustUpsertBookmark(t, r, onTrashed)
+
+	if err := r.Threads.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete thread: %v", err)
+	}
+
+	filters := []struct {
+		name   string
+		filter usecase.BookmarkFilter
+		want   []uuid.UUID
+	}{
+		{"all", usecase.BookmarkFilter{}, []uuid.UUID{onPost.ID, saved.ID}},
+		{"in a folder", usecase.BookmarkFilter{FolderID: &folder.ID}, []uuid.UUID{saved.ID}},
+		{"unfiled", usecase.BookmarkFilter{Unfiled: true}, []uuid.UUID{onPost.ID}},
+		{"threads", usecase.BookmarkFilter{Type: domain.BookmarkTypeThread}, []uuid.UUID{saved.ID}},
+		{"posts in a folder", usecase.BookmarkFilter{FolderID: &folder.ID, Type: domain.BookmarkTypePost}, nil},
+	}
+
+	for _, f := range filters {
+		bookmarks, err := r.Bookmarks.GetByUserID(ctx, user.ID, f.filter, usecase.PaginationParams{Limit: 10})
+		if err != nil {
+			t.Fatalf("GetByUserID %s: %v", f.name, err)
+		}
+
+		assertBookmarkIDs(t, "GetByUserID "+f.name, bookmarks, f.want...)
+
+		if count, err := r.Bookmarks.CountByUserID(ctx, user.ID, f.filter); err != nil || count != len(f.want) {
+			t.Errorf("CountByUserID %s: got %d, %v, want %d", f.name, count, err, len(f.want))
+		}
+	}
+
+	page, err := r.Bookmarks.GetByUserID(ctx, user.ID, usecase.BookmarkFilter{}, usecase.PaginationParams{Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	assertBookmarkIDs(t, "GetByUserID second page", page, saved.ID)
+
+	threads, err := r.Bookmarks.GetBookmarkedThreadIDs(ctx, user.ID, []uuid.UUID{thread.ID, trashed.ID, uuid.New()})
+	if err != nil {
+		t.Fatalf("GetBookmarkedThreadIDs: %v", err)
+	}
+
+	if len(threads) != 2 || !threads[thread.ID] || !threads[trashed.ID] {
+		t.Errorf("GetBookmarkedThreadIDs returned %v, want %s and %s", threads, thread.ID, trashed.ID)
+	}
+
+	posts, err := r.Bookmarks.GetBookmarkedPostIDs(ctx, user.ID, []uuid.UUID{post.ID, uuid.New()})
+	if err != nil {
+		t.Fatalf("GetBookmarkedPostIDs: %v", err)
+	}
+
+	if len(posts) != 1 || !posts[post.ID] {
+		t.Errorf("GetBookmarkedPostIDs returned %v, want only %s", posts, post.ID)
+	}
+
+	if ids, err := r.Bookmarks.GetBookmarkedThreadIDs(ctx, uuid.New(), []uuid.UUID{thread.ID}); err != nil || len(ids) != 0 {
+		t.Errorf("GetBookmarkedThreadIDs of another user: got %v, %v, want none", ids, err)
+	}
+
+	// Deleting the thread bookmark leaves the post bookmark in the thread.
+	if err := r.Bookmarks.Delete(ctx, user.ID, thread.ID, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if err := r.Bookmarks.Delete(ctx, user.ID, thread.ID, nil); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Delete of a deleted bookmark: got %v, want ErrNotFound", err)
+	}
+
+	if count, err := r.Bookmarks.CountByUserID(ctx, user.ID, usecase.BookmarkFilter{}); err != nil || count != 1 {
+		t.Errorf("CountByUserID after Delete: got %d, %v, want 1", count, err)
+	}
+
+	if err := r.Bookmarks.Delete(ctx, user.ID, thread.ID, &post.ID); err != nil {
+		t.Fatalf("Delete of a post bookmark: %v", err)
+	}
+
+	if err := r.Threads.Restore(ctx, trashed.ID); err != nil {
+		t.Fatalf("Restore thread: %v", err)
+	}
+
+	bookmarks, err := r.Bookmarks.GetByUserID(ctx, user.ID, usecase.BookmarkFilter{}, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	assertBookmarkIDs(t, "GetByUserID after restoring a thread", bookmarks, onTrashed.ID)
+}
+
+func testBookmarkFolders(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	other := newUser("other", "member", base)
+	mustCreateUsers(t, r, other)
+	thread := newThread(user, category, base)
+	trashed := newThread(user, category, base)
+	mustCreateThreads(t, r, thread, trashed)
+
+	if _, err := r.Bookmarks.GetFolderByID(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetFolderByID of unknown folder: got %v, want ErrNotFound", err)
+	}
+
+	reading := newBookmarkFolder(user, "reading", base)
+	archive := newBookmarkFolder(user, "Archive", base)
+	mustCreateFolders(t, r, reading, archive, newBookmarkFolder(other, "Reading", base))
+
+	if err := r.Bookmarks.CreateFolder(ctx, newBookmarkFolder(user, "READING", base)); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("CreateFolder with a taken name in another case: got %v, want ErrConflict", err)
+	}
+
+	for _, th := range []*domain.Thread{thread, trashed} {
+		bookmark := newBookmark(user, th, nil, base)
+		bookmark.FolderID = &reading.ID
+		mustUpsertBookmark(t, r, bookmark)
+	}
+
+	if err := r.Threads.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete thread: %v", err)
+	}
+
+	folders, err := r.Bookmarks.GetFoldersByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetFoldersByUserID: %v", err)
+	}
+
+	assertFolderIDs(t, "GetFoldersByUserID", folders, archive.ID, reading.ID)
+
+	if folders[1].BookmarkCount != 1 || folders[0].BookmarkCount != 0 {
+		t.Errorf("GetFoldersByUserID counted %d and %d bookmarks, want 0 and 1 leaving out the trashed thread", folders[0].BookmarkCount, folders[1].BookmarkCount)
+	}
+
+	got, err := r.Bookmarks.GetFolderByID(ctx, reading.ID)
+	if err != nil {
+		t.Fatalf("GetFolderByID: %v", err)
+	}
+
+	if got.UserID != user.ID || got.Name != reading.Name || !got.CreatedAt.Equal(reading.CreatedAt) || got.BookmarkCount != 1 {
+		t.Errorf("GetFolderByID returned %+v, want %+v with one bookmark", got, reading)
+	}
+
+	if count, err := r.Bookmarks.CountFoldersByUserID(ctx, user.ID); err != nil || count != 2 {
+		t.Errorf("CountFoldersByUserID: got %d, %v, want 2", count, err)
+	}
+
+	if err := r.Bookmarks.RenameFolder(ctx, archive.ID, "Reading"); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("RenameFolder to a taken name: got %v, want ErrConflict", err)
+	}
+
+	if err := r.Bookmarks.RenameFolder(ctx, reading.ID, "Reading"); err != nil {
+		t.Errorf("RenameFolder to its own name in another case: %v", err)
+	}
+
+	if err := r.Bookmarks.RenameFolder(ctx, uuid.New(), "Elsewhere"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("RenameFolder of unknown folder: got %v, want ErrNotFound", err)
+	}
+
+	if got, err := r.Bookmarks.GetFolderByID(ctx, reading.ID); err != nil || got.Name != "Reading" {
+		t.Errorf("GetFolderByID after RenameFolder: got %+v, %v, want Reading", got, err)
+	}
+
+	// Deleting a folder leaves its bookmarks unfiled.
+	if err := r.Bookmarks.DeleteFolder(ctx, reading.ID); err != nil {
+		t.Fatalf("DeleteFolder: %v", err)
+	}
+
+	if err := r.Bookmarks.DeleteFolder(ctx, reading.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("DeleteFolder of a deleted folder: got %v, want ErrNotFound", err)
+	}
+
+	if count, err := r.Bookmarks.CountByUserID(ctx, user.ID, usecase.BookmarkFilter{Unfiled: true}); err != nil || count != 1 {
+		t.Errorf("CountByUserID unfiled after DeleteFolder: got %d, %v, want 1", count, err)
+	}
+
+	if count, err := r.Bookmarks.CountFoldersByUserID(ctx, user.ID); err != nil || count != 1 {
+		t.Errorf("CountFoldersByUserID after DeleteFolder: got %d, %v, want 1", count, err)
+	}
+}
+
+func newBookmark(user *domain.User, thread *domain.Thread, postID *uuid.UUID, at time.Time) *domain.Bookmark {
+	return &domain.Bookmark{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		ThreadID:  thread.ID,
+		PostID:    postID,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+}
+
+func newBookmarkFolder(user *domain.User, name string, createdAt time.Time) *domain.BookmarkFolder {
+	return &domain.BookmarkFolder{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Name:      name,
+		CreatedAt: createdAt,
+	}
+}
+
+func mustUpsertBookmark(t *testing.T, r Repos, bookmark *domain.Bookmark) {
+	t.Helper()
+
+	if err := r.Bookmarks.Upsert(context.Background(), bookmark); err != nil {
+		t.Fatalf("Upsert bookmark: %v", err)
+	}
+}
+
+func mustCreateFolders(t *testing.T, r Repos, folders ...*domain.BookmarkFolder) {
+	t.Helper()
+
+	for _, f := range folders {
+		if err := r.Bookmarks.CreateFolder(context.Background(), f); err != nil {
+			t.Fatalf("CreateFolder %s: %v", f.Name, err)
+		}
+	}
+}
+
+func assertBookmarkIDs(t *testing.T, name string, bookmarks []*domain.Bookmark, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(bookmarks))
+	for i, b := range bookmarks {
+		got[i] = b.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
+
+func assertFolderIDs(t *testing.T, name string, folders []*domain.BookmarkFolder, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(folders))
+	for i, f := range folders {
+		got[i] = f.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
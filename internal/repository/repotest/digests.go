@@ -0,0 +1,191 @@
+package repotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+func testDigestPreferences(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user := newUser("reader", "member", base)
+	mustCreateUsers(t, r, user)
+
+	prefs := mustEnsureDigestPreferences(t, r, user, "first-token")
+	if prefs.UserID != user.ID || prefs.Frequency != domain.DefaultDigestFrequency || prefs.UnsubscribeToken != "first-token" || prefs.LastSentAt != nil {
+		t.Errorf("EnsurePreferences returned %+v, want the default with first-token", prefs)
+	}
+
+	if prefs := mustEnsureDigestPreferences(t, r, user, "second-token"); prefs.UnsubscribeToken != "first-token" {
+		t.Errorf("EnsurePreferences again returned token %q, want the first one kept", prefs.UnsubscribeToken)
+	}
+
+	if err := r.Digests.UpdateFrequency(ctx, user.ID, domain.DigestDaily); err != nil {
+		t.Fatalf("UpdateFrequency: %v", err)
+	}
+
+	if prefs := mustEnsureDigestPreferences(t, r, user, "third-token"); prefs.Frequency != domain.DigestDaily {
+		t.Errorf("EnsurePreferences after UpdateFrequency returned %+v, want daily", prefs)
+	}
+
+	if err := r.Digests.UpdateFrequency(ctx, uuid.New(), domain.DigestDaily); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateFrequency of a user without preferences: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Digests.MarkSent(ctx, user.ID, base); err != nil {
+		t.Fatalf("MarkSent: %v", err)
+	}
+
+	if err := r.Digests.MarkSent(ctx, uuid.New(), base); err != nil {
+		t.Errorf("MarkSent of a user without preferences: %v", err)
+	}
+
+	if prefs := mustEnsureDigestPreferences(t, r, user, "third-token"); prefs.LastSentAt == nil || !prefs.LastSentAt.Equal(base) {
+		t.Errorf("EnsurePreferences after MarkSent returned last sent %v, want %v", prefs.LastSentAt, base)
+	}
+
+	if err := r.Digests.UnsubscribeByToken(ctx, "unknown-token"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UnsubscribeByToken of unknown token: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Digests.UnsubscribeByToken(ctx, "first-token"); err != nil {
+		t.Fatalf("UnsubscribeByToken: %v", err)
+	}
+
+	if prefs := mustEnsureDigestPreferences(t, r, user, "third-token"); prefs.Frequency != domain.DigestOff {
+		t.Errorf("EnsurePreferences after UnsubscribeByToken returned %+v, want off", prefs)
+	}
+}
+
+func testDueDigests(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	now := base.Add(time.Hour)
+	cutoffs := map[string]time.Time{
+		domain.DigestDaily:  now.Add(-24 * time.Hour),
+		domain.DigestWeekly: now.Add(-7 * 24 * time.Hour),
+	}
+
+	// Users without preferences get the default digest, never sent.
+	fresh := newUser("fresh", "member", base)
+	mustCreateUsers(t, r, fresh)
+
+	due := []*domain.User{fresh}
+	digestUser := func(name, frequency string, sentAt *time.Time, isDue bool) *domain.User {
+		t.Helper()
+
+		user := newUser(name, "member", base)
+		mustCreateUsers(t, r, user)
+		mustEnsureDigestPreferences(t, r, user, "token-"+name)
+
+		if err := r.Digests.UpdateFrequency(ctx, user.ID, frequency); err != nil {
+			t.Fatalf("UpdateFrequency: %v", err)
+		}
+
+		if sentAt != nil {
+			if err := r.Digests.MarkSent(ctx, user.ID, *sentAt); err != nil {
+				t.Fatalf("MarkSent: %v", err)
+			}
+		}
+
+		if isDue {
+			due = append(due, user)
+		}
+
+		return user
+	}
+
+	lastSent := func(ago time.Duration) *time.Time {
+		at := now.Add(-ago)
+
+		return &at
+	}
+
+	daily := digestUser("daily", domain.DigestDaily, lastSent(25*time.Hour), true)
+	digestUser("daily-exact", domain.DigestDaily, lastSent(24*time.Hour), true)
+	digestUser("daily-recent", domain.DigestDaily, lastSent(time.Hour), false)
+	digestUser("weekly-recent", domain.DigestWeekly, lastSent(3*24*time.Hour), false)
+	digestUser("weekly-never", domain.DigestWeekly, nil, true)
+	digestUser("off", domain.DigestOff, nil, false)
+
+	slices.SortFunc(due, func(a, b *domain.User) int { return bytes.Compare(a.ID[:], b.ID[:]) })
+	want := make([]uuid.UUID, len(due))
+	for i, u := range due {
+		want[i] = u.ID
+	}
+
+	first, err := r.Digests.GetDueRecipients(ctx, cutoffs, uuid.Nil, 2)
+	if err != nil {
+		t.Fatalf("GetDueRecipients: %v", err)
+	}
+
+	assertRecipientIDs(t, "GetDueRecipients first page", first, want[:2]...)
+
+	if len(first) == 0 {
+		t.FailNow()
+	}
+
+	rest, err := r.Digests.GetDueRecipients(ctx, cutoffs, first[len(first)-1].UserID, 10)
+	if err != nil {
+		t.Fatalf("GetDueRecipients: %v", err)
+	}
+
+	assertRecipientIDs(t, "GetDueRecipients after the first page", rest, want[2:]...)
+
+	for _, recipient := range append(first, rest...) {
+		switch recipient.UserID {
+		case fresh.ID:
+			if recipient.Frequency != domain.DefaultDigestFrequency || recipient.LastSentAt != nil || recipient.Email != fresh.Email {
+				t.Errorf("GetDueRecipients returned %+v for a user without preferences, want the default never sent", recipient)
+			}
+		case daily.ID:
+			if recipient.Frequency != domain.DigestDaily || recipient.LastSentAt == nil || !recipient.LastSentAt.Equal(now.Add(-25*time.Hour)) ||
+				recipient.Username != daily.Username || recipient.Email != daily.Email {
+				t.Errorf("GetDueRecipients returned %+v, want %s's daily digest", recipient, daily.Username)
+			}
+		}
+	}
+
+	weeklyOnly := map[string]time.Time{domain.DigestWeekly: cutoffs[domain.DigestWeekly]}
+	weekly, err := r.Digests.GetDueRecipients(ctx, weeklyOnly, uuid.Nil, 10)
+	if err != nil {
+		t.Fatalf("GetDueRecipients: %v", err)
+	}
+
+	if len(weekly) != 2 {
+		t.Errorf("GetDueRecipients of weekly digests returned %d recipients, want 2", len(weekly))
+	}
+
+	if none, err := r.Digests.GetDueRecipients(ctx, map[string]time.Time{}, uuid.Nil, 10); err != nil || len(none) != 0 {
+		t.Errorf("GetDueRecipients without cutoffs: got %v, %v, want none", none, err)
+	}
+}
+
+func mustEnsureDigestPreferences(t *testing.T, r Repos, user *domain.User, token string) *domain.DigestPreferences {
+	t.Helper()
+
+	prefs, err := r.Digests.EnsurePreferences(context.Background(), user.ID, token)
+	if err != nil {
+		t.Fatalf("EnsurePreferences: %v", err)
+	}
+
+	return prefs
+}
+
+func assertRecipientIDs(t *testing.T, name string, recipients []*domain.DigestRecipient, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(recipients))
+	for i, recipient := range recipients {
+		got[i] = recipient.UserID
+	}
+
+	assertIDs(t, name, got, want)
+}
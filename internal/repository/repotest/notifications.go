@@ -0,0 +1,201 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+func testNotifications(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	other := newUser("other", "member", base)
+	mustCreateUsers(t, r, other)
+	thread := newThread(user, category, base)
+	mustCreateThreads(t, r, thread)
+
+	eventID := uuid.New()
+	reply := newNotification(user, domain.NotificationThreadReply, thread, base)
+	reply.EventID = &eventID
+	reply.ActorID = &other.ID
+	mustCreateNotification(t, r, reply, true)
+
+	again := *reply
+	mustCreateNotification(t, r, &again, false)
+
+	sameEvent := newNotification(user, domain.NotificationThreadReply, thread, base)
+	sameEvent.EventID = &eventID
+	mustCreateNotification(t, r, sameEvent, false)
+
+	otherUser := newNotification(other, domain.NotificationThreadReply, thread, base)
+	otherUser.EventID = &eventID
+	mustCreateNotification(t, r, otherUser, true)
+
+	// A milestone is announced once per target, even without an event.
+	ten := newMilestone(user, thread, 10, base.Add(time.Second))
+	mustCreateNotification(t, r, ten, true)
+	mustCreateNotification(t, r, newMilestone(user, thread, 10, base), false)
+
+	twenty := newMilestone(user, thread, 20, base.Add(2*time.Second))
+	mustCreateNotification(t, r, twenty, true)
+
+	all, err := r.Notifications.GetByUserID(ctx, user.ID, false, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	assertNotificationIDs(t, "GetByUserID", all, twenty.ID, ten.ID, reply.ID)
+
+	got := all[2]
+	if got.Type != reply.Type || got.EventID == nil || *got.EventID != eventID || got.ActorID == nil || *got.ActorID != other.ID ||
+		got.ThreadID == nil || *got.ThreadID != thread.ID || got.IsRead || got.ReadAt != nil || !got.CreatedAt.Equal(reply.CreatedAt) {
+		t.Errorf("GetByUserID returned %+v, want %+v", got, reply)
+	}
+
+	if got := all[0]; got.Milestone == nil || *got.Milestone != 20 {
+		t.Errorf("GetByUserID returned milestone %v, want 20", got.Milestone)
+	}
+
+	page, err := r.Notifications.GetByUserID(ctx, user.ID, false, usecase.PaginationParams{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	assertNotificationIDs(t, "GetByUserID second page", page, reply.ID)
+
+	if err := r.Notifications.MarkRead(ctx, reply.ID, other.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("MarkRead of another user's notification: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Notifications.MarkRead(ctx, uuid.New(), user.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("MarkRead of unknown notification: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Notifications.MarkRead(ctx, reply.ID, user.ID); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+
+	if err := r.Notifications.MarkRead(ctx, reply.ID, user.ID); err != nil {
+		t.Errorf("MarkRead of a read notification: %v", err)
+	}
+
+	unread, err := r.Notifications.GetByUserID(ctx, user.ID, true, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByUserID unread: %v", err)
+	}
+
+	assertNotificationIDs(t, "GetByUserID unread", unread, twenty.ID, ten.ID)
+
+	if count, err := r.Notifications.CountByUserID(ctx, user.ID, true); err != nil || count != 2 {
+		t.Errorf("CountByUserID unread: got %d, %v, want 2", count, err)
+	}
+
+	if count, err := r.Notifications.CountByUserID(ctx, user.ID, false); err != nil || count != 3 {
+		t.Errorf("CountByUserID: got %d, %v, want 3", count, err)
+	}
+
+	if marked, err := r.Notifications.MarkAllRead(ctx, user.ID); err != nil || marked != 2 {
+		t.Errorf("MarkAllRead: got %d, %v, want 2", marked, err)
+	}
+
+	if marked, err := r.Notifications.MarkAllRead(ctx, user.ID); err != nil || marked != 0 {
+		t.Errorf("MarkAllRead again: got %d, %v, want 0", marked, err)
+	}
+
+	all, err = r.Notifications.GetByUserID(ctx, user.ID, false, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	for _, n := range all {
+		if !n.IsRead || n.ReadAt == nil {
+			t.Errorf("notification %s after MarkAllRead: read %v at %v, want read", n.ID, n.IsRead, n.ReadAt)
+		}
+	}
+
+	if count, err := r.Notifications.CountByUserID(ctx, other.ID, true); err != nil || count != 1 {
+		t.Errorf("CountByUserID unread of the other user: got %d, %v, want 1", count, err)
+	}
+}
+
+func testNotificationPreferences(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user := newUser("member", "member", base)
+	mustCreateUsers(t, r, user)
+
+	if _, err := r.Notifications.GetPreferences(ctx, user.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetPreferences before saving any: got %v, want ErrNotFound", err)
+	}
+
+	prefs := domain.DefaultNotificationPreferences(user.ID)
+	prefs.Mention = false
+	prefs.UpdatedAt = base
+	if err := r.Notifications.UpsertPreferences(ctx, prefs); err != nil {
+		t.Fatalf("UpsertPreferences: %v", err)
+	}
+
+	prefs.Mention = true
+	prefs.VoteMilestone = false
+	prefs.UpdatedAt = base.Add(time.Second)
+	if err := r.Notifications.UpsertPreferences(ctx, prefs); err != nil {
+		t.Fatalf("UpsertPreferences again: %v", err)
+	}
+
+	got, err := r.Notifications.GetPreferences(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+
+	if got.UserID != user.ID || got.ThreadReply != prefs.ThreadReply || got.PostReply != prefs.PostReply || got.Mention != prefs.Mention ||
+		got.VoteMilestone != prefs.VoteMilestone || !got.UpdatedAt.Equal(prefs.UpdatedAt) {
+		t.Errorf("GetPreferences returned %+v, want %+v", got, prefs)
+	}
+}
+
+func newNotification(user *domain.User, kind string, thread *domain.Thread, createdAt time.Time) *domain.Notification {
+	return &domain.Notification{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Type:      kind,
+		ThreadID:  &thread.ID,
+		CreatedAt: createdAt,
+	}
+}
+
+func newMilestone(user *domain.User, thread *domain.Thread, milestone int, createdAt time.Time) *domain.Notification {
+	n := newNotification(user, domain.NotificationVoteMilestone, thread, createdAt)
+	n.Milestone = &milestone
+
+	return n
+}
+
+func mustCreateNotification(t *testing.T, r Repos, n *domain.Notification, want bool) {
+	t.Helper()
+
+	created, err := r.Notifications.Create(context.Background(), n)
+	if err != nil {
+		t.Fatalf("Create notification: %v", err)
+	}
+
+	if created != want {
+		t.Errorf("Create notification %+v: got created %v, want %v", n, created, want)
+	}
+}
+
+func assertNotificationIDs(t *testing.T, name string, notifications []*domain.Notification, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(notifications))
+	for i, n := range notifications {
+		got[i] = n.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
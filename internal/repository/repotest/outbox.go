@@ -0,0 +1,166 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+func testOutbox(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	now := base.Add(time.Hour)
+	lease := time.Minute
+
+	first := newOutboxEvent(base, base)
+	second := newOutboxEvent(base, base)
+	later := newOutboxEvent(base, now.Add(time.Hour))
+	if err := r.Outbox.Add(ctx, first, second, later); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if err := r.Outbox.Add(ctx); err != nil {
+		t.Errorf("Add of no events: %v", err)
+	}
+
+	claimed, err := r.Outbox.ClaimPending(ctx, now, lease, 10)
+	if err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+
+	assertOutboxIDs(t, "ClaimPending", claimed, first.ID, second.ID)
+
+	if claimed[0].Seq >= claimed[1].Seq {
+		t.Errorf("ClaimPending returned seq %d then %d, want them in the order added", claimed[0].Seq, claimed[1].Seq)
+	}
+
+	for _, e := range claimed {
+		if !e.NextAttemptAt.Equal(now.Add(lease)) || e.AggregateType != first.AggregateType || e.EventType != first.EventType {
+			t.Errorf("ClaimPending returned %+v, want it leased until %v", e, now.Add(lease))
+		}
+	}
+
+	claimed, err = r.Outbox.ClaimPending(ctx, now, lease, 10)
+	if err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+
+	assertOutboxIDs(t, "ClaimPending of leased events", claimed)
+
+	claimed, err = r.Outbox.ClaimPending(ctx, now.Add(lease), lease, 1)
+	if err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+
+	assertOutboxIDs(t, "ClaimPending after the lease", claimed, first.ID)
+
+	lastError := "broker unavailable"
+	second.Attempts = 1
+	second.NextAttemptAt = now
+	second.LastError = &lastError
+	if err := r.Outbox.MarkFailed(ctx, second); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+
+	claimed, err = r.Outbox.ClaimPending(ctx, now, lease, 10)
+	if err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+
+	assertOutboxIDs(t, "ClaimPending of a failed event", claimed, second.ID)
+
+	if got := claimed[0]; got.Attempts != 1 || got.LastError == nil || *got.LastError != lastError {
+		t.Errorf("ClaimPending of a failed event returned %+v, want one attempt and its error", got)
+	}
+
+	if err := r.Outbox.MarkPublished(ctx, first.ID, base); err != nil {
+		t.Fatalf("MarkPublished: %v", err)
+	}
+
+	if err := r.Outbox.MarkPublished(ctx, second.ID, now); err != nil {
+		t.Fatalf("MarkPublished: %v", err)
+	}
+
+	claimed, err = r.Outbox.ClaimPending(ctx, now.Add(24*time.Hour), lease, 10)
+	if err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+
+	assertOutboxIDs(t, "ClaimPending after MarkPublished", claimed, later.ID)
+
+	if purged, err := r.Outbox.PurgePublishedBefore(ctx, base.Add(time.Second)); err != nil || purged != 1 {
+		t.Errorf("PurgePublishedBefore: got %d, %v, want 1", purged, err)
+	}
+
+	if purged, err := r.Outbox.PurgePublishedBefore(ctx, now.Add(24*time.Hour)); err != nil || purged != 1 {
+		t.Errorf("PurgePublishedBefore: got %d, %v, want 1 leaving the unpublished event", purged, err)
+	}
+
+	claimed, err = r.Outbox.ClaimPending(ctx, now.Add(48*time.Hour), lease, 10)
+	if err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+
+	assertOutboxIDs(t, "ClaimPending after PurgePublishedBefore", claimed, later.ID)
+}
+
+func testProcessedEvents(t *testing.T, r Repos) {
+	ctx := context.Background()
+	eventID := uuid.New()
+
+	if processed, err := r.ProcessedEvents.IsProcessed(ctx, "notifier", eventID); err != nil || processed {
+		t.Fatalf("IsProcessed before MarkProcessed: got %v, %v, want false", processed, err)
+	}
+
+	for _, consumer := range []string{"notifier", "notifier", "webhooks"} {
+		if err := r.ProcessedEvents.MarkProcessed(ctx, consumer, eventID); err != nil {
+			t.Fatalf("MarkProcessed: %v", err)
+		}
+	}
+
+	if processed, err := r.ProcessedEvents.IsProcessed(ctx, "notifier", eventID); err != nil || !processed {
+		t.Errorf("IsProcessed: got %v, %v, want true", processed, err)
+	}
+
+	if processed, err := r.ProcessedEvents.IsProcessed(ctx, "search", eventID); err != nil || processed {
+		t.Errorf("IsProcessed by another consumer: got %v, %v, want false", processed, err)
+	}
+
+	if purged, err := r.ProcessedEvents.PurgeBefore(ctx, time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+		t.Errorf("PurgeBefore an earlier cutoff: got %d, %v, want 0", purged, err)
+	}
+
+	if purged, err := r.ProcessedEvents.PurgeBefore(ctx, time.Now().Add(time.Hour)); err != nil || purged != 2 {
+		t.Errorf("PurgeBefore: got %d, %v, want 2", purged, err)
+	}
+
+	if processed, err := r.ProcessedEvents.IsProcessed(ctx, "notifier", eventID); err != nil || processed {
+		t.Errorf("IsProcessed after PurgeBefore: got %v, %v, want false", processed, err)
+	}
+}
+
+func newOutboxEvent(createdAt, nextAttemptAt time.Time) *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: domain.AggregateThread,
+		AggregateID:   uuid.New(),
+		EventType:     domain.EventThreadCreated,
+		Payload:       []byte(`{}`),
+		NextAttemptAt: nextAttemptAt,
+		CreatedAt:     createdAt,
+	}
+}
+
+func assertOutboxIDs(t *testing.T, name string, events []*domain.OutboxEvent, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(events))
+	for i, e := range events {
+		got[i] = e.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
@@ -0,0 +1,926 @@
+// Package repotest is a conformance suite for repository implementations.
+// Every implementation of the repositories runs it, so usecases behave the
+// same whichever one they are given.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// Repos are repositories backed by the same data, so a thread created through
+// Threads can be posted to through Posts.
+type Repos struct {
+	Users      usecase.UserRepository
+	Categories usecase.CategoryRepository
+	Threads    usecase.ThreadRepository
+	Posts      usecase.PostRepository
+	Votes      usecase.VoteRepository
+	Tx         usecase.TxManager
+
+	Notifications   usecase.NotificationRepository
+	Outbox          usecase.OutboxRepository
+	ProcessedEvents usecase.ProcessedEventRepository
+	Webhooks        usecase.WebhookRepository
+	Tags            usecase.TagRepository
+	Subscriptions   usecase.SubscriptionRepository
+	Bookmarks       usecase.BookmarkRepository
+	Digests         usecase.DigestRepository
+}
+
+// Factory returns repositories over an empty data set. It is called once per
+// subtest.
+type Factory func(t *testing.T) Repos
+
+// Run runs the conformance suite against the repositories newRepos returns.
+func Run(t *testing.T, newRepos Factory) {
+	t.Run("Users", func(t *testing.T) { testUsers(t, newRepos(t)) })
+	t.Run("Categories", func(t *testing.T) { testCategories(t, newRepos(t)) })
+	t.Run("Threads", func(t *testing.T) { testThreads(t, newRepos(t)) })
+	t.Run("SoftDelete", func(t *testing.T) { testSoftDelete(t, newRepos(t)) })
+	t.Run("Posts", func(t *testing.T) { testPosts(t, newRepos(t)) })
+	t.Run("Votes", func(t *testing.T) { testVotes(t, newRepos(t)) })
+	t.Run("ConcurrentVoteCounts", func(t *testing.T) { testConcurrentVoteCounts(t, newRepos(t)) })
+	t.Run("ConcurrentVotesBySameUser", func(t *testing.T) { testConcurrentVotesBySameUser(t, newRepos(t)) })
+	t.Run("Rollback", func(t *testing.T) { testRollback(t, newRepos(t)) })
+	t.Run("Notifications", func(t *testing.T) { testNotifications(t, newRepos(t)) })
+	t.Run("NotificationPreferences", func(t *testing.T) { testNotificationPreferences(t, newRepos(t)) })
+	t.Run("Outbox", func(t *testing.T) { testOutbox(t, newRepos(t)) })
+	t.Run("ProcessedEvents", func(t *testing.T) { testProcessedEvents(t, newRepos(t)) })
+	t.Run("Webhooks", func(t *testing.T) { testWebhooks(t, newRepos(t)) })
+	t.Run("WebhookDeliveries", func(t *testing.T) { testWebhookDeliveries(t, newRepos(t)) })
+	t.Run("Tags", func(t *testing.T) { testTags(t, newRepos(t)) })
+	t.Run("TagRenameAndMerge", func(t *testing.T) { testTagRenameAndMerge(t, newRepos(t)) })
+	t.Run("Subscriptions", func(t *testing.T) { testSubscriptions(t, newRepos(t)) })
+	t.Run("ReadPositions", func(t *testing.T) { testReadPositions(t, newRepos(t)) })
+	t.Run("Bookmarks", func(t *testing.T) { testBookmarks(t, newRepos(t)) })
+	t.Run("BookmarkFolders", func(t *testing.T) { testBookmarkFolders(t, newRepos(t)) })
+	t.Run("DigestPreferences", func(t *testing.T) { testDigestPreferences(t, newRepos(t)) })
+	t.Run("DueDigests", func(t *testing.T) { testDueDigests(t, newRepos(t)) })
+}
+
+// baseTime is truncated to microseconds, the precision postgres keeps.
+func baseTime() time.Time {
+	return time.Now().UTC().Add(-time.Hour).Truncate(time.Microsecond)
+}
+
+func testUsers(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+
+	if _, err := r.Users.GetByID(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByID of unknown user: got %v, want ErrNotFound", err)
+	}
+
+	if _, err := r.Users.GetByEmail(ctx, "nobody@example.com"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByEmail of unknown email: got %v, want ErrNotFound", err)
+	}
+
+	admin := newUser("root", "admin", base)
+	bob := newUser("bob", "member", base.Add(time.Second))
+	alice := newUser("Alice", "member", base.Add(2*time.Second))
+	mustCreateUsers(t, r, alice, admin, bob)
+
+	got, err := r.Users.GetByEmail(ctx, alice.Email)
+	if err != nil {
+		t.Fatalf("GetByEmail: %v", err)
+	}
+
+	if got.ID != alice.ID || got.Username != alice.Username || got.PasswordHash != alice.PasswordHash || got.Role != alice.Role || !got.CreatedAt.Equal(alice.CreatedAt) {
+		t.Errorf("GetByEmail returned %+v, want %+v", got, alice)
+	}
+
+	dupEmail := newUser("alice2", "member", base)
+	dupEmail.Email = alice.Email
+	if err := r.Users.Create(ctx, dupEmail); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Create with a taken email: got %v, want ErrConflict", err)
+	}
+
+	dupUsername := newUser(alice.Username, "member", base)
+	dupUsername.Email = "other@example.com"
+	if err := r.Users.Create(ctx, dupUsername); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Create with a taken username: got %v, want ErrConflict", err)
+	}
+
+	users, err := r.Users.GetUsers(ctx)
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+
+	assertUserIDs(t, "GetUsers", users, bob.ID, alice.ID)
+
+	if err := r.Users.UpdateRole(ctx, bob.ID, "admin"); err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+
+	if got, err := r.Users.GetByID(ctx, bob.ID); err != nil || got.Role != "admin" {
+		t.Errorf("GetByID after UpdateRole: got %+v, %v, want role admin", got, err)
+	}
+
+	if err := r.Users.UpdateRole(ctx, uuid.New(), "admin"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateRole of unknown user: got %v, want ErrNotFound", err)
+	}
+
+	byID, err := r.Users.GetByIDs(ctx, []uuid.UUID{alice.ID, uuid.New()})
+	if err != nil {
+		t.Fatalf("GetByIDs: %v", err)
+	}
+
+	if len(byID) != 1 || byID[alice.ID] == nil {
+		t.Fatalf("GetByIDs returned %v, want only %s", byID, alice.ID)
+	}
+
+	if byID[alice.ID].PasswordHash != "" {
+		t.Errorf("GetByIDs exposed the password hash")
+	}
+
+	byName, err := r.Users.GetByUsernames(ctx, []string{"alice", "nobody"})
+	if err != nil {
+		t.Fatalf("GetByUsernames: %v", err)
+	}
+
+	if len(byName) != 1 || byName["alice"] == nil || byName["alice"].ID != alice.ID {
+		t.Errorf("GetByUsernames returned %v, want alice keyed in lowercase", byName)
+	}
+}
+
+func testCategories(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+
+	if _, err := r.Categories.GetByID(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByID of unknown category: got %v, want ErrNotFound", err)
+	}
+
+	if _, err := r.Categories.GetBySlug(ctx, "nothing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetBySlug of unknown slug: got %v, want ErrNotFound", err)
+	}
+
+	zeta := newCategory("zeta", base)
+	alpha := newCategory("alpha", base.Add(time.Second))
+	mustCreateCategories(t, r, alpha, zeta)
+
+	if got, err := r.Categories.GetBySlug(ctx, alpha.Slug); err != nil || got.ID != alpha.ID {
+		t.Errorf("GetBySlug: got %+v, %v, want %s", got, err, alpha.ID)
+	}
+
+	dupName := newCategory("other", base)
+	dupName.Name = alpha.Name
+	if err := r.Categories.Create(ctx, dupName); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Create with a taken name: got %v, want ErrConflict", err)
+	}
+
+	dupSlug := newCategory("another", base)
+	dupSlug.Slug = alpha.Slug
+	if err := r.Categories.Create(ctx, dupSlug); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Create with a taken slug: got %v, want ErrConflict", err)
+	}
+
+	all, err := r.Categories.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+
+	assertCategoryIDs(t, "GetAll", all, zeta.ID, alpha.ID)
+
+	byID, err := r.Categories.GetByIDs(ctx, []uuid.UUID{zeta.ID, uuid.New()})
+	if err != nil {
+		t.Fatalf("GetByIDs: %v", err)
+	}
+
+	if len(byID) != 1 || byID[zeta.ID] == nil {
+		t.Errorf("GetByIDs returned %v, want only %s", byID, zeta.ID)
+	}
+
+	user := newUser("follower", "member", base)
+	mustCreateUsers(t, r, user)
+
+	for _, id := range []uuid.UUID{zeta.ID, alpha.ID, alpha.ID} {
+		if err := r.Categories.Follow(ctx, user.ID, id); err != nil {
+			t.Fatalf("Follow: %v", err)
+		}
+	}
+
+	followed, err := r.Categories.GetFollowedByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetFollowedByUserID: %v", err)
+	}
+
+	assertCategoryIDs(t, "GetFollowedByUserID", followed, alpha.ID, zeta.ID)
+
+	if err := r.Categories.Unfollow(ctx, user.ID, zeta.ID); err != nil {
+		t.Fatalf("Unfollow: %v", err)
+	}
+
+	if err := r.Categories.Unfollow(ctx, user.ID, zeta.ID); err != nil {
+		t.Errorf("Unfollow of a category not followed: %v", err)
+	}
+
+	followed, err = r.Categories.GetFollowedByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetFollowedByUserID: %v", err)
+	}
+
+	assertCategoryIDs(t, "GetFollowedByUserID after Unfollow", followed, alpha.ID)
+}
+
+func testThreads(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+
+	if _, err := r.Threads.GetByID(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByID of unknown thread: got %v, want ErrNotFound", err)
+	}
+
+	first := newThread(user, category, base)
+	second := newThread(user, category, base.Add(time.Second))
+	third := newThread(user, category, base.Add(2*time.Second))
+	mustCreateThreads(t, r, second, third, first)
+
+	page, err := r.Threads.GetAll(ctx, usecase.ThreadFilter{}, usecase.PaginationParams{Limit: 2, Offset: 0})
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+
+	assertThreadIDs(t, "GetAll first page", page, third.ID, second.ID)
+
+	page, err = r.Threads.GetAll(ctx, usecase.ThreadFilter{}, usecase.PaginationParams{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+
+	assertThreadIDs(t, "GetAll second page", page, first.ID)
+
+	if count, err := r.Threads.CountAll(ctx, usecase.ThreadFilter{}); err != nil || count != 3 {
+		t.Errorf("CountAll: got %d, %v, want 3", count, err)
+	}
+
+	tagged := usecase.ThreadFilter{Tags: []string{"go"}}
+	if count, err := r.Threads.CountAll(ctx, tagged); err != nil || count != 0 {
+		t.Errorf("CountAll of an unused tag: got %d, %v, want 0", count, err)
+	}
+
+	if count, err := r.Threads.UpdateVoteCount(ctx, first.ID, 3); err != nil || count != 3 {
+		t.Fatalf("UpdateVoteCount: got %d, %v, want 3", count, err)
+	}
+
+	if count, err := r.Threads.UpdateVoteCount(ctx, first.ID, -1); err != nil || count != 2 {
+		t.Fatalf("UpdateVoteCount: got %d, %v, want 2", count, err)
+	}
+
+	if _, err := r.Threads.UpdateVoteCount(ctx, uuid.New(), 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateVoteCount of unknown thread: got %v, want ErrNotFound", err)
+	}
+
+	got, err := r.Threads.GetByID(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if got.VoteCount != 2 || got.Title != first.Title || got.UserID != user.ID || got.CategoryID != category.ID || !got.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("GetByID returned %+v, want %+v with 2 votes", got, first)
+	}
+
+	updatedAt := base.Add(time.Minute)
+	second.Title = "Edited title"
+	second.Content = "Edited content"
+	second.ContentHTML = "<p>Edited content</p>"
+	second.UpdatedAt = &updatedAt
+	if err := r.Threads.Update(ctx, second); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err = r.Threads.GetByID(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if got.Title != second.Title || got.ContentHTML != second.ContentHTML || got.UpdatedAt == nil || !got.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("GetByID after Update returned %+v, want %+v", got, second)
+	}
+
+	if err := r.Threads.SetLocked(ctx, third.ID, true); err != nil {
+		t.Fatalf("SetLocked: %v", err)
+	}
+
+	if got, err := r.Threads.GetByID(ctx, third.ID); err != nil || !got.IsLocked {
+		t.Errorf("GetByID after SetLocked: got %+v, %v, want locked", got, err)
+	}
+
+	if err := r.Threads.SetLocked(ctx, uuid.New(), true); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SetLocked of unknown thread: got %v, want ErrNotFound", err)
+	}
+
+	top, err := r.Threads.GetTop(ctx, nil, base, 10)
+	if err != nil {
+		t.Fatalf("GetTop: %v", err)
+	}
+
+	assertThreadIDs(t, "GetTop", top, first.ID, third.ID, second.ID)
+
+	top, err = r.Threads.GetTop(ctx, []uuid.UUID{category.ID}, base.Add(time.Second), 1)
+	if err != nil {
+		t.Fatalf("GetTop: %v", err)
+	}
+
+	assertThreadIDs(t, "GetTop since the second thread", top, third.ID)
+
+	top, err = r.Threads.GetTop(ctx, []uuid.UUID{uuid.New()}, base, 10)
+	if err != nil {
+		t.Fatalf("GetTop: %v", err)
+	}
+
+	assertThreadIDs(t, "GetTop of another category", top)
+
+	byTag, err := r.Threads.GetByTagID(ctx, uuid.New(), usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByTagID: %v", err)
+	}
+
+	assertThreadIDs(t, "GetByTagID of an unused tag", byTag)
+
+	if count, err := r.Threads.CountMentioning(ctx, user.ID); err != nil || count != 0 {
+		t.Errorf("CountMentioning: got %d, %v, want 0", count, err)
+	}
+}
+
+func testSoftDelete(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+
+	kept := newThread(user, category, base)
+	deleted := newThread(user, category, base.Add(time.Second))
+	mustCreateThreads(t, r, kept, deleted)
+
+	if err := r.Threads.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if err := r.Threads.Delete(ctx, deleted.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Delete of a deleted thread: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Threads.Delete(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Delete of unknown thread: got %v, want ErrNotFound", err)
+	}
+
+	if _, err := r.Threads.GetByID(ctx, deleted.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByID of a deleted thread: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Threads.SetLocked(ctx, deleted.ID, true); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("SetLocked of a deleted thread: got %v, want ErrNotFound", err)
+	}
+
+	if count, err := r.Threads.CountAll(ctx, usecase.ThreadFilter{}); err != nil || count != 1 {
+		t.Errorf("CountAll: got %d, %v, want 1", count, err)
+	}
+
+	byID, err := r.Threads.GetByIDs(ctx, []uuid.UUID{kept.ID, deleted.ID})
+	if err != nil {
+		t.Fatalf("GetByIDs: %v", err)
+	}
+
+	if len(byID) != 1 || byID[kept.ID] == nil {
+		t.Errorf("GetByIDs returned %v, want only %s", byID, kept.ID)
+	}
+
+	trash, err := r.Threads.GetDeleted(ctx, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetDeleted: %v", err)
+	}
+
+	assertThreadIDs(t, "GetDeleted", trash, deleted.ID)
+
+	if trash[0].DeletedAt == nil {
+		t.Errorf("GetDeleted returned a thread without DeletedAt")
+	}
+
+	if count, err := r.Threads.CountDeleted(ctx); err != nil || count != 1 {
+		t.Errorf("CountDeleted: got %d, %v, want 1", count, err)
+	}
+
+	if err := r.Threads.Restore(ctx, deleted.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	if err := r.Threads.Restore(ctx, deleted.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Restore of a thread not deleted: got %v, want ErrNotFound", err)
+	}
+
+	if _, err := r.Threads.GetByID(ctx, deleted.ID); err != nil {
+		t.Errorf("GetByID of a restored thread: %v", err)
+	}
+
+	if err := r.Threads.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if purged, err := r.Threads.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+		t.Errorf("PurgeDeletedBefore an earlier cutoff: got %d, %v, want 0", purged, err)
+	}
+
+	if purged, err := r.Threads.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour)); err != nil || purged != 1 {
+		t.Errorf("PurgeDeletedBefore: got %d, %v, want 1", purged, err)
+	}
+
+	if err := r.Threads.Restore(ctx, deleted.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Restore of a purged thread: got %v, want ErrNotFound", err)
+	}
+
+	if _, err := r.Threads.GetByID(ctx, kept.ID); err != nil {
+		t.Errorf("GetByID of the thread that was not deleted: %v", err)
+	}
+}
+
+func testPosts(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	thread := newThread(user, category, base)
+	other := newThread(user, category, base)
+	mustCreateThreads(t, r, thread, other)
+
+	if _, err := r.Posts.GetByID(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByID of unknown post: got %v, want ErrNotFound", err)
+	}
+
+	first := newPost(user, thread, nil, base.Add(time.Second))
+	second := newPost(user, thread, &first.ID, base.Add(2*time.Second))
+	elsewhere := newPost(user, other, nil, base)
+	for _, p := range []*domain.Post{first, second, elsewhere} {
+		if err := r.Posts.Create(ctx, p); err != nil {
+			t.Fatalf("Create post: %v", err)
+		}
+	}
+
+	page, err := r.Posts.GetByThreadID(ctx, thread.ID, usecase.PaginationParams{Limit: 1, Offset: 0})
+	if err != nil {
+		t.Fatalf("GetByThreadID: %v", err)
+	}
+
+	assertPostIDs(t, "GetByThreadID first page", page, first.ID)
+
+	page, err = r.Posts.GetByThreadID(ctx, thread.ID, usecase.PaginationParams{Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("GetByThreadID: %v", err)
+	}
+
+	assertPostIDs(t, "GetByThreadID second page", page, second.ID)
+
+	if count, err := r.Posts.CountByThreadID(ctx, thread.ID); err != nil || count != 2 {
+		t.Errorf("CountByThreadID: got %d, %v, want 2", count, err)
+	}
+
+	got, err := r.Posts.GetByID(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if got.ParentPostID == nil || *got.ParentPostID != first.ID || got.Content != second.Content || !got.CreatedAt.Equal(second.CreatedAt) {
+		t.Errorf("GetByID returned %+v, want %+v", got, second)
+	}
+
+	if count, err := r.Posts.UpdateVoteCount(ctx, first.ID, -1); err != nil || count != -1 {
+		t.Fatalf("UpdateVoteCount: got %d, %v, want -1", count, err)
+	}
+
+	if _, err := r.Posts.UpdateVoteCount(ctx, uuid.New(), 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("UpdateVoteCount of unknown post: got %v, want ErrNotFound", err)
+	}
+
+	updatedAt := base.Add(time.Minute)
+	first.Content = "Edited"
+	first.ContentHTML = "<p>Edited</p>"
+	first.UpdatedAt = &updatedAt
+	if err := r.Posts.Update(ctx, first); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	byID, err := r.Posts.GetByIDs(ctx, []uuid.UUID{first.ID, uuid.New()})
+	if err != nil {
+		t.Fatalf("GetByIDs: %v", err)
+	}
+
+	got = byID[first.ID]
+	if len(byID) != 1 || got == nil {
+		t.Fatalf("GetByIDs returned %v, want only %s", byID, first.ID)
+	}
+
+	if got.VoteCount != -1 || got.Content != "Edited" || got.ContentHTML != "<p>Edited</p>" || got.UpdatedAt == nil || !got.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("GetByIDs after Update returned %+v", got)
+	}
+}
+
+func testVotes(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	thread := newThread(user, category, base)
+	mustCreateThreads(t, r, thread)
+
+	post := newPost(user, thread, nil, base)
+	if err := r.Posts.Create(ctx, post); err != nil {
+		t.Fatalf("Create post: %v", err)
+	}
+
+	targets := []struct {
+		name   string
+		id     uuid.UUID
+		get    func(ctx context.Context, userID, id uuid.UUID) (*domain.ThreadVote, error)
+		upsert func(ctx context.Context, vote *domain.ThreadVote) error
+		delete func(ctx context.Context, userID, id uuid.UUID) error
+	}{
+		{"thread", thread.ID, r.Votes.GetThreadVoteForUpdate, r.Votes.UpsertThreadVote, r.Votes.DeleteThreadVote},
+		{"post", post.ID, r.Votes.GetPostVoteForUpdate, r.Votes.UpsertPostVote, r.Votes.DeletePostVote},
+	}
+
+	for _, target := range targets {
+		if _, err := target.get(ctx, user.ID, target.id); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("Get %s vote before voting: got %v, want ErrNotFound", target.name, err)
+		}
+
+		vote := &domain.ThreadVote{UserID: user.ID, ThreadID: target.id, VoteType: 1}
+		if err := target.upsert(ctx, vote); err != nil {
+			t.Fatalf("Upsert %s vote: %v", target.name, err)
+		}
+
+		if vote.CreatedAt.IsZero() {
+			t.Errorf("Upsert %s vote did not set CreatedAt", target.name)
+		}
+
+		if err := target.upsert(ctx, &domain.ThreadVote{UserID: user.ID, ThreadID: target.id, VoteType: -1}); err != nil {
+			t.Fatalf("Upsert %s vote again: %v", target.name, err)
+		}
+
+		got, err := target.get(ctx, user.ID, target.id)
+		if err != nil {
+			t.Fatalf("Get %s vote: %v", target.name, err)
+		}
+
+		if got.UserID != user.ID || got.ThreadID != target.id || got.VoteType != -1 {
+			t.Errorf("Get %s vote returned %+v, want a -1 vote on %s", target.name, got, target.id)
+		}
+
+		if err := target.delete(ctx, user.ID, target.id); err != nil {
+			t.Fatalf("Delete %s vote: %v", target.name, err)
+		}
+
+		if err := target.delete(ctx, user.ID, target.id); err != nil {
+			t.Errorf("Delete of a missing %s vote: %v", target.name, err)
+		}
+
+		if _, err := target.get(ctx, user.ID, target.id); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Get %s vote after Delete: got %v, want ErrNotFound", target.name, err)
+		}
+	}
+}
+
+func testConcurrentVoteCounts(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	thread := newThread(user, category, base)
+	mustCreateThreads(t, r, thread)
+
+	const workers = 20
+
+	type result struct {
+		count int
+		err   error
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan result, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count, err := r.Threads.UpdateVoteCount(ctx, thread.ID, 1)
+			results <- result{count: count, err: err}
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+
+	// Each update sees the count it produced, so no two return the same one.
+	seen := make(map[int]bool)
+	for res := range results {
+		if res.err != nil {
+			t.Fatalf("UpdateVoteCount: %v", res.err)
+		}
+
+		if res.count < 1 || res.count > workers || seen[res.count] {
+			t.Errorf("UpdateVoteCount returned %d, want a distinct count in 1..%d", res.count, workers)
+		}
+		seen[res.count] = true
+	}
+
+	got, err := r.Threads.GetByID(ctx, thread.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if got.VoteCount != workers {
+		t.Errorf("VoteCount after concurrent updates: got %d, want %d", got.VoteCount, workers)
+	}
+}
+
+// testConcurrentVotesBySameUser casts the same first vote from many
+// transactions at once. The ForUpdate read must make all but one of them see
+// the vote already cast, so it is counted once.
+func testConcurrentVotesBySameUser(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	thread := newThread(user, category, base)
+	mustCreateThreads(t, r, thread)
+
+	const workers = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				_, err := r.Votes.GetThreadVoteForUpdate(ctx, user.ID, thread.ID)
+				if err == nil {
+					return nil
+				}
+
+				if !errors.Is(err, domain.ErrNotFound) {
+					return err
+				}
+
+				// Give the other transactions time to read the vote too,
+				// should the read not lock it.
+				time.Sleep(time.Millisecond)
+
+				if err := r.Votes.UpsertThreadVote(ctx, &domain.ThreadVote{UserID: user.ID, ThreadID: thread.ID, VoteType: 1}); err != nil {
+					return err
+				}
+
+				_, err = r.Threads.UpdateVoteCount(ctx, thread.ID, 1)
+
+				return err
+			})
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("vote: %v", err)
+		}
+	}
+
+	got, err := r.Threads.GetByID(ctx, thread.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if got.VoteCount != 1 {
+		t.Errorf("VoteCount after concurrent votes by one user: got %d, want 1", got.VoteCount)
+	}
+}
+
+// testRollback checks that a failed transaction leaves nothing behind,
+// including changes to rows that existed before it started.
+func testRollback(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	thread := newThread(user, category, base)
+	mustCreateThreads(t, r, thread)
+
+	fail := errors.New("fail")
+	created := newThread(user, category, base.Add(time.Second))
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.Threads.Create(ctx, created); err != nil {
+			return err
+		}
+
+		if _, err := r.Threads.UpdateVoteCount(ctx, thread.ID, 5); err != nil {
+			return err
+		}
+
+		if err := r.Votes.UpsertThreadVote(ctx, &domain.ThreadVote{UserID: user.ID, ThreadID: thread.ID, VoteType: 1}); err != nil {
+			return err
+		}
+
+		// A nested call joins the transaction and is undone with it.
+		return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := r.Threads.Delete(ctx, thread.ID); err != nil {
+				return err
+			}
+
+			return fail
+		})
+	})
+	if !errors.Is(err, fail) {
+		t.Fatalf("WithinTx: got %v, want %v", err, fail)
+	}
+
+	if _, err := r.Threads.GetByID(ctx, created.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByID of a thread created in a failed transaction: got %v, want ErrNotFound", err)
+	}
+
+	got, err := r.Threads.GetByID(ctx, thread.ID)
+	if err != nil {
+		t.Fatalf("GetByID of a thread deleted in a failed transaction: %v", err)
+	}
+
+	if got.VoteCount != 0 {
+		t.Errorf("VoteCount after a failed transaction: got %d, want 0", got.VoteCount)
+	}
+
+	if _, err := r.Votes.GetThreadVoteForUpdate(ctx, user.ID, thread.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("vote cast in a failed transaction: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := r.Threads.UpdateVoteCount(ctx, thread.ID, 2)
+
+		return err
+	}); err != nil {
+		t.Fatalf("WithinTx: %v", err)
+	}
+
+	if got, err := r.Threads.GetByID(ctx, thread.ID); err != nil || got.VoteCount != 2 {
+		t.Errorf("VoteCount after a committed transaction: got %+v, %v, want 2", got, err)
+	}
+}
+
+func seed(t *testing.T, r Repos, at time.Time) (*domain.User, *domain.Category) {
+	t.Helper()
+
+	user := newUser("author", "member", at)
+	mustCreateUsers(t, r, user)
+
+	category := newCategory("general", at)
+	mustCreateCategories(t, r, category)
+
+	return user, category
+}
+
+func newUser(username, role string, createdAt time.Time) *domain.User {
+	return &domain.User{
+		ID:           uuid.New(),
+		Username:     username,
+		Email:        username + "@example.com",
+		PasswordHash: "hash-" + username,
+		Role:         role,
+		CreatedAt:    createdAt,
+	}
+}
+
+func newCategory(name string, createdAt time.Time) *domain.Category {
+	return &domain.Category{
+		ID:        uuid.New(),
+		Name:      name,
+		Slug:      name,
+		CreatedAt: createdAt,
+	}
+}
+
+func newThread(user *domain.User, category *domain.Category, createdAt time.Time) *domain.Thread {
+	return &domain.Thread{
+		ID:          uuid.New(),
+		Title:       "Thread " + createdAt.Format(time.RFC3339Nano),
+		Slug:        "thread",
+		Content:     "Content",
+		ContentHTML: "<p>Content</p>",
+		UserID:      user.ID,
+		CategoryID:  category.ID,
+		CreatedAt:   createdAt,
+	}
+}
+
+func newPost(user *domain.User, thread *domain.Thread, parentID *uuid.UUID, createdAt time.Time) *domain.Post {
+	return &domain.Post{
+		ID:           uuid.New(),
+		Content:      "Reply",
+		ContentHTML:  "<p>Reply</p>",
+		UserID:       user.ID,
+		ThreadID:     thread.ID,
+		ParentPostID: parentID,
+		CreatedAt:    createdAt,
+	}
+}
+
+func mustCreateUsers(t *testing.T, r Repos, users ...*domain.User) {
+	t.Helper()
+
+	for _, u := range users {
+		if err := r.Users.Create(context.Background(), u); err != nil {
+			t.Fatalf("Create user %s: %v", u.Username, err)
+		}
+	}
+}
+
+func mustCreateCategories(t *testing.T, r Repos, categories ...*domain.Category) {
+	t.Helper()
+
+	for _, c := range categories {
+		if err := r.Categories.Create(context.Background(), c); err != nil {
+			t.Fatalf("Create category %s: %v", c.Name, err)
+		}
+	}
+}
+
+func mustCreateThreads(t *testing.T, r Repos, threads ...*domain.Thread) {
+	t.Helper()
+
+	for _, th := range threads {
+		if err := r.Threads.Create(context.Background(), th); err != nil {
+			t.Fatalf("Create thread: %v", err)
+		}
+	}
+}
+
+func mustCreatePosts(t *testing.T, r Repos, posts ...*domain.Post) {
+	t.Helper()
+
+	for _, p := range posts {
+		if err := r.Posts.Create(context.Background(), p); err != nil {
+			t.Fatalf("Create post: %v", err)
+		}
+	}
+}
+
+func assertUserIDs(t *testing.T, name string, users []*domain.User, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(users))
+	for i, u := range users {
+		got[i] = u.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
+
+func assertCategoryIDs(t *testing.T, name string, categories []*domain.Category, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(categories))
+	for i, c := range categories {
+		got[i] = c.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
+
+func assertThreadIDs(t *testing.T, name string, threads []*domain.Thread, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(threads))
+	for i, th := range threads {
+		got[i] = th.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
+
+func assertPostIDs(t *testing.T, name string, posts []*domain.Post, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(posts))
+	for i, p := range posts {
+		got[i] = p.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
+
+func assertIDs(t *testing.T, name string, got, want []uuid.UUID) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("%s returned %v, want %v", name, got, want)
+
+		return
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s returned %v, want %v", name, got, want)
+
+			return
+		}
+	}
+}
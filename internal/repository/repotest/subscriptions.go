@@ -0,0 +1,192 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+func testSubscriptions(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	author, category := seed(t, r, base)
+	reader := newUser("reader", "member", base)
+	mustCreateUsers(t, r, reader)
+
+	quiet := newThread(author, category, base.Add(time.Minute))
+	busy := newThread(author, category, base)
+	trashed := newThread(author, category, base)
+	mustCreateThreads(t, r, quiet, busy, trashed)
+	mustCreatePosts(t, r, newPost(author, busy, nil, base.Add(2*time.Minute)))
+
+	for _, thread := range []*domain.Thread{quiet, busy, busy, trashed} {
+		if err := r.Subscriptions.Subscribe(ctx, reader.ID, thread.ID); err != nil {
+			t.Fatalf("Subscribe: %v", err)
+		}
+	}
+
+	if err := r.Threads.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete thread: %v", err)
+	}
+
+	// Subscriptions are listed most recently active first, leaving out
+	// trashed threads.
+	states, err := r.Subscriptions.GetByUserID(ctx, reader.ID, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	assertReadStateIDs(t, "GetByUserID", states, busy.ID, quiet.ID)
+
+	if got := states[0]; got.SubscribedAt == nil || got.UnreadCount != 1 || !got.LastActivityAt.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("GetByUserID returned %+v, want subscribed with one unread post", got)
+	}
+
+	if got := states[1]; got.UnreadCount != 0 || !got.LastActivityAt.Equal(quiet.CreatedAt) {
+		t.Errorf("GetByUserID returned %+v, want no unread posts and activity when created", got)
+	}
+
+	page, err := r.Subscriptions.GetByUserID(ctx, reader.ID, usecase.PaginationParams{Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	assertReadStateIDs(t, "GetByUserID second page", page, quiet.ID)
+
+	if count, err := r.Subscriptions.CountByUserID(ctx, reader.ID); err != nil || count != 2 {
+		t.Errorf("CountByUserID: got %d, %v, want 2", count, err)
+	}
+
+	unread, err := r.Subscriptions.GetUnreadByUserID(ctx, reader.ID, 10)
+	if err != nil {
+		t.Fatalf("GetUnreadByUserID: %v", err)
+	}
+
+	assertReadStateIDs(t, "GetUnreadByUserID", unread, busy.ID)
+
+	for i := 0; i < 2; i++ {
+		if err := r.Subscriptions.Unsubscribe(ctx, reader.ID, busy.ID); err != nil {
+			t.Fatalf("Unsubscribe: %v", err)
+		}
+	}
+
+	if count, err := r.Subscriptions.CountByUserID(ctx, reader.ID); err != nil || count != 1 {
+		t.Errorf("CountByUserID after Unsubscribe: got %d, %v, want 1", count, err)
+	}
+
+	unread, err = r.Subscriptions.GetUnreadByUserID(ctx, reader.ID, 10)
+	if err != nil {
+		t.Fatalf("GetUnreadByUserID: %v", err)
+	}
+
+	assertReadStateIDs(t, "GetUnreadByUserID after Unsubscribe", unread)
+
+	if err := r.Threads.Restore(ctx, trashed.ID); err != nil {
+		t.Fatalf("Restore thread: %v", err)
+	}
+
+	if count, err := r.Subscriptions.CountByUserID(ctx, reader.ID); err != nil || count != 2 {
+		t.Errorf("CountByUserID after restoring a thread: got %d, %v, want 2", count, err)
+	}
+}
+
+func testReadPositions(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	author, category := seed(t, r, base)
+	reader := newUser("reader", "member", base)
+	mustCreateUsers(t, r, reader)
+	thread := newThread(author, category, base)
+	mustCreateThreads(t, r, thread)
+
+	first := newPost(author, thread, nil, base.Add(time.Second))
+	own := newPost(reader, thread, nil, base.Add(2*time.Second))
+	last := newPost(author, thread, nil, base.Add(3*time.Second))
+	mustCreatePosts(t, r, first, own, last)
+
+	if _, err := r.Subscriptions.GetReadState(ctx, reader.ID, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetReadState of unknown thread: got %v, want ErrNotFound", err)
+	}
+
+	// The reader's own post never counts as unread, subscribed or not.
+	state := mustGetReadState(t, r, reader, thread)
+	if state.SubscribedAt != nil || state.LastReadAt != nil || state.UnreadCount != 2 || state.FirstUnreadPostID == nil || *state.FirstUnreadPostID != first.ID {
+		t.Errorf("GetReadState before reading returned %+v, want two unread from %s", state, first.ID)
+	}
+
+	mustSaveReadPosition(t, r, reader, thread, first)
+
+	state = mustGetReadState(t, r, reader, thread)
+	if state.LastReadPostID == nil || *state.LastReadPostID != first.ID || state.LastReadAt == nil || !state.LastReadAt.Equal(first.CreatedAt) ||
+		state.UnreadCount != 1 || state.FirstUnreadPostID == nil || *state.FirstUnreadPostID != last.ID {
+		t.Errorf("GetReadState after reading the first post returned %+v, want %s unread", state, last.ID)
+	}
+
+	// An older position is ignored.
+	mustSaveReadPosition(t, r, reader, thread, nil)
+
+	if state := mustGetReadState(t, r, reader, thread); state.LastReadPostID == nil || *state.LastReadPostID != first.ID || state.UnreadCount != 1 {
+		t.Errorf("GetReadState after saving an older position returned %+v, want the position kept", state)
+	}
+
+	mustSaveReadPosition(t, r, reader, thread, last)
+
+	if state := mustGetReadState(t, r, reader, thread); state.UnreadCount != 0 || state.FirstUnreadPostID != nil {
+		t.Errorf("GetReadState after reading the last post returned %+v, want nothing unread", state)
+	}
+
+	if state := mustGetReadState(t, r, author, thread); state.UnreadCount != 1 || state.FirstUnreadPostID == nil || *state.FirstUnreadPostID != own.ID {
+		t.Errorf("GetReadState of another user returned %+v, want only %s unread", state, own.ID)
+	}
+
+	if err := r.Threads.Delete(ctx, thread.ID); err != nil {
+		t.Fatalf("Delete thread: %v", err)
+	}
+
+	if _, err := r.Subscriptions.GetReadState(ctx, reader.ID, thread.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetReadState of a trashed thread: got %v, want ErrNotFound", err)
+	}
+}
+
+func mustGetReadState(t *testing.T, r Repos, user *domain.User, thread *domain.Thread) *domain.ThreadReadState {
+	t.Helper()
+
+	state, err := r.Subscriptions.GetReadState(context.Background(), user.ID, thread.ID)
+	if err != nil {
+		t.Fatalf("GetReadState: %v", err)
+	}
+
+	return state
+}
+
+// mustSaveReadPosition saves the user's position at post, or at the thread
+// itself when post is nil.
+func mustSaveReadPosition(t *testing.T, r Repos, user *domain.User, thread *domain.Thread, post *domain.Post) {
+	t.Helper()
+
+	pos := &domain.ThreadReadPosition{UserID: user.ID, ThreadID: thread.ID, LastReadAt: thread.CreatedAt, UpdatedAt: time.Now()}
+	if post != nil {
+		pos.LastReadPostID = &post.ID
+		pos.LastReadAt = post.CreatedAt
+	}
+
+	if err := r.Subscriptions.SaveReadPosition(context.Background(), pos); err != nil {
+		t.Fatalf("SaveReadPosition: %v", err)
+	}
+}
+
+func assertReadStateIDs(t *testing.T, name string, states []*domain.ThreadReadState, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(states))
+	for i, s := range states {
+		got[i] = s.ThreadID
+	}
+
+	assertIDs(t, name, got, want)
+}
@@ -0,0 +1,248 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+func testTags(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	explicit := newThread(user, category, base)
+	replied := newThread(user, category, base.Add(time.Second))
+	trashed := newThread(user, category, base.Add(2*time.Second))
+	mustCreateThreads(t, r, explicit, replied, trashed)
+	reply := newPost(user, replied, nil, base)
+	mustCreatePosts(t, r, reply)
+
+	if _, err := r.Tags.GetByName(ctx, "go"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByName of unknown tag: got %v, want ErrNotFound", err)
+	}
+
+	tags := mustUpsertTags(t, r, "go", "golang", "rust")
+	again := mustUpsertTags(t, r, "go", "java")
+	if again["go"].ID != tags["go"].ID {
+		t.Errorf("UpsertByNames of an existing tag returned %s, want %s", again["go"].ID, tags["go"].ID)
+	}
+
+	goTag, golang, rust, java := tags["go"], tags["golang"], tags["rust"], again["java"]
+	if got, err := r.Tags.GetByName(ctx, "java"); err != nil || got.ID != java.ID {
+		t.Errorf("GetByName: got %+v, %v, want %s", got, err, java.ID)
+	}
+
+	mustReplaceThreadTags(t, r, explicit, domain.TagSourceExplicit, rust.ID)
+	mustReplaceThreadTags(t, r, explicit, domain.TagSourceContent, goTag.ID, rust.ID)
+	mustReplaceThreadTags(t, r, trashed, domain.TagSourceContent, goTag.ID)
+	if err := r.Tags.ReplacePostTags(ctx, reply.ID, []uuid.UUID{golang.ID}); err != nil {
+		t.Fatalf("ReplacePostTags: %v", err)
+	}
+
+	if err := r.Threads.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete thread: %v", err)
+	}
+
+	byThread, err := r.Tags.GetByThreadIDs(ctx, []uuid.UUID{explicit.ID, replied.ID, trashed.ID})
+	if err != nil {
+		t.Fatalf("GetByThreadIDs: %v", err)
+	}
+
+	assertTagNames(t, "GetByThreadIDs of a thread tagged from both sources", byThread[explicit.ID], "go", "rust")
+	assertTagNames(t, "GetByThreadIDs of a thread tagged only in a post", byThread[replied.ID])
+	assertTagNames(t, "GetByThreadIDs of a trashed thread", byThread[trashed.ID], "go")
+
+	// Search counts live threads tagged themselves, not through their posts.
+	found, err := r.Tags.Search(ctx, "", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	assertTagNames(t, "Search", found, "go", "rust", "golang", "java")
+	assertTagCounts(t, "Search", found, 1, 1, 0, 0)
+
+	found, err = r.Tags.Search(ctx, "go", 1)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	assertTagNames(t, "Search by prefix", found, "go")
+
+	found, err = r.Tags.Search(ctx, "%", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	assertTagNames(t, "Search for a LIKE wildcard", found)
+
+	assertThreadIDs(t, "GetByTagID of a tag used in a post", mustGetByTagID(t, r, golang.ID), replied.ID)
+	assertThreadIDs(t, "GetByTagID", mustGetByTagID(t, r, goTag.ID), explicit.ID)
+
+	// Replacing one source leaves the other alone.
+	mustReplaceThreadTags(t, r, explicit, domain.TagSourceContent)
+
+	byThread, err = r.Tags.GetByThreadIDs(ctx, []uuid.UUID{explicit.ID})
+	if err != nil {
+		t.Fatalf("GetByThreadIDs: %v", err)
+	}
+
+	assertTagNames(t, "GetByThreadIDs after replacing the content tags", byThread[explicit.ID], "rust")
+
+	if err := r.Tags.ReplacePostTags(ctx, reply.ID, nil); err != nil {
+		t.Fatalf("ReplacePostTags: %v", err)
+	}
+
+	if count, err := r.Threads.CountByTagID(ctx, golang.ID); err != nil || count != 0 {
+		t.Errorf("CountByTagID after clearing the post's tags: got %d, %v, want 0", count, err)
+	}
+}
+
+func testTagRenameAndMerge(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	user, category := seed(t, r, base)
+	first := newThread(user, category, base)
+	second := newThread(user, category, base.Add(time.Second))
+	mustCreateThreads(t, r, first, second)
+	reply := newPost(user, second, nil, base)
+	mustCreatePosts(t, r, reply)
+
+	tags := mustUpsertTags(t, r, "go", "golang", "rust")
+	goTag, golang := tags["go"], tags["golang"]
+
+	if err := r.Tags.Rename(ctx, golang.ID, "go"); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Rename to a taken name: got %v, want ErrConflict", err)
+	}
+
+	if err := r.Tags.Rename(ctx, uuid.New(), "new"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Rename of unknown tag: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Tags.Rename(ctx, tags["rust"].ID, "rustlang"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	if got, err := r.Tags.GetByName(ctx, "rustlang"); err != nil || got.ID != tags["rust"].ID {
+		t.Errorf("GetByName after Rename: got %+v, %v, want %s", got, err, tags["rust"].ID)
+	}
+
+	if _, err := r.Tags.GetByName(ctx, "rust"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByName of the old name: got %v, want ErrNotFound", err)
+	}
+
+	// A thread carrying both tags keeps one of each source after the merge.
+	mustReplaceThreadTags(t, r, first, domain.TagSourceExplicit, goTag.ID, golang.ID)
+	mustReplaceThreadTags(t, r, second, domain.TagSourceContent, golang.ID)
+	if err := r.Tags.ReplacePostTags(ctx, reply.ID, []uuid.UUID{golang.ID, goTag.ID}); err != nil {
+		t.Fatalf("ReplacePostTags: %v", err)
+	}
+
+	if err := r.Tags.Merge(ctx, golang.ID, goTag.ID); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	if _, err := r.Tags.GetByName(ctx, "golang"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByName of a merged tag: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Tags.Merge(ctx, golang.ID, goTag.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Merge of a merged tag: got %v, want ErrNotFound", err)
+	}
+
+	byThread, err := r.Tags.GetByThreadIDs(ctx, []uuid.UUID{first.ID, second.ID})
+	if err != nil {
+		t.Fatalf("GetByThreadIDs: %v", err)
+	}
+
+	assertTagNames(t, "GetByThreadIDs after Merge", byThread[first.ID], "go")
+	assertTagNames(t, "GetByThreadIDs of a thread moved by Merge", byThread[second.ID], "go")
+
+	if count, err := r.Threads.CountByTagID(ctx, goTag.ID); err != nil || count != 2 {
+		t.Errorf("CountByTagID after Merge: got %d, %v, want 2", count, err)
+	}
+
+	found, err := r.Tags.Search(ctx, "go", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	assertTagNames(t, "Search after Merge", found, "go")
+	assertTagCounts(t, "Search after Merge", found, 2)
+}
+
+func mustUpsertTags(t *testing.T, r Repos, names ...string) map[string]*domain.Tag {
+	t.Helper()
+
+	tags, err := r.Tags.UpsertByNames(context.Background(), names)
+	if err != nil {
+		t.Fatalf("UpsertByNames: %v", err)
+	}
+
+	byName := make(map[string]*domain.Tag, len(tags))
+	for _, tag := range tags {
+		byName[tag.Name] = tag
+	}
+
+	if len(byName) != len(names) {
+		t.Fatalf("UpsertByNames(%v) returned %d tags", names, len(byName))
+	}
+
+	return byName
+}
+
+func mustReplaceThreadTags(t *testing.T, r Repos, thread *domain.Thread, source string, tagIDs ...uuid.UUID) {
+	t.Helper()
+
+	if err := r.Tags.ReplaceThreadTags(context.Background(), thread.ID, source, tagIDs); err != nil {
+		t.Fatalf("ReplaceThreadTags: %v", err)
+	}
+}
+
+func mustGetByTagID(t *testing.T, r Repos, tagID uuid.UUID) []*domain.Thread {
+	t.Helper()
+
+	threads, err := r.Threads.GetByTagID(context.Background(), tagID, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByTagID: %v", err)
+	}
+
+	return threads
+}
+
+func assertTagNames(t *testing.T, name string, tags []*domain.Tag, want ...string) {
+	t.Helper()
+
+	got := make([]string, len(tags))
+	for i, tag := range tags {
+		got[i] = tag.Name
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("%s returned %v, want %v", name, got, want)
+
+		return
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s returned %v, want %v", name, got, want)
+
+			return
+		}
+	}
+}
+
+func assertTagCounts(t *testing.T, name string, tags []*domain.Tag, want ...int) {
+	t.Helper()
+
+	for i, tag := range tags {
+		if i < len(want) && tag.ThreadCount != want[i] {
+			t.Errorf("%s returned %d threads for %s, want %d", name, tag.ThreadCount, tag.Name, want[i])
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+func testWebhooks(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	admin := newUser("admin", "admin", base)
+	mustCreateUsers(t, r, admin)
+
+	if _, err := r.Webhooks.GetByID(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByID of unknown webhook: got %v, want ErrNotFound", err)
+	}
+
+	first := newWebhook(admin, base, domain.WebhookEventPostCreated, domain.WebhookEventThreadCreated)
+	description := "Chat bridge"
+	first.Description = &description
+	second := newWebhook(admin, base.Add(time.Second), domain.WebhookEventThreadCreated)
+	second.IsActive = false
+	mustCreateWebhooks(t, r, second, first)
+
+	got, err := r.Webhooks.GetByID(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if got.URL != first.URL || got.Secret != first.Secret || got.Description == nil || *got.Description != description || !got.IsActive ||
+		got.CreatedBy == nil || *got.CreatedBy != admin.ID || !got.CreatedAt.Equal(first.CreatedAt) || !slices.Equal(got.Events, first.Events) {
+		t.Errorf("GetByID returned %+v, want %+v", got, first)
+	}
+
+	all, err := r.Webhooks.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+
+	assertWebhookIDs(t, "GetAll", all, first.ID, second.ID)
+
+	if !slices.Equal(all[1].Events, second.Events) {
+		t.Errorf("GetAll returned events %v, want %v", all[1].Events, second.Events)
+	}
+
+	active, err := r.Webhooks.GetActiveByEvent(ctx, domain.WebhookEventThreadCreated)
+	if err != nil {
+		t.Fatalf("GetActiveByEvent: %v", err)
+	}
+
+	assertWebhookIDs(t, "GetActiveByEvent", active, first.ID)
+
+	active, err = r.Webhooks.GetActiveByEvent(ctx, domain.WebhookEventVoteCast)
+	if err != nil {
+		t.Fatalf("GetActiveByEvent: %v", err)
+	}
+
+	assertWebhookIDs(t, "GetActiveByEvent of an event nobody subscribed to", active)
+
+	updatedAt := base.Add(time.Minute)
+	second.URL = "https://example.com/other"
+	second.IsActive = true
+	second.Events = []string{domain.WebhookEventVoteCast}
+	second.UpdatedAt = &updatedAt
+	if err := r.Webhooks.Update(ctx, second); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err = r.Webhooks.GetByID(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if got.URL != second.URL || !got.IsActive || !slices.Equal(got.Events, second.Events) || got.UpdatedAt == nil || !got.UpdatedAt.Equal(updatedAt) ||
+		got.CreatedBy == nil || *got.CreatedBy != admin.ID || !got.CreatedAt.Equal(second.CreatedAt) {
+		t.Errorf("GetByID after Update returned %+v, want %+v", got, second)
+	}
+
+	active, err = r.Webhooks.GetActiveByEvent(ctx, domain.WebhookEventThreadCreated)
+	if err != nil {
+		t.Fatalf("GetActiveByEvent: %v", err)
+	}
+
+	assertWebhookIDs(t, "GetActiveByEvent after Update", active, first.ID)
+
+	if err := r.Webhooks.Update(ctx, newWebhook(admin, base)); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Update of unknown webhook: got %v, want ErrNotFound", err)
+	}
+
+	if err := r.Webhooks.Delete(ctx, second.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if err := r.Webhooks.Delete(ctx, second.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Delete of a deleted webhook: got %v, want ErrNotFound", err)
+	}
+
+	all, err = r.Webhooks.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+
+	assertWebhookIDs(t, "GetAll after Delete", all, first.ID)
+}
+
+func testWebhookDeliveries(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := baseTime()
+	now := base.Add(time.Hour)
+	lease := time.Minute
+	admin := newUser("admin", "admin", base)
+	mustCreateUsers(t, r, admin)
+
+	webhook := newWebhook(admin, base, domain.WebhookEventThreadCreated)
+	paused := newWebhook(admin, base, domain.WebhookEventThreadCreated)
+	paused.IsActive = false
+	mustCreateWebhooks(t, r, webhook, paused)
+
+	eventID := uuid.New()
+	original := newDelivery(webhook, eventID, base, base)
+	duplicate := newDelivery(webhook, eventID, base, base)
+	redelivery := newDelivery(webhook, eventID, base.Add(time.Second), base.Add(time.Second))
+	redelivery.RedeliveryOf = &original.ID
+	notDue := newDelivery(webhook, uuid.New(), base.Add(2*time.Second), now.Add(time.Hour))
+	held := newDelivery(paused, eventID, base, base)
+	if err := r.Webhooks.CreateDeliveries(ctx, []*domain.WebhookDelivery{original, duplicate, redelivery, notDue, held}); err != nil {
+		t.Fatalf("CreateDeliveries: %v", err)
+	}
+
+	// Dispatching an event again queues nothing new.
+	if err := r.Webhooks.CreateDeliveries(ctx, []*domain.WebhookDelivery{newDelivery(webhook, eventID, base, base)}); err != nil {
+		t.Fatalf("CreateDeliveries again: %v", err)
+	}
+
+	if _, err := r.Webhooks.GetDeliveryByID(ctx, duplicate.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetDeliveryByID of a duplicate delivery: got %v, want ErrNotFound", err)
+	}
+
+	if count, err := r.Webhooks.CountDeliveriesByWebhookID(ctx, webhook.ID); err != nil || count != 3 {
+		t.Errorf("CountDeliveriesByWebhookID: got %d, %v, want 3", count, err)
+	}
+
+	page, err := r.Webhooks.GetDeliveriesByWebhookID(ctx, webhook.ID, usecase.PaginationParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("GetDeliveriesByWebhookID: %v", err)
+	}
+
+	assertDeliveryIDs(t, "GetDeliveriesByWebhookID first page", page, notDue.ID, redelivery.ID)
+
+	page, err = r.Webhooks.GetDeliveriesByWebhookID(ctx, webhook.ID, usecase.PaginationParams{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("GetDeliveriesByWebhookID: %v", err)
+	}
+
+	assertDeliveryIDs(t, "GetDeliveriesByWebhookID second page", page, original.ID)
+
+	got, err := r.Webhooks.GetDeliveryByID(ctx, redelivery.ID)
+	if err != nil {
+		t.Fatalf("GetDeliveryByID: %v", err)
+	}
+
+	if got.WebhookID != webhook.ID || got.EventID != eventID || got.EventType != redelivery.EventType || got.Status != domain.WebhookDeliveryPending ||
+		got.RedeliveryOf == nil || *got.RedeliveryOf != original.ID || !got.CreatedAt.Equal(redelivery.CreatedAt) {
+		t.Errorf("GetDeliveryByID returned %+v, want %+v", got, redelivery)
+	}
+
+	claimed, err := r.Webhooks.ClaimDueDeliveries(ctx, now, lease, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueDeliveries: %v", err)
+	}
+
+	assertDeliveryIDs(t, "ClaimDueDeliveries", claimed, original.ID, redelivery.ID)
+
+	for _, d := range claimed {
+		if !d.NextAttemptAt.Equal(now.Add(lease)) {
+			t.Errorf("ClaimDueDeliveries returned next attempt %v, want it leased until %v", d.NextAttemptAt, now.Add(lease))
+		}
+	}
+
+	claimed, err = r.Webhooks.ClaimDueDeliveries(ctx, now, lease, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueDeliveries: %v", err)
+	}
+
+	assertDeliveryIDs(t, "ClaimDueDeliveries of leased deliveries", claimed)
+
+	status := 200
+	body := "ok"
+	original.Status = domain.WebhookDeliveryDelivered
+	original.Attempts = 1
+	original.LastAttemptAt = &now
+	original.ResponseStatus = &status
+	original.ResponseBody = &body
+	original.DeliveredAt = &now
+	if err := r.Webhooks.SaveDeliveryResult(ctx, original); err != nil {
+		t.Fatalf("SaveDeliveryResult: %v", err)
+	}
+
+	got, err = r.Webhooks.GetDeliveryByID(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("GetDeliveryByID: %v", err)
+	}
+
+	if got.Status != domain.WebhookDeliveryDelivered || got.Attempts != 1 || got.ResponseStatus == nil || *got.ResponseStatus != status ||
+		got.ResponseBody == nil || *got.ResponseBody != body || got.DeliveredAt == nil || !got.DeliveredAt.Equal(now) {
+		t.Errorf("GetDeliveryByID after SaveDeliveryResult returned %+v, want %+v", got, original)
+	}
+
+	lastError := "connection refused"
+	redelivery.Status = domain.WebhookDeliveryFailed
+	redelivery.Attempts = 5
+	redelivery.LastError = &lastError
+	if err := r.Webhooks.SaveDeliveryResult(ctx, redelivery); err != nil {
+		t.Fatalf("SaveDeliveryResult: %v", err)
+	}
+
+	// Finished deliveries are never claimed again, however long they wait.
+	claimed, err = r.Webhooks.ClaimDueDeliveries(ctx, now.Add(24*time.Hour), lease, 10)
+	if err != nil {
+		t.Fatalf("ClaimDueDeliveries: %v", err)
+	}
+
+	assertDeliveryIDs(t, "ClaimDueDeliveries after SaveDeliveryResult", claimed, notDue.ID)
+
+	if purged, err := r.Webhooks.PurgeDeliveriesBefore(ctx, base.Add(time.Second)); err != nil || purged != 1 {
+		t.Errorf("PurgeDeliveriesBefore: got %d, %v, want 1", purged, err)
+	}
+
+	if got, err := r.Webhooks.GetDeliveryByID(ctx, redelivery.ID); err != nil || got.RedeliveryOf != nil {
+		t.Errorf("GetDeliveryByID of a redelivery of a purged delivery: got %+v, %v, want it without RedeliveryOf", got, err)
+	}
+
+	if purged, err := r.Webhooks.PurgeDeliveriesBefore(ctx, now.Add(24*time.Hour)); err != nil || purged != 1 {
+		t.Errorf("PurgeDeliveriesBefore: got %d, %v, want 1 leaving the pending deliveries", purged, err)
+	}
+
+	if count, err := r.Webhooks.CountDeliveriesByWebhookID(ctx, webhook.ID); err != nil || count != 1 {
+		t.Errorf("CountDeliveriesByWebhookID after PurgeDeliveriesBefore: got %d, %v, want 1", count, err)
+	}
+
+	if err := r.Webhooks.Delete(ctx, webhook.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := r.Webhooks.GetDeliveryByID(ctx, notDue.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetDeliveryByID of a deleted webhook's delivery: got %v, want ErrNotFound", err)
+	}
+
+	if _, err := r.Webhooks.GetDeliveryByID(ctx, held.ID); err != nil {
+		t.Errorf("GetDeliveryByID of another webhook's delivery: %v", err)
+	}
+}
+
+func newWebhook(createdBy *domain.User, createdAt time.Time, events ...string) *domain.Webhook {
+	return &domain.Webhook{
+		ID:        uuid.New(),
+		URL:       "https://example.com/hook",
+		Secret:    "secret",
+		IsActive:  true,
+		CreatedBy: &createdBy.ID,
+		CreatedAt: createdAt,
+		Events:    events,
+	}
+}
+
+func newDelivery(webhook *domain.Webhook, eventID uuid.UUID, createdAt, nextAttemptAt time.Time) *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhook.ID,
+		EventID:       eventID,
+		EventType:     domain.WebhookEventThreadCreated,
+		Payload:       []byte(`{}`),
+		Status:        domain.WebhookDeliveryPending,
+		NextAttemptAt: nextAttemptAt,
+		CreatedAt:     createdAt,
+	}
+}
+
+func mustCreateWebhooks(t *testing.T, r Repos, webhooks ...*domain.Webhook) {
+	t.Helper()
+
+	for _, w := range webhooks {
+		if err := r.Webhooks.Create(context.Background(), w); err != nil {
+			t.Fatalf("Create webhook: %v", err)
+		}
+	}
+}
+
+func assertWebhookIDs(t *testing.T, name string, webhooks []*domain.Webhook, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(webhooks))
+	for i, w := range webhooks {
+		got[i] = w.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
+
+func assertDeliveryIDs(t *testing.T, name string, deliveries []*domain.WebhookDelivery, want ...uuid.UUID) {
+	t.Helper()
+
+	got := make([]uuid.UUID, len(deliveries))
+	for i, d := range deliveries {
+		got[i] = d.ID
+	}
+
+	assertIDs(t, name, got, want)
+}
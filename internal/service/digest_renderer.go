@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"net/url"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+//go:embed templates/digest.html.tmpl templates/digest.txt.tmpl
+var digestTemplates embed.FS
+
+type digestRenderer struct {
+	appBaseURL string
+	apiBaseURL string
+	html       *htmltemplate.Template
+	text       *texttemplate.Template
+}
+
+// NewDigestRenderer renders digest emails. Thread links point at the web
+// app under appBaseURL, and the unsubscribe link at the API under
+// apiBaseURL, so it works without signing in.
+func NewDigestRenderer(appBaseURL, apiBaseURL string) usecase.DigestRenderer {
+	return &digestRenderer{
+		appBaseURL: strings.TrimRight(appBaseURL, "/"),
+		apiBaseURL: strings.TrimRight(apiBaseURL, "/"),
+		html:       htmltemplate.Must(htmltemplate.ParseFS(digestTemplates, "templates/digest.html.tmpl")),
+		text:       texttemplate.Must(texttemplate.ParseFS(digestTemplates, "templates/digest.txt.tmpl")),
+	}
+}
+
+type digestView struct {
+	Subject        string
+	Username       string
+	Frequency      string
+	PeriodLabel    string
+	Unread         []digestUnreadView
+	TopThreads     []digestThreadView
+	UnsubscribeURL string
+}
+
+type digestUnreadView struct {
+	Title       string
+	URL         string
+	UnreadCount int
+}
+
+type digestThreadView struct {
+	Title     string
+	URL       string
+	Category  string
+	VoteCount int
+}
+
+func (r *digestRenderer) Render(digest *domain.Digest) (*domain.EmailMessage, error) {
+	view := digestView{
+		Subject:        "Your " + digest.Recipient.Frequency + " Agora digest",
+		Username:       digest.Recipient.Username,
+		Frequency:      digest.Recipient.Frequency,
+		PeriodLabel:    "this week",
+		UnsubscribeURL: r.apiBaseURL + "/api/v1/digest/unsubscribe?token=" + url.QueryEscape(digest.UnsubscribeToken),
+	}
+	if digest.Recipient.Frequency == domain.DigestDaily {
+		view.PeriodLabel = "today"
+	}
+
+	for _, s := range digest.Unread {
+		if s.Thread == nil {
+			continue
+		}
+
+		link := r.threadURL(s.Thread)
+		if s.FirstUnreadPostID != nil {
+			link += "#post-" + s.FirstUnreadPostID.String()
+		}
+
+		view.Unread = append(view.Unread, digestUnreadView{
+			Title:       s.Thread.Title,
+			URL:         link,
+			UnreadCount: s.UnreadCount,
+		})
+	}
+
+	for _, t := range digest.TopThreads {
+		thread := digestThreadView{
+			Title:     t.Title,
+			URL:       r.threadURL(t),
+			VoteCount: t.VoteCount,
+		}
+		if cat := digest.Categories[t.CategoryID]; cat != nil {
+			thread.Category = cat.Name
+		}
+
+		view.TopThreads = append(view.TopThreads, thread)
+	}
+
+	var html, text bytes.Buffer
+	if err := r.html.Execute(&html, view); err != nil {
+		return nil, err
+	}
+
+	if err := r.text.Execute(&text, view); err != nil {
+		return nil, err
+	}
+
+	return &domain.EmailMessage{
+		To:      digest.Recipient.Email,
+		Subject: view.Subject,
+		HTML:    html.String(),
+		Text:    text.String(),
+		Headers: map[string]string{
+			// RFC 8058 one-click unsubscribe, honoured by most mail clients.
+			"List-Unsubscribe":      "<" + view.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+func (r *digestRenderer) threadURL(t *domain.Thread) string {
+	return r.appBaseURL + "/threads/" + t.ID.String()
+}
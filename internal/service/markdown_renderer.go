@@ -0,0 +1,246 @@
+package service
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/srgjo27/agora/internal/usecase"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	mentionLinkPrefix = "/users/"
+	tagLinkPrefix     = "/tags/"
+
+	maxMentionsPerContent = 20
+	maxTagsPerContent     = 10
+)
+
+var (
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w@#&/])(@([A-Za-z0-9_-]{3,50}))`)
+	tagPattern     = regexp.MustCompile(`(?:^|[^\w@#&/])(#([A-Za-z][A-Za-z0-9_-]{0,49}))`)
+
+	linkableMentionsKey = parser.NewContextKey()
+)
+
+type markdownRenderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+	// scanner parses without referenceLinker, so Extract sees references
+	// as plain text rather than as the links Render turns them into.
+	scanner parser.Parser
+}
+
+// NewMarkdownRenderer renders user-authored Markdown to HTML that is safe to
+// embed in other users' pages. Raw HTML in the source is dropped by the
+// Markdown parser, and the output is run through an allowlist sanitizer.
+func NewMarkdownRenderer() usecase.ContentRenderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+		),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(
+				util.Prioritized(&referenceLinker{}, 999),
+			),
+		),
+	)
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowURLSchemes("http", "https", "mailto")
+	policy.RequireNoFollowOnLinks(true)
+	policy.RequireNoReferrerOnLinks(true)
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^(mention|hashtag)$`)).OnElements("a")
+
+	scanner := goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser()
+
+	return &markdownRenderer{md: md, policy: policy, scanner: scanner}
+}
+
+func (r *markdownRenderer) Render(source string, mentions map[string]bool) (string, error) {
+	pc := parser.NewContext()
+	pc.Set(linkableMentionsKey, mentions)
+
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(source), &buf, parser.WithContext(pc)); err != nil {
+		return "", err
+	}
+
+	return r.policy.Sanitize(buf.String()), nil
+}
+
+func (r *markdownRenderer) Extract(source string) usecase.ContentReferences {
+	src := []byte(source)
+	doc := r.scanner.Parse(text.NewReader(src))
+
+	refs := usecase.ContentReferences{
+		Mentions: []string{},
+		Tags:     []string{},
+	}
+	seenMentions := make(map[string]bool)
+	seenTags := make(map[string]bool)
+
+	for _, node := range collectTextNodes(doc) {
+		for _, ref := range scanReferences(node.Segment.Value(src)) {
+			switch ref.kind {
+			case '@':
+				if !seenMentions[ref.name] && len(refs.Mentions) < maxMentionsPerContent {
+					seenMentions[ref.name] = true
+					refs.Mentions = append(refs.Mentions, ref.name)
+				}
+			case '#':
+				if !seenTags[ref.name] && len(refs.Tags) < maxTagsPerContent {
+					seenTags[ref.name] = true
+					refs.Tags = append(refs.Tags, ref.name)
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+type reference struct {
+	kind       byte
+	name       string
+	start, end int
+}
+
+// scanReferences finds @mentions and #tags in a run of plain text. Names are
+// lowercased; offsets cover the sigil and the name.
+func scanReferences(value []byte) []reference {
+	refs := make([]reference, 0)
+
+	for _, m := range mentionPattern.FindAllSubmatchIndex(value, -1) {
+		refs = append(refs, reference{kind: '@', name: strings.ToLower(string(value[m[4]:m[5]])), start: m[2], end: m[3]})
+	}
+
+	for _, m := range tagPattern.FindAllSubmatchIndex(value, -1) {
+		refs = append(refs, reference{kind: '#', name: strings.ToLower(string(value[m[4]:m[5]])), start: m[2], end: m[3]})
+	}
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].start < refs[j].start })
+
+	return refs
+}
+
+// collectTextNodes returns text nodes that are not inside code, links or
+// images, which are the only places references are recognised. The parser
+// splits text at characters that could delimit emphasis, such as the
+// underscore in "@jane_doe"; adjacent pieces of one run are merged back so
+// references are matched whole.
+func collectTextNodes(doc ast.Node) []*ast.Text {
+	nodes := make([]*ast.Text, 0)
+	// merged are the pieces folded into the last run; previous is the last
+	// piece seen, which is still in the tree until the walk ends.
+	var merged []*ast.Text
+	var previous *ast.Text
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n.Kind() {
+		case ast.KindCodeSpan, ast.KindCodeBlock, ast.KindFencedCodeBlock, ast.KindLink, ast.KindAutoLink, ast.KindImage, ast.KindHTMLBlock, ast.KindRawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+
+		if t, ok := n.(*ast.Text); ok && !t.IsRaw() {
+			if previous != nil && continues(previous, t) {
+				run := nodes[len(nodes)-1]
+				run.Segment = run.Segment.WithStop(t.Segment.Stop)
+				run.SetSoftLineBreak(t.SoftLineBreak())
+				run.SetHardLineBreak(t.HardLineBreak())
+				merged = append(merged, t)
+			} else {
+				nodes = append(nodes, t)
+			}
+			previous = t
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for _, t := range merged {
+		t.Parent().RemoveChild(t.Parent(), t)
+	}
+
+	return nodes
+}
+
+// continues reports whether next directly follows prev in both the tree and
+// the source, on the same line.
+func continues(prev, next *ast.Text) bool {
+	return next.PreviousSibling() == prev &&
+		prev.Segment.Stop == next.Segment.Start &&
+		!prev.SoftLineBreak() && !prev.HardLineBreak()
+}
+
+// referenceLinker turns #tags and resolved @mentions into links.
+type referenceLinker struct{}
+
+func (l *referenceLinker) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	mentions, _ := pc.Get(linkableMentionsKey).(map[string]bool)
+	source := reader.Source()
+
+	for _, node := range collectTextNodes(doc) {
+		segment := node.Segment
+		refs := scanReferences(segment.Value(source))
+		if len(refs) == 0 {
+			continue
+		}
+
+		parent := node.Parent()
+		cursor := 0
+
+		for _, ref := range refs {
+			var destination, class string
+			switch ref.kind {
+			case '@':
+				if !mentions[ref.name] {
+					continue
+				}
+				destination, class = mentionLinkPrefix+url.PathEscape(ref.name), "mention"
+			case '#':
+				destination, class = tagLinkPrefix+url.PathEscape(ref.name), "hashtag"
+			}
+
+			if ref.start > cursor {
+				before := ast.NewTextSegment(text.NewSegment(segment.Start+cursor, segment.Start+ref.start))
+				parent.InsertBefore(parent, node, before)
+			}
+
+			link := ast.NewLink()
+			link.Destination = []byte(destination)
+			link.SetAttributeString("class", []byte(class))
+			link.AppendChild(link, ast.NewTextSegment(text.NewSegment(segment.Start+ref.start, segment.Start+ref.end)))
+			parent.InsertBefore(parent, node, link)
+
+			cursor = ref.end
+		}
+
+		if cursor == 0 {
+			continue
+		}
+
+		// The remainder keeps the original line-break flags, even when empty.
+		last := ast.NewTextSegment(text.NewSegment(segment.Start+cursor, segment.Stop))
+		parent.InsertBefore(parent, node, last)
+
+		last.SetSoftLineBreak(node.SoftLineBreak())
+		last.SetHardLineBreak(node.HardLineBreak())
+		parent.RemoveChild(parent, node)
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRenderSanitizesContent(t *testing.T) {
+	r := NewMarkdownRenderer()
+
+	for _, tc := range []struct {
+		name, source, want string
+	}{
+		{"script block", "<script>alert(1)</script>", "\n"},
+		{"inline script", "hi <script>alert(1)</script> there", "<p>hi alert(1) there</p>\n"},
+		{"javascript link", "[x](javascript:alert(1))", "<p>x</p>\n"},
+		{"raw javascript anchor", `<a href="javascript:alert(1)">x</a>`, "<p>x</p>\n"},
+		{"onerror image", "<img src=x onerror=alert(1)>", "\n"},
+		{"raw html", "<b>bold</b> and <div>block</div>", "<p>bold and block</p>\n"},
+		{"image", "![x](x.png)", `<p><img src="x.png" alt="x"></p>` + "\n"},
+		{
+			"external link",
+			"[site](https://example.com)",
+			`<p><a href="https://example.com" rel="nofollow noreferrer noopener" target="_blank">site</a></p>` + "\n",
+		},
+		{"mailto link", "<mailto:a@example.com>", `<p><a href="mailto:a@example.com" rel="nofollow noreferrer">mailto:a@example.com</a></p>` + "\n"},
+		{"code language", "```go\nfmt.Println()\n```", `<pre><code class="language-go">fmt.Println()` + "\n</code></pre>\n"},
+		{"code info attributes", "```go onclick=x\nx\n```", `<pre><code class="language-go">x` + "\n</code></pre>\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.Render(tc.source, nil)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("Render(%q) = %q, want %q", tc.source, got, tc.want)
+			}
+		})
+	}
+}
+
+// The sanitizer is the last line of defence, so it is checked on HTML the
+// Markdown parser would never produce.
+func TestSanitizerPolicy(t *testing.T) {
+	policy := NewMarkdownRenderer().(*markdownRenderer).policy
+
+	for _, tc := range []struct {
+		name, html, want string
+	}{
+		{"script", `<p>a<script>alert(1)</script>b</p>`, `<p>ab</p>`},
+		{"event handler", `<img src="x.png" onerror="alert(1)">`, `<img src="x.png">`},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `x`},
+		{"data href", `<a href="data:text/html,x">x</a>`, `x`},
+		{"iframe", `<iframe src="https://example.com"></iframe>`, ``},
+		{"style", `<p style="color:red">x</p>`, `<p>x</p>`},
+		{"relative link", `<a href="/threads/1">x</a>`, `<a href="/threads/1" rel="nofollow noreferrer">x</a>`},
+		{"language class", `<code class="language-c++">x</code>`, `<code class="language-c++">x</code>`},
+		{"other code class", `<code class="evil">x</code>`, `<code>x</code>`},
+		{"code class with spaces", `<code class="language-go evil">x</code>`, `<code>x</code>`},
+		{"mention class", `<a href="/users/a" class="mention">@a</a>`, `<a href="/users/a" class="mention" rel="nofollow noreferrer">@a</a>`},
+		{"other link class", `<a href="/users/a" class="button">@a</a>`, `<a href="/users/a" rel="nofollow noreferrer">@a</a>`},
+		{"class on other element", `<p class="language-go">x</p>`, `<p>x</p>`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := policy.Sanitize(tc.html); got != tc.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tc.html, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderLinksReferences(t *testing.T) {
+	r := NewMarkdownRenderer()
+	known := map[string]bool{"alice": true, "jane_doe": true}
+
+	const (
+		alice  = `<a href="/users/alice" class="mention" rel="nofollow noreferrer">@alice</a>`
+		golang = `<a href="/tags/go" class="hashtag" rel="nofollow noreferrer">#go</a>`
+	)
+
+	for _, tc := range []struct {
+		name, source, want string
+	}{
+		{"mention and tag", "hi @alice, see #go", "<p>hi " + alice + ", see " + golang + "</p>\n"},
+		{"unknown user", "hi @bob", "<p>hi @bob</p>\n"},
+		{"case", "@Alice", `<p><a href="/users/alice" class="mention" rel="nofollow noreferrer">@Alice</a></p>` + "\n"},
+		{"trailing punctuation", "@alice! #go? (#go) #go.", "<p>" + alice + "! " + golang + "? (" + golang + ") " + golang + ".</p>\n"},
+		{
+			"underscores",
+			"@jane_doe #go_lang",
+			`<p><a href="/users/jane_doe" class="mention" rel="nofollow noreferrer">@jane_doe</a> <a href="/tags/go_lang" class="hashtag" rel="nofollow noreferrer">#go_lang</a></p>` + "\n",
+		},
+		{"emphasis", "**@alice** _#go_", "<p><strong>" + alice + "</strong> <em>" + golang + "</em></p>\n"},
+		{"line breaks", "@alice\n#go  \nend", "<p>" + alice + "\n" + golang + "<br>\nend</p>\n"},
+		{"code span", "`@alice #go`", "<p><code>@alice #go</code></p>\n"},
+		{"fenced block", "```\n@alice #go\n```", "<pre><code>@alice #go\n</code></pre>\n"},
+		{"indented block", "    @alice #go", "<pre><code>@alice #go\n</code></pre>\n"},
+		{
+			"existing link",
+			"[@alice #go](https://example.com)",
+			`<p><a href="https://example.com" rel="nofollow noreferrer noopener" target="_blank">@alice #go</a></p>` + "\n",
+		},
+		{
+			"autolink",
+			"https://example.com/@alice#go",
+			`<p><a href="https://example.com/@alice#go" rel="nofollow noreferrer noopener" target="_blank">https://example.com/@alice#go</a></p>` + "\n",
+		},
+		{"email", "a@alice.com", `<p><a href="mailto:a@alice.com" rel="nofollow noreferrer">a@alice.com</a></p>` + "\n"},
+		{"inside words", "x@alice x#go &#35;go", "<p>x@alice x#go #go</p>\n"},
+		{"heading", "# go", "<h1>go</h1>\n"},
+		{"numeric tag", "#1", "<p>#1</p>\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.Render(tc.source, known)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("Render(%q) = %q, want %q", tc.source, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExtractReferences(t *testing.T) {
+	r := NewMarkdownRenderer()
+
+	for _, tc := range []struct {
+		name, source   string
+		mentions, tags []string
+	}{
+		{"none", "plain text", []string{}, []string{}},
+		{"deduplicated and lowercased", "@Alice @alice #Go #go @bob", []string{"alice", "bob"}, []string{"go"}},
+		{"underscores", "@jane_doe #go_lang", []string{"jane_doe"}, []string{"go_lang"}},
+		{"code and links", "`@a1c`\n\n```\n@b2c\n```\n\n[@c3c #x](https://example.com) @d4c", []string{"d4c"}, []string{}},
+		{"too short", "@ab @abc", []string{"abc"}, []string{}},
+		{
+			"limits",
+			"@user_x " + numbered("@user", 25) + numbered("#tag", 15),
+			append([]string{"user_x"}, names("user", 19)...),
+			names("tag", 10),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			refs := r.Extract(tc.source)
+
+			if !slices.Equal(refs.Mentions, tc.mentions) {
+				t.Errorf("mentions = %q, want %q", refs.Mentions, tc.mentions)
+			}
+			if !slices.Equal(refs.Tags, tc.tags) {
+				t.Errorf("tags = %q, want %q", refs.Tags, tc.tags)
+			}
+		})
+	}
+}
+
+// numbered returns prefix0 prefix1 ... as space-separated words.
+func numbered(prefix string, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "%s%d ", prefix, i)
+	}
+
+	return b.String()
+}
+
+func names(prefix string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+
+	return out
+}
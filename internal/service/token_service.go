@@ -27,7 +27,7 @@ func NewTokenService(cfg *config.Config) usecase.TokenService {
 }
 
 func (s *tokenService) GenerateRefreshToken(ctx context.Context, user *domain.User) (string, error) {
-	expirationTime := time.Now().Add(time.Duration(s.cfg.RefreshTokenDurationHours) * time.Hour)
+	expirationTime := time.Now().Add(time.Duration(s.cfg.Auth.RefreshTokenDurationHours) * time.Hour)
 
 	claims := &jwtClaims{
 		UserID: user.ID,
@@ -40,11 +40,11 @@ func (s *tokenService) GenerateRefreshToken(ctx context.Context, user *domain.Us
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	return token.SignedString([]byte(s.cfg.JWTSecretKey))
+	return token.SignedString([]byte(s.cfg.Auth.JWTSecretKey))
 }
 
 func (s *tokenService) GenerateAccessToken(ctx context.Context, user *domain.User) (string, error) {
-	expirationTime := time.Now().Add(time.Duration(s.cfg.AccessTokenDurationMinutes) * time.Minute)
+	expirationTime := time.Now().Add(time.Duration(s.cfg.Auth.AccessTokenDurationMinutes) * time.Minute)
 
 	claims := &jwtClaims{
 		UserID: user.ID,
@@ -57,7 +57,7 @@ func (s *tokenService) GenerateAccessToken(ctx context.Context, user *domain.Use
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	return token.SignedString([]byte(s.cfg.JWTSecretKey))
+	return token.SignedString([]byte(s.cfg.Auth.JWTSecretKey))
 }
 
 func (s *tokenService) ValidateToken(ctx context.Context, tokenString string) (uuid.UUID, string, error) {
@@ -68,7 +68,7 @@ func (s *tokenService) ValidateToken(ctx context.Context, tokenString string) (u
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		return []byte(s.cfg.JWTSecretKey), nil
+		return []byte(s.cfg.Auth.JWTSecretKey), nil
 	})
 
 	if err != nil {
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// maxWebhookResponseBody caps how much of a receiver's response is kept in
+// the delivery log.
+const maxWebhookResponseBody = 2048
+
+type httpWebhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender posts webhook payloads with the given per-request
+// timeout. Redirects are not followed, so a receiver cannot bounce a
+// signed payload to another host. Connections to addresses that are not
+// public are refused after DNS resolution, so a hostname cannot point a
+// webhook at an internal service either.
+func NewWebhookSender(timeout time.Duration) usecase.WebhookSender {
+	return newWebhookSender(timeout, refuseNonPublicAddr)
+}
+
+func newWebhookSender(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *httpWebhookSender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be the address checked instead of the receiver's.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &httpWebhookSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// refuseNonPublicAddr is a net.Dialer Control hook that runs once the
+// address to connect to is resolved.
+func refuseNonPublicAddr(network, address string, c syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !domain.IsPublicWebhookAddr(addrPort.Addr()) {
+		return fmt.Errorf("webhook receiver address %s is not public", addrPort.Addr())
+	}
+
+	return nil
+}
+
+func (s *httpWebhookSender) Post(ctx context.Context, url string, headers map[string]string, body []byte) (*domain.WebhookResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxWebhookResponseBody))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return &domain.WebhookResponse{StatusCode: res.StatusCode, Body: string(data)}, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookSenderRefusesNonPublicAddresses(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(time.Second)
+
+	// The receiver is on loopback, both by address and by name.
+	urls := []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)}
+	for _, url := range urls {
+		if _, err := sender.Post(context.Background(), url, nil, []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "not public") {
+			t.Errorf("Post(%s) error = %v, want the address refused", url, err)
+		}
+	}
+
+	if n := hits.Load(); n != 0 {
+		t.Errorf("receiver got %d requests, want none", n)
+	}
+}
+
+func TestWebhookSenderPostsToAllowedAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Agora-Event") != "thread.created" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(strings.Repeat("x", maxWebhookResponseBody+10)))
+	}))
+	defer server.Close()
+
+	// Allow loopback here so the test server can be reached.
+	sender := newWebhookSender(time.Second, nil)
+
+	res, err := sender.Post(context.Background(), server.URL, map[string]string{"X-Agora-Event": "thread.created"}, []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+
+	if res.StatusCode != http.StatusAccepted || len(res.Body) != maxWebhookResponseBody {
+		t.Errorf("response = %d with a %d byte body, want 202 with %d bytes", res.StatusCode, len(res.Body), maxWebhookResponseBody)
+	}
+}
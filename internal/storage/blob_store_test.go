@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlobStore: %v", err)
+	}
+
+	testBlobStore(t, store)
+
+	// A blob longer than declared is rejected too, not silently truncated.
+	if err := store.Put(context.Background(), "attachments/long", strings.NewReader("too long"), 3, "text/plain"); err == nil {
+		t.Error("Put accepted more bytes than declared")
+	}
+
+	for _, key := range []string{"../escape", "attachments/../../escape", "/../escape", ".."} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put(%q) accepted a key outside the store", key)
+		}
+		if _, err := store.Get(context.Background(), key); err == nil || errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Get(%q) = %v, want an invalid key error", key, err)
+		}
+		if err := store.Delete(context.Background(), key); err == nil {
+			t.Errorf("Delete(%q) accepted a key outside the store", key)
+		}
+	}
+}
+
+func TestS3BlobStore(t *testing.T) {
+	srv := httptest.NewTLSServer(newFakeS3("uploads"))
+	t.Cleanup(srv.Close)
+
+	client, err := minio.New(strings.TrimPrefix(srv.URL, "https://"), &minio.Options{
+		Creds:     credentials.NewStaticV4("access", "secret", ""),
+		Secure:    true,
+		Region:    "us-east-1",
+		Transport: srv.Client().Transport,
+		// Fail the short upload at once instead of backing off and retrying.
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("minio.New: %v", err)
+	}
+
+	testBlobStore(t, &s3BlobStore{client: client, bucket: "uploads"})
+}
+
+// testBlobStore checks the behaviour every BlobStore shares.
+func testBlobStore(t *testing.T, store usecase.BlobStore) {
+	t.Helper()
+
+	ctx := context.Background()
+	const key = "attachments/2026/10/blob"
+	content := []byte("hello, blob")
+
+	if err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	r, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || !bytes.Equal(got, content) {
+		t.Fatalf("Get read %q, %v; want %q", got, err, content)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := store.Get(ctx, key); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+
+	if _, err := store.Get(ctx, "attachments/missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Get of a missing key = %v, want ErrNotFound", err)
+	}
+
+	if err := store.Delete(ctx, "attachments/missing"); err != nil {
+		t.Errorf("Delete of a missing key = %v, want nil", err)
+	}
+
+	if err := store.Put(ctx, "attachments/short", strings.NewReader("short"), 10, "text/plain"); err == nil {
+		t.Error("Put accepted fewer bytes than declared")
+	}
+
+	if _, err := store.Get(ctx, "attachments/short"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Get after a failed Put = %v, want ErrNotFound", err)
+	}
+}
+
+// fakeS3 serves the object calls s3BlobStore makes, for a single bucket.
+type fakeS3 struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.bucket+"/")
+	if !ok || key == "" {
+		http.Error(w, "unknown bucket", http.StatusBadRequest)
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil || int64(len(body)) != r.ContentLength {
+			http.Error(w, "incomplete body", http.StatusBadRequest)
+
+			return
+		}
+		s.objects[key] = body
+		s.types[key] = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", etag(body))
+	case http.MethodGet, http.MethodHead:
+		body, ok := s.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message><Key>%s</Key><BucketName>%s</BucketName></Error>`, key, s.bucket)
+
+			return
+		}
+		w.Header().Set("Content-Type", s.types[key])
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		w.Header().Set("ETag", etag(body))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	case http.MethodDelete:
+		delete(s.objects, key)
+		delete(s.types, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unsupported method", http.StatusMethodNotAllowed)
+	}
+}
+
+func etag(body []byte) string {
+	return fmt.Sprintf(`"%x"`, len(body))
+}
@@ -0,0 +1,122 @@
+// Package tracing sets up OpenTelemetry tracing for the API and the worker.
+// Spans are created by the Gin middleware, the usecase decorators in
+// usecase/traced and the pgx tracer; this package only decides where they
+// are exported.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
+	"github.com/srgjo27/agora/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// Setup installs the global tracer provider and the W3C trace context and
+// baggage propagators, so trace context sent by a client is continued. The
+// returned function flushes pending spans and must be called on shutdown.
+//
+// With TRACING_EXPORTER=none the provider stays a no-op: incoming trace
+// context is still propagated, but no span is recorded.
+func Setup(ctx context.Context, cfg *config.Config, serviceName string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var closeFile func() error
+
+	switch cfg.Tracing.Exporter {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		e, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		exporter = e
+	case "file":
+		f, err := os.OpenFile(cfg.Tracing.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+
+			return nil, err
+		}
+		exporter = e
+		closeFile = f.Close
+	case "otlp":
+		e, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Tracing.OTLPEndpoint))
+		if err != nil {
+			return nil, err
+		}
+		exporter = e
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Tracing.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeFile != nil {
+			if closeErr := closeFile(); err == nil {
+				err = closeErr
+			}
+		}
+
+		return err
+	}, nil
+}
+
+// NewQueryTracer returns a pgx tracer recording a span per SQL query on the
+// global tracer provider. Query parameters are never recorded.
+func NewQueryTracer() pgx.QueryTracer {
+	return otelpgx.NewTracer(otelpgx.WithSpanNameFunc(querySpanName))
+}
+
+var queryTablePattern = regexp.MustCompile(`(?i)\b(?:from|into|update|join)\s+([a-z_][a-z0-9_.]*)`)
+
+// querySpanName names a query span after its operation and first table, e.g.
+// "SELECT threads", following the OpenTelemetry database conventions. The
+// full statement is kept in the span's attributes.
+func querySpanName(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "query"
+	}
+
+	operation := strings.ToUpper(fields[0])
+	if operation == "WITH" {
+		return "WITH"
+	}
+
+	if m := queryTablePattern.FindStringSubmatch(sql); m != nil {
+		return operation + " " + m[1]
+	}
+
+	return operation
+}
@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+const (
+	sniffLength          = 512
+	maxFilenameLength    = 255
+	orphanPurgeBatchSize = 100
+)
+
+type attachmentUsecase struct {
+	txManager      TxManager
+	attachmentRepo AttachmentRepository
+	threadRepo     ThreadRepository
+	postRepo       PostRepository
+	blobStore      BlobStore
+	auditLogger    AuditLogger
+	maxSize        int64
+	quota          int64
+	logger         *slog.Logger
+}
+
+// NewAttachmentUsecase limits single uploads to maxSize bytes and each
+// user's total stored attachments to quota bytes.
+func NewAttachmentUsecase(tm TxManager, ar AttachmentRepository, tr ThreadRepository, pr PostRepository, bs BlobStore, al AuditLogger, maxSize, quota int64, logger *slog.Logger) AttachmentUsecase {
+	return &attachmentUsecase{
+		txManager:      tm,
+		attachmentRepo: ar,
+		threadRepo:     tr,
+		postRepo:       pr,
+		blobStore:      bs,
+		auditLogger:    al,
+		maxSize:        maxSize,
+		quota:          quota,
+		logger:         logger,
+	}
+}
+
+func (uc *attachmentUsecase) UploadToThread(ctx context.Context, threadID, userID uuid.UUID, role string, params UploadAttachmentParams) (*domain.Attachment, error) {
+	thread, err := uc.threadRepo.GetByID(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	isAdmin := role == "admin"
+
+	if thread.UserID != userID && !isAdmin {
+		return nil, domain.ErrForbidden
+	}
+
+	if thread.IsLocked && !isAdmin {
+		return nil, domain.ErrThreadLocked
+	}
+
+	return uc.upload(ctx, userID, &threadID, nil, params)
+}
+
+func (uc *attachmentUsecase) UploadToPost(ctx context.Context, postID, userID uuid.UUID, role string, params UploadAttachmentParams) (*domain.Attachment, error) {
+	post, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	isAdmin := role == "admin"
+
+	if post.UserID != userID && !isAdmin {
+		return nil, domain.ErrForbidden
+	}
+
+	thread, err := uc.threadRepo.GetByID(ctx, post.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if thread.IsLocked && !isAdmin {
+		return nil, domain.ErrThreadLocked
+	}
+
+	return uc.upload(ctx, userID, nil, &postID, params)
+}
+
+// upload checks the declared size and the sniffed content type before
+// anything is stored. The quota is checked up front to fail fast and again
+// atomically when the record is inserted.
+func (uc *attachmentUsecase) upload(ctx context.Context, userID uuid.UUID, threadID, postID *uuid.UUID, params UploadAttachmentParams) (*domain.Attachment, error) {
+	if params.Size <= 0 {
+		return nil, domain.Invalid(domain.FieldError{Field: "file", Rule: "not_empty"})
+	}
+
+	if params.Size > uc.maxSize {
+		return nil, domain.ErrFileTooLarge
+	}
+
+	used, err := uc.attachmentRepo.SumSizeByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if used+params.Size > uc.quota {
+		return nil, domain.ErrQuotaExceeded
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(params.Content, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !domain.AllowedAttachmentTypes[mediaType] {
+		return nil, domain.ErrUnsupportedMediaType
+	}
+
+	now := time.Now()
+	id := uuid.New()
+	attachment := &domain.Attachment{
+		ID:          id,
+		UserID:      userID,
+		ThreadID:    threadID,
+		PostID:      postID,
+		StorageKey:  path.Join("attachments", now.Format("2006/01"), id.String()),
+		Filename:    sanitizeFilename(params.Filename),
+		ContentType: contentType,
+		SizeBytes:   params.Size,
+		CreatedAt:   now,
+	}
+
+	content := io.MultiReader(bytes.NewReader(head), params.Content)
+	if err := uc.blobStore.Put(ctx, attachment.StorageKey, content, attachment.SizeBytes, contentType); err != nil {
+		return nil, err
+	}
+
+	if err := uc.attachmentRepo.CreateWithinQuota(ctx, attachment, uc.quota); err != nil {
+		if delErr := uc.blobStore.Delete(ctx, attachment.StorageKey); delErr != nil {
+			uc.logger.ErrorContext(ctx, "failed to delete blob after failed upload", "storage_key", attachment.StorageKey, "error", delErr)
+		}
+
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// Open returns the attachment and its content. Attachments of deleted
+// threads and posts are not served.
+func (uc *attachmentUsecase) Open(ctx context.Context, id uuid.UUID) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := uc.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	threadID := attachment.ThreadID
+	if attachment.PostID != nil {
+		post, err := uc.postRepo.GetByID(ctx, *attachment.PostID)
+		if err != nil {
+			return nil, nil, err
+		}
+		threadID = &post.ThreadID
+	}
+
+	if threadID == nil {
+		return nil, nil, domain.ErrNotFound
+	}
+
+	if _, err := uc.threadRepo.GetByID(ctx, *threadID); err != nil {
+		return nil, nil, err
+	}
+
+	content, err := uc.blobStore.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+func (uc *attachmentUsecase) Delete(ctx context.Context, id, userID uuid.UUID, role string) error {
+	attachment, err := uc.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	isOwner := attachment.UserID == userID
+
+	if !isOwner && role != "admin" {
+		return domain.ErrForbidden
+	}
+
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if !isOwner {
+			if err := recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+				ActorID:    userID,
+				ActorRole:  role,
+				Action:     domain.AuditActionAttachmentDelete,
+				TargetType: domain.AuditTargetAttachment,
+				TargetID:   attachment.ID,
+				Details:    &attachment.Filename,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return uc.remove(ctx, attachment)
+	})
+}
+
+// PurgeOrphaned removes attachments whose thread or post has been purged.
+func (uc *attachmentUsecase) PurgeOrphaned(ctx context.Context) (int, error) {
+	purged := 0
+
+	for {
+		attachments, err := uc.attachmentRepo.GetOrphaned(ctx, orphanPurgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, a := range attachments {
+			if err := uc.remove(ctx, a); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(attachments) < orphanPurgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// remove deletes the blob before the record, so a failure never leaves a
+// blob that no record points to.
+func (uc *attachmentUsecase) remove(ctx context.Context, attachment *domain.Attachment) error {
+	if err := uc.blobStore.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+
+	return uc.attachmentRepo.Delete(ctx, attachment.ID)
+}
+
+// sanitizeFilename keeps only the base name of a client-supplied filename
+// and strips characters that are unsafe in a Content-Disposition header.
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' || r == utf8.RuneError {
+			return -1
+		}
+
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	for len(name) > maxFilenameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+
+	return name
+}
@@ -0,0 +1,121 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// attachmentMap is an AttachmentRepository holding attachments by ID.
+type attachmentMap struct {
+	usecase.AttachmentRepository
+	attachments map[uuid.UUID]*domain.Attachment
+}
+
+func (m attachmentMap) GetByID(ctx context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	a, ok := m.attachments[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	return a, nil
+}
+
+func (m attachmentMap) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.attachments, id)
+
+	return nil
+}
+
+// blobSet is a BlobStore that only tracks which keys are stored.
+type blobSet map[string]bool
+
+func (b blobSet) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	b[key] = true
+
+	return nil
+}
+
+func (b blobSet) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (b blobSet) Delete(ctx context.Context, key string) error {
+	delete(b, key)
+
+	return nil
+}
+
+// newAttachmentUsecase returns an attachment usecase over the given
+// attachments, with a blob stored for each of them.
+func newAttachmentUsecase(audit usecase.AuditLogger, attachments ...*domain.Attachment) (usecase.AttachmentUsecase, attachmentMap, blobSet) {
+	s := memory.NewStore()
+	repo := attachmentMap{attachments: map[uuid.UUID]*domain.Attachment{}}
+	blobs := blobSet{}
+
+	for _, a := range attachments {
+		repo.attachments[a.ID] = a
+		blobs[a.StorageKey] = true
+	}
+
+	uc := usecase.NewAttachmentUsecase(memory.NewTxManager(s), repo, memory.NewMemoryThreadRepo(s), memory.NewMemoryPostRepo(s), blobs, audit, 1<<20, 1<<20, discardLogger)
+
+	return uc, repo, blobs
+}
+
+func TestDeleteAttachmentAuditsAdminsOnly(t *testing.T) {
+	ctx := context.Background()
+	ownerID, adminID := uuid.New(), uuid.New()
+	own := &domain.Attachment{ID: uuid.New(), UserID: ownerID, StorageKey: "own", Filename: "own.png"}
+	other := &domain.Attachment{ID: uuid.New(), UserID: ownerID, StorageKey: "other", Filename: "other.png"}
+
+	audit := &recordingAuditLogger{}
+	uc, repo, blobs := newAttachmentUsecase(audit, own, other)
+
+	if err := uc.Delete(ctx, own.ID, ownerID, "member"); err != nil {
+		t.Fatalf("Delete by the owner: %v", err)
+	}
+
+	if len(audit.entries) != 0 {
+		t.Fatalf("owner deleting their attachment recorded %d audit entries, want none", len(audit.entries))
+	}
+
+	if err := uc.Delete(ctx, other.ID, adminID, "admin"); err != nil {
+		t.Fatalf("Delete by an admin: %v", err)
+	}
+
+	if len(audit.entries) != 1 {
+		t.Fatalf("admin delete recorded %d audit entries, want 1", len(audit.entries))
+	}
+
+	entry := audit.entries[0]
+	if entry.Action != domain.AuditActionAttachmentDelete || entry.TargetType != domain.AuditTargetAttachment || entry.TargetID != other.ID || entry.ActorID != adminID {
+		t.Errorf("audit entry = %+v, want attachment.delete of %s by the admin", entry, other.ID)
+	}
+
+	if len(repo.attachments) != 0 || len(blobs) != 0 {
+		t.Errorf("left %d attachments and %d blobs, want none", len(repo.attachments), len(blobs))
+	}
+}
+
+func TestDeleteAttachmentKeepsItWhenTheAuditFails(t *testing.T) {
+	ctx := context.Background()
+	attachment := &domain.Attachment{ID: uuid.New(), UserID: uuid.New(), StorageKey: "key", Filename: "a.png"}
+
+	auditErr := errors.New("audit log unavailable")
+	uc, repo, blobs := newAttachmentUsecase(&recordingAuditLogger{err: auditErr}, attachment)
+
+	if err := uc.Delete(ctx, attachment.ID, uuid.New(), "admin"); !errors.Is(err, auditErr) {
+		t.Fatalf("Delete error = %v, want %v", err, auditErr)
+	}
+
+	if _, ok := repo.attachments[attachment.ID]; !ok || !blobs[attachment.StorageKey] {
+		t.Errorf("attachment or blob removed although the audit failed")
+	}
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+type auditLogUsecase struct {
+	auditRepo AuditLogRepository
+	userRepo  UserRepository
+}
+
+func NewAuditLogUsecase(ar AuditLogRepository, ur UserRepository) AuditLogUsecase {
+	return &auditLogUsecase{
+		auditRepo: ar,
+		userRepo:  ur,
+	}
+}
+
+func (uc *auditLogUsecase) Log(ctx context.Context, entry *domain.AuditLog) error {
+	if entry.ActorID == uuid.Nil || entry.Action == "" || entry.TargetType == "" || entry.TargetID == uuid.Nil {
+		return domain.ErrInvalid
+	}
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	return uc.auditRepo.Create(ctx, entry)
+}
+
+func (uc *auditLogUsecase) GetAll(ctx context.Context, filter AuditLogFilter, params PaginationParams) ([]*domain.AuditLog, map[uuid.UUID]*domain.User, int, error) {
+	total, err := uc.auditRepo.CountAll(ctx, filter)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	entries, err := uc.auditRepo.GetAll(ctx, filter, params)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if len(entries) == 0 {
+		return []*domain.AuditLog{}, map[uuid.UUID]*domain.User{}, total, nil
+	}
+
+	actorIDs := make([]uuid.UUID, 0)
+	for _, e := range entries {
+		actorIDs = append(actorIDs, e.ActorID)
+	}
+
+	userMap, err := uc.userRepo.GetByIDs(ctx, actorIDs)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return entries, userMap, total, nil
+}
+
+// recordAudit writes the audit entry for a privileged action. It is called
+// inside the action's transaction, so an action is never committed without
+// its entry.
+func recordAudit(ctx context.Context, auditLogger AuditLogger, entry *domain.AuditLog) error {
+	if auditLogger == nil {
+		return nil
+	}
+
+	return auditLogger.Log(ctx, entry)
+}
@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// recordingAuditLogger keeps the entries it is given, or rejects them all
+// when err is set.
+type recordingAuditLogger struct {
+	mu      sync.Mutex
+	err     error
+	entries []*domain.AuditLog
+}
+
+func (l *recordingAuditLogger) Log(ctx context.Context, entry *domain.AuditLog) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return l.err
+	}
+
+	l.entries = append(l.entries, entry)
+
+	return nil
+}
+
+func TestCategoryCreateIsUndoneWhenTheAuditFails(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	categories := memory.NewMemoryCategoryRepo(s)
+	auditErr := errors.New("audit log unavailable")
+	uc := usecase.NewCategoryUsecase(memory.NewTxManager(s), categories, &recordingAuditLogger{err: auditErr})
+
+	if _, err := uc.Create(ctx, uuid.New(), "admin", "Go", nil); !errors.Is(err, auditErr) {
+		t.Fatalf("Create error = %v, want %v", err, auditErr)
+	}
+
+	if _, err := categories.GetBySlug(ctx, "go"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetBySlug after a failed audit = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+func TestUpdateRoleIsUndoneWhenTheAuditFails(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	users := memory.NewMemoryUserRepo(s)
+	auditErr := errors.New("audit log unavailable")
+	uc := usecase.NewUserUsecase(memory.NewTxManager(s), users, nil, &recordingAuditLogger{err: auditErr})
+
+	target := &domain.User{ID: uuid.New(), Username: "member", Email: "member@example.com", Role: "member", CreatedAt: time.Now()}
+	if err := users.Create(ctx, target); err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+
+	if _, err := uc.UpdateRole(ctx, uuid.New(), "admin", target.ID, "admin", nil); !errors.Is(err, auditErr) {
+		t.Fatalf("UpdateRole error = %v, want %v", err, auditErr)
+	}
+
+	user, err := users.GetByID(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if user.Role != "member" {
+		t.Errorf("role after a failed audit = %q, want member", user.Role)
+	}
+}
@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+const (
+	maxBookmarkFolderNameLength = 50
+	maxBookmarkNoteLength       = 1000
+)
+
+type bookmarkUsecase struct {
+	bookmarkRepo BookmarkRepository
+	threadRepo   ThreadRepository
+	postRepo     PostRepository
+	userRepo     UserRepository
+	categoryRepo CategoryRepository
+}
+
+func NewBookmarkUsecase(br BookmarkRepository, tr ThreadRepository, pr PostRepository, ur UserRepository, cr CategoryRepository) BookmarkUsecase {
+	return &bookmarkUsecase{
+		bookmarkRepo: br,
+		threadRepo:   tr,
+		postRepo:     pr,
+		userRepo:     ur,
+		categoryRepo: cr,
+	}
+}
+
+func (uc *bookmarkUsecase) BookmarkThread(ctx context.Context, userID, threadID uuid.UUID, params SaveBookmarkParams) (*domain.Bookmark, error) {
+	thread, err := uc.threadRepo.GetByID(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.save(ctx, userID, thread.ID, nil, params)
+}
+
+func (uc *bookmarkUsecase) BookmarkPost(ctx context.Context, userID, postID uuid.UUID, params SaveBookmarkParams) (*domain.Bookmark, error) {
+	post, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.threadRepo.GetByID(ctx, post.ThreadID); err != nil {
+		return nil, err
+	}
+
+	return uc.save(ctx, userID, post.ThreadID, &post.ID, params)
+}
+
+func (uc *bookmarkUsecase) save(ctx context.Context, userID, threadID uuid.UUID, postID *uuid.UUID, params SaveBookmarkParams) (*domain.Bookmark, error) {
+	if params.FolderID != nil {
+		folder, err := uc.bookmarkRepo.GetFolderByID(ctx, *params.FolderID)
+		if errors.Is(err, domain.ErrNotFound) || (err == nil && folder.UserID != userID) {
+			return nil, domain.Invalid(domain.FieldError{Field: "folder_id", Rule: "exists"})
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	note, err := normalizeBookmarkNote(params.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	bookmark := &domain.Bookmark{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ThreadID:  threadID,
+		PostID:    postID,
+		FolderID:  params.FolderID,
+		Note:      note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := uc.bookmarkRepo.Upsert(ctx, bookmark); err != nil {
+		return nil, err
+	}
+
+	return bookmark, nil
+}
+
+func normalizeBookmarkNote(note *string) (*string, error) {
+	if note == nil {
+		return nil, nil
+	}
+
+	trimmed := strings.TrimSpace(*note)
+	if trimmed == "" {
+		return nil, nil
+	}
+
+	if utf8.RuneCountInString(trimmed) > maxBookmarkNoteLength {
+		return nil, domain.Invalid(domain.FieldError{Field: "note", Rule: "max_length", Param: strconv.Itoa(maxBookmarkNoteLength)})
+	}
+
+	return &trimmed, nil
+}
+
+func (uc *bookmarkUsecase) RemoveThreadBookmark(ctx context.Context, userID, threadID uuid.UUID) error {
+	return uc.bookmarkRepo.Delete(ctx, userID, threadID, nil)
+}
+
+func (uc *bookmarkUsecase) RemovePostBookmark(ctx context.Context, userID, postID uuid.UUID) error {
+	post, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	return uc.bookmarkRepo.Delete(ctx, userID, post.ThreadID, &post.ID)
+}
+
+func (uc *bookmarkUsecase) GetByUserID(ctx context.Context, userID uuid.UUID, filter BookmarkFilter, params PaginationParams) ([]*domain.Bookmark, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error) {
+	if filter.Type != "" && filter.Type != domain.BookmarkTypeThread && filter.Type != domain.BookmarkTypePost {
+		return nil, nil, nil, 0, domain.Invalid(domain.FieldError{Field: "type", Rule: "oneof", Param: domain.BookmarkTypeThread + " " + domain.BookmarkTypePost})
+	}
+
+	if filter.FolderID != nil && !filter.Unfiled {
+		folder, err := uc.bookmarkRepo.GetFolderByID(ctx, *filter.FolderID)
+		if err != nil {
+			return nil, nil, nil, 0, err
+		}
+
+		if folder.UserID != userID {
+			return nil, nil, nil, 0, domain.ErrNotFound
+		}
+	}
+
+	total, err := uc.bookmarkRepo.CountByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	bookmarks, err := uc.bookmarkRepo.GetByUserID(ctx, userID, filter, params)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	if len(bookmarks) == 0 {
+		return bookmarks, nil, nil, total, nil
+	}
+
+	threadIDs := make([]uuid.UUID, 0, len(bookmarks))
+	postIDs := make([]uuid.UUID, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		threadIDs = append(threadIDs, b.ThreadID)
+		if b.PostID != nil {
+			postIDs = append(postIDs, *b.PostID)
+		}
+	}
+
+	threadMap, err := uc.threadRepo.GetByIDs(ctx, threadIDs)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	postMap := map[uuid.UUID]*domain.Post{}
+	if len(postIDs) > 0 {
+		postMap, err = uc.postRepo.GetByIDs(ctx, postIDs)
+		if err != nil {
+			return nil, nil, nil, 0, err
+		}
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(bookmarks)*2)
+	catIDs := make([]uuid.UUID, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		b.Thread = threadMap[b.ThreadID]
+		if b.Thread != nil {
+			userIDs = append(userIDs, b.Thread.UserID)
+			catIDs = append(catIDs, b.Thread.CategoryID)
+		}
+
+		if b.PostID != nil {
+			b.Post = postMap[*b.PostID]
+			if b.Post != nil {
+				userIDs = append(userIDs, b.Post.UserID)
+			}
+		}
+	}
+
+	userMap, err := uc.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	catMap, err := uc.categoryRepo.GetByIDs(ctx, catIDs)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	return bookmarks, userMap, catMap, total, nil
+}
+
+func (uc *bookmarkUsecase) GetBookmarkedThreadIDs(ctx context.Context, userID uuid.UUID, threadIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	return uc.bookmarkRepo.GetBookmarkedThreadIDs(ctx, userID, threadIDs)
+}
+
+func (uc *bookmarkUsecase) GetBookmarkedPostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	return uc.bookmarkRepo.GetBookmarkedPostIDs(ctx, userID, postIDs)
+}
+
+func (uc *bookmarkUsecase) GetFolders(ctx context.Context, userID uuid.UUID) ([]*domain.BookmarkFolder, error) {
+	return uc.bookmarkRepo.GetFoldersByUserID(ctx, userID)
+}
+
+func (uc *bookmarkUsecase) CreateFolder(ctx context.Context, userID uuid.UUID, name string) (*domain.BookmarkFolder, error) {
+	name, err := normalizeBookmarkFolderName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.bookmarkRepo.CountFoldersByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if count >= domain.MaxBookmarkFolders {
+		return nil, domain.ErrLimitReached
+	}
+
+	folder := &domain.BookmarkFolder{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+
+	if err := uc.bookmarkRepo.CreateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	return folder, nil
+}
+
+func (uc *bookmarkUsecase) RenameFolder(ctx context.Context, userID, folderID uuid.UUID, name string) (*domain.BookmarkFolder, error) {
+	name, err := normalizeBookmarkFolderName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := uc.getOwnFolder(ctx, userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.bookmarkRepo.RenameFolder(ctx, folder.ID, name); err != nil {
+		return nil, err
+	}
+
+	folder.Name = name
+
+	return folder, nil
+}
+
+func (uc *bookmarkUsecase) DeleteFolder(ctx context.Context, userID, folderID uuid.UUID) error {
+	folder, err := uc.getOwnFolder(ctx, userID, folderID)
+	if err != nil {
+		return err
+	}
+
+	return uc.bookmarkRepo.DeleteFolder(ctx, folder.ID)
+}
+
+// getOwnFolder treats another user's folder as missing so folder ids cannot
+// be probed.
+func (uc *bookmarkUsecase) getOwnFolder(ctx context.Context, userID, folderID uuid.UUID) (*domain.BookmarkFolder, error) {
+	folder, err := uc.bookmarkRepo.GetFolderByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if folder.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+
+	return folder, nil
+}
+
+func normalizeBookmarkFolderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", domain.Invalid(domain.FieldError{Field: "name", Rule: "required"})
+	}
+
+	if utf8.RuneCountInString(name) > maxBookmarkFolderNameLength {
+		return "", domain.Invalid(domain.FieldError{Field: "name", Rule: "max_length", Param: strconv.Itoa(maxBookmarkFolderNameLength)})
+	}
+
+	return name, nil
+}
@@ -0,0 +1,256 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type bookmarkFixture struct {
+	threads usecase.ThreadRepository
+	uc      usecase.BookmarkUsecase
+	thread  *domain.Thread
+	post    *domain.Post
+}
+
+func newBookmarkFixture(t *testing.T) *bookmarkFixture {
+	t.Helper()
+
+	ctx := context.Background()
+	s := memory.NewStore()
+	posts := memory.NewMemoryPostRepo(s)
+	f := &bookmarkFixture{threads: memory.NewMemoryThreadRepo(s)}
+	f.uc = usecase.NewBookmarkUsecase(memory.NewMemoryBookmarkRepo(s), f.threads, posts, memory.NewMemoryUserRepo(s), memory.NewMemoryCategoryRepo(s))
+
+	now := time.Now()
+	f.thread = &domain.Thread{ID: uuid.New(), Title: "Saved", Slug: "saved", UserID: uuid.New(), CategoryID: uuid.New(), CreatedAt: now}
+	if err := f.threads.Create(ctx, f.thread); err != nil {
+		t.Fatalf("create thread: %v", err)
+	}
+
+	f.post = &domain.Post{ID: uuid.New(), ThreadID: f.thread.ID, UserID: uuid.New(), Content: "Reply", CreatedAt: now}
+	if err := posts.Create(ctx, f.post); err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+
+	return f
+}
+
+func (f *bookmarkFixture) folder(t *testing.T, userID uuid.UUID, name string) *domain.BookmarkFolder {
+	t.Helper()
+
+	folder, err := f.uc.CreateFolder(context.Background(), userID, name)
+	if err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+
+	return folder
+}
+
+func (f *bookmarkFixture) list(t *testing.T, userID uuid.UUID, filter usecase.BookmarkFilter) ([]*domain.Bookmark, int) {
+	t.Helper()
+
+	bookmarks, _, _, total, err := f.uc.GetByUserID(context.Background(), userID, filter, usecase.PaginationParams{Limit: 20})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	return bookmarks, total
+}
+
+func (f *bookmarkFixture) bookmarkCount(t *testing.T, userID, folderID uuid.UUID) int {
+	t.Helper()
+
+	folders, err := f.uc.GetFolders(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetFolders: %v", err)
+	}
+
+	for _, folder := range folders {
+		if folder.ID == folderID {
+			return folder.BookmarkCount
+		}
+	}
+
+	t.Fatalf("folder %s not listed", folderID)
+
+	return 0
+}
+
+func note(s string) *string {
+	return &s
+}
+
+func TestBookmarkAgainReplacesFolderAndNote(t *testing.T) {
+	ctx := context.Background()
+	f := newBookmarkFixture(t)
+	userID := uuid.New()
+	reading := f.folder(t, userID, "Reading")
+
+	first, err := f.uc.BookmarkThread(ctx, userID, f.thread.ID, usecase.SaveBookmarkParams{FolderID: &reading.ID, Note: note("later")})
+	if err != nil {
+		t.Fatalf("BookmarkThread: %v", err)
+	}
+
+	again, err := f.uc.BookmarkThread(ctx, userID, f.thread.ID, usecase.SaveBookmarkParams{Note: note("  done  ")})
+	if err != nil {
+		t.Fatalf("BookmarkThread again: %v", err)
+	}
+
+	if again.ID != first.ID || !again.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("saving again returned bookmark %s created %v, want the original %s", again.ID, again.CreatedAt, first.ID)
+	}
+
+	if again.FolderID != nil || again.Note == nil || *again.Note != "done" {
+		t.Errorf("saving again = folder %v note %v, want unfiled with the trimmed new note", again.FolderID, again.Note)
+	}
+
+	// A post bookmark is separate from its thread's bookmark.
+	if _, err := f.uc.BookmarkPost(ctx, userID, f.post.ID, usecase.SaveBookmarkParams{FolderID: &reading.ID}); err != nil {
+		t.Fatalf("BookmarkPost: %v", err)
+	}
+
+	bookmarks, total := f.list(t, userID, usecase.BookmarkFilter{})
+	if total != 2 || len(bookmarks) != 2 {
+		t.Fatalf("listed %d of %d bookmarks, want the thread and the post", len(bookmarks), total)
+	}
+
+	if _, total := f.list(t, userID, usecase.BookmarkFilter{Unfiled: true}); total != 1 {
+		t.Errorf("unfiled bookmarks = %d, want the thread", total)
+	}
+
+	if n := f.bookmarkCount(t, userID, reading.ID); n != 1 {
+		t.Errorf("folder holds %d bookmarks, want only the post", n)
+	}
+}
+
+func TestBookmarkFoldersBelongToTheirOwner(t *testing.T) {
+	ctx := context.Background()
+	f := newBookmarkFixture(t)
+	owner, other := uuid.New(), uuid.New()
+	folder := f.folder(t, owner, "Private")
+
+	if _, err := f.uc.BookmarkThread(ctx, other, f.thread.ID, usecase.SaveBookmarkParams{FolderID: &folder.ID}); !errors.Is(err, domain.ErrInvalid) {
+		t.Errorf("saving into another user's folder = %v, want %v", err, domain.ErrInvalid)
+	}
+
+	missing := uuid.New()
+	if _, err := f.uc.BookmarkPost(ctx, other, f.post.ID, usecase.SaveBookmarkParams{FolderID: &missing}); !errors.Is(err, domain.ErrInvalid) {
+		t.Errorf("saving into a missing folder = %v, want %v", err, domain.ErrInvalid)
+	}
+
+	// Another user's folder looks missing, so its id cannot be probed.
+	if _, err := f.uc.RenameFolder(ctx, other, folder.ID, "Mine"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("renaming another user's folder = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	if err := f.uc.DeleteFolder(ctx, other, folder.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("deleting another user's folder = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	if _, _, _, _, err := f.uc.GetByUserID(ctx, other, usecase.BookmarkFilter{FolderID: &folder.ID}, usecase.PaginationParams{Limit: 20}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("listing another user's folder = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	if _, total := f.list(t, other, usecase.BookmarkFilter{}); total != 0 {
+		t.Errorf("other user has %d bookmarks, want none saved", total)
+	}
+
+	folders, err := f.uc.GetFolders(ctx, owner)
+	if err != nil {
+		t.Fatalf("GetFolders: %v", err)
+	}
+
+	if len(folders) != 1 || folders[0].Name != "Private" {
+		t.Errorf("owner's folders = %+v, want Private unchanged", folders)
+	}
+
+	renamed, err := f.uc.RenameFolder(ctx, owner, folder.ID, "Shared")
+	if err != nil || renamed.Name != "Shared" {
+		t.Errorf("owner renaming their folder = %v, %v; want Shared", renamed, err)
+	}
+}
+
+func TestCreateFolderStopsAtTheLimit(t *testing.T) {
+	ctx := context.Background()
+	f := newBookmarkFixture(t)
+	userID := uuid.New()
+
+	for i := 0; i < domain.MaxBookmarkFolders; i++ {
+		f.folder(t, userID, fmt.Sprintf("Folder %d", i))
+	}
+
+	if _, err := f.uc.CreateFolder(ctx, userID, "One more"); !errors.Is(err, domain.ErrLimitReached) {
+		t.Fatalf("CreateFolder past the limit = %v, want %v", err, domain.ErrLimitReached)
+	}
+
+	// The limit is per user.
+	f.folder(t, uuid.New(), "One more")
+
+	// Deleting a folder makes room again.
+	folders, err := f.uc.GetFolders(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetFolders: %v", err)
+	}
+
+	if err := f.uc.DeleteFolder(ctx, userID, folders[0].ID); err != nil {
+		t.Fatalf("DeleteFolder: %v", err)
+	}
+
+	f.folder(t, userID, "One more")
+}
+
+func TestBookmarksOnTrashedThreadsAreHidden(t *testing.T) {
+	ctx := context.Background()
+	f := newBookmarkFixture(t)
+	userID := uuid.New()
+	folder := f.folder(t, userID, "Reading")
+
+	if _, err := f.uc.BookmarkThread(ctx, userID, f.thread.ID, usecase.SaveBookmarkParams{FolderID: &folder.ID}); err != nil {
+		t.Fatalf("BookmarkThread: %v", err)
+	}
+
+	if _, err := f.uc.BookmarkPost(ctx, userID, f.post.ID, usecase.SaveBookmarkParams{FolderID: &folder.ID}); err != nil {
+		t.Fatalf("BookmarkPost: %v", err)
+	}
+
+	if err := f.threads.Delete(ctx, f.thread.ID); err != nil {
+		t.Fatalf("Delete thread: %v", err)
+	}
+
+	if bookmarks, total := f.list(t, userID, usecase.BookmarkFilter{}); total != 0 || len(bookmarks) != 0 {
+		t.Errorf("listed %d of %d bookmarks on a trashed thread, want none", len(bookmarks), total)
+	}
+
+	if n := f.bookmarkCount(t, userID, folder.ID); n != 0 {
+		t.Errorf("folder counts %d bookmarks on a trashed thread, want 0", n)
+	}
+
+	if _, err := f.uc.BookmarkThread(ctx, userID, f.thread.ID, usecase.SaveBookmarkParams{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("bookmarking a trashed thread = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	if _, err := f.uc.BookmarkPost(ctx, userID, f.post.ID, usecase.SaveBookmarkParams{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("bookmarking a post of a trashed thread = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	// The bookmarks were kept and come back with the thread.
+	if err := f.threads.Restore(ctx, f.thread.ID); err != nil {
+		t.Fatalf("Restore thread: %v", err)
+	}
+
+	if _, total := f.list(t, userID, usecase.BookmarkFilter{FolderID: &folder.ID}); total != 2 {
+		t.Errorf("listed %d bookmarks after restoring, want both back", total)
+	}
+
+	if n := f.bookmarkCount(t, userID, folder.ID); n != 2 {
+		t.Errorf("folder counts %d bookmarks after restoring, want 2", n)
+	}
+}
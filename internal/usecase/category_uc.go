@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,22 +11,24 @@ import (
 )
 
 type categoryUsecase struct {
+	txManager    TxManager
 	categoryRepo CategoryRepository
+	auditLogger  AuditLogger
 }
 
-func NewCategoryUsecase(cr CategoryRepository) CategoryUsecase {
-	return &categoryUsecase{categoryRepo: cr}
+func NewCategoryUsecase(tm TxManager, cr CategoryRepository, al AuditLogger) CategoryUsecase {
+	return &categoryUsecase{txManager: tm, categoryRepo: cr, auditLogger: al}
 }
 
-func (uc *categoryUsecase) Create(ctx context.Context, name string, description *string) (*domain.Category, error) {
+func (uc *categoryUsecase) Create(ctx context.Context, actorID uuid.UUID, actorRole string, name string, description *string) (*domain.Category, error) {
 	if name == "" {
-		return nil, domain.ErrInvalid
+		return nil, domain.Invalid(domain.FieldError{Field: "name", Rule: "required"})
 	}
 
 	categorySlug := slug.Make(name)
 
 	existing, err := uc.categoryRepo.GetBySlug(ctx, categorySlug)
-	if err != nil && err != domain.ErrNotFound {
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
 		return nil, err
 	}
 
@@ -41,7 +44,21 @@ func (uc *categoryUsecase) Create(ctx context.Context, name string, description
 		CreatedAt:   time.Now(),
 	}
 
-	if err := uc.categoryRepo.Create(ctx, category); err != nil {
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.categoryRepo.Create(ctx, category); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+			ActorID:    actorID,
+			ActorRole:  actorRole,
+			Action:     domain.AuditActionCategoryCreate,
+			TargetType: domain.AuditTargetCategory,
+			TargetID:   category.ID,
+			Details:    &category.Name,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -51,3 +68,19 @@ func (uc *categoryUsecase) Create(ctx context.Context, name string, description
 func (uc *categoryUsecase) GetAll(ctx context.Context) ([]*domain.Category, error) {
 	return uc.categoryRepo.GetAll(ctx)
 }
+
+func (uc *categoryUsecase) Follow(ctx context.Context, userID, categoryID uuid.UUID) error {
+	if _, err := uc.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return err
+	}
+
+	return uc.categoryRepo.Follow(ctx, userID, categoryID)
+}
+
+func (uc *categoryUsecase) Unfollow(ctx context.Context, userID, categoryID uuid.UUID) error {
+	return uc.categoryRepo.Unfollow(ctx, userID, categoryID)
+}
+
+func (uc *categoryUsecase) GetFollowed(ctx context.Context, userID uuid.UUID) ([]*domain.Category, error) {
+	return uc.categoryRepo.GetFollowedByUserID(ctx, userID)
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+// Rows written before HTML caching was introduced have an empty
+// content_html; render them on read so clients always get safe HTML.
+
+func ensureThreadHTML(ctx context.Context, logger *slog.Logger, renderer ContentRenderer, thread *domain.Thread) {
+	if thread.ContentHTML != "" || thread.Content == "" {
+		return
+	}
+
+	rendered, err := renderer.Render(thread.Content, nil)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to render thread content", "thread_id", thread.ID, "error", err)
+
+		return
+	}
+
+	thread.ContentHTML = rendered
+}
+
+func ensurePostHTML(ctx context.Context, logger *slog.Logger, renderer ContentRenderer, post *domain.Post) {
+	if post.ContentHTML != "" || post.Content == "" {
+		return
+	}
+
+	rendered, err := renderer.Render(post.Content, nil)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to render post content", "post_id", post.ID, "error", err)
+
+		return
+	}
+
+	post.ContentHTML = rendered
+}
+
+// processedContent is user-authored content with its references resolved.
+type processedContent struct {
+	HTML      string
+	Tags      []string
+	Mentioned []uuid.UUID
+}
+
+// contentProcessor renders content and keeps its tag and mention
+// associations in sync with the source.
+type contentProcessor struct {
+	renderer    ContentRenderer
+	userRepo    UserRepository
+	tagRepo     TagRepository
+	mentionRepo MentionRepository
+}
+
+// process resolves @mentions against existing users and renders the content.
+// Mentions of unknown usernames are left as plain text.
+func (p *contentProcessor) process(ctx context.Context, source string) (*processedContent, error) {
+	refs := p.renderer.Extract(source)
+
+	linkable := make(map[string]bool, len(refs.Mentions))
+	mentioned := make([]uuid.UUID, 0, len(refs.Mentions))
+	if len(refs.Mentions) > 0 {
+		users, err := p.userRepo.GetByUsernames(ctx, refs.Mentions)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, username := range refs.Mentions {
+			if user, ok := users[username]; ok {
+				linkable[username] = true
+				mentioned = append(mentioned, user.ID)
+			}
+		}
+	}
+
+	html, err := p.renderer.Render(source, linkable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &processedContent{HTML: html, Tags: refs.Tags, Mentioned: mentioned}, nil
+}
+
+// saveThread stores the references of a thread body. Call it in the
+// transaction that saves the thread, so the two cannot disagree.
+func (p *contentProcessor) saveThread(ctx context.Context, thread *domain.Thread, content *processedContent) error {
+	if err := p.mentionRepo.ReplaceForThread(ctx, thread.ID, thread.UserID, content.Mentioned); err != nil {
+		return err
+	}
+
+	tagIDs, err := p.tagIDs(ctx, content.Tags)
+	if err != nil {
+		return err
+	}
+
+	return p.tagRepo.ReplaceThreadTags(ctx, thread.ID, domain.TagSourceContent, tagIDs)
+}
+
+// savePost stores the references of a post, like saveThread.
+func (p *contentProcessor) savePost(ctx context.Context, post *domain.Post, content *processedContent) error {
+	if err := p.mentionRepo.ReplaceForPost(ctx, post.ThreadID, post.ID, post.UserID, content.Mentioned); err != nil {
+		return err
+	}
+
+	tagIDs, err := p.tagIDs(ctx, content.Tags)
+	if err != nil {
+		return err
+	}
+
+	return p.tagRepo.ReplacePostTags(ctx, post.ID, tagIDs)
+}
+
+func (p *contentProcessor) tagIDs(ctx context.Context, names []string) ([]uuid.UUID, error) {
+	tags, err := p.tagRepo.UpsertByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+const (
+	digestBatchSize     = 100
+	digestTopThreads    = 5
+	digestUnreadThreads = 10
+
+	// digestGrace absorbs scheduling jitter, so a digest that falls due at
+	// the moment the job runs is not pushed back to its next run.
+	digestGrace = 5 * time.Minute
+)
+
+type digestUsecase struct {
+	digestRepo       DigestRepository
+	categoryRepo     CategoryRepository
+	threadRepo       ThreadRepository
+	subscriptionRepo SubscriptionRepository
+	renderer         DigestRenderer
+	mailer           Mailer
+	logger           *slog.Logger
+}
+
+func NewDigestUsecase(dr DigestRepository, cr CategoryRepository, tr ThreadRepository, sr SubscriptionRepository, r DigestRenderer, m Mailer, logger *slog.Logger) DigestUsecase {
+	return &digestUsecase{
+		digestRepo:       dr,
+		categoryRepo:     cr,
+		threadRepo:       tr,
+		subscriptionRepo: sr,
+		renderer:         r,
+		mailer:           m,
+		logger:           logger,
+	}
+}
+
+func (uc *digestUsecase) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.DigestPreferences, error) {
+	return uc.digestRepo.EnsurePreferences(ctx, userID, rand.Text())
+}
+
+func (uc *digestUsecase) UpdateFrequency(ctx context.Context, userID uuid.UUID, frequency string) (*domain.DigestPreferences, error) {
+	if frequency != domain.DigestOff && domain.DigestPeriod(frequency) == 0 {
+		return nil, domain.Invalid(domain.FieldError{Field: "frequency", Rule: "oneof", Param: "off daily weekly"})
+	}
+
+	prefs, err := uc.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.digestRepo.UpdateFrequency(ctx, userID, frequency); err != nil {
+		return nil, err
+	}
+
+	prefs.Frequency = frequency
+	prefs.UpdatedAt = time.Now()
+
+	return prefs, nil
+}
+
+func (uc *digestUsecase) Unsubscribe(ctx context.Context, token string) error {
+	if token == "" {
+		return domain.ErrNotFound
+	}
+
+	return uc.digestRepo.UnsubscribeByToken(ctx, token)
+}
+
+// SendDue sends every digest that is due and returns how many went out. A
+// failure for one recipient is logged and does not stop the others; they
+// are retried on the next run.
+func (uc *digestUsecase) SendDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	cutoffs := map[string]time.Time{
+		domain.DigestDaily:  now.Add(-domain.DigestPeriod(domain.DigestDaily) + digestGrace),
+		domain.DigestWeekly: now.Add(-domain.DigestPeriod(domain.DigestWeekly) + digestGrace),
+	}
+
+	sent := 0
+	after := uuid.Nil
+	for {
+		recipients, err := uc.digestRepo.GetDueRecipients(ctx, cutoffs, after, digestBatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, recipient := range recipients {
+			if err := ctx.Err(); err != nil {
+				return sent, err
+			}
+
+			ok, err := uc.send(ctx, recipient, now)
+			if err != nil {
+				uc.logger.ErrorContext(ctx, "failed to send digest", "user_id", recipient.UserID, "error", err)
+
+				continue
+			}
+
+			if ok {
+				sent++
+			}
+		}
+
+		if len(recipients) < digestBatchSize {
+			return sent, nil
+		}
+
+		after = recipients[len(recipients)-1].UserID
+	}
+}
+
+// send compiles and mails one digest. An empty digest is not sent but still
+// counts as delivered, so the next one covers a fresh period.
+func (uc *digestUsecase) send(ctx context.Context, recipient *domain.DigestRecipient, now time.Time) (bool, error) {
+	prefs, err := uc.GetPreferences(ctx, recipient.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	digest, err := uc.compile(ctx, recipient, now)
+	if err != nil {
+		return false, err
+	}
+	digest.UnsubscribeToken = prefs.UnsubscribeToken
+
+	if digest.IsEmpty() {
+		return false, uc.digestRepo.MarkSent(ctx, recipient.UserID, now)
+	}
+
+	msg, err := uc.renderer.Render(digest)
+	if err != nil {
+		return false, err
+	}
+
+	if err := uc.mailer.Send(ctx, msg); err != nil {
+		return false, err
+	}
+
+	if err := uc.digestRepo.MarkSent(ctx, recipient.UserID, now); err != nil {
+		// The mail is out; failing here only risks a duplicate next run.
+		uc.logger.ErrorContext(ctx, "failed to record sent digest", "user_id", recipient.UserID, "error", err)
+	}
+
+	return true, nil
+}
+
+// compile gathers the top threads of the recipient's followed categories,
+// or of all categories when they follow none, and their subscribed threads
+// with replies they have not read that arrived during the period.
+func (uc *digestUsecase) compile(ctx context.Context, recipient *domain.DigestRecipient, now time.Time) (*domain.Digest, error) {
+	since := now.Add(-domain.DigestPeriod(recipient.Frequency))
+	if recipient.LastSentAt != nil && recipient.LastSentAt.After(since) {
+		since = *recipient.LastSentAt
+	}
+
+	followed, err := uc.categoryRepo.GetFollowedByUserID(ctx, recipient.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs := make([]uuid.UUID, len(followed))
+	for i, cat := range followed {
+		categoryIDs[i] = cat.ID
+	}
+
+	top, err := uc.threadRepo.GetTop(ctx, categoryIDs, since, digestTopThreads)
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := uc.subscriptionRepo.GetUnreadByUserID(ctx, recipient.UserID, digestUnreadThreads)
+	if err != nil {
+		return nil, err
+	}
+
+	unread := make([]*domain.ThreadReadState, 0, len(states))
+	threadIDs := make([]uuid.UUID, 0, len(states))
+	for _, s := range states {
+		if s.LastActivityAt.After(since) {
+			unread = append(unread, s)
+			threadIDs = append(threadIDs, s.ThreadID)
+		}
+	}
+
+	digest := &domain.Digest{
+		Recipient:  recipient,
+		Since:      since,
+		TopThreads: top,
+		Unread:     unread,
+	}
+
+	if len(threadIDs) > 0 {
+		threadMap, err := uc.threadRepo.GetByIDs(ctx, threadIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range unread {
+			s.Thread = threadMap[s.ThreadID]
+		}
+	}
+
+	catIDs := make([]uuid.UUID, 0, len(top)+len(unread))
+	for _, t := range top {
+		catIDs = append(catIDs, t.CategoryID)
+	}
+	for _, s := range unread {
+		if s.Thread != nil {
+			catIDs = append(catIDs, s.Thread.CategoryID)
+		}
+	}
+
+	if len(catIDs) > 0 {
+		digest.Categories, err = uc.categoryRepo.GetByIDs(ctx, catIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return digest, nil
+}
@@ -0,0 +1,266 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// digestOutbox is a DigestRenderer and Mailer that keeps every digest it
+// renders and every message it sends.
+type digestOutbox struct {
+	digests map[string]*domain.Digest
+	sent    []string
+}
+
+func (o *digestOutbox) Render(digest *domain.Digest) (*domain.EmailMessage, error) {
+	o.digests[digest.Recipient.Email] = digest
+
+	return &domain.EmailMessage{To: digest.Recipient.Email, Subject: "Digest"}, nil
+}
+
+func (o *digestOutbox) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	o.sent = append(o.sent, msg.To)
+
+	return nil
+}
+
+type digestFixture struct {
+	users      usecase.UserRepository
+	categories usecase.CategoryRepository
+	threads    usecase.ThreadRepository
+	digests    usecase.DigestRepository
+	outbox     *digestOutbox
+	uc         usecase.DigestUsecase
+}
+
+func newDigestFixture() *digestFixture {
+	s := memory.NewStore()
+	f := &digestFixture{
+		users:      memory.NewMemoryUserRepo(s),
+		categories: memory.NewMemoryCategoryRepo(s),
+		threads:    memory.NewMemoryThreadRepo(s),
+		digests:    memory.NewMemoryDigestRepo(s),
+		outbox:     &digestOutbox{digests: map[string]*domain.Digest{}},
+	}
+	f.uc = usecase.NewDigestUsecase(f.digests, f.categories, f.threads, memory.NewMemorySubscriptionRepo(s), f.outbox, f.outbox, discardLogger)
+
+	return f
+}
+
+func (f *digestFixture) user(t *testing.T, name string) *domain.User {
+	t.Helper()
+
+	user := &domain.User{ID: uuid.New(), Username: name, Email: name + "@example.com", Role: "member", CreatedAt: time.Now()}
+	if err := f.users.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	return user
+}
+
+func (f *digestFixture) category(t *testing.T, name string) *domain.Category {
+	t.Helper()
+
+	category := &domain.Category{ID: uuid.New(), Name: name, Slug: name, CreatedAt: time.Now()}
+	if err := f.categories.Create(context.Background(), category); err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+
+	return category
+}
+
+func (f *digestFixture) thread(t *testing.T, category *domain.Category, age time.Duration) *domain.Thread {
+	t.Helper()
+
+	thread := &domain.Thread{ID: uuid.New(), Title: category.Name, Slug: category.Slug, UserID: uuid.New(), CategoryID: category.ID, CreatedAt: time.Now().Add(-age)}
+	if err := f.threads.Create(context.Background(), thread); err != nil {
+		t.Fatalf("create thread: %v", err)
+	}
+
+	return thread
+}
+
+// setDigest stores the user's frequency and when their last digest went out.
+func (f *digestFixture) setDigest(t *testing.T, userID uuid.UUID, frequency string, sentAgo time.Duration) {
+	t.Helper()
+
+	ctx := context.Background()
+	if _, err := f.uc.UpdateFrequency(ctx, userID, frequency); err != nil {
+		t.Fatalf("UpdateFrequency: %v", err)
+	}
+
+	if sentAgo > 0 {
+		if err := f.digests.MarkSent(ctx, userID, time.Now().Add(-sentAgo)); err != nil {
+			t.Fatalf("MarkSent: %v", err)
+		}
+	}
+}
+
+func (f *digestFixture) sendDue(t *testing.T) int {
+	t.Helper()
+
+	sent, err := f.uc.SendDue(context.Background())
+	if err != nil {
+		t.Fatalf("SendDue: %v", err)
+	}
+
+	return sent
+}
+
+func threadIDs(threads []*domain.Thread) map[uuid.UUID]bool {
+	ids := make(map[uuid.UUID]bool, len(threads))
+	for _, thread := range threads {
+		ids[thread.ID] = true
+	}
+
+	return ids
+}
+
+func TestDigestSelectsThreadsOfFollowedCategories(t *testing.T) {
+	ctx := context.Background()
+	f := newDigestFixture()
+	golang, rust := f.category(t, "go"), f.category(t, "rust")
+
+	follower, everyone := f.user(t, "follower"), f.user(t, "everyone")
+	if err := f.categories.Follow(ctx, follower.ID, golang.ID); err != nil {
+		t.Fatalf("Follow: %v", err)
+	}
+
+	goThread := f.thread(t, golang, time.Hour)
+	rustThread := f.thread(t, rust, time.Hour)
+	f.thread(t, golang, 8*24*time.Hour)
+
+	if sent := f.sendDue(t); sent != 2 {
+		t.Fatalf("sent %d digests, want 2", sent)
+	}
+
+	digest := f.outbox.digests[follower.Email]
+	if got := threadIDs(digest.TopThreads); len(got) != 1 || !got[goThread.ID] {
+		t.Errorf("follower's top threads = %v, want only the recent thread in the followed category", got)
+	}
+
+	if digest.Categories[golang.ID] == nil {
+		t.Errorf("follower's digest categories = %v, want the followed category", digest.Categories)
+	}
+
+	// Following nothing means hearing about every category.
+	digest = f.outbox.digests[everyone.Email]
+	if got := threadIDs(digest.TopThreads); len(got) != 2 || !got[goThread.ID] || !got[rustThread.ID] {
+		t.Errorf("top threads for a member following nothing = %v, want both recent threads", got)
+	}
+
+	if digest.UnsubscribeToken == "" {
+		t.Error("digest has no unsubscribe token")
+	}
+}
+
+func TestDigestIsSentOncePerPeriod(t *testing.T) {
+	f := newDigestFixture()
+	f.thread(t, f.category(t, "go"), time.Hour)
+
+	recentDaily := f.user(t, "recent-daily")
+	f.setDigest(t, recentDaily.ID, domain.DigestDaily, 2*time.Hour)
+
+	recentWeekly := f.user(t, "recent-weekly")
+	f.setDigest(t, recentWeekly.ID, domain.DigestWeekly, 3*24*time.Hour)
+
+	dueDaily := f.user(t, "due-daily")
+	f.setDigest(t, dueDaily.ID, domain.DigestDaily, 25*time.Hour)
+
+	// Within the grace period of a full day counts as due.
+	almostDaily := f.user(t, "almost-daily")
+	f.setDigest(t, almostDaily.ID, domain.DigestDaily, 24*time.Hour-time.Minute)
+
+	dueWeekly := f.user(t, "due-weekly")
+	f.setDigest(t, dueWeekly.ID, domain.DigestWeekly, 8*24*time.Hour)
+
+	off := f.user(t, "off")
+	f.setDigest(t, off.ID, domain.DigestOff, 0)
+
+	neverSent := f.user(t, "never-sent")
+
+	if sent := f.sendDue(t); sent != 4 {
+		t.Errorf("sent %d digests, want 4", sent)
+	}
+
+	want := map[string]bool{dueDaily.Email: true, almostDaily.Email: true, dueWeekly.Email: true, neverSent.Email: true}
+	for _, to := range f.outbox.sent {
+		if !want[to] {
+			t.Errorf("sent a digest to %s, which is not due", to)
+		}
+		delete(want, to)
+	}
+
+	if len(want) != 0 {
+		t.Errorf("no digest sent to %v", want)
+	}
+
+	// A daily digest covers the day, not the time since the last one.
+	if since := f.outbox.digests[dueDaily.Email].Since; time.Since(since) > 24*time.Hour+time.Minute {
+		t.Errorf("daily digest covers activity since %v, want the last day only", since)
+	}
+
+	if sent := f.sendDue(t); sent != 0 {
+		t.Errorf("sending again right away sent %d digests, want none", sent)
+	}
+}
+
+func TestEmptyDigestIsSkippedButCountsAsSent(t *testing.T) {
+	f := newDigestFixture()
+	f.user(t, "quiet")
+
+	if sent := f.sendDue(t); sent != 0 || len(f.outbox.sent) != 0 {
+		t.Fatalf("sent %d digests with nothing to report, want none", sent)
+	}
+
+	// The next digest covers a fresh period once there is activity.
+	f.thread(t, f.category(t, "go"), time.Hour)
+	if sent := f.sendDue(t); sent != 0 {
+		t.Errorf("sent %d digests before the next one was due, want none", sent)
+	}
+}
+
+func TestUnsubscribeByToken(t *testing.T) {
+	ctx := context.Background()
+	f := newDigestFixture()
+	f.thread(t, f.category(t, "go"), time.Hour)
+	user, other := f.user(t, "leaving"), f.user(t, "staying")
+
+	prefs, err := f.uc.GetPreferences(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+
+	if prefs.Frequency != domain.DefaultDigestFrequency || prefs.UnsubscribeToken == "" {
+		t.Fatalf("preferences = %+v, want the default with a token", prefs)
+	}
+
+	if again, err := f.uc.GetPreferences(ctx, user.ID); err != nil || again.UnsubscribeToken != prefs.UnsubscribeToken {
+		t.Fatalf("GetPreferences again = %+v, %v; want the same token", again, err)
+	}
+
+	for _, token := range []string{"", "unknown"} {
+		if err := f.uc.Unsubscribe(ctx, token); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Unsubscribe(%q) = %v, want %v", token, err, domain.ErrNotFound)
+		}
+	}
+
+	if err := f.uc.Unsubscribe(ctx, prefs.UnsubscribeToken); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+
+	if prefs, err := f.uc.GetPreferences(ctx, user.ID); err != nil || prefs.Frequency != domain.DigestOff {
+		t.Errorf("preferences after unsubscribing = %+v, %v; want off", prefs, err)
+	}
+
+	if sent := f.sendDue(t); sent != 1 || f.outbox.sent[0] != other.Email {
+		t.Errorf("sent digests to %v, want only %s", f.outbox.sent, other.Email)
+	}
+}
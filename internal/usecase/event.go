@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+// publishEvent pushes a real-time event after the underlying change has been
+// persisted. Delivery is best-effort, so failures are only logged.
+func publishEvent(ctx context.Context, logger *slog.Logger, publisher EventPublisher, topic, eventType string, data interface{}) {
+	if publisher == nil {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to encode event", "event_type", eventType, "error", err)
+
+		return
+	}
+
+	event := &domain.Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		Topic:      topic,
+		Data:       payload,
+		OccurredAt: time.Now(),
+	}
+
+	if err := publisher.Publish(ctx, event); err != nil {
+		logger.ErrorContext(ctx, "failed to publish event", "event_type", eventType, "topic", topic, "error", err)
+	}
+}
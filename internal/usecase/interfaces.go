@@ -2,9 +2,10 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/srgjo27/agora/internal/domain"
 )
 
@@ -14,6 +15,8 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.User, error)
 	GetUsers(ctx context.Context) ([]*domain.User, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, role string) error
+	GetByUsernames(ctx context.Context, usernames []string) (map[string]*domain.User, error)
 }
 
 type UserUsecase interface {
@@ -22,6 +25,7 @@ type UserUsecase interface {
 	Login(ctx context.Context, email, password string) (accessToken string, refreshToken string, err error)
 	Refresh(ctx context.Context, refreshToken string) (newAccessToken string, err error)
 	GetUsers(ctx context.Context) ([]*domain.User, error)
+	UpdateRole(ctx context.Context, actorID uuid.UUID, actorRole string, targetID uuid.UUID, role string, reason *string) (*domain.User, error)
 }
 
 type TokenService interface {
@@ -30,40 +34,350 @@ type TokenService interface {
 	ValidateToken(ctx context.Context, tokenString string) (uuid.UUID, string, error)
 }
 
+// ContentReferences are the distinct @mentions and #tags found in content,
+// lowercased and in order of first appearance.
+type ContentReferences struct {
+	Mentions []string
+	Tags     []string
+}
+
+// ContentRenderer turns Markdown source into sanitized HTML. Only usernames
+// present in mentions are linked; #tags are always linked.
+type ContentRenderer interface {
+	Render(source string, mentions map[string]bool) (string, error)
+	Extract(source string) ContentReferences
+}
+
 type CategoryRepository interface {
 	Create(ctx context.Context, category *domain.Category) error
 	GetBySlug(ctx context.Context, slug string) (*domain.Category, error)
 	GetAll(ctx context.Context) ([]*domain.Category, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error)
 	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Category, error)
+	Follow(ctx context.Context, userID, categoryID uuid.UUID) error
+	Unfollow(ctx context.Context, userID, categoryID uuid.UUID) error
+	GetFollowedByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Category, error)
 }
 
 type CategoryUsecase interface {
-	Create(ctx context.Context, name string, description *string) (*domain.Category, error)
+	Create(ctx context.Context, actorID uuid.UUID, actorRole string, name string, description *string) (*domain.Category, error)
 	GetAll(ctx context.Context) ([]*domain.Category, error)
+	Follow(ctx context.Context, userID, categoryID uuid.UUID) error
+	Unfollow(ctx context.Context, userID, categoryID uuid.UUID) error
+	GetFollowed(ctx context.Context, userID uuid.UUID) ([]*domain.Category, error)
 }
 
 type UpdateThreadParams struct {
 	Title   *string
 	Content *string
+	// Tags, when set, replaces the tags chosen by the author. #tags in the
+	// content are tracked separately and follow the content.
+	Tags *[]string
+	// Reason is recorded in the audit log when an admin edits someone else's thread.
+	Reason *string
+}
+
+// ThreadFilter narrows thread listings. With MatchAll a thread must carry
+// every tag in Tags; otherwise any one of them is enough.
+type ThreadFilter struct {
+	Tags     []string
+	MatchAll bool
 }
 
 type ThreadRepository interface {
 	Create(ctx context.Context, thread *domain.Thread) error
-	GetAll(ctx context.Context, params PaginationParams) ([]*domain.Thread, error)
+	GetAll(ctx context.Context, filter ThreadFilter, params PaginationParams) ([]*domain.Thread, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Thread, error)
-	UpdateVoteCount(ctx context.Context, tx *sqlx.Tx, threadID uuid.UUID, delta int) error
-	CountAll(ctx context.Context) (int, error)
+	// UpdateVoteCount adds delta to the thread's vote count and returns the
+	// new count.
+	UpdateVoteCount(ctx context.Context, threadID uuid.UUID, delta int) (int, error)
+	CountAll(ctx context.Context, filter ThreadFilter) (int, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	Update(ctx context.Context, thread *domain.Thread) error
+	GetDeleted(ctx context.Context, params PaginationParams) ([]*domain.Thread, error)
+	CountDeleted(ctx context.Context) (int, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Thread, error)
+	// GetTop returns the highest voted threads created since the given time,
+	// limited to categoryIDs unless it is empty.
+	GetTop(ctx context.Context, categoryIDs []uuid.UUID, since time.Time, limit int) ([]*domain.Thread, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int, error)
+	SetLocked(ctx context.Context, id uuid.UUID, locked bool) error
+	GetByTagID(ctx context.Context, tagID uuid.UUID, params PaginationParams) ([]*domain.Thread, error)
+	CountByTagID(ctx context.Context, tagID uuid.UUID) (int, error)
+	GetMentioning(ctx context.Context, userID uuid.UUID, params PaginationParams) ([]*domain.Thread, error)
+	CountMentioning(ctx context.Context, userID uuid.UUID) (int, error)
 }
 
 type ThreadUsecase interface {
-	Create(ctx context.Context, title, content string, userID, categoryID uuid.UUID) (*domain.Thread, *domain.User, *domain.Category, error)
-	GetAll(ctx context.Context, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error)
+	Create(ctx context.Context, title, content string, tags []string, userID, categoryID uuid.UUID) (*domain.Thread, *domain.User, *domain.Category, error)
+	GetAll(ctx context.Context, filter ThreadFilter, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Thread, *domain.User, *domain.Category, error)
-	Delete(ctx context.Context, threadID, userID uuid.UUID, role string) error
+	Delete(ctx context.Context, threadID, userID uuid.UUID, role string, reason *string) error
 	Update(ctx context.Context, threadID, userID uuid.UUID, role string, params UpdateThreadParams) (*domain.Thread, *domain.User, *domain.Category, error)
+	GetDeleted(ctx context.Context, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error)
+	Restore(ctx context.Context, threadID, userID uuid.UUID, role string, reason *string) (*domain.Thread, *domain.User, *domain.Category, error)
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int, error)
+	SetLocked(ctx context.Context, threadID, userID uuid.UUID, role string, locked bool, reason *string) (*domain.Thread, *domain.User, *domain.Category, error)
+	GetByTag(ctx context.Context, tag string, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error)
+	GetMentioning(ctx context.Context, userID uuid.UUID, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error)
+}
+
+type TagRepository interface {
+	UpsertByNames(ctx context.Context, names []string) ([]*domain.Tag, error)
+	GetByName(ctx context.Context, name string) (*domain.Tag, error)
+	GetByThreadIDs(ctx context.Context, threadIDs []uuid.UUID) (map[uuid.UUID][]*domain.Tag, error)
+	Search(ctx context.Context, prefix string, limit int) ([]*domain.Tag, error)
+	ReplaceThreadTags(ctx context.Context, threadID uuid.UUID, source string, tagIDs []uuid.UUID) error
+	ReplacePostTags(ctx context.Context, postID uuid.UUID, tagIDs []uuid.UUID) error
+	Rename(ctx context.Context, id uuid.UUID, name string) error
+	Merge(ctx context.Context, sourceID, targetID uuid.UUID) error
+}
+
+type SubscriptionRepository interface {
+	Subscribe(ctx context.Context, userID, threadID uuid.UUID) error
+	Unsubscribe(ctx context.Context, userID, threadID uuid.UUID) error
+	GetByUserID(ctx context.Context, userID uuid.UUID, params PaginationParams) ([]*domain.ThreadReadState, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	SaveReadPosition(ctx context.Context, pos *domain.ThreadReadPosition) error
+	GetReadState(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadReadState, error)
+	GetUnreadByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*domain.ThreadReadState, error)
+}
+
+// ThreadFollower is told when a user creates or replies to a thread, so they
+// follow it and their read position moves past their own contribution.
+// post is nil for the opening post.
+type ThreadFollower interface {
+	FollowThread(ctx context.Context, userID uuid.UUID, thread *domain.Thread, post *domain.Post)
+}
+
+type SubscriptionUsecase interface {
+	ThreadFollower
+	Subscribe(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadReadState, error)
+	Unsubscribe(ctx context.Context, userID, threadID uuid.UUID) error
+	GetByUserID(ctx context.Context, userID uuid.UUID, params PaginationParams) ([]*domain.ThreadReadState, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error)
+	GetReadState(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadReadState, error)
+	MarkRead(ctx context.Context, userID, threadID uuid.UUID, postID *uuid.UUID) (*domain.ThreadReadState, error)
+}
+
+// BookmarkFilter narrows a user's bookmarks. Unfiled selects bookmarks
+// outside any folder and takes precedence over FolderID; Type is one of
+// the domain.BookmarkType constants or empty for both.
+type BookmarkFilter struct {
+	FolderID *uuid.UUID
+	Unfiled  bool
+	Type     string
+}
+
+type BookmarkRepository interface {
+	Upsert(ctx context.Context, bookmark *domain.Bookmark) error
+	Delete(ctx context.Context, userID, threadID uuid.UUID, postID *uuid.UUID) error
+	GetByUserID(ctx context.Context, userID uuid.UUID, filter BookmarkFilter, params PaginationParams) ([]*domain.Bookmark, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID, filter BookmarkFilter) (int, error)
+	GetBookmarkedThreadIDs(ctx context.Context, userID uuid.UUID, threadIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	GetBookmarkedPostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	CreateFolder(ctx context.Context, folder *domain.BookmarkFolder) error
+	GetFolderByID(ctx context.Context, id uuid.UUID) (*domain.BookmarkFolder, error)
+	GetFoldersByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.BookmarkFolder, error)
+	CountFoldersByUserID(ctx context.Context, userID uuid.UUID) (int, error)
+	RenameFolder(ctx context.Context, id uuid.UUID, name string) error
+	DeleteFolder(ctx context.Context, id uuid.UUID) error
+}
+
+type SaveBookmarkParams struct {
+	FolderID *uuid.UUID
+	Note     *string
+}
+
+type BookmarkUsecase interface {
+	BookmarkThread(ctx context.Context, userID, threadID uuid.UUID, params SaveBookmarkParams) (*domain.Bookmark, error)
+	BookmarkPost(ctx context.Context, userID, postID uuid.UUID, params SaveBookmarkParams) (*domain.Bookmark, error)
+	RemoveThreadBookmark(ctx context.Context, userID, threadID uuid.UUID) error
+	RemovePostBookmark(ctx context.Context, userID, postID uuid.UUID) error
+	GetByUserID(ctx context.Context, userID uuid.UUID, filter BookmarkFilter, params PaginationParams) ([]*domain.Bookmark, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error)
+	GetBookmarkedThreadIDs(ctx context.Context, userID uuid.UUID, threadIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	GetBookmarkedPostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	GetFolders(ctx context.Context, userID uuid.UUID) ([]*domain.BookmarkFolder, error)
+	CreateFolder(ctx context.Context, userID uuid.UUID, name string) (*domain.BookmarkFolder, error)
+	RenameFolder(ctx context.Context, userID, folderID uuid.UUID, name string) (*domain.BookmarkFolder, error)
+	DeleteFolder(ctx context.Context, userID, folderID uuid.UUID) error
+}
+
+type DigestRepository interface {
+	// EnsurePreferences creates the user's default preferences with token
+	// when they have none yet, and returns the stored preferences.
+	EnsurePreferences(ctx context.Context, userID uuid.UUID, token string) (*domain.DigestPreferences, error)
+	UpdateFrequency(ctx context.Context, userID uuid.UUID, frequency string) error
+	UnsubscribeByToken(ctx context.Context, token string) error
+	// GetDueRecipients pages through users, ordered by id and starting after
+	// the given one, whose digest was last sent before the cutoff for their
+	// frequency.
+	GetDueRecipients(ctx context.Context, cutoffs map[string]time.Time, after uuid.UUID, limit int) ([]*domain.DigestRecipient, error)
+	MarkSent(ctx context.Context, userID uuid.UUID, sentAt time.Time) error
+}
+
+type DigestRenderer interface {
+	Render(digest *domain.Digest) (*domain.EmailMessage, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg *domain.EmailMessage) error
+}
+
+type DigestUsecase interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.DigestPreferences, error)
+	UpdateFrequency(ctx context.Context, userID uuid.UUID, frequency string) (*domain.DigestPreferences, error)
+	Unsubscribe(ctx context.Context, token string) error
+	SendDue(ctx context.Context) (int, error)
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *domain.Webhook) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error)
+	GetAll(ctx context.Context) ([]*domain.Webhook, error)
+	GetActiveByEvent(ctx context.Context, eventType string) ([]*domain.Webhook, error)
+	Update(ctx context.Context, webhook *domain.Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// CreateDeliveries skips a delivery whose webhook already has one for
+	// the same event, unless it is a redelivery.
+	CreateDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
+	GetDeliveriesByWebhookID(ctx context.Context, webhookID uuid.UUID, params PaginationParams) ([]*domain.WebhookDelivery, error)
+	CountDeliveriesByWebhookID(ctx context.Context, webhookID uuid.UUID) (int, error)
+	// ClaimDueDeliveries picks pending deliveries of active webhooks that
+	// are due and pushes their next attempt back by lease, so concurrent
+	// workers skip them and a crashed worker's claim eventually expires.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+	SaveDeliveryResult(ctx context.Context, delivery *domain.WebhookDelivery) error
+	PurgeDeliveriesBefore(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// WebhookSender performs the HTTP request of one delivery attempt. A
+// non-2xx response is returned as a response, not as an error.
+type WebhookSender interface {
+	Post(ctx context.Context, url string, headers map[string]string, body []byte) (*domain.WebhookResponse, error)
+}
+
+// WebhookDispatcher queues an event for every active webhook subscribed to
+// its type. eventID identifies the event to receivers; dispatching the same
+// event again queues nothing new, so a failed dispatch can be retried.
+type WebhookDispatcher interface {
+	Dispatch(ctx context.Context, eventID uuid.UUID, eventType string, data interface{}) error
+}
+
+type CreateWebhookParams struct {
+	URL         string
+	Events      []string
+	Description *string
+}
+
+type UpdateWebhookParams struct {
+	URL          *string
+	Events       *[]string
+	Description  *string
+	IsActive     *bool
+	RotateSecret bool
+}
+
+type WebhookUsecase interface {
+	WebhookDispatcher
+	Create(ctx context.Context, actorID uuid.UUID, actorRole string, params CreateWebhookParams) (*domain.Webhook, error)
+	GetAll(ctx context.Context) ([]*domain.Webhook, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error)
+	Update(ctx context.Context, actorID uuid.UUID, actorRole string, id uuid.UUID, params UpdateWebhookParams) (*domain.Webhook, error)
+	Delete(ctx context.Context, actorID uuid.UUID, actorRole string, id uuid.UUID) error
+	GetDeliveries(ctx context.Context, webhookID uuid.UUID, params PaginationParams) ([]*domain.WebhookDelivery, int, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+	DeliverDue(ctx context.Context) (int, error)
+	PurgeDeliveries(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// TxManager runs work in a database transaction. The transaction travels in
+// the context passed to fn, and every repository call made with that
+// context takes part in it.
+type TxManager interface {
+	// WithinTx commits if fn returns nil and rolls back if it returns an
+	// error or panics. A nested call joins the outer transaction.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type OutboxRepository interface {
+	// Add records events; call it inside TxManager.WithinTx together with
+	// the change the events describe.
+	Add(ctx context.Context, events ...*domain.OutboxEvent) error
+	// ClaimPending picks unpublished events that are due, oldest first, and
+	// pushes their next attempt back by lease so concurrent relays skip them.
+	ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id uuid.UUID, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, event *domain.OutboxEvent) error
+	PurgePublishedBefore(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// EventBus carries outbox events to their consumers. An error makes the
+// relay retry the event later, so delivery is at least once.
+type EventBus interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// EventHandler consumes one outbox event.
+type EventHandler func(ctx context.Context, event *domain.OutboxEvent) error
+
+// ProcessedEventRepository remembers which events each consumer handled.
+type ProcessedEventRepository interface {
+	IsProcessed(ctx context.Context, consumer string, eventID uuid.UUID) (bool, error)
+	MarkProcessed(ctx context.Context, consumer string, eventID uuid.UUID) error
+	PurgeBefore(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+type OutboxUsecase interface {
+	Relay(ctx context.Context) (int, error)
+	Purge(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// BlobStore holds attachment content. Keys are generated by the usecase and
+// never contain user input.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type AttachmentRepository interface {
+	// CreateWithinQuota inserts the attachment unless it would take the
+	// owner's total stored bytes above quota, in which case it returns
+	// domain.ErrQuotaExceeded.
+	CreateWithinQuota(ctx context.Context, attachment *domain.Attachment, quota int64) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Attachment, error)
+	GetByThreadID(ctx context.Context, threadID uuid.UUID) ([]*domain.Attachment, error)
+	GetByPostIDs(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]*domain.Attachment, error)
+	GetOrphaned(ctx context.Context, limit int) ([]*domain.Attachment, error)
+	SumSizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type UploadAttachmentParams struct {
+	Filename string
+	Size     int64
+	Content  io.Reader
+}
+
+type AttachmentUsecase interface {
+	UploadToThread(ctx context.Context, threadID, userID uuid.UUID, role string, params UploadAttachmentParams) (*domain.Attachment, error)
+	UploadToPost(ctx context.Context, postID, userID uuid.UUID, role string, params UploadAttachmentParams) (*domain.Attachment, error)
+	Open(ctx context.Context, id uuid.UUID) (*domain.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, id, userID uuid.UUID, role string) error
+	PurgeOrphaned(ctx context.Context) (int, error)
+}
+
+type TagUsecase interface {
+	Search(ctx context.Context, query string, limit int) ([]*domain.Tag, error)
+	Rename(ctx context.Context, actorID uuid.UUID, actorRole string, name, newName string, reason *string) (*domain.Tag, error)
+	Merge(ctx context.Context, actorID uuid.UUID, actorRole string, sourceName, targetName string, reason *string) (*domain.Tag, error)
+}
+
+type MentionRepository interface {
+	ReplaceForThread(ctx context.Context, threadID, actorID uuid.UUID, userIDs []uuid.UUID) error
+	ReplaceForPost(ctx context.Context, threadID, postID, actorID uuid.UUID, userIDs []uuid.UUID) error
 }
 
 type PostRepository interface {
@@ -71,22 +385,30 @@ type PostRepository interface {
 	GetByThreadID(ctx context.Context, threadID uuid.UUID, params PaginationParams) ([]*domain.Post, error)
 	CountByThreadID(ctx context.Context, threadID uuid.UUID) (int, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Post, error)
-	UpdateVoteCount(ctx context.Context, tx *sqlx.Tx, postID uuid.UUID, delta int) error
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Post, error)
+	// UpdateVoteCount adds delta to the post's vote count and returns the new
+	// count.
+	UpdateVoteCount(ctx context.Context, postID uuid.UUID, delta int) (int, error)
+	Update(ctx context.Context, post *domain.Post) error
 }
 
 type PostUsecase interface {
 	Create(ctx context.Context, content string, userID, threadID uuid.UUID, parentPostID *uuid.UUID) (*domain.Post, error)
 	GetByThreadID(ctx context.Context, threadID uuid.UUID, params PaginationParams) ([]*domain.Post, map[uuid.UUID]*domain.User, int, error)
+	Update(ctx context.Context, postID, userID uuid.UUID, role string, content string, reason *string) (*domain.Post, *domain.User, error)
 }
 
+// VoteRepository's ForUpdate reads lock the user's vote on the target until
+// the surrounding transaction ends, even when there is no vote yet, so a
+// concurrent vote by the same user waits and then sees the result.
 type VoteRepository interface {
-	GetThreadVote(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadVote, error)
-	UpsertThreadVote(ctx context.Context, tx *sqlx.Tx, vote *domain.ThreadVote) error
-	DeleteThreadVote(ctx context.Context, tx *sqlx.Tx, userID, threadID uuid.UUID) error
+	GetThreadVoteForUpdate(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadVote, error)
+	UpsertThreadVote(ctx context.Context, vote *domain.ThreadVote) error
+	DeleteThreadVote(ctx context.Context, userID, threadID uuid.UUID) error
 
-	GetPostVote(ctx context.Context, userID, postID uuid.UUID) (*domain.ThreadVote, error)
-	UpsertPostVote(ctx context.Context, tx *sqlx.Tx, vote *domain.ThreadVote) error
-	DeletePostVote(ctx context.Context, tx *sqlx.Tx, userID, postID uuid.UUID) error
+	GetPostVoteForUpdate(ctx context.Context, userID, postID uuid.UUID) (*domain.ThreadVote, error)
+	UpsertPostVote(ctx context.Context, vote *domain.ThreadVote) error
+	DeletePostVote(ctx context.Context, userID, postID uuid.UUID) error
 }
 
 type VoteUsecase interface {
@@ -94,6 +416,81 @@ type VoteUsecase interface {
 	VoteOnPost(ctx context.Context, userID, postID uuid.UUID, voteType int) error
 }
 
+type AuditLogFilter struct {
+	ActorID    *uuid.UUID
+	TargetType *string
+	TargetID   *uuid.UUID
+	Action     *string
+}
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *domain.AuditLog) error
+	GetAll(ctx context.Context, filter AuditLogFilter, params PaginationParams) ([]*domain.AuditLog, error)
+	CountAll(ctx context.Context, filter AuditLogFilter) (int, error)
+}
+
+// AuditLogger records privileged actions (moderation, role changes, etc.) in the audit log.
+type AuditLogger interface {
+	Log(ctx context.Context, entry *domain.AuditLog) error
+}
+
+type AuditLogUsecase interface {
+	AuditLogger
+	GetAll(ctx context.Context, filter AuditLogFilter, params PaginationParams) ([]*domain.AuditLog, map[uuid.UUID]*domain.User, int, error)
+}
+
+type NotificationRepository interface {
+	// Create reports whether the notification was inserted. A vote milestone
+	// that was already announced, or a second notification to the same user
+	// about the same event, is skipped without an error.
+	Create(ctx context.Context, notification *domain.Notification) (bool, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, params PaginationParams) ([]*domain.Notification, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool) (int, error)
+	MarkRead(ctx context.Context, id, userID uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error)
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error)
+	UpsertPreferences(ctx context.Context, prefs *domain.NotificationPreferences) error
+}
+
+// Notifier is the producer side of notifications. eventID is the outbox
+// event being handled; notifying about the same event again creates nothing
+// new, so a call that failed part way can be retried.
+type Notifier interface {
+	NotifyThreadCreated(ctx context.Context, eventID uuid.UUID, thread *domain.Thread, mentioned []uuid.UUID) error
+	NotifyPostCreated(ctx context.Context, eventID uuid.UUID, post *domain.Post, thread *domain.Thread, parent *domain.Post, mentioned []uuid.UUID) error
+	NotifyVoteMilestone(ctx context.Context, eventID, ownerID, threadID uuid.UUID, postID *uuid.UUID, oldCount, newCount int) error
+}
+
+type UpdateNotificationPreferencesParams struct {
+	ThreadReply   *bool
+	PostReply     *bool
+	Mention       *bool
+	VoteMilestone *bool
+}
+
+type NotificationUsecase interface {
+	Notifier
+	GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, params PaginationParams) ([]*domain.Notification, map[uuid.UUID]*domain.User, int, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error)
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, params UpdateNotificationPreferencesParams) (*domain.NotificationPreferences, error)
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event *domain.Event) error
+}
+
+type EventSubscription interface {
+	Events() <-chan *domain.Event
+	Close()
+}
+
+type EventSubscriber interface {
+	Subscribe(topic string) EventSubscription
+}
+
 type PaginationParams struct {
 	Limit  int
 	Offset int
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+type notificationUsecase struct {
+	notificationRepo NotificationRepository
+	userRepo         UserRepository
+	publisher        EventPublisher
+	logger           *slog.Logger
+}
+
+func NewNotificationUsecase(nr NotificationRepository, ur UserRepository, ep EventPublisher, logger *slog.Logger) NotificationUsecase {
+	return &notificationUsecase{
+		notificationRepo: nr,
+		userRepo:         ur,
+		publisher:        ep,
+		logger:           logger,
+	}
+}
+
+func (uc *notificationUsecase) NotifyThreadCreated(ctx context.Context, eventID uuid.UUID, thread *domain.Thread, mentioned []uuid.UUID) error {
+	threadID := thread.ID
+
+	return uc.notifyMentions(ctx, eventID, thread.UserID, mentioned, &threadID, nil, map[uuid.UUID]bool{thread.UserID: true})
+}
+
+func (uc *notificationUsecase) NotifyPostCreated(ctx context.Context, eventID uuid.UUID, post *domain.Post, thread *domain.Thread, parent *domain.Post, mentioned []uuid.UUID) error {
+	notified := map[uuid.UUID]bool{post.UserID: true}
+	threadID := thread.ID
+	postID := post.ID
+
+	if parent != nil && !notified[parent.UserID] {
+		if err := uc.notify(ctx, &domain.Notification{
+			UserID:   parent.UserID,
+			EventID:  &eventID,
+			ActorID:  &post.UserID,
+			Type:     domain.NotificationPostReply,
+			ThreadID: &threadID,
+			PostID:   &postID,
+		}); err != nil {
+			return err
+		}
+		notified[parent.UserID] = true
+	}
+
+	if !notified[thread.UserID] {
+		if err := uc.notify(ctx, &domain.Notification{
+			UserID:   thread.UserID,
+			EventID:  &eventID,
+			ActorID:  &post.UserID,
+			Type:     domain.NotificationThreadReply,
+			ThreadID: &threadID,
+			PostID:   &postID,
+		}); err != nil {
+			return err
+		}
+		notified[thread.UserID] = true
+	}
+
+	return uc.notifyMentions(ctx, eventID, post.UserID, mentioned, &threadID, &postID, notified)
+}
+
+func (uc *notificationUsecase) NotifyVoteMilestone(ctx context.Context, eventID, ownerID, threadID uuid.UUID, postID *uuid.UUID, oldCount, newCount int) error {
+	for _, milestone := range domain.VoteMilestones {
+		if oldCount < milestone && newCount >= milestone {
+			m := milestone
+			if err := uc.notify(ctx, &domain.Notification{
+				UserID:    ownerID,
+				Type:      domain.NotificationVoteMilestone,
+				ThreadID:  &threadID,
+				PostID:    postID,
+				Milestone: &m,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// notifyMentions notifies every mentioned user who has not already been
+// notified about the same event.
+func (uc *notificationUsecase) notifyMentions(ctx context.Context, eventID, actorID uuid.UUID, mentioned []uuid.UUID, threadID, postID *uuid.UUID, notified map[uuid.UUID]bool) error {
+	for _, userID := range mentioned {
+		if notified[userID] {
+			continue
+		}
+
+		if err := uc.notify(ctx, &domain.Notification{
+			UserID:   userID,
+			EventID:  &eventID,
+			ActorID:  &actorID,
+			Type:     domain.NotificationMention,
+			ThreadID: threadID,
+			PostID:   postID,
+		}); err != nil {
+			return err
+		}
+		notified[userID] = true
+	}
+
+	return nil
+}
+
+// notify stores n if the user wants notifications of its type and publishes
+// it to them. Publishing is best effort: the notification is already stored
+// and is listed the next time the user asks.
+func (uc *notificationUsecase) notify(ctx context.Context, n *domain.Notification) error {
+	prefs, err := uc.GetPreferences(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !prefs.Allows(n.Type) {
+		return nil
+	}
+
+	n.ID = uuid.New()
+	n.CreatedAt = time.Now()
+
+	inserted, err := uc.notificationRepo.Create(ctx, n)
+	if err != nil {
+		return err
+	}
+
+	if !inserted {
+		return nil
+	}
+
+	publishEvent(ctx, uc.logger, uc.publisher, domain.UserTopic(n.UserID), domain.EventNotification, &domain.NotificationEventData{
+		ID:        n.ID,
+		Type:      n.Type,
+		ActorID:   n.ActorID,
+		ThreadID:  n.ThreadID,
+		PostID:    n.PostID,
+		Milestone: n.Milestone,
+		CreatedAt: n.CreatedAt,
+	})
+
+	return nil
+}
+
+func (uc *notificationUsecase) GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, params PaginationParams) ([]*domain.Notification, map[uuid.UUID]*domain.User, int, error) {
+	total, err := uc.notificationRepo.CountByUserID(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	notifications, err := uc.notificationRepo.GetByUserID(ctx, userID, unreadOnly, params)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	actorIDs := make([]uuid.UUID, 0)
+	for _, n := range notifications {
+		if n.ActorID != nil {
+			actorIDs = append(actorIDs, *n.ActorID)
+		}
+	}
+
+	if len(actorIDs) == 0 {
+		return notifications, map[uuid.UUID]*domain.User{}, total, nil
+	}
+
+	userMap, err := uc.userRepo.GetByIDs(ctx, actorIDs)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return notifications, userMap, total, nil
+}
+
+func (uc *notificationUsecase) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	return uc.notificationRepo.CountByUserID(ctx, userID, true)
+}
+
+func (uc *notificationUsecase) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	return uc.notificationRepo.MarkRead(ctx, notificationID, userID)
+}
+
+func (uc *notificationUsecase) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	return uc.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+func (uc *notificationUsecase) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	prefs, err := uc.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.DefaultNotificationPreferences(userID), nil
+		}
+
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+func (uc *notificationUsecase) UpdatePreferences(ctx context.Context, userID uuid.UUID, params UpdateNotificationPreferencesParams) (*domain.NotificationPreferences, error) {
+	prefs, err := uc.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.ThreadReply != nil {
+		prefs.ThreadReply = *params.ThreadReply
+	}
+
+	if params.PostReply != nil {
+		prefs.PostReply = *params.PostReply
+	}
+
+	if params.Mention != nil {
+		prefs.Mention = *params.Mention
+	}
+
+	if params.VoteMilestone != nil {
+		prefs.VoteMilestone = *params.VoteMilestone
+	}
+
+	prefs.UpdatedAt = time.Now()
+
+	if err := uc.notificationRepo.UpsertPreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
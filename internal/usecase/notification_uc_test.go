@@ -0,0 +1,221 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+func newNotificationUsecase(t *testing.T) (usecase.NotificationUsecase, *recordingPublisher) {
+	t.Helper()
+
+	s := memory.NewStore()
+	publisher := &recordingPublisher{}
+
+	return usecase.NewNotificationUsecase(memory.NewMemoryNotificationRepo(s), memory.NewMemoryUserRepo(s), publisher, discardLogger), publisher
+}
+
+// mustNotify fails the test when a Notify call returns an error.
+func mustNotify(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+}
+
+func TestNotifyVoteMilestoneAnnouncesEachMilestoneOnce(t *testing.T) {
+	ctx := context.Background()
+	uc, publisher := newNotificationUsecase(t)
+	ownerID, threadID := uuid.New(), uuid.New()
+
+	// Toggling a vote crosses the same milestone again.
+	mustNotify(t, uc.NotifyVoteMilestone(ctx, uuid.New(), ownerID, threadID, nil, 9, 10))
+	mustNotify(t, uc.NotifyVoteMilestone(ctx, uuid.New(), ownerID, threadID, nil, 9, 10))
+
+	notifications, _, total, err := uc.GetByUserID(ctx, ownerID, false, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	if total != 1 || len(notifications) != 1 {
+		t.Fatalf("got %d notifications, want 1", total)
+	}
+
+	if m := notifications[0].Milestone; m == nil || *m != 10 {
+		t.Errorf("milestone = %v, want 10", m)
+	}
+
+	if events := publisher.published(); len(events) != 1 {
+		t.Errorf("published %d events, want 1 for the one inserted notification", len(events))
+	}
+}
+
+func TestNotifyVoteMilestoneSeparatesTargets(t *testing.T) {
+	ctx := context.Background()
+	uc, publisher := newNotificationUsecase(t)
+	ownerID, threadID, postID := uuid.New(), uuid.New(), uuid.New()
+
+	mustNotify(t, uc.NotifyVoteMilestone(ctx, uuid.New(), ownerID, threadID, nil, 0, 25))
+	mustNotify(t, uc.NotifyVoteMilestone(ctx, uuid.New(), ownerID, threadID, &postID, 0, 10))
+
+	count, err := uc.CountUnread(ctx, ownerID)
+	if err != nil {
+		t.Fatalf("CountUnread: %v", err)
+	}
+
+	// 10 and 25 on the thread, 10 on the post.
+	if count != 3 {
+		t.Errorf("got %d notifications, want 3", count)
+	}
+
+	if events := publisher.published(); len(events) != 3 {
+		t.Errorf("published %d events, want 3", len(events))
+	}
+}
+
+func TestNotifyPostCreatedNotifiesEachUserOnce(t *testing.T) {
+	ctx := context.Background()
+	uc, publisher := newNotificationUsecase(t)
+	authorID, replierID := uuid.New(), uuid.New()
+
+	thread := &domain.Thread{ID: uuid.New(), UserID: authorID}
+	parent := &domain.Post{ID: uuid.New(), ThreadID: thread.ID, UserID: authorID}
+	post := &domain.Post{ID: uuid.New(), ThreadID: thread.ID, UserID: replierID}
+
+	mustNotify(t, uc.NotifyPostCreated(ctx, uuid.New(), post, thread, parent, []uuid.UUID{authorID, replierID}))
+
+	notifications, _, _, err := uc.GetByUserID(ctx, authorID, false, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	if len(notifications) != 1 || notifications[0].Type != domain.NotificationPostReply {
+		t.Errorf("author got %+v, want a single post_reply", notifications)
+	}
+
+	if count, _ := uc.CountUnread(ctx, replierID); count != 0 {
+		t.Errorf("replier got %d notifications about their own post", count)
+	}
+
+	if events := publisher.published(); len(events) != 1 {
+		t.Errorf("published %d events, want 1", len(events))
+	}
+}
+
+func TestNotifyRespectsPreferences(t *testing.T) {
+	ctx := context.Background()
+	uc, publisher := newNotificationUsecase(t)
+	ownerID := uuid.New()
+
+	off := false
+	if _, err := uc.UpdatePreferences(ctx, ownerID, usecase.UpdateNotificationPreferencesParams{VoteMilestone: &off}); err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+
+	mustNotify(t, uc.NotifyVoteMilestone(ctx, uuid.New(), ownerID, uuid.New(), nil, 0, 10))
+
+	if count, _ := uc.CountUnread(ctx, ownerID); count != 0 {
+		t.Errorf("got %d notifications with vote milestones turned off", count)
+	}
+
+	if events := publisher.published(); len(events) != 0 {
+		t.Errorf("published %d events, want none", len(events))
+	}
+}
+
+func TestNotifyPostCreatedIsIdempotentPerEvent(t *testing.T) {
+	ctx := context.Background()
+	uc, publisher := newNotificationUsecase(t)
+	authorID, replierID, mentionedID := uuid.New(), uuid.New(), uuid.New()
+
+	thread := &domain.Thread{ID: uuid.New(), UserID: authorID}
+	post := &domain.Post{ID: uuid.New(), ThreadID: thread.ID, UserID: replierID}
+	eventID := uuid.New()
+
+	// The relay hands the same event over again after a failed attempt.
+	for i := 0; i < 2; i++ {
+		mustNotify(t, uc.NotifyPostCreated(ctx, eventID, post, thread, nil, []uuid.UUID{mentionedID}))
+	}
+
+	for _, userID := range []uuid.UUID{authorID, mentionedID} {
+		if count, _ := uc.CountUnread(ctx, userID); count != 1 {
+			t.Errorf("user got %d notifications for one event, want 1", count)
+		}
+	}
+
+	if events := publisher.published(); len(events) != 2 {
+		t.Errorf("published %d events, want 2", len(events))
+	}
+
+	// Another event about the same post is a new notification.
+	mustNotify(t, uc.NotifyPostCreated(ctx, uuid.New(), post, thread, nil, nil))
+
+	if count, _ := uc.CountUnread(ctx, authorID); count != 2 {
+		t.Errorf("author got %d notifications for two events, want 2", count)
+	}
+}
+
+// failingNotificationRepo is a NotificationRepository whose Create fails.
+type failingNotificationRepo struct {
+	usecase.NotificationRepository
+	err error
+}
+
+func (r *failingNotificationRepo) Create(ctx context.Context, n *domain.Notification) (bool, error) {
+	return false, r.err
+}
+
+func TestNotificationHandlerRetriesFailedNotifications(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	threads := memory.NewMemoryThreadRepo(s)
+	processed := memory.NewMemoryProcessedEventRepo(s)
+
+	thread := &domain.Thread{ID: uuid.New(), UserID: uuid.New(), CategoryID: uuid.New(), Title: "t", Content: "c"}
+	if err := threads.Create(ctx, thread); err != nil {
+		t.Fatalf("Create thread: %v", err)
+	}
+
+	mentionedID := uuid.New()
+	payload, err := json.Marshal(&domain.ThreadCreatedEventData{ID: thread.ID, Mentioned: []uuid.UUID{mentionedID}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	e := newTestOutboxEvent(domain.EventThreadCreated)
+	e.Payload = payload
+
+	handler := func(repo usecase.NotificationRepository) (usecase.NotificationUsecase, usecase.EventHandler) {
+		notifier := usecase.NewNotificationUsecase(repo, memory.NewMemoryUserRepo(s), &recordingPublisher{}, discardLogger)
+
+		return notifier, usecase.Deduplicate(usecase.NotificationConsumer, processed, usecase.NewNotificationHandler(notifier, threads, memory.NewMemoryPostRepo(s)))
+	}
+
+	storeErr := errors.New("notifications unavailable")
+	_, failing := handler(&failingNotificationRepo{NotificationRepository: memory.NewMemoryNotificationRepo(s), err: storeErr})
+
+	if err := failing(ctx, e); !errors.Is(err, storeErr) {
+		t.Fatalf("handler error = %v, want %v", err, storeErr)
+	}
+
+	if done, err := processed.IsProcessed(ctx, usecase.NotificationConsumer, e.ID); err != nil || done {
+		t.Fatalf("IsProcessed after a failure = %v, %v; want false, nil", done, err)
+	}
+
+	notifier, working := handler(memory.NewMemoryNotificationRepo(s))
+	for i := 0; i < 2; i++ {
+		if err := working(ctx, e); err != nil {
+			t.Fatalf("retry: %v", err)
+		}
+	}
+
+	if count, _ := notifier.CountUnread(ctx, mentionedID); count != 1 {
+		t.Errorf("mentioned user got %d notifications after the retry, want 1", count)
+	}
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+// Outbox consumer names key the events each consumer has handled. Renaming
+// one makes it handle every retained event again.
+const (
+	RealtimeConsumer     = "realtime"
+	NotificationConsumer = "notifications"
+	WebhookConsumer      = "webhooks"
+)
+
+// webhookEventTypes maps outbox event types to the webhook events they are
+// delivered as.
+var webhookEventTypes = map[string]string{
+	domain.EventThreadCreated: domain.WebhookEventThreadCreated,
+	domain.EventThreadDeleted: domain.WebhookEventThreadDeleted,
+	domain.EventPostCreated:   domain.WebhookEventPostCreated,
+	domain.EventThreadVoted:   domain.WebhookEventVoteCast,
+	domain.EventPostVoted:     domain.WebhookEventVoteCast,
+}
+
+// NewRealtimeHandler publishes outbox events to real-time subscribers. The
+// published event keeps the outbox event ID, so clients can drop a repeat.
+func NewRealtimeHandler(publisher EventPublisher) EventHandler {
+	return func(ctx context.Context, e *domain.OutboxEvent) error {
+		var topic string
+		data := json.RawMessage(e.Payload)
+
+		switch e.EventType {
+		case domain.EventThreadCreated:
+			var created domain.ThreadCreatedEventData
+			if err := json.Unmarshal(e.Payload, &created); err != nil {
+				return err
+			}
+			topic = domain.CategoryTopic(created.CategoryID)
+		case domain.EventPostCreated:
+			var created domain.PostCreatedEventData
+			if err := json.Unmarshal(e.Payload, &created); err != nil {
+				return err
+			}
+			topic = domain.ThreadTopic(created.ThreadID)
+		case domain.EventThreadUpdated, domain.EventThreadLockChange:
+			var thread domain.ThreadEventData
+			if err := json.Unmarshal(e.Payload, &thread); err != nil {
+				return err
+			}
+			topic = domain.ThreadTopic(thread.ID)
+		case domain.EventPostUpdated:
+			var post domain.PostEventData
+			if err := json.Unmarshal(e.Payload, &post); err != nil {
+				return err
+			}
+			topic = domain.ThreadTopic(post.ThreadID)
+		case domain.EventThreadVoted, domain.EventPostVoted:
+			var cast domain.VoteCastEventData
+			if err := json.Unmarshal(e.Payload, &cast); err != nil {
+				return err
+			}
+			topic = domain.ThreadTopic(cast.ThreadID)
+
+			payload, err := json.Marshal(&domain.VoteEventData{ThreadID: cast.ThreadID, PostID: cast.PostID, VoteCount: cast.VoteCount})
+			if err != nil {
+				return err
+			}
+			data = payload
+		default:
+			return nil
+		}
+
+		return publisher.Publish(ctx, &domain.Event{
+			ID:         e.ID,
+			Type:       e.EventType,
+			Topic:      topic,
+			Data:       data,
+			OccurredAt: e.CreatedAt,
+		})
+	}
+}
+
+// NewWebhookHandler queues webhook deliveries for outbox events. The outbox
+// event ID becomes the webhook event ID, so handling an event twice queues
+// nothing new.
+func NewWebhookHandler(dispatcher WebhookDispatcher) EventHandler {
+	return func(ctx context.Context, e *domain.OutboxEvent) error {
+		eventType, ok := webhookEventTypes[e.EventType]
+		if !ok {
+			return nil
+		}
+
+		return dispatcher.Dispatch(ctx, e.ID, eventType, json.RawMessage(e.Payload))
+	}
+}
+
+// NewNotificationHandler notifies users about outbox events. Content that
+// was deleted before its event was handled no longer notifies anyone.
+func NewNotificationHandler(notifier Notifier, tr ThreadRepository, pr PostRepository) EventHandler {
+	h := &notificationHandler{notifier: notifier, threadRepo: tr, postRepo: pr}
+
+	return func(ctx context.Context, e *domain.OutboxEvent) error {
+		var err error
+
+		switch e.EventType {
+		case domain.EventThreadCreated:
+			err = h.threadCreated(ctx, e)
+		case domain.EventPostCreated:
+			err = h.postCreated(ctx, e)
+		case domain.EventThreadVoted, domain.EventPostVoted:
+			err = h.voteCast(ctx, e)
+		}
+
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+}
+
+type notificationHandler struct {
+	notifier   Notifier
+	threadRepo ThreadRepository
+	postRepo   PostRepository
+}
+
+func (h *notificationHandler) threadCreated(ctx context.Context, e *domain.OutboxEvent) error {
+	var created domain.ThreadCreatedEventData
+	if err := json.Unmarshal(e.Payload, &created); err != nil {
+		return err
+	}
+
+	thread, err := h.threadRepo.GetByID(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+
+	return h.notifier.NotifyThreadCreated(ctx, e.ID, thread, created.Mentioned)
+}
+
+func (h *notificationHandler) postCreated(ctx context.Context, e *domain.OutboxEvent) error {
+	var created domain.PostCreatedEventData
+	if err := json.Unmarshal(e.Payload, &created); err != nil {
+		return err
+	}
+
+	post, err := h.postRepo.GetByID(ctx, created.ID)
+	if err != nil {
+		return err
+	}
+
+	thread, err := h.threadRepo.GetByID(ctx, post.ThreadID)
+	if err != nil {
+		return err
+	}
+
+	var parent *domain.Post
+	if post.ParentPostID != nil {
+		parent, err = h.postRepo.GetByID(ctx, *post.ParentPostID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+	}
+
+	return h.notifier.NotifyPostCreated(ctx, e.ID, post, thread, parent, created.Mentioned)
+}
+
+func (h *notificationHandler) voteCast(ctx context.Context, e *domain.OutboxEvent) error {
+	var cast domain.VoteCastEventData
+	if err := json.Unmarshal(e.Payload, &cast); err != nil {
+		return err
+	}
+
+	if cast.VoteCount <= cast.PreviousCount {
+		return nil
+	}
+
+	if cast.PostID != nil {
+		post, err := h.postRepo.GetByID(ctx, *cast.PostID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := h.threadRepo.GetByID(ctx, post.ThreadID); err != nil {
+			return err
+		}
+
+		return h.notifier.NotifyVoteMilestone(ctx, e.ID, post.UserID, post.ThreadID, &post.ID, cast.PreviousCount, cast.VoteCount)
+	}
+
+	thread, err := h.threadRepo.GetByID(ctx, cast.ThreadID)
+	if err != nil {
+		return err
+	}
+
+	return h.notifier.NotifyVoteMilestone(ctx, e.ID, thread.UserID, thread.ID, nil, cast.PreviousCount, cast.VoteCount)
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+const (
+	outboxBatchSize = 100
+
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = 10 * time.Minute
+
+	// outboxLease keeps a claimed event away from other relays while it is
+	// being published.
+	outboxLease = time.Minute
+)
+
+type outboxUsecase struct {
+	outboxRepo    OutboxRepository
+	processedRepo ProcessedEventRepository
+	bus           EventBus
+	logger        *slog.Logger
+}
+
+func NewOutboxUsecase(or OutboxRepository, per ProcessedEventRepository, bus EventBus, logger *slog.Logger) OutboxUsecase {
+	return &outboxUsecase{
+		outboxRepo:    or,
+		processedRepo: per,
+		bus:           bus,
+		logger:        logger,
+	}
+}
+
+// Relay publishes pending outbox events in the order they were recorded and
+// returns how many were published. An event the bus rejects is retried with
+// exponential backoff; it is never dropped. Because an event is marked
+// published only after the bus accepts it, a crash in between publishes it
+// again, so consumers must deduplicate by event ID.
+func (uc *outboxUsecase) Relay(ctx context.Context) (int, error) {
+	published := 0
+
+	for ctx.Err() == nil {
+		events, err := uc.outboxRepo.ClaimPending(ctx, time.Now(), outboxLease, outboxBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		for _, e := range events {
+			if err := uc.bus.Publish(ctx, e); err != nil {
+				uc.fail(ctx, e, err)
+
+				continue
+			}
+
+			if err := uc.outboxRepo.MarkPublished(context.WithoutCancel(ctx), e.ID, time.Now()); err != nil {
+				uc.logger.ErrorContext(ctx, "failed to mark outbox event as published", "event_id", e.ID, "error", err)
+
+				continue
+			}
+
+			published++
+		}
+
+		if len(events) < outboxBatchSize {
+			break
+		}
+	}
+
+	return published, ctx.Err()
+}
+
+func (uc *outboxUsecase) fail(ctx context.Context, e *domain.OutboxEvent, cause error) {
+	uc.logger.ErrorContext(ctx, "failed to publish outbox event", "event_type", e.EventType, "event_id", e.ID, "error", cause)
+
+	msg := cause.Error()
+	e.Attempts++
+	e.LastError = &msg
+	e.NextAttemptAt = time.Now().Add(outboxBackoff(e.Attempts))
+
+	if err := uc.outboxRepo.MarkFailed(context.WithoutCancel(ctx), e); err != nil {
+		uc.logger.ErrorContext(ctx, "failed to reschedule outbox event", "event_id", e.ID, "error", err)
+	}
+}
+
+// Purge removes published events and consumer dedup records older than
+// retention. Retention must outlast the longest retry backoff, or a late
+// redelivery would no longer be recognised as a duplicate.
+func (uc *outboxUsecase) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	cutoff := time.Now().Add(-retention)
+
+	purged, err := uc.outboxRepo.PurgePublishedBefore(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := uc.processedRepo.PurgeBefore(ctx, cutoff); err != nil {
+		return purged, err
+	}
+
+	return purged, nil
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+
+	return backoff
+}
+
+// newOutboxEvent builds an event for OutboxRepository.Add. It is meant to be
+// added in the transaction of the change it describes.
+func newOutboxEvent(aggregateType string, aggregateID uuid.UUID, eventType string, data interface{}) (*domain.OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &domain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Deduplicate wraps a consumer's handler so an event it already handled is
+// skipped. The event is recorded only after handler succeeds, so a crash in
+// between runs handler again; handlers should still be safe to repeat.
+func Deduplicate(consumer string, repo ProcessedEventRepository, handler EventHandler) EventHandler {
+	return func(ctx context.Context, event *domain.OutboxEvent) error {
+		processed, err := repo.IsProcessed(ctx, consumer, event.ID)
+		if err != nil {
+			return err
+		}
+
+		if processed {
+			return nil
+		}
+
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+
+		return repo.MarkProcessed(ctx, consumer, event.ID)
+	}
+}
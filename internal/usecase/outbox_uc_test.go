@@ -0,0 +1,287 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// scriptedBus is an EventBus that fails while failing is set and counts the
+// events it accepts.
+type scriptedBus struct {
+	failing   bool
+	published int
+}
+
+func (b *scriptedBus) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	if b.failing {
+		return errors.New("bus unavailable")
+	}
+
+	b.published++
+
+	return nil
+}
+
+func newTestOutboxEvent(eventType string) *domain.OutboxEvent {
+	now := time.Now()
+
+	return &domain.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: domain.AggregateThread,
+		AggregateID:   uuid.New(),
+		EventType:     eventType,
+		Payload:       []byte(`{}`),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+func TestRelayRetriesRejectedEventsWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	outbox := memory.NewMemoryOutboxRepo(s)
+	bus := &scriptedBus{failing: true}
+	uc := usecase.NewOutboxUsecase(outbox, memory.NewMemoryProcessedEventRepo(s), bus, discardLogger)
+
+	if err := outbox.Add(ctx, newTestOutboxEvent(domain.EventThreadCreated)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	start := time.Now()
+	published, err := uc.Relay(ctx)
+	if err != nil || published != 0 {
+		t.Fatalf("Relay = %d, %v; want 0, nil", published, err)
+	}
+
+	e := s.OutboxEvents()[0]
+	if e.Attempts != 1 || e.LastError == nil || *e.LastError != "bus unavailable" || e.PublishedAt != nil {
+		t.Fatalf("after one failure: attempts = %d, last error = %v, published = %v", e.Attempts, e.LastError, e.PublishedAt)
+	}
+
+	if delay := e.NextAttemptAt.Sub(start); delay < 5*time.Second || delay > 6*time.Second {
+		t.Errorf("first retry in %v, want about 5s", delay)
+	}
+
+	// Not yet due: the relay leaves the event alone.
+	if published, err := uc.Relay(ctx); err != nil || published != 0 {
+		t.Fatalf("Relay before the retry = %d, %v; want 0, nil", published, err)
+	}
+
+	if got := s.OutboxEvents()[0].Attempts; got != 1 {
+		t.Fatalf("attempts before the retry = %d, want 1", got)
+	}
+
+	// Make the event due again; the second failure doubles the delay.
+	e.NextAttemptAt = time.Now()
+	if err := outbox.MarkFailed(ctx, e); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+
+	start = time.Now()
+	if _, err := uc.Relay(ctx); err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+
+	e = s.OutboxEvents()[0]
+	if delay := e.NextAttemptAt.Sub(start); e.Attempts != 2 || delay < 10*time.Second || delay > 11*time.Second {
+		t.Errorf("after two failures: attempts = %d, next retry in %v; want 2 and about 10s", e.Attempts, delay)
+	}
+
+	e.NextAttemptAt = time.Now()
+	if err := outbox.MarkFailed(ctx, e); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+
+	bus.failing = false
+	if published, err := uc.Relay(ctx); err != nil || published != 1 {
+		t.Fatalf("Relay after recovery = %d, %v; want 1, nil", published, err)
+	}
+
+	e = s.OutboxEvents()[0]
+	if e.PublishedAt == nil || e.LastError != nil {
+		t.Errorf("after recovery: published = %v, last error = %v; want published without error", e.PublishedAt, e.LastError)
+	}
+
+	if published, err := uc.Relay(ctx); err != nil || published != 0 || bus.published != 1 {
+		t.Errorf("Relay after publishing = %d, %v with %d accepted; want the event published once", published, err, bus.published)
+	}
+}
+
+func TestRelayPublishesInRecordedOrder(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStore()
+	outbox := memory.NewMemoryOutboxRepo(s)
+
+	var got []string
+	bus := busFunc(func(ctx context.Context, e *domain.OutboxEvent) error {
+		got = append(got, e.EventType)
+
+		return nil
+	})
+	uc := usecase.NewOutboxUsecase(outbox, memory.NewMemoryProcessedEventRepo(s), bus, discardLogger)
+
+	want := []string{domain.EventThreadCreated, domain.EventPostCreated, domain.EventPostVoted}
+	for _, eventType := range want {
+		if err := outbox.Add(ctx, newTestOutboxEvent(eventType)); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	if published, err := uc.Relay(ctx); err != nil || published != len(want) {
+		t.Fatalf("Relay = %d, %v; want %d, nil", published, err, len(want))
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
+
+// busFunc adapts a function to an EventBus.
+type busFunc func(ctx context.Context, event *domain.OutboxEvent) error
+
+func (f busFunc) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	return f(ctx, event)
+}
+
+func TestDeduplicateHandlesEachEventOncePerConsumer(t *testing.T) {
+	ctx := context.Background()
+	processed := memory.NewMemoryProcessedEventRepo(memory.NewStore())
+
+	calls := map[string]int{}
+	counting := func(consumer string) usecase.EventHandler {
+		return usecase.Deduplicate(consumer, processed, func(ctx context.Context, e *domain.OutboxEvent) error {
+			calls[consumer]++
+
+			return nil
+		})
+	}
+	realtime := counting(usecase.RealtimeConsumer)
+	webhooks := counting(usecase.WebhookConsumer)
+
+	first := newTestOutboxEvent(domain.EventThreadCreated)
+	second := newTestOutboxEvent(domain.EventThreadCreated)
+
+	for _, e := range []*domain.OutboxEvent{first, first, second, first} {
+		if err := realtime(ctx, e); err != nil {
+			t.Fatalf("realtime: %v", err)
+		}
+	}
+
+	if err := webhooks(ctx, first); err != nil {
+		t.Fatalf("webhooks: %v", err)
+	}
+
+	if calls[usecase.RealtimeConsumer] != 2 || calls[usecase.WebhookConsumer] != 1 {
+		t.Errorf("calls = %v, want 2 for realtime and 1 for webhooks", calls)
+	}
+}
+
+func TestDeduplicateRetriesAFailedEvent(t *testing.T) {
+	ctx := context.Background()
+	processed := memory.NewMemoryProcessedEventRepo(memory.NewStore())
+
+	calls := 0
+	handlerErr := errors.New("handler failed")
+	handler := usecase.Deduplicate(usecase.NotificationConsumer, processed, func(ctx context.Context, e *domain.OutboxEvent) error {
+		calls++
+		if calls == 1 {
+			return handlerErr
+		}
+
+		return nil
+	})
+
+	e := newTestOutboxEvent(domain.EventPostCreated)
+
+	if err := handler(ctx, e); !errors.Is(err, handlerErr) {
+		t.Fatalf("first attempt error = %v, want %v", err, handlerErr)
+	}
+
+	if done, err := processed.IsProcessed(ctx, usecase.NotificationConsumer, e.ID); err != nil || done {
+		t.Fatalf("IsProcessed after a failure = %v, %v; want false, nil", done, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := handler(ctx, e); err != nil {
+			t.Fatalf("retry: %v", err)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+}
+
+func TestWebhookHandlerQueuesMappedEvents(t *testing.T) {
+	ctx := context.Background()
+	dispatcher := &recordingDispatcher{}
+	handler := usecase.NewWebhookHandler(dispatcher)
+
+	payload, err := json.Marshal(&domain.ThreadEventData{ID: uuid.New()})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	for _, eventType := range []string{domain.EventThreadCreated, domain.EventThreadUpdated, domain.EventPostVoted} {
+		e := newTestOutboxEvent(eventType)
+		e.Payload = payload
+
+		if err := handler(ctx, e); err != nil {
+			t.Fatalf("handle %s: %v", eventType, err)
+		}
+	}
+
+	want := []string{domain.WebhookEventThreadCreated, domain.WebhookEventVoteCast}
+	if !slices.Equal(dispatcher.types, want) {
+		t.Errorf("dispatched %v, want %v", dispatcher.types, want)
+	}
+}
+
+func TestRealtimeHandlerPublishesEditsToTheThread(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	handler := usecase.NewRealtimeHandler(publisher)
+
+	thread := &domain.Thread{ID: uuid.New(), Title: "t", IsLocked: true}
+	post := &domain.Post{ID: uuid.New(), ThreadID: thread.ID}
+
+	payloads := map[string]interface{}{
+		domain.EventThreadUpdated:    domain.NewThreadEventData(thread),
+		domain.EventThreadLockChange: domain.NewThreadEventData(thread),
+		domain.EventPostUpdated:      domain.NewPostEventData(post),
+	}
+
+	for eventType, data := range payloads {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+
+		e := newTestOutboxEvent(eventType)
+		e.Payload = payload
+
+		if err := handler(ctx, e); err != nil {
+			t.Fatalf("handle %s: %v", eventType, err)
+		}
+	}
+
+	events := publisher.published()
+	if len(events) != len(payloads) {
+		t.Fatalf("published %d events, want %d", len(events), len(payloads))
+	}
+
+	for _, e := range events {
+		if e.Topic != domain.ThreadTopic(thread.ID) {
+			t.Errorf("%s published to %q, want the thread topic", e.Type, e.Topic)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,28 +11,49 @@ import (
 )
 
 type postUsecase struct {
-	postRepo   PostRepository
-	threadRepo ThreadRepository
-	userRepo   UserRepository
+	txManager      TxManager
+	postRepo       PostRepository
+	threadRepo     ThreadRepository
+	userRepo       UserRepository
+	follower       ThreadFollower
+	auditLogger    AuditLogger
+	renderer       ContentRenderer
+	attachmentRepo AttachmentRepository
+	outboxRepo     OutboxRepository
+	content        *contentProcessor
+	logger         *slog.Logger
 }
 
-func NewPostUsecase(pr PostRepository, tr ThreadRepository, ur UserRepository) PostUsecase {
+func NewPostUsecase(tm TxManager, pr PostRepository, tr ThreadRepository, ur UserRepository, tgr TagRepository, mr MentionRepository, atr AttachmentRepository, or OutboxRepository, f ThreadFollower, al AuditLogger, r ContentRenderer, logger *slog.Logger) PostUsecase {
 	return &postUsecase{
-		postRepo:   pr,
-		threadRepo: tr,
-		userRepo:   ur,
+		txManager:      tm,
+		postRepo:       pr,
+		threadRepo:     tr,
+		userRepo:       ur,
+		follower:       f,
+		auditLogger:    al,
+		renderer:       r,
+		attachmentRepo: atr,
+		outboxRepo:     or,
+		content: &contentProcessor{
+			renderer:    r,
+			userRepo:    ur,
+			tagRepo:     tgr,
+			mentionRepo: mr,
+		},
+		logger: logger,
 	}
 }
 
 func (uc *postUsecase) Create(ctx context.Context, content string, userID uuid.UUID, threadID uuid.UUID, parentPostID *uuid.UUID) (*domain.Post, error) {
 	if content == "" {
-		return nil, domain.ErrInvalid
+		return nil, domain.Invalid(domain.FieldError{Field: "content", Rule: "required"})
 	}
 
 	thread, err := uc.threadRepo.GetByID(ctx, threadID)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			return nil, domain.ErrInvalid
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.Invalid(domain.FieldError{Field: "thread_id", Rule: "exists"})
 		}
 
 		return nil, err
@@ -40,24 +63,62 @@ func (uc *postUsecase) Create(ctx context.Context, content string, userID uuid.U
 		return nil, domain.ErrThreadLocked
 	}
 
-	// (optional) validate parentPostID
+	var parent *domain.Post
 	if parentPostID != nil {
-		// You might want to implement a check here to see if the parent post exists
+		parent, err = uc.postRepo.GetByID(ctx, *parentPostID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.Invalid(domain.FieldError{Field: "parent_post_id", Rule: "exists"})
+			}
+
+			return nil, err
+		}
+
+		if parent.ThreadID != threadID {
+			return nil, domain.Invalid(domain.FieldError{Field: "parent_post_id", Rule: "same_thread"})
+		}
+	}
+
+	processed, err := uc.content.process(ctx, content)
+	if err != nil {
+		return nil, err
 	}
 
 	post := &domain.Post{
 		ID:           uuid.New(),
 		Content:      content,
+		ContentHTML:  processed.HTML,
 		UserID:       userID,
 		ThreadID:     threadID,
 		ParentPostID: parentPostID,
 		CreatedAt:    time.Now(),
 	}
 
-	if err := uc.postRepo.Create(ctx, post); err != nil {
+	event, err := newOutboxEvent(domain.AggregatePost, post.ID, domain.EventPostCreated, &domain.PostCreatedEventData{
+		PostEventData: *domain.NewPostEventData(post),
+		Mentioned:     processed.Mentioned,
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.postRepo.Create(ctx, post); err != nil {
+			return err
+		}
+
+		if err := uc.content.savePost(ctx, post, processed); err != nil {
+			return err
+		}
+
+		return uc.outboxRepo.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.follower.FollowThread(ctx, userID, thread, post)
+
 	return post, nil
 }
 
@@ -82,8 +143,20 @@ func (uc *postUsecase) GetByThreadID(ctx context.Context, threadID uuid.UUID, pa
 	}
 
 	userIDs := make([]uuid.UUID, 0)
+	postIDs := make([]uuid.UUID, 0, len(posts))
 	for _, p := range posts {
+		ensurePostHTML(ctx, uc.logger, uc.renderer, p)
 		userIDs = append(userIDs, p.UserID)
+		postIDs = append(postIDs, p.ID)
+	}
+
+	attachmentMap, err := uc.attachmentRepo.GetByPostIDs(ctx, postIDs)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "failed to load post attachments", "thread_id", threadID, "error", err)
+	}
+
+	for _, p := range posts {
+		p.Attachments = attachmentMap[p.ID]
 	}
 
 	userMap, err := uc.userRepo.GetByIDs(ctx, userIDs)
@@ -93,3 +166,86 @@ func (uc *postUsecase) GetByThreadID(ctx context.Context, threadID uuid.UUID, pa
 
 	return posts, userMap, total, nil
 }
+
+func (uc *postUsecase) Update(ctx context.Context, postID, userID uuid.UUID, role string, content string, reason *string) (*domain.Post, *domain.User, error) {
+	if content == "" {
+		return nil, nil, domain.Invalid(domain.FieldError{Field: "content", Rule: "required"})
+	}
+
+	post, err := uc.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isOwner := post.UserID == userID
+	isAdmin := role == "admin"
+
+	if !isOwner && !isAdmin {
+		return nil, nil, domain.ErrForbidden
+	}
+
+	thread, err := uc.threadRepo.GetByID(ctx, post.ThreadID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if thread.IsLocked && !isAdmin {
+		return nil, nil, domain.ErrThreadLocked
+	}
+
+	processed, err := uc.content.process(ctx, content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	post.Content = content
+	post.ContentHTML = processed.HTML
+	post.UpdatedAt = &now
+
+	event, err := newOutboxEvent(domain.AggregatePost, postID, domain.EventPostUpdated, domain.NewPostEventData(post))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.postRepo.Update(ctx, post); err != nil {
+			return err
+		}
+
+		if err := uc.content.savePost(ctx, post, processed); err != nil {
+			return err
+		}
+
+		if !isOwner {
+			if err := recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+				ActorID:    userID,
+				ActorRole:  role,
+				Action:     domain.AuditActionPostUpdate,
+				TargetType: domain.AuditTargetPost,
+				TargetID:   postID,
+				Reason:     reason,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return uc.outboxRepo.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachmentMap, err := uc.attachmentRepo.GetByPostIDs(ctx, []uuid.UUID{post.ID})
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "failed to load post attachments", "post_id", post.ID, "error", err)
+	}
+	post.Attachments = attachmentMap[post.ID]
+
+	author, err := uc.userRepo.GetByID(ctx, post.UserID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, nil, err
+	}
+
+	return post, author, nil
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+type subscriptionUsecase struct {
+	subscriptionRepo SubscriptionRepository
+	threadRepo       ThreadRepository
+	postRepo         PostRepository
+	userRepo         UserRepository
+	categoryRepo     CategoryRepository
+	logger           *slog.Logger
+}
+
+func NewSubscriptionUsecase(sr SubscriptionRepository, tr ThreadRepository, pr PostRepository, ur UserRepository, cr CategoryRepository, logger *slog.Logger) SubscriptionUsecase {
+	return &subscriptionUsecase{
+		subscriptionRepo: sr,
+		threadRepo:       tr,
+		postRepo:         pr,
+		userRepo:         ur,
+		categoryRepo:     cr,
+		logger:           logger,
+	}
+}
+
+func (uc *subscriptionUsecase) FollowThread(ctx context.Context, userID uuid.UUID, thread *domain.Thread, post *domain.Post) {
+	if err := uc.subscriptionRepo.Subscribe(ctx, userID, thread.ID); err != nil {
+		uc.logger.ErrorContext(ctx, "failed to subscribe user to thread", "user_id", userID, "thread_id", thread.ID, "error", err)
+	}
+
+	pos := &domain.ThreadReadPosition{
+		UserID:     userID,
+		ThreadID:   thread.ID,
+		LastReadAt: thread.CreatedAt,
+		UpdatedAt:  time.Now(),
+	}
+	if post != nil {
+		pos.LastReadPostID = &post.ID
+		pos.LastReadAt = post.CreatedAt
+	}
+
+	if err := uc.subscriptionRepo.SaveReadPosition(ctx, pos); err != nil {
+		uc.logger.ErrorContext(ctx, "failed to save read position", "user_id", userID, "thread_id", thread.ID, "error", err)
+	}
+}
+
+func (uc *subscriptionUsecase) Subscribe(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadReadState, error) {
+	if _, err := uc.threadRepo.GetByID(ctx, threadID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.subscriptionRepo.Subscribe(ctx, userID, threadID); err != nil {
+		return nil, err
+	}
+
+	return uc.subscriptionRepo.GetReadState(ctx, userID, threadID)
+}
+
+func (uc *subscriptionUsecase) Unsubscribe(ctx context.Context, userID, threadID uuid.UUID) error {
+	return uc.subscriptionRepo.Unsubscribe(ctx, userID, threadID)
+}
+
+func (uc *subscriptionUsecase) GetByUserID(ctx context.Context, userID uuid.UUID, params PaginationParams) ([]*domain.ThreadReadState, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error) {
+	total, err := uc.subscriptionRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	states, err := uc.subscriptionRepo.GetByUserID(ctx, userID, params)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	if len(states) == 0 {
+		return states, nil, nil, total, nil
+	}
+
+	threadIDs := make([]uuid.UUID, len(states))
+	for i, s := range states {
+		threadIDs[i] = s.ThreadID
+	}
+
+	threadMap, err := uc.threadRepo.GetByIDs(ctx, threadIDs)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(threadMap))
+	catIDs := make([]uuid.UUID, 0, len(threadMap))
+	for _, s := range states {
+		s.Thread = threadMap[s.ThreadID]
+		if s.Thread != nil {
+			userIDs = append(userIDs, s.Thread.UserID)
+			catIDs = append(catIDs, s.Thread.CategoryID)
+		}
+	}
+
+	userMap, err := uc.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	catMap, err := uc.categoryRepo.GetByIDs(ctx, catIDs)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	return states, userMap, catMap, total, nil
+}
+
+func (uc *subscriptionUsecase) GetReadState(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadReadState, error) {
+	return uc.subscriptionRepo.GetReadState(ctx, userID, threadID)
+}
+
+// MarkRead records that the user has read up to and including postID. With
+// no postID the whole thread is marked read.
+func (uc *subscriptionUsecase) MarkRead(ctx context.Context, userID, threadID uuid.UUID, postID *uuid.UUID) (*domain.ThreadReadState, error) {
+	if _, err := uc.threadRepo.GetByID(ctx, threadID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pos := &domain.ThreadReadPosition{
+		UserID:     userID,
+		ThreadID:   threadID,
+		LastReadAt: now,
+		UpdatedAt:  now,
+	}
+
+	if postID != nil {
+		post, err := uc.postRepo.GetByID(ctx, *postID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.Invalid(domain.FieldError{Field: "post_id", Rule: "exists"})
+			}
+
+			return nil, err
+		}
+
+		if post.ThreadID != threadID {
+			return nil, domain.Invalid(domain.FieldError{Field: "post_id", Rule: "same_thread"})
+		}
+
+		pos.LastReadPostID = &post.ID
+		pos.LastReadAt = post.CreatedAt
+	}
+
+	if err := uc.subscriptionRepo.SaveReadPosition(ctx, pos); err != nil {
+		return nil, err
+	}
+
+	return uc.subscriptionRepo.GetReadState(ctx, userID, threadID)
+}
@@ -0,0 +1,237 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/service"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// discardMentions is a MentionRepository that stores nothing.
+type discardMentions struct{}
+
+func (discardMentions) ReplaceForThread(ctx context.Context, threadID, actorID uuid.UUID, userIDs []uuid.UUID) error {
+	return nil
+}
+
+func (discardMentions) ReplaceForPost(ctx context.Context, threadID, postID, actorID uuid.UUID, userIDs []uuid.UUID) error {
+	return nil
+}
+
+type subscriptionFixture struct {
+	users         usecase.UserRepository
+	categories    usecase.CategoryRepository
+	threads       usecase.ThreadRepository
+	posts         usecase.PostRepository
+	subscriptions usecase.SubscriptionUsecase
+	threadUC      usecase.ThreadUsecase
+	postUC        usecase.PostUsecase
+}
+
+func newSubscriptionFixture() *subscriptionFixture {
+	s := memory.NewStore()
+	tm := memory.NewTxManager(s)
+	tags := memory.NewMemoryTagRepo(s)
+	outbox := memory.NewMemoryOutboxRepo(s)
+	renderer := service.NewMarkdownRenderer()
+
+	f := &subscriptionFixture{
+		users:      memory.NewMemoryUserRepo(s),
+		categories: memory.NewMemoryCategoryRepo(s),
+		threads:    memory.NewMemoryThreadRepo(s),
+		posts:      memory.NewMemoryPostRepo(s),
+	}
+	f.subscriptions = usecase.NewSubscriptionUsecase(memory.NewMemorySubscriptionRepo(s), f.threads, f.posts, f.users, f.categories, discardLogger)
+	f.threadUC = usecase.NewThreadUsecase(tm, f.threads, f.categories, f.users, tags, discardMentions{}, nil, outbox, nil, f.subscriptions, renderer, discardLogger)
+	f.postUC = usecase.NewPostUsecase(tm, f.posts, f.threads, f.users, tags, discardMentions{}, nil, outbox, f.subscriptions, nil, renderer, discardLogger)
+
+	return f
+}
+
+func (f *subscriptionFixture) user(t *testing.T, name string) uuid.UUID {
+	t.Helper()
+
+	user := &domain.User{ID: uuid.New(), Username: name, Email: name + "@example.com", Role: "member", CreatedAt: time.Now()}
+	if err := f.users.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	return user.ID
+}
+
+// thread creates a thread by userID directly in the repository, so its
+// author is not subscribed.
+func (f *subscriptionFixture) thread(t *testing.T, userID uuid.UUID, at time.Time) *domain.Thread {
+	t.Helper()
+
+	thread := &domain.Thread{ID: uuid.New(), Title: "Thread", Slug: "thread", UserID: userID, CategoryID: uuid.New(), CreatedAt: at}
+	if err := f.threads.Create(context.Background(), thread); err != nil {
+		t.Fatalf("create thread: %v", err)
+	}
+
+	return thread
+}
+
+// post creates a post by userID directly in the repository.
+func (f *subscriptionFixture) post(t *testing.T, threadID, userID uuid.UUID, at time.Time) *domain.Post {
+	t.Helper()
+
+	post := &domain.Post{ID: uuid.New(), ThreadID: threadID, UserID: userID, Content: "Reply", CreatedAt: at}
+	if err := f.posts.Create(context.Background(), post); err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+
+	return post
+}
+
+func (f *subscriptionFixture) state(t *testing.T, userID, threadID uuid.UUID) *domain.ThreadReadState {
+	t.Helper()
+
+	state, err := f.subscriptions.GetReadState(context.Background(), userID, threadID)
+	if err != nil {
+		t.Fatalf("GetReadState: %v", err)
+	}
+
+	return state
+}
+
+func TestAuthorsFollowWhatTheyWrite(t *testing.T) {
+	ctx := context.Background()
+	f := newSubscriptionFixture()
+	alice, bob := f.user(t, "alice"), f.user(t, "bob")
+
+	category := &domain.Category{ID: uuid.New(), Name: "General", Slug: "general", CreatedAt: time.Now()}
+	if err := f.categories.Create(ctx, category); err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+
+	thread, _, _, err := f.threadUC.Create(ctx, "Hello", "First!", nil, alice, category.ID)
+	if err != nil {
+		t.Fatalf("create thread: %v", err)
+	}
+
+	state := f.state(t, alice, thread.ID)
+	if state.SubscribedAt == nil || state.LastReadAt == nil || !state.LastReadAt.Equal(thread.CreatedAt) || state.UnreadCount != 0 {
+		t.Fatalf("author's state = %+v, want subscribed and read up to the thread", state)
+	}
+
+	reply, err := f.postUC.Create(ctx, "Welcome", bob, thread.ID, nil)
+	if err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+
+	state = f.state(t, bob, thread.ID)
+	if state.SubscribedAt == nil || state.LastReadPostID == nil || *state.LastReadPostID != reply.ID || state.UnreadCount != 0 {
+		t.Errorf("replier's state = %+v, want subscribed and read up to their reply", state)
+	}
+
+	state = f.state(t, alice, thread.ID)
+	if state.UnreadCount != 1 || state.FirstUnreadPostID == nil || *state.FirstUnreadPostID != reply.ID {
+		t.Errorf("author's state after a reply = %+v, want the reply unread", state)
+	}
+
+	// Replying moves the replier's position to their own reply.
+	if _, err := f.postUC.Create(ctx, "Thanks", alice, thread.ID, &reply.ID); err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+
+	if state := f.state(t, alice, thread.ID); state.UnreadCount != 0 {
+		t.Errorf("author's state after replying = %+v, want nothing unread", state)
+	}
+
+	for _, userID := range []uuid.UUID{alice, bob} {
+		if _, _, _, total, err := f.subscriptions.GetByUserID(ctx, userID, usecase.PaginationParams{Limit: 10}); err != nil || total != 1 {
+			t.Errorf("GetByUserID = %d, %v; want the one thread", total, err)
+		}
+	}
+}
+
+func TestMarkReadNeverMovesBackwards(t *testing.T) {
+	ctx := context.Background()
+	f := newSubscriptionFixture()
+	alice, bob := f.user(t, "alice"), f.user(t, "bob")
+
+	start := time.Now().Add(-time.Hour)
+	thread := f.thread(t, bob, start)
+	first := f.post(t, thread.ID, bob, start.Add(time.Minute))
+	second := f.post(t, thread.ID, bob, start.Add(2*time.Minute))
+	third := f.post(t, thread.ID, bob, start.Add(3*time.Minute))
+
+	if _, err := f.subscriptions.Subscribe(ctx, alice, thread.ID); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	state, err := f.subscriptions.MarkRead(ctx, alice, thread.ID, &second.ID)
+	if err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+
+	if state.UnreadCount != 1 || *state.FirstUnreadPostID != third.ID {
+		t.Fatalf("after reading the second post: %+v, want only the third unread", state)
+	}
+
+	// A client reporting an older post, e.g. from a stale tab, does not mark
+	// newer posts unread again.
+	state, err = f.subscriptions.MarkRead(ctx, alice, thread.ID, &first.ID)
+	if err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+
+	if state.LastReadPostID == nil || *state.LastReadPostID != second.ID || state.UnreadCount != 1 {
+		t.Errorf("after reading the first post again: %+v, want the position kept at the second", state)
+	}
+
+	state, err = f.subscriptions.MarkRead(ctx, alice, thread.ID, nil)
+	if err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+
+	if state.UnreadCount != 0 || state.LastReadPostID != nil {
+		t.Errorf("after marking the thread read: %+v, want nothing unread", state)
+	}
+
+	other := f.thread(t, bob, start)
+	elsewhere := f.post(t, other.ID, bob, start.Add(4*time.Minute))
+	if _, err := f.subscriptions.MarkRead(ctx, alice, thread.ID, &elsewhere.ID); !errors.Is(err, domain.ErrInvalid) {
+		t.Errorf("MarkRead with another thread's post = %v, want %v", err, domain.ErrInvalid)
+	}
+}
+
+func TestOwnPostsAreNeverUnread(t *testing.T) {
+	ctx := context.Background()
+	f := newSubscriptionFixture()
+	alice, bob := f.user(t, "alice"), f.user(t, "bob")
+
+	start := time.Now().Add(-time.Hour)
+	busy := f.thread(t, alice, start)
+	f.post(t, busy.ID, alice, start.Add(time.Minute))
+	fromBob := f.post(t, busy.ID, bob, start.Add(2*time.Minute))
+	f.post(t, busy.ID, alice, start.Add(3*time.Minute))
+	f.post(t, busy.ID, bob, start.Add(4*time.Minute))
+
+	quiet := f.thread(t, alice, start)
+	f.post(t, quiet.ID, alice, start.Add(time.Minute))
+
+	for _, thread := range []*domain.Thread{busy, quiet} {
+		f.subscriptions.FollowThread(ctx, alice, thread, nil)
+	}
+
+	state := f.state(t, alice, busy.ID)
+	if state.UnreadCount != 2 || state.FirstUnreadPostID == nil || *state.FirstUnreadPostID != fromBob.ID {
+		t.Errorf("state = %+v, want both of bob's posts unread from the first", state)
+	}
+
+	if state := f.state(t, alice, quiet.ID); state.UnreadCount != 0 || state.FirstUnreadPostID != nil {
+		t.Errorf("state of a thread with only alice's posts = %+v, want nothing unread", state)
+	}
+
+	if state := f.state(t, bob, busy.ID); state.SubscribedAt != nil || state.UnreadCount != 2 {
+		t.Errorf("state for a reader who never opened the thread = %+v, want alice's two posts unread", state)
+	}
+}
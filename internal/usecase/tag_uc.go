@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+const (
+	defaultTagSearchLimit = 10
+	maxTagSearchLimit     = 50
+)
+
+type tagUsecase struct {
+	txManager   TxManager
+	tagRepo     TagRepository
+	auditLogger AuditLogger
+}
+
+func NewTagUsecase(tm TxManager, tgr TagRepository, al AuditLogger) TagUsecase {
+	return &tagUsecase{txManager: tm, tagRepo: tgr, auditLogger: al}
+}
+
+// Search backs tag autocomplete. An empty query lists the most used tags.
+func (uc *tagUsecase) Search(ctx context.Context, query string, limit int) ([]*domain.Tag, error) {
+	prefix := ""
+	if query != "" {
+		name, ok := domain.NormalizeTagName(query)
+		if !ok {
+			return []*domain.Tag{}, nil
+		}
+		prefix = name
+	}
+
+	if limit <= 0 {
+		limit = defaultTagSearchLimit
+	}
+
+	if limit > maxTagSearchLimit {
+		limit = maxTagSearchLimit
+	}
+
+	return uc.tagRepo.Search(ctx, prefix, limit)
+}
+
+func (uc *tagUsecase) Rename(ctx context.Context, actorID uuid.UUID, actorRole string, name, newName string, reason *string) (*domain.Tag, error) {
+	if actorRole != "admin" {
+		return nil, domain.ErrForbidden
+	}
+
+	newName, ok := domain.NormalizeTagName(newName)
+	if !ok {
+		return nil, domain.Invalid(domain.FieldError{Field: "name", Rule: "tag_name"})
+	}
+
+	tag, err := uc.getByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.Name == newName {
+		return tag, nil
+	}
+
+	details := fmt.Sprintf("%s -> %s", tag.Name, newName)
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.tagRepo.Rename(ctx, tag.ID, newName); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+			ActorID:    actorID,
+			ActorRole:  actorRole,
+			Action:     domain.AuditActionTagRename,
+			TargetType: domain.AuditTargetTag,
+			TargetID:   tag.ID,
+			Reason:     reason,
+			Details:    &details,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tag.Name = newName
+
+	return tag, nil
+}
+
+// Merge folds the source tag into the target tag and returns the target.
+func (uc *tagUsecase) Merge(ctx context.Context, actorID uuid.UUID, actorRole string, sourceName, targetName string, reason *string) (*domain.Tag, error) {
+	if actorRole != "admin" {
+		return nil, domain.ErrForbidden
+	}
+
+	source, err := uc.getByName(ctx, sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := uc.getByName(ctx, targetName)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.ID == target.ID {
+		return nil, domain.Invalid(domain.FieldError{Field: "into", Rule: "different"})
+	}
+
+	details := fmt.Sprintf("%s -> %s", source.Name, target.Name)
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.tagRepo.Merge(ctx, source.ID, target.ID); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+			ActorID:    actorID,
+			ActorRole:  actorRole,
+			Action:     domain.AuditActionTagMerge,
+			TargetType: domain.AuditTargetTag,
+			TargetID:   target.ID,
+			Reason:     reason,
+			Details:    &details,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+func (uc *tagUsecase) getByName(ctx context.Context, name string) (*domain.Tag, error) {
+	normalized, ok := domain.NormalizeTagName(name)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	return uc.tagRepo.GetByName(ctx, normalized)
+}
+
+// normalizeTagNames validates tag names supplied by a client and returns
+// them normalized and without duplicates.
+func normalizeTagNames(names []string, max int) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+
+	for _, name := range names {
+		n, ok := domain.NormalizeTagName(name)
+		if !ok {
+			return nil, domain.Invalid(domain.FieldError{Field: "tags", Rule: "tag_name"})
+		}
+
+		if seen[n] {
+			continue
+		}
+
+		seen[n] = true
+		normalized = append(normalized, n)
+	}
+
+	if len(normalized) > max {
+		return nil, domain.Invalid(domain.FieldError{Field: "tags", Rule: "max_items", Param: strconv.Itoa(max)})
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,206 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type tagFixture struct {
+	tags    usecase.TagRepository
+	threads usecase.ThreadRepository
+	posts   usecase.PostRepository
+	audit   *recordingAuditLogger
+	uc      usecase.TagUsecase
+}
+
+func newTagFixture() *tagFixture {
+	s := memory.NewStore()
+	f := &tagFixture{
+		tags:    memory.NewMemoryTagRepo(s),
+		threads: memory.NewMemoryThreadRepo(s),
+		posts:   memory.NewMemoryPostRepo(s),
+		audit:   &recordingAuditLogger{},
+	}
+	f.uc = usecase.NewTagUsecase(memory.NewTxManager(s), f.tags, f.audit)
+
+	return f
+}
+
+// thread creates a thread carrying the named tags from source.
+func (f *tagFixture) thread(t *testing.T, source string, names ...string) *domain.Thread {
+	t.Helper()
+
+	ctx := context.Background()
+	thread := &domain.Thread{ID: uuid.New(), Title: "Tagged", Slug: "tagged", UserID: uuid.New(), CategoryID: uuid.New(), CreatedAt: time.Now()}
+	if err := f.threads.Create(ctx, thread); err != nil {
+		t.Fatalf("create thread: %v", err)
+	}
+
+	f.tag(t, thread.ID, source, names...)
+
+	return thread
+}
+
+func (f *tagFixture) tag(t *testing.T, threadID uuid.UUID, source string, names ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+	tags, err := f.tags.UpsertByNames(ctx, names)
+	if err != nil {
+		t.Fatalf("UpsertByNames: %v", err)
+	}
+
+	ids := make([]uuid.UUID, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+
+	if err := f.tags.ReplaceThreadTags(ctx, threadID, source, ids); err != nil {
+		t.Fatalf("ReplaceThreadTags: %v", err)
+	}
+}
+
+// counts returns the thread count of every tag, as autocomplete shows them.
+func (f *tagFixture) counts(t *testing.T) map[string]int {
+	t.Helper()
+
+	tags, err := f.uc.Search(context.Background(), "", 50)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	counts := make(map[string]int, len(tags))
+	for _, tag := range tags {
+		counts[tag.Name] = tag.ThreadCount
+	}
+
+	return counts
+}
+
+func TestMergeTagCountsEveryThreadOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newTagFixture()
+
+	f.thread(t, domain.TagSourceExplicit, "go")
+	f.thread(t, domain.TagSourceContent, "golang")
+	both := f.thread(t, domain.TagSourceExplicit, "go")
+	f.tag(t, both.ID, domain.TagSourceContent, "golang")
+	f.thread(t, domain.TagSourceExplicit, "rust")
+
+	// A thread whose only golang tag is on a reply is listed under the tag
+	// but not counted for it.
+	replied := f.thread(t, domain.TagSourceExplicit)
+	reply := &domain.Post{ID: uuid.New(), ThreadID: replied.ID, UserID: uuid.New(), Content: "#golang", CreatedAt: time.Now()}
+	if err := f.posts.Create(ctx, reply); err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+
+	golang, err := f.tags.GetByName(ctx, "golang")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+
+	if err := f.tags.ReplacePostTags(ctx, reply.ID, []uuid.UUID{golang.ID}); err != nil {
+		t.Fatalf("ReplacePostTags: %v", err)
+	}
+
+	if got := f.counts(t); got["go"] != 2 || got["golang"] != 2 || got["rust"] != 1 {
+		t.Fatalf("counts before merging = %v, want go 2, golang 2, rust 1", got)
+	}
+
+	target, err := f.uc.Merge(ctx, uuid.New(), "admin", "GoLang", "go", nil)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	if target.Name != "go" {
+		t.Errorf("Merge returned %q, want the target tag", target.Name)
+	}
+
+	if got := f.counts(t); len(got) != 2 || got["go"] != 3 || got["rust"] != 1 {
+		t.Errorf("counts after merging = %v, want go 3 and rust 1", got)
+	}
+
+	if _, err := f.tags.GetByName(ctx, "golang"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetByName of the merged tag = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	tagged, err := f.tags.GetByThreadIDs(ctx, []uuid.UUID{both.ID})
+	if err != nil {
+		t.Fatalf("GetByThreadIDs: %v", err)
+	}
+
+	if tags := tagged[both.ID]; len(tags) != 1 || tags[0].ID != target.ID {
+		t.Errorf("thread tagged with both now has %d tags, want go once", len(tags))
+	}
+
+	if n, err := f.threads.CountByTagID(ctx, target.ID); err != nil || n != 4 {
+		t.Errorf("CountByTagID = %d, %v; want the 3 tagged threads and the one with a tagged reply", n, err)
+	}
+
+	if len(f.audit.entries) != 1 || f.audit.entries[0].Action != domain.AuditActionTagMerge || *f.audit.entries[0].Details != "golang -> go" {
+		t.Errorf("audit entries = %+v, want one golang -> go merge", f.audit.entries)
+	}
+}
+
+func TestMergeTagIntoItselfIsInvalid(t *testing.T) {
+	ctx := context.Background()
+	f := newTagFixture()
+	f.thread(t, domain.TagSourceExplicit, "go")
+
+	if _, err := f.uc.Merge(ctx, uuid.New(), "admin", "go", "Go", nil); !errors.Is(err, domain.ErrInvalid) {
+		t.Fatalf("Merge into itself = %v, want %v", err, domain.ErrInvalid)
+	}
+
+	if got := f.counts(t); got["go"] != 1 {
+		t.Errorf("counts = %v, want go kept with its thread", got)
+	}
+
+	if len(f.audit.entries) != 0 {
+		t.Errorf("recorded %d audit entries, want none", len(f.audit.entries))
+	}
+}
+
+func TestRenameTagOntoAnExistingTagConflicts(t *testing.T) {
+	ctx := context.Background()
+	f := newTagFixture()
+	f.thread(t, domain.TagSourceExplicit, "go")
+	f.thread(t, domain.TagSourceExplicit, "golang")
+
+	if _, err := f.uc.Rename(ctx, uuid.New(), "admin", "golang", "Go", nil); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("Rename onto an existing tag = %v, want %v", err, domain.ErrConflict)
+	}
+
+	if got := f.counts(t); len(got) != 2 || got["go"] != 1 || got["golang"] != 1 {
+		t.Errorf("counts = %v, want both tags unchanged", got)
+	}
+
+	if len(f.audit.entries) != 0 {
+		t.Errorf("recorded %d audit entries, want none", len(f.audit.entries))
+	}
+
+	// Renaming onto a free name keeps the tag's threads.
+	renamed, err := f.uc.Rename(ctx, uuid.New(), "admin", "golang", "Go-Lang", nil)
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	if renamed.Name != "go-lang" {
+		t.Errorf("renamed to %q, want the normalized name", renamed.Name)
+	}
+
+	if got := f.counts(t); len(got) != 2 || got["go"] != 1 || got["go-lang"] != 1 {
+		t.Errorf("counts after renaming = %v, want go 1 and go-lang 1", got)
+	}
+
+	if len(f.audit.entries) != 1 || f.audit.entries[0].Action != domain.AuditActionTagRename || *f.audit.entries[0].Details != "golang -> go-lang" {
+		t.Errorf("audit entries = %+v, want one golang -> go-lang rename", f.audit.entries)
+	}
+}
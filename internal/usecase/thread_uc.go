@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,28 +13,60 @@ import (
 )
 
 type threadUsecase struct {
-	threadRepo   ThreadRepository
-	categoryRepo CategoryRepository
-	userRepo     UserRepository
+	txManager      TxManager
+	threadRepo     ThreadRepository
+	categoryRepo   CategoryRepository
+	userRepo       UserRepository
+	auditLogger    AuditLogger
+	follower       ThreadFollower
+	renderer       ContentRenderer
+	tagRepo        TagRepository
+	attachmentRepo AttachmentRepository
+	outboxRepo     OutboxRepository
+	content        *contentProcessor
+	logger         *slog.Logger
 }
 
-func NewThreadUsecase(tr ThreadRepository, cr CategoryRepository, ur UserRepository) ThreadUsecase {
+func NewThreadUsecase(tm TxManager, tr ThreadRepository, cr CategoryRepository, ur UserRepository, tgr TagRepository, mr MentionRepository, atr AttachmentRepository, or OutboxRepository, al AuditLogger, f ThreadFollower, r ContentRenderer, logger *slog.Logger) ThreadUsecase {
 	return &threadUsecase{
-		threadRepo:   tr,
-		categoryRepo: cr,
-		userRepo:     ur,
+		txManager:      tm,
+		threadRepo:     tr,
+		categoryRepo:   cr,
+		userRepo:       ur,
+		auditLogger:    al,
+		follower:       f,
+		renderer:       r,
+		tagRepo:        tgr,
+		attachmentRepo: atr,
+		outboxRepo:     or,
+		content: &contentProcessor{
+			renderer:    r,
+			userRepo:    ur,
+			tagRepo:     tgr,
+			mentionRepo: mr,
+		},
+		logger: logger,
 	}
 }
 
-func (uc *threadUsecase) Create(ctx context.Context, title string, content string, userID uuid.UUID, categoryID uuid.UUID) (*domain.Thread, *domain.User, *domain.Category, error) {
-	if title == "" || content == "" {
-		return nil, nil, nil, domain.ErrInvalid
+func (uc *threadUsecase) Create(ctx context.Context, title string, content string, tags []string, userID uuid.UUID, categoryID uuid.UUID) (*domain.Thread, *domain.User, *domain.Category, error) {
+	if title == "" {
+		return nil, nil, nil, domain.Invalid(domain.FieldError{Field: "title", Rule: "required"})
+	}
+
+	if content == "" {
+		return nil, nil, nil, domain.Invalid(domain.FieldError{Field: "content", Rule: "required"})
+	}
+
+	tagNames, err := normalizeTagNames(tags, domain.MaxTagsPerThread)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	category, err := uc.categoryRepo.GetByID(ctx, categoryID)
 	if err != nil {
-		if err == domain.ErrNotFound {
-			return nil, nil, nil, domain.ErrInvalid
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, nil, domain.Invalid(domain.FieldError{Field: "category_id", Rule: "exists"})
 		}
 
 		return nil, nil, nil, err
@@ -40,40 +74,85 @@ func (uc *threadUsecase) Create(ctx context.Context, title string, content strin
 
 	user, err := uc.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		if err == domain.ErrNotFound {
+		if errors.Is(err, domain.ErrNotFound) {
 			return nil, nil, nil, domain.ErrInvalid
 		}
 
 		return nil, nil, nil, err
 	}
 
+	processed, err := uc.content.process(ctx, content)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	threadSlug := slug.Make(title)
 
 	thread := &domain.Thread{
-		ID:         uuid.New(),
-		Title:      title,
-		Slug:       threadSlug,
-		Content:    content,
-		UserID:     userID,
-		CategoryID: categoryID,
-		CreatedAt:  time.Now(),
-		VoteCount:  0,
+		ID:          uuid.New(),
+		Title:       title,
+		Slug:        threadSlug,
+		Content:     content,
+		ContentHTML: processed.HTML,
+		UserID:      userID,
+		CategoryID:  categoryID,
+		CreatedAt:   time.Now(),
+		VoteCount:   0,
+	}
+
+	created := &domain.ThreadCreatedEventData{
+		ID:         thread.ID,
+		Title:      thread.Title,
+		Slug:       thread.Slug,
+		UserID:     thread.UserID,
+		CategoryID: thread.CategoryID,
+		Mentioned:  processed.Mentioned,
+		CreatedAt:  thread.CreatedAt,
 	}
 
-	if err := uc.threadRepo.Create(ctx, thread); err != nil {
+	event, err := newOutboxEvent(domain.AggregateThread, thread.ID, domain.EventThreadCreated, created)
+	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.threadRepo.Create(ctx, thread); err != nil {
+			return err
+		}
+
+		if err := uc.content.saveThread(ctx, thread, processed); err != nil {
+			return err
+		}
+
+		if err := uc.saveExplicitTags(ctx, thread, tagNames); err != nil {
+			return err
+		}
+
+		return uc.outboxRepo.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	uc.attachTags(ctx, []*domain.Thread{thread})
+	uc.follower.FollowThread(ctx, userID, thread, nil)
+
 	return thread, user, category, nil
 }
 
-func (uc *threadUsecase) GetAll(ctx context.Context, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error) {
-	threads, err := uc.threadRepo.GetAll(ctx, params)
+func (uc *threadUsecase) GetAll(ctx context.Context, filter ThreadFilter, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error) {
+	tagNames, err := normalizeTagNames(filter.Tags, domain.MaxTagsPerThread)
 	if err != nil {
 		return nil, nil, nil, 0, err
 	}
+	filter.Tags = tagNames
 
-	total, err := uc.threadRepo.CountAll(ctx)
+	threads, err := uc.threadRepo.GetAll(ctx, filter, params)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	total, err := uc.threadRepo.CountAll(ctx, filter)
 	if err != nil {
 		return nil, nil, nil, 0, err
 	}
@@ -82,6 +161,17 @@ func (uc *threadUsecase) GetAll(ctx context.Context, params PaginationParams) ([
 		return []*domain.Thread{}, nil, nil, total, nil
 	}
 
+	uc.attachTags(ctx, threads)
+
+	userMap, catMap, err := uc.loadAuthorsAndCategories(ctx, threads)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	return threads, userMap, catMap, total, nil
+}
+
+func (uc *threadUsecase) loadAuthorsAndCategories(ctx context.Context, threads []*domain.Thread) (map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, error) {
 	userIDs := make([]uuid.UUID, 0)
 	catIDs := make([]uuid.UUID, 0)
 	for _, t := range threads {
@@ -91,15 +181,15 @@ func (uc *threadUsecase) GetAll(ctx context.Context, params PaginationParams) ([
 
 	userMap, err := uc.userRepo.GetByIDs(ctx, userIDs)
 	if err != nil {
-		return nil, nil, nil, 0, err
+		return nil, nil, err
 	}
 
 	catMap, err := uc.categoryRepo.GetByIDs(ctx, catIDs)
 	if err != nil {
-		return nil, nil, nil, 0, err
+		return nil, nil, err
 	}
 
-	return threads, userMap, catMap, total, nil
+	return userMap, catMap, nil
 }
 
 func (uc *threadUsecase) GetByID(ctx context.Context, id uuid.UUID) (*domain.Thread, *domain.User, *domain.Category, error) {
@@ -108,20 +198,24 @@ func (uc *threadUsecase) GetByID(ctx context.Context, id uuid.UUID) (*domain.Thr
 		return nil, nil, nil, err
 	}
 
+	ensureThreadHTML(ctx, uc.logger, uc.renderer, thread)
+	uc.attachTags(ctx, []*domain.Thread{thread})
+	uc.attachAttachments(ctx, thread)
+
 	user, err := uc.userRepo.GetByID(ctx, thread.UserID)
 	if err != nil {
-		log.Printf("[ERROR]: User not found for thread %s: %v", id, err)
+		uc.logger.ErrorContext(ctx, "failed to load thread author", "thread_id", id, "error", err)
 	}
 
 	cat, err := uc.categoryRepo.GetByID(ctx, thread.CategoryID)
 	if err != nil {
-		log.Printf("[ERROR]: Category not found for thread %s: %v", id, err)
+		uc.logger.ErrorContext(ctx, "failed to load thread category", "thread_id", id, "error", err)
 	}
 
 	return thread, user, cat, nil
 }
 
-func (uc *threadUsecase) Delete(ctx context.Context, threadID, userID uuid.UUID, role string) error {
+func (uc *threadUsecase) Delete(ctx context.Context, threadID, userID uuid.UUID, role string, reason *string) error {
 	thread, err := uc.threadRepo.GetByID(ctx, threadID)
 	if err != nil {
 		return err
@@ -134,7 +228,38 @@ func (uc *threadUsecase) Delete(ctx context.Context, threadID, userID uuid.UUID,
 		return domain.ErrForbidden
 	}
 
-	return uc.threadRepo.Delete(ctx, threadID)
+	deleted := &domain.ThreadDeletedEventData{
+		ID:        threadID,
+		DeletedBy: userID,
+		DeletedAt: time.Now(),
+	}
+
+	event, err := newOutboxEvent(domain.AggregateThread, threadID, domain.EventThreadDeleted, deleted)
+	if err != nil {
+		return err
+	}
+
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.threadRepo.Delete(ctx, threadID); err != nil {
+			return err
+		}
+
+		if !isOwner {
+			if err := recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+				ActorID:    userID,
+				ActorRole:  role,
+				Action:     domain.AuditActionThreadDelete,
+				TargetType: domain.AuditTargetThread,
+				TargetID:   threadID,
+				Reason:     reason,
+				Details:    &thread.Title,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return uc.outboxRepo.Add(ctx, event)
+	})
 }
 
 func (uc *threadUsecase) Update(ctx context.Context, threadID uuid.UUID, userID uuid.UUID, role string, params UpdateThreadParams) (*domain.Thread, *domain.User, *domain.Category, error) {
@@ -150,31 +275,321 @@ func (uc *threadUsecase) Update(ctx context.Context, threadID uuid.UUID, userID
 		return nil, nil, nil, domain.ErrForbidden
 	}
 
+	var tagNames []string
+	if params.Tags != nil {
+		tagNames, err = normalizeTagNames(*params.Tags, domain.MaxTagsPerThread)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	changed := make([]string, 0, 3)
+
 	if params.Title != nil {
 		thread.Title = *params.Title
 		thread.Slug = slug.Make(*params.Title)
+		changed = append(changed, "title")
 	}
 
+	var processed *processedContent
 	if params.Content != nil {
+		processed, err = uc.content.process(ctx, *params.Content)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
 		thread.Content = *params.Content
+		thread.ContentHTML = processed.HTML
+		changed = append(changed, "content")
 	}
 
 	now := time.Now()
 	thread.UpdatedAt = &now
 
-	if err := uc.threadRepo.Update(ctx, thread); err != nil {
+	if params.Tags != nil {
+		changed = append(changed, "tags")
+	}
+
+	event, err := newOutboxEvent(domain.AggregateThread, threadID, domain.EventThreadUpdated, domain.NewThreadEventData(thread))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.threadRepo.Update(ctx, thread); err != nil {
+			return err
+		}
+
+		if processed != nil {
+			if err := uc.content.saveThread(ctx, thread, processed); err != nil {
+				return err
+			}
+		}
+
+		if params.Tags != nil {
+			if err := uc.saveExplicitTags(ctx, thread, tagNames); err != nil {
+				return err
+			}
+		}
+
+		if !isOwner {
+			details := "changed: " + strings.Join(changed, ", ")
+			if err := recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+				ActorID:    userID,
+				ActorRole:  role,
+				Action:     domain.AuditActionThreadUpdate,
+				TargetType: domain.AuditTargetThread,
+				TargetID:   threadID,
+				Reason:     params.Reason,
+				Details:    &details,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return uc.outboxRepo.Add(ctx, event)
+	})
+	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	uc.attachTags(ctx, []*domain.Thread{thread})
+	uc.attachAttachments(ctx, thread)
+
 	user, err := uc.userRepo.GetByID(ctx, thread.UserID)
 	if err != nil {
-		log.Fatalf("[ERROR]: User not found for thread %s: %v", thread.ID, err)
+		return nil, nil, nil, err
 	}
 
 	cat, err := uc.categoryRepo.GetByID(ctx, thread.CategoryID)
 	if err != nil {
-		log.Fatalf("[ERROR]: Category not found for thread %s: %v", thread.ID, err)
+		return nil, nil, nil, err
 	}
 
 	return thread, user, cat, nil
 }
+
+func (uc *threadUsecase) GetDeleted(ctx context.Context, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error) {
+	threads, err := uc.threadRepo.GetDeleted(ctx, params)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	total, err := uc.threadRepo.CountDeleted(ctx)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	if len(threads) == 0 {
+		return []*domain.Thread{}, nil, nil, total, nil
+	}
+
+	uc.attachTags(ctx, threads)
+
+	userMap, catMap, err := uc.loadAuthorsAndCategories(ctx, threads)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	return threads, userMap, catMap, total, nil
+}
+
+func (uc *threadUsecase) Restore(ctx context.Context, threadID, userID uuid.UUID, role string, reason *string) (*domain.Thread, *domain.User, *domain.Category, error) {
+	if role != "admin" {
+		return nil, nil, nil, domain.ErrForbidden
+	}
+
+	restored := &domain.ThreadRestoredEventData{
+		ID:         threadID,
+		RestoredBy: userID,
+		RestoredAt: time.Now(),
+	}
+
+	event, err := newOutboxEvent(domain.AggregateThread, threadID, domain.EventThreadRestored, restored)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.threadRepo.Restore(ctx, threadID); err != nil {
+			return err
+		}
+
+		if err := recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+			ActorID:    userID,
+			ActorRole:  role,
+			Action:     domain.AuditActionThreadRestore,
+			TargetType: domain.AuditTargetThread,
+			TargetID:   threadID,
+			Reason:     reason,
+		}); err != nil {
+			return err
+		}
+
+		return uc.outboxRepo.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return uc.GetByID(ctx, threadID)
+}
+
+func (uc *threadUsecase) PurgeDeleted(ctx context.Context, retention time.Duration) (int, error) {
+	if retention <= 0 {
+		return 0, domain.ErrInvalid
+	}
+
+	return uc.threadRepo.PurgeDeletedBefore(ctx, time.Now().Add(-retention))
+}
+
+func (uc *threadUsecase) SetLocked(ctx context.Context, threadID, userID uuid.UUID, role string, locked bool, reason *string) (*domain.Thread, *domain.User, *domain.Category, error) {
+	if role != "admin" {
+		return nil, nil, nil, domain.ErrForbidden
+	}
+
+	thread, err := uc.threadRepo.GetByID(ctx, threadID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if thread.IsLocked != locked {
+		thread.IsLocked = locked
+
+		event, err := newOutboxEvent(domain.AggregateThread, threadID, domain.EventThreadLockChange, domain.NewThreadEventData(thread))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		action := domain.AuditActionThreadUnlock
+		if locked {
+			action = domain.AuditActionThreadLock
+		}
+
+		err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if err := uc.threadRepo.SetLocked(ctx, threadID, locked); err != nil {
+				return err
+			}
+
+			if err := recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+				ActorID:    userID,
+				ActorRole:  role,
+				Action:     action,
+				TargetType: domain.AuditTargetThread,
+				TargetID:   threadID,
+				Reason:     reason,
+			}); err != nil {
+				return err
+			}
+
+			return uc.outboxRepo.Add(ctx, event)
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return uc.GetByID(ctx, threadID)
+}
+
+func (uc *threadUsecase) GetByTag(ctx context.Context, tag string, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error) {
+	name, ok := domain.NormalizeTagName(tag)
+	if !ok {
+		return nil, nil, nil, 0, domain.Invalid(domain.FieldError{Field: "tag", Rule: "tag_name"})
+	}
+
+	t, err := uc.tagRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	threads, err := uc.threadRepo.GetByTagID(ctx, t.ID, params)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	total, err := uc.threadRepo.CountByTagID(ctx, t.ID)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	if len(threads) == 0 {
+		return []*domain.Thread{}, nil, nil, total, nil
+	}
+
+	uc.attachTags(ctx, threads)
+
+	userMap, catMap, err := uc.loadAuthorsAndCategories(ctx, threads)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	return threads, userMap, catMap, total, nil
+}
+
+func (uc *threadUsecase) GetMentioning(ctx context.Context, userID uuid.UUID, params PaginationParams) ([]*domain.Thread, map[uuid.UUID]*domain.User, map[uuid.UUID]*domain.Category, int, error) {
+	threads, err := uc.threadRepo.GetMentioning(ctx, userID, params)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	total, err := uc.threadRepo.CountMentioning(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	if len(threads) == 0 {
+		return []*domain.Thread{}, nil, nil, total, nil
+	}
+
+	uc.attachTags(ctx, threads)
+
+	userMap, catMap, err := uc.loadAuthorsAndCategories(ctx, threads)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	return threads, userMap, catMap, total, nil
+}
+
+// saveExplicitTags stores the tags chosen by the author. Like the content
+// references, it belongs in the transaction that saves the thread.
+func (uc *threadUsecase) saveExplicitTags(ctx context.Context, thread *domain.Thread, names []string) error {
+	tagIDs, err := uc.content.tagIDs(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	return uc.tagRepo.ReplaceThreadTags(ctx, thread.ID, domain.TagSourceExplicit, tagIDs)
+}
+
+// attachTags fills in Tags on each thread. Tags are supplementary, so a
+// failure leaves them empty instead of failing the request.
+func (uc *threadUsecase) attachTags(ctx context.Context, threads []*domain.Thread) {
+	threadIDs := make([]uuid.UUID, len(threads))
+	for i, t := range threads {
+		threadIDs[i] = t.ID
+	}
+
+	tagMap, err := uc.tagRepo.GetByThreadIDs(ctx, threadIDs)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "failed to load thread tags", "error", err)
+
+		return
+	}
+
+	for _, t := range threads {
+		t.Tags = tagMap[t.ID]
+	}
+}
+
+func (uc *threadUsecase) attachAttachments(ctx context.Context, thread *domain.Thread) {
+	attachments, err := uc.attachmentRepo.GetByThreadID(ctx, thread.ID)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "failed to load thread attachments", "thread_id", thread.ID, "error", err)
+
+		return
+	}
+
+	thread.Attachments = attachments
+}
@@ -0,0 +1,320 @@
+// Command gen writes the traced usecase decorators. It reads the usecase
+// interfaces file and emits, for every interface whose name ends in
+// "Usecase", a type recording a span around each method taking a context.
+//
+// Run it through go generate in internal/usecase/traced.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	usecasePath = "github.com/srgjo27/agora/internal/usecase"
+	tracePath   = "go.opentelemetry.io/otel/trace"
+)
+
+// reserved are the identifiers the generated methods declare themselves.
+var reserved = map[string]bool{"d": true, "span": true, "err": true}
+
+func main() {
+	in := flag.String("in", "../interfaces.go", "usecase interfaces file")
+	out := flag.String("out", "usecases_gen.go", "output file")
+	flag.Parse()
+
+	src, err := generate(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	fset       *token.FileSet
+	interfaces map[string]*ast.InterfaceType
+	imports    map[string]string
+	used       map[string]bool
+	buf        bytes.Buffer
+}
+
+func generate(path string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		fset:       fset,
+		interfaces: map[string]*ast.InterfaceType{},
+		imports:    map[string]string{},
+		used:       map[string]bool{"usecase": true, "trace": true},
+	}
+
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		g.imports[name] = importPath
+	}
+
+	var names []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			iface, ok := ts.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+
+			g.interfaces[ts.Name.Name] = iface
+			if strings.HasSuffix(ts.Name.Name, "Usecase") {
+				names = append(names, ts.Name.Name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if err := g.decorator(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by gen; DO NOT EDIT.\n\npackage traced\n\nimport (\n")
+
+	var std, thirdParty []string
+	for name := range g.used {
+		importPath := g.imports[name]
+		switch name {
+		case "usecase":
+			importPath = usecasePath
+		case "trace":
+			importPath = tracePath
+		}
+
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			thirdParty = append(thirdParty, importPath)
+		} else {
+			std = append(std, importPath)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(thirdParty)
+
+	for _, importPath := range std {
+		fmt.Fprintf(&src, "\t%q\n", importPath)
+	}
+	src.WriteString("\n")
+	for _, importPath := range thirdParty {
+		fmt.Fprintf(&src, "\t%q\n", importPath)
+	}
+	src.WriteString(")\n")
+	src.Write(g.buf.Bytes())
+
+	return format.Source(src.Bytes())
+}
+
+// methods lists the methods of the named interface, including those of the
+// interfaces it embeds.
+func (g *generator) methods(name string) ([]*ast.Field, error) {
+	iface, ok := g.interfaces[name]
+	if !ok {
+		return nil, fmt.Errorf("interface %s not found", name)
+	}
+
+	var methods []*ast.Field
+	for _, field := range iface.Methods.List {
+		if len(field.Names) > 0 {
+			methods = append(methods, field)
+			continue
+		}
+
+		ident, ok := field.Type.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("%s embeds an interface from another package", name)
+		}
+
+		embedded, err := g.methods(ident.Name)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, embedded...)
+	}
+
+	return methods, nil
+}
+
+func (g *generator) decorator(name string) error {
+	methods, err := g.methods(name)
+	if err != nil {
+		return err
+	}
+
+	typeName := "traced" + name
+	fmt.Fprintf(&g.buf, "\ntype %s struct {\n\tnext usecase.%s\n\ttracer trace.Tracer\n}\n", typeName, name)
+	fmt.Fprintf(&g.buf, "\nfunc NewTraced%s(next usecase.%s, tp trace.TracerProvider) usecase.%s {\n", name, name, name)
+	fmt.Fprintf(&g.buf, "\treturn &%s{next: next, tracer: tp.Tracer(tracerName)}\n}\n", typeName)
+
+	for _, method := range methods {
+		if err := g.method(name, typeName, method); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) method(iface, typeName string, method *ast.Field) error {
+	name := method.Names[0].Name
+	fn := method.Type.(*ast.FuncType)
+
+	var params, args []string
+	for i, field := range fn.Params.List {
+		typ := g.expr(field.Type)
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("p%d", i))}
+		}
+
+		for _, n := range names {
+			if reserved[n.Name] {
+				return fmt.Errorf("%s.%s: parameter name %q is reserved", iface, name, n.Name)
+			}
+
+			params = append(params, n.Name+" "+typ)
+			arg := n.Name
+			if _, ok := field.Type.(*ast.Ellipsis); ok {
+				arg += "..."
+			}
+			args = append(args, arg)
+		}
+	}
+
+	var results []string
+	returnsErr := false
+	if fn.Results != nil {
+		var types []string
+		for _, field := range fn.Results.List {
+			for range max(len(field.Names), 1) {
+				types = append(types, g.expr(field.Type))
+			}
+		}
+
+		for i, typ := range types {
+			if i == len(types)-1 && typ == "error" {
+				results = append(results, "err error")
+				returnsErr = true
+				continue
+			}
+			results = append(results, fmt.Sprintf("r%d %s", i, typ))
+		}
+	}
+
+	traced := len(fn.Params.List) > 0 && g.expr(fn.Params.List[0].Type) == "context.Context"
+	if traced && len(fn.Params.List[0].Names) > 0 {
+		// Spans start from the first argument, so it must be named ctx for
+		// the child context to reach next.
+		if first := fn.Params.List[0].Names[0].Name; first != "ctx" {
+			return fmt.Errorf("%s.%s: context parameter must be named ctx, not %q", iface, name, first)
+		}
+	}
+
+	fmt.Fprintf(&g.buf, "\nfunc (d *%s) %s(%s)", typeName, name, strings.Join(params, ", "))
+	if len(results) > 0 {
+		fmt.Fprintf(&g.buf, " (%s)", strings.Join(results, ", "))
+	}
+	g.buf.WriteString(" {\n")
+
+	if traced {
+		fmt.Fprintf(&g.buf, "\tctx, span := d.tracer.Start(ctx, %q)\n", iface+"."+name)
+		if returnsErr {
+			g.buf.WriteString("\tdefer func() { endSpan(span, err) }()\n\n")
+		} else {
+			g.buf.WriteString("\tdefer span.End()\n\n")
+		}
+	}
+
+	call := fmt.Sprintf("d.next.%s(%s)", name, strings.Join(args, ", "))
+	if len(results) > 0 {
+		fmt.Fprintf(&g.buf, "\treturn %s\n}\n", call)
+	} else {
+		fmt.Fprintf(&g.buf, "\t%s\n}\n", call)
+	}
+
+	return nil
+}
+
+// expr prints a type as seen from package traced: identifiers declared in
+// the usecase package are qualified and the imports they need are recorded.
+func (g *generator) expr(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return "usecase." + t.Name
+		}
+
+		return t.Name
+	case *ast.SelectorExpr:
+		pkg := t.X.(*ast.Ident).Name
+		g.used[pkg] = true
+
+		return pkg + "." + t.Sel.Name
+	case *ast.StarExpr:
+		return "*" + g.expr(t.X)
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return "[" + g.expr(t.Len) + "]" + g.expr(t.Elt)
+		}
+
+		return "[]" + g.expr(t.Elt)
+	case *ast.MapType:
+		return "map[" + g.expr(t.Key) + "]" + g.expr(t.Value)
+	case *ast.Ellipsis:
+		return "..." + g.expr(t.Elt)
+	case *ast.ChanType:
+		switch t.Dir {
+		case ast.SEND:
+			return "chan<- " + g.expr(t.Value)
+		case ast.RECV:
+			return "<-chan " + g.expr(t.Value)
+		default:
+			return "chan " + g.expr(t.Value)
+		}
+	case *ast.InterfaceType:
+		if len(t.Methods.List) == 0 {
+			return "interface{}"
+		}
+	case *ast.BasicLit:
+		return t.Value
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, g.fset, e); err != nil {
+		log.Fatal(err)
+	}
+	log.Fatalf("unsupported type %s", buf.String())
+
+	return ""
+}
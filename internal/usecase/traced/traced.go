@@ -0,0 +1,36 @@
+// Package traced decorates usecases with OpenTelemetry spans, one per method
+// call, named after the interface and method, e.g. "ThreadUsecase.GetAll".
+// The decorators are generated from usecase/interfaces.go; run go generate
+// after changing a usecase interface.
+//
+// Domain errors such as not found or invalid input are expected outcomes,
+// so they are recorded as an attribute and leave the span status unset.
+// Any other error marks the span as failed.
+package traced
+
+//go:generate go run ./gen -in ../interfaces.go -out usecases_gen.go
+
+import (
+	"errors"
+
+	"github.com/srgjo27/agora/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/srgjo27/agora/internal/usecase"
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		var domainErr *domain.Error
+		if errors.As(err, &domainErr) {
+			span.SetAttributes(attribute.String("agora.error_code", string(domainErr.Code)))
+		} else {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+
+	span.End()
+}
@@ -0,0 +1,693 @@
+// Code generated by gen; DO NOT EDIT.
+
+package traced
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/usecase"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedUserUsecase struct {
+	next   usecase.UserUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedUserUsecase(next usecase.UserUsecase, tp trace.TracerProvider) usecase.UserUsecase {
+	return &tracedUserUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedUserUsecase) Register(ctx context.Context, username string, email string, password string) (r0 *domain.User, err error) {
+	ctx, span := d.tracer.Start(ctx, "UserUsecase.Register")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Register(ctx, username, email, password)
+}
+
+func (d *tracedUserUsecase) GetUserByID(ctx context.Context, id uuid.UUID) (r0 *domain.User, err error) {
+	ctx, span := d.tracer.Start(ctx, "UserUsecase.GetUserByID")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetUserByID(ctx, id)
+}
+
+func (d *tracedUserUsecase) Login(ctx context.Context, email string, password string) (r0 string, r1 string, err error) {
+	ctx, span := d.tracer.Start(ctx, "UserUsecase.Login")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Login(ctx, email, password)
+}
+
+func (d *tracedUserUsecase) Refresh(ctx context.Context, refreshToken string) (r0 string, err error) {
+	ctx, span := d.tracer.Start(ctx, "UserUsecase.Refresh")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Refresh(ctx, refreshToken)
+}
+
+func (d *tracedUserUsecase) GetUsers(ctx context.Context) (r0 []*domain.User, err error) {
+	ctx, span := d.tracer.Start(ctx, "UserUsecase.GetUsers")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetUsers(ctx)
+}
+
+func (d *tracedUserUsecase) UpdateRole(ctx context.Context, actorID uuid.UUID, actorRole string, targetID uuid.UUID, role string, reason *string) (r0 *domain.User, err error) {
+	ctx, span := d.tracer.Start(ctx, "UserUsecase.UpdateRole")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.UpdateRole(ctx, actorID, actorRole, targetID, role, reason)
+}
+
+type tracedCategoryUsecase struct {
+	next   usecase.CategoryUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedCategoryUsecase(next usecase.CategoryUsecase, tp trace.TracerProvider) usecase.CategoryUsecase {
+	return &tracedCategoryUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedCategoryUsecase) Create(ctx context.Context, actorID uuid.UUID, actorRole string, name string, description *string) (r0 *domain.Category, err error) {
+	ctx, span := d.tracer.Start(ctx, "CategoryUsecase.Create")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Create(ctx, actorID, actorRole, name, description)
+}
+
+func (d *tracedCategoryUsecase) GetAll(ctx context.Context) (r0 []*domain.Category, err error) {
+	ctx, span := d.tracer.Start(ctx, "CategoryUsecase.GetAll")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetAll(ctx)
+}
+
+func (d *tracedCategoryUsecase) Follow(ctx context.Context, userID uuid.UUID, categoryID uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "CategoryUsecase.Follow")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Follow(ctx, userID, categoryID)
+}
+
+func (d *tracedCategoryUsecase) Unfollow(ctx context.Context, userID uuid.UUID, categoryID uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "CategoryUsecase.Unfollow")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Unfollow(ctx, userID, categoryID)
+}
+
+func (d *tracedCategoryUsecase) GetFollowed(ctx context.Context, userID uuid.UUID) (r0 []*domain.Category, err error) {
+	ctx, span := d.tracer.Start(ctx, "CategoryUsecase.GetFollowed")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetFollowed(ctx, userID)
+}
+
+type tracedThreadUsecase struct {
+	next   usecase.ThreadUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedThreadUsecase(next usecase.ThreadUsecase, tp trace.TracerProvider) usecase.ThreadUsecase {
+	return &tracedThreadUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedThreadUsecase) Create(ctx context.Context, title string, content string, tags []string, userID uuid.UUID, categoryID uuid.UUID) (r0 *domain.Thread, r1 *domain.User, r2 *domain.Category, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.Create")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Create(ctx, title, content, tags, userID, categoryID)
+}
+
+func (d *tracedThreadUsecase) GetAll(ctx context.Context, filter usecase.ThreadFilter, params usecase.PaginationParams) (r0 []*domain.Thread, r1 map[uuid.UUID]*domain.User, r2 map[uuid.UUID]*domain.Category, r3 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.GetAll")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetAll(ctx, filter, params)
+}
+
+func (d *tracedThreadUsecase) GetByID(ctx context.Context, id uuid.UUID) (r0 *domain.Thread, r1 *domain.User, r2 *domain.Category, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.GetByID")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetByID(ctx, id)
+}
+
+func (d *tracedThreadUsecase) Delete(ctx context.Context, threadID uuid.UUID, userID uuid.UUID, role string, reason *string) (err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.Delete")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Delete(ctx, threadID, userID, role, reason)
+}
+
+func (d *tracedThreadUsecase) Update(ctx context.Context, threadID uuid.UUID, userID uuid.UUID, role string, params usecase.UpdateThreadParams) (r0 *domain.Thread, r1 *domain.User, r2 *domain.Category, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.Update")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Update(ctx, threadID, userID, role, params)
+}
+
+func (d *tracedThreadUsecase) GetDeleted(ctx context.Context, params usecase.PaginationParams) (r0 []*domain.Thread, r1 map[uuid.UUID]*domain.User, r2 map[uuid.UUID]*domain.Category, r3 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.GetDeleted")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetDeleted(ctx, params)
+}
+
+func (d *tracedThreadUsecase) Restore(ctx context.Context, threadID uuid.UUID, userID uuid.UUID, role string, reason *string) (r0 *domain.Thread, r1 *domain.User, r2 *domain.Category, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.Restore")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Restore(ctx, threadID, userID, role, reason)
+}
+
+func (d *tracedThreadUsecase) PurgeDeleted(ctx context.Context, retention time.Duration) (r0 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.PurgeDeleted")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.PurgeDeleted(ctx, retention)
+}
+
+func (d *tracedThreadUsecase) SetLocked(ctx context.Context, threadID uuid.UUID, userID uuid.UUID, role string, locked bool, reason *string) (r0 *domain.Thread, r1 *domain.User, r2 *domain.Category, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.SetLocked")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.SetLocked(ctx, threadID, userID, role, locked, reason)
+}
+
+func (d *tracedThreadUsecase) GetByTag(ctx context.Context, tag string, params usecase.PaginationParams) (r0 []*domain.Thread, r1 map[uuid.UUID]*domain.User, r2 map[uuid.UUID]*domain.Category, r3 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.GetByTag")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetByTag(ctx, tag, params)
+}
+
+func (d *tracedThreadUsecase) GetMentioning(ctx context.Context, userID uuid.UUID, params usecase.PaginationParams) (r0 []*domain.Thread, r1 map[uuid.UUID]*domain.User, r2 map[uuid.UUID]*domain.Category, r3 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "ThreadUsecase.GetMentioning")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetMentioning(ctx, userID, params)
+}
+
+type tracedSubscriptionUsecase struct {
+	next   usecase.SubscriptionUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedSubscriptionUsecase(next usecase.SubscriptionUsecase, tp trace.TracerProvider) usecase.SubscriptionUsecase {
+	return &tracedSubscriptionUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedSubscriptionUsecase) FollowThread(ctx context.Context, userID uuid.UUID, thread *domain.Thread, post *domain.Post) {
+	ctx, span := d.tracer.Start(ctx, "SubscriptionUsecase.FollowThread")
+	defer span.End()
+
+	d.next.FollowThread(ctx, userID, thread, post)
+}
+
+func (d *tracedSubscriptionUsecase) Subscribe(ctx context.Context, userID uuid.UUID, threadID uuid.UUID) (r0 *domain.ThreadReadState, err error) {
+	ctx, span := d.tracer.Start(ctx, "SubscriptionUsecase.Subscribe")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Subscribe(ctx, userID, threadID)
+}
+
+func (d *tracedSubscriptionUsecase) Unsubscribe(ctx context.Context, userID uuid.UUID, threadID uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "SubscriptionUsecase.Unsubscribe")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Unsubscribe(ctx, userID, threadID)
+}
+
+func (d *tracedSubscriptionUsecase) GetByUserID(ctx context.Context, userID uuid.UUID, params usecase.PaginationParams) (r0 []*domain.ThreadReadState, r1 map[uuid.UUID]*domain.User, r2 map[uuid.UUID]*domain.Category, r3 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "SubscriptionUsecase.GetByUserID")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetByUserID(ctx, userID, params)
+}
+
+func (d *tracedSubscriptionUsecase) GetReadState(ctx context.Context, userID uuid.UUID, threadID uuid.UUID) (r0 *domain.ThreadReadState, err error) {
+	ctx, span := d.tracer.Start(ctx, "SubscriptionUsecase.GetReadState")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetReadState(ctx, userID, threadID)
+}
+
+func (d *tracedSubscriptionUsecase) MarkRead(ctx context.Context, userID uuid.UUID, threadID uuid.UUID, postID *uuid.UUID) (r0 *domain.ThreadReadState, err error) {
+	ctx, span := d.tracer.Start(ctx, "SubscriptionUsecase.MarkRead")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.MarkRead(ctx, userID, threadID, postID)
+}
+
+type tracedBookmarkUsecase struct {
+	next   usecase.BookmarkUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedBookmarkUsecase(next usecase.BookmarkUsecase, tp trace.TracerProvider) usecase.BookmarkUsecase {
+	return &tracedBookmarkUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedBookmarkUsecase) BookmarkThread(ctx context.Context, userID uuid.UUID, threadID uuid.UUID, params usecase.SaveBookmarkParams) (r0 *domain.Bookmark, err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.BookmarkThread")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.BookmarkThread(ctx, userID, threadID, params)
+}
+
+func (d *tracedBookmarkUsecase) BookmarkPost(ctx context.Context, userID uuid.UUID, postID uuid.UUID, params usecase.SaveBookmarkParams) (r0 *domain.Bookmark, err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.BookmarkPost")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.BookmarkPost(ctx, userID, postID, params)
+}
+
+func (d *tracedBookmarkUsecase) RemoveThreadBookmark(ctx context.Context, userID uuid.UUID, threadID uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.RemoveThreadBookmark")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.RemoveThreadBookmark(ctx, userID, threadID)
+}
+
+func (d *tracedBookmarkUsecase) RemovePostBookmark(ctx context.Context, userID uuid.UUID, postID uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.RemovePostBookmark")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.RemovePostBookmark(ctx, userID, postID)
+}
+
+func (d *tracedBookmarkUsecase) GetByUserID(ctx context.Context, userID uuid.UUID, filter usecase.BookmarkFilter, params usecase.PaginationParams) (r0 []*domain.Bookmark, r1 map[uuid.UUID]*domain.User, r2 map[uuid.UUID]*domain.Category, r3 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.GetByUserID")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetByUserID(ctx, userID, filter, params)
+}
+
+func (d *tracedBookmarkUsecase) GetBookmarkedThreadIDs(ctx context.Context, userID uuid.UUID, threadIDs []uuid.UUID) (r0 map[uuid.UUID]bool, err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.GetBookmarkedThreadIDs")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetBookmarkedThreadIDs(ctx, userID, threadIDs)
+}
+
+func (d *tracedBookmarkUsecase) GetBookmarkedPostIDs(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (r0 map[uuid.UUID]bool, err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.GetBookmarkedPostIDs")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetBookmarkedPostIDs(ctx, userID, postIDs)
+}
+
+func (d *tracedBookmarkUsecase) GetFolders(ctx context.Context, userID uuid.UUID) (r0 []*domain.BookmarkFolder, err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.GetFolders")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetFolders(ctx, userID)
+}
+
+func (d *tracedBookmarkUsecase) CreateFolder(ctx context.Context, userID uuid.UUID, name string) (r0 *domain.BookmarkFolder, err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.CreateFolder")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.CreateFolder(ctx, userID, name)
+}
+
+func (d *tracedBookmarkUsecase) RenameFolder(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, name string) (r0 *domain.BookmarkFolder, err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.RenameFolder")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.RenameFolder(ctx, userID, folderID, name)
+}
+
+func (d *tracedBookmarkUsecase) DeleteFolder(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "BookmarkUsecase.DeleteFolder")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.DeleteFolder(ctx, userID, folderID)
+}
+
+type tracedDigestUsecase struct {
+	next   usecase.DigestUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedDigestUsecase(next usecase.DigestUsecase, tp trace.TracerProvider) usecase.DigestUsecase {
+	return &tracedDigestUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedDigestUsecase) GetPreferences(ctx context.Context, userID uuid.UUID) (r0 *domain.DigestPreferences, err error) {
+	ctx, span := d.tracer.Start(ctx, "DigestUsecase.GetPreferences")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetPreferences(ctx, userID)
+}
+
+func (d *tracedDigestUsecase) UpdateFrequency(ctx context.Context, userID uuid.UUID, frequency string) (r0 *domain.DigestPreferences, err error) {
+	ctx, span := d.tracer.Start(ctx, "DigestUsecase.UpdateFrequency")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.UpdateFrequency(ctx, userID, frequency)
+}
+
+func (d *tracedDigestUsecase) Unsubscribe(ctx context.Context, token string) (err error) {
+	ctx, span := d.tracer.Start(ctx, "DigestUsecase.Unsubscribe")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Unsubscribe(ctx, token)
+}
+
+func (d *tracedDigestUsecase) SendDue(ctx context.Context) (r0 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "DigestUsecase.SendDue")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.SendDue(ctx)
+}
+
+type tracedWebhookUsecase struct {
+	next   usecase.WebhookUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedWebhookUsecase(next usecase.WebhookUsecase, tp trace.TracerProvider) usecase.WebhookUsecase {
+	return &tracedWebhookUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedWebhookUsecase) Dispatch(ctx context.Context, eventID uuid.UUID, eventType string, data interface{}) (err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.Dispatch")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Dispatch(ctx, eventID, eventType, data)
+}
+
+func (d *tracedWebhookUsecase) Create(ctx context.Context, actorID uuid.UUID, actorRole string, params usecase.CreateWebhookParams) (r0 *domain.Webhook, err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.Create")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Create(ctx, actorID, actorRole, params)
+}
+
+func (d *tracedWebhookUsecase) GetAll(ctx context.Context) (r0 []*domain.Webhook, err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.GetAll")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetAll(ctx)
+}
+
+func (d *tracedWebhookUsecase) GetByID(ctx context.Context, id uuid.UUID) (r0 *domain.Webhook, err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.GetByID")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetByID(ctx, id)
+}
+
+func (d *tracedWebhookUsecase) Update(ctx context.Context, actorID uuid.UUID, actorRole string, id uuid.UUID, params usecase.UpdateWebhookParams) (r0 *domain.Webhook, err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.Update")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Update(ctx, actorID, actorRole, id, params)
+}
+
+func (d *tracedWebhookUsecase) Delete(ctx context.Context, actorID uuid.UUID, actorRole string, id uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.Delete")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Delete(ctx, actorID, actorRole, id)
+}
+
+func (d *tracedWebhookUsecase) GetDeliveries(ctx context.Context, webhookID uuid.UUID, params usecase.PaginationParams) (r0 []*domain.WebhookDelivery, r1 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.GetDeliveries")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetDeliveries(ctx, webhookID, params)
+}
+
+func (d *tracedWebhookUsecase) Redeliver(ctx context.Context, webhookID uuid.UUID, deliveryID uuid.UUID) (r0 *domain.WebhookDelivery, err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.Redeliver")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Redeliver(ctx, webhookID, deliveryID)
+}
+
+func (d *tracedWebhookUsecase) DeliverDue(ctx context.Context) (r0 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.DeliverDue")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.DeliverDue(ctx)
+}
+
+func (d *tracedWebhookUsecase) PurgeDeliveries(ctx context.Context, retention time.Duration) (r0 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "WebhookUsecase.PurgeDeliveries")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.PurgeDeliveries(ctx, retention)
+}
+
+type tracedOutboxUsecase struct {
+	next   usecase.OutboxUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedOutboxUsecase(next usecase.OutboxUsecase, tp trace.TracerProvider) usecase.OutboxUsecase {
+	return &tracedOutboxUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedOutboxUsecase) Relay(ctx context.Context) (r0 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "OutboxUsecase.Relay")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Relay(ctx)
+}
+
+func (d *tracedOutboxUsecase) Purge(ctx context.Context, retention time.Duration) (r0 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "OutboxUsecase.Purge")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Purge(ctx, retention)
+}
+
+type tracedAttachmentUsecase struct {
+	next   usecase.AttachmentUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedAttachmentUsecase(next usecase.AttachmentUsecase, tp trace.TracerProvider) usecase.AttachmentUsecase {
+	return &tracedAttachmentUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedAttachmentUsecase) UploadToThread(ctx context.Context, threadID uuid.UUID, userID uuid.UUID, role string, params usecase.UploadAttachmentParams) (r0 *domain.Attachment, err error) {
+	ctx, span := d.tracer.Start(ctx, "AttachmentUsecase.UploadToThread")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.UploadToThread(ctx, threadID, userID, role, params)
+}
+
+func (d *tracedAttachmentUsecase) UploadToPost(ctx context.Context, postID uuid.UUID, userID uuid.UUID, role string, params usecase.UploadAttachmentParams) (r0 *domain.Attachment, err error) {
+	ctx, span := d.tracer.Start(ctx, "AttachmentUsecase.UploadToPost")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.UploadToPost(ctx, postID, userID, role, params)
+}
+
+func (d *tracedAttachmentUsecase) Open(ctx context.Context, id uuid.UUID) (r0 *domain.Attachment, r1 io.ReadCloser, err error) {
+	ctx, span := d.tracer.Start(ctx, "AttachmentUsecase.Open")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Open(ctx, id)
+}
+
+func (d *tracedAttachmentUsecase) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID, role string) (err error) {
+	ctx, span := d.tracer.Start(ctx, "AttachmentUsecase.Delete")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Delete(ctx, id, userID, role)
+}
+
+func (d *tracedAttachmentUsecase) PurgeOrphaned(ctx context.Context) (r0 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "AttachmentUsecase.PurgeOrphaned")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.PurgeOrphaned(ctx)
+}
+
+type tracedTagUsecase struct {
+	next   usecase.TagUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedTagUsecase(next usecase.TagUsecase, tp trace.TracerProvider) usecase.TagUsecase {
+	return &tracedTagUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedTagUsecase) Search(ctx context.Context, query string, limit int) (r0 []*domain.Tag, err error) {
+	ctx, span := d.tracer.Start(ctx, "TagUsecase.Search")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Search(ctx, query, limit)
+}
+
+func (d *tracedTagUsecase) Rename(ctx context.Context, actorID uuid.UUID, actorRole string, name string, newName string, reason *string) (r0 *domain.Tag, err error) {
+	ctx, span := d.tracer.Start(ctx, "TagUsecase.Rename")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Rename(ctx, actorID, actorRole, name, newName, reason)
+}
+
+func (d *tracedTagUsecase) Merge(ctx context.Context, actorID uuid.UUID, actorRole string, sourceName string, targetName string, reason *string) (r0 *domain.Tag, err error) {
+	ctx, span := d.tracer.Start(ctx, "TagUsecase.Merge")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Merge(ctx, actorID, actorRole, sourceName, targetName, reason)
+}
+
+type tracedPostUsecase struct {
+	next   usecase.PostUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedPostUsecase(next usecase.PostUsecase, tp trace.TracerProvider) usecase.PostUsecase {
+	return &tracedPostUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedPostUsecase) Create(ctx context.Context, content string, userID uuid.UUID, threadID uuid.UUID, parentPostID *uuid.UUID) (r0 *domain.Post, err error) {
+	ctx, span := d.tracer.Start(ctx, "PostUsecase.Create")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Create(ctx, content, userID, threadID, parentPostID)
+}
+
+func (d *tracedPostUsecase) GetByThreadID(ctx context.Context, threadID uuid.UUID, params usecase.PaginationParams) (r0 []*domain.Post, r1 map[uuid.UUID]*domain.User, r2 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "PostUsecase.GetByThreadID")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetByThreadID(ctx, threadID, params)
+}
+
+func (d *tracedPostUsecase) Update(ctx context.Context, postID uuid.UUID, userID uuid.UUID, role string, content string, reason *string) (r0 *domain.Post, r1 *domain.User, err error) {
+	ctx, span := d.tracer.Start(ctx, "PostUsecase.Update")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Update(ctx, postID, userID, role, content, reason)
+}
+
+type tracedVoteUsecase struct {
+	next   usecase.VoteUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedVoteUsecase(next usecase.VoteUsecase, tp trace.TracerProvider) usecase.VoteUsecase {
+	return &tracedVoteUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedVoteUsecase) VoteOnThread(ctx context.Context, userID uuid.UUID, threadID uuid.UUID, voteType int) (err error) {
+	ctx, span := d.tracer.Start(ctx, "VoteUsecase.VoteOnThread")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.VoteOnThread(ctx, userID, threadID, voteType)
+}
+
+func (d *tracedVoteUsecase) VoteOnPost(ctx context.Context, userID uuid.UUID, postID uuid.UUID, voteType int) (err error) {
+	ctx, span := d.tracer.Start(ctx, "VoteUsecase.VoteOnPost")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.VoteOnPost(ctx, userID, postID, voteType)
+}
+
+type tracedAuditLogUsecase struct {
+	next   usecase.AuditLogUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedAuditLogUsecase(next usecase.AuditLogUsecase, tp trace.TracerProvider) usecase.AuditLogUsecase {
+	return &tracedAuditLogUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedAuditLogUsecase) Log(ctx context.Context, entry *domain.AuditLog) (err error) {
+	ctx, span := d.tracer.Start(ctx, "AuditLogUsecase.Log")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.Log(ctx, entry)
+}
+
+func (d *tracedAuditLogUsecase) GetAll(ctx context.Context, filter usecase.AuditLogFilter, params usecase.PaginationParams) (r0 []*domain.AuditLog, r1 map[uuid.UUID]*domain.User, r2 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "AuditLogUsecase.GetAll")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetAll(ctx, filter, params)
+}
+
+type tracedNotificationUsecase struct {
+	next   usecase.NotificationUsecase
+	tracer trace.Tracer
+}
+
+func NewTracedNotificationUsecase(next usecase.NotificationUsecase, tp trace.TracerProvider) usecase.NotificationUsecase {
+	return &tracedNotificationUsecase{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (d *tracedNotificationUsecase) NotifyThreadCreated(ctx context.Context, eventID uuid.UUID, thread *domain.Thread, mentioned []uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "NotificationUsecase.NotifyThreadCreated")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.NotifyThreadCreated(ctx, eventID, thread, mentioned)
+}
+
+func (d *tracedNotificationUsecase) NotifyPostCreated(ctx context.Context, eventID uuid.UUID, post *domain.Post, thread *domain.Thread, parent *domain.Post, mentioned []uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "NotificationUsecase.NotifyPostCreated")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.NotifyPostCreated(ctx, eventID, post, thread, parent, mentioned)
+}
+
+func (d *tracedNotificationUsecase) NotifyVoteMilestone(ctx context.Context, eventID uuid.UUID, ownerID uuid.UUID, threadID uuid.UUID, postID *uuid.UUID, oldCount int, newCount int) (err error) {
+	ctx, span := d.tracer.Start(ctx, "NotificationUsecase.NotifyVoteMilestone")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.NotifyVoteMilestone(ctx, eventID, ownerID, threadID, postID, oldCount, newCount)
+}
+
+func (d *tracedNotificationUsecase) GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, params usecase.PaginationParams) (r0 []*domain.Notification, r1 map[uuid.UUID]*domain.User, r2 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "NotificationUsecase.GetByUserID")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetByUserID(ctx, userID, unreadOnly, params)
+}
+
+func (d *tracedNotificationUsecase) CountUnread(ctx context.Context, userID uuid.UUID) (r0 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "NotificationUsecase.CountUnread")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.CountUnread(ctx, userID)
+}
+
+func (d *tracedNotificationUsecase) MarkRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) (err error) {
+	ctx, span := d.tracer.Start(ctx, "NotificationUsecase.MarkRead")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.MarkRead(ctx, userID, notificationID)
+}
+
+func (d *tracedNotificationUsecase) MarkAllRead(ctx context.Context, userID uuid.UUID) (r0 int, err error) {
+	ctx, span := d.tracer.Start(ctx, "NotificationUsecase.MarkAllRead")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.MarkAllRead(ctx, userID)
+}
+
+func (d *tracedNotificationUsecase) GetPreferences(ctx context.Context, userID uuid.UUID) (r0 *domain.NotificationPreferences, err error) {
+	ctx, span := d.tracer.Start(ctx, "NotificationUsecase.GetPreferences")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.GetPreferences(ctx, userID)
+}
+
+func (d *tracedNotificationUsecase) UpdatePreferences(ctx context.Context, userID uuid.UUID, params usecase.UpdateNotificationPreferencesParams) (r0 *domain.NotificationPreferences, err error) {
+	ctx, span := d.tracer.Start(ctx, "NotificationUsecase.UpdatePreferences")
+	defer func() { endSpan(span, err) }()
+
+	return d.next.UpdatePreferences(ctx, userID, params)
+}
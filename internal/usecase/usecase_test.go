@@ -0,0 +1,50 @@
+package usecase_test
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// recordingPublisher is an EventPublisher that keeps every event it is given.
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []*domain.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+func (p *recordingPublisher) published() []*domain.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]*domain.Event(nil), p.events...)
+}
+
+// recordingDispatcher is a WebhookDispatcher that keeps the types of the
+// events it is given.
+type recordingDispatcher struct {
+	mu    sync.Mutex
+	types []string
+}
+
+func (d *recordingDispatcher) Dispatch(ctx context.Context, eventID uuid.UUID, eventType string, data interface{}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.types = append(d.types, eventType)
+
+	return nil
+}
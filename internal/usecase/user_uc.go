@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,12 +11,14 @@ import (
 )
 
 type userUsecase struct {
-	userRepo UserRepository
-	tokenSvc TokenService
+	txManager   TxManager
+	userRepo    UserRepository
+	tokenSvc    TokenService
+	auditLogger AuditLogger
 }
 
-func NewUserUsecase(ur UserRepository, ts TokenService) UserUsecase {
-	return &userUsecase{userRepo: ur, tokenSvc: ts}
+func NewUserUsecase(tm TxManager, ur UserRepository, ts TokenService, al AuditLogger) UserUsecase {
+	return &userUsecase{txManager: tm, userRepo: ur, tokenSvc: ts, auditLogger: al}
 }
 
 func (uc *userUsecase) Login(ctx context.Context, email string, password string) (string, string, error) {
@@ -25,7 +28,7 @@ func (uc *userUsecase) Login(ctx context.Context, email string, password string)
 
 	user, err := uc.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		if err == domain.ErrNotFound {
+		if errors.Is(err, domain.ErrNotFound) {
 			return "", "", domain.ErrUnauthorized
 		}
 
@@ -60,7 +63,7 @@ func (uc *userUsecase) Register(ctx context.Context, username string, email stri
 	}
 
 	existingUser, err := uc.userRepo.GetByEmail(ctx, email)
-	if err != nil && err != domain.ErrNotFound {
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
 		return nil, err
 	}
 
@@ -99,7 +102,7 @@ func (uc *userUsecase) Refresh(ctx context.Context, refreshToken string) (string
 
 	user, err := uc.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		if err == domain.ErrNotFound {
+		if errors.Is(err, domain.ErrNotFound) {
 			return "", domain.ErrUnauthorized
 		}
 
@@ -117,3 +120,52 @@ func (uc *userUsecase) Refresh(ctx context.Context, refreshToken string) (string
 func (uc *userUsecase) GetUsers(ctx context.Context) ([]*domain.User, error) {
 	return uc.userRepo.GetUsers(ctx)
 }
+
+func (uc *userUsecase) UpdateRole(ctx context.Context, actorID uuid.UUID, actorRole string, targetID uuid.UUID, role string, reason *string) (*domain.User, error) {
+	if role != "admin" && role != "member" {
+		return nil, domain.Invalid(domain.FieldError{Field: "role", Rule: "oneof", Param: "admin member"})
+	}
+
+	if actorRole != "admin" {
+		return nil, domain.ErrForbidden
+	}
+
+	if actorID == targetID {
+		return nil, domain.ErrForbidden
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == role {
+		return user, nil
+	}
+
+	oldRole := user.Role
+
+	details := oldRole + " -> " + role
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.UpdateRole(ctx, targetID, role); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+			ActorID:    actorID,
+			ActorRole:  actorRole,
+			Action:     domain.AuditActionUserRoleUpdate,
+			TargetType: domain.AuditTargetUser,
+			TargetID:   targetID,
+			Reason:     reason,
+			Details:    &details,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = role
+
+	return user, nil
+}
@@ -2,143 +2,159 @@ package usecase
 
 import (
 	"context"
-	"log"
+	"errors"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/srgjo27/agora/internal/domain"
 )
 
 type voteUsecase struct {
-	db         *sqlx.DB
+	txManager  TxManager
 	voteRepo   VoteRepository
 	threadRepo ThreadRepository
 	postRepo   PostRepository
+	outboxRepo OutboxRepository
 }
 
-func NewVoteUsecase(db *sqlx.DB, vr VoteRepository, tr ThreadRepository, pr PostRepository) VoteUsecase {
+// NewVoteUsecase records a vote and its outbox event together. Milestone
+// notifications, real-time updates and webhooks follow from the event.
+func NewVoteUsecase(tm TxManager, vr VoteRepository, tr ThreadRepository, pr PostRepository, or OutboxRepository) VoteUsecase {
 	return &voteUsecase{
-		db:         db,
+		txManager:  tm,
 		voteRepo:   vr,
 		threadRepo: tr,
 		postRepo:   pr,
+		outboxRepo: or,
 	}
 }
 
 func (uc *voteUsecase) VoteOnThread(ctx context.Context, userID uuid.UUID, threadID uuid.UUID, voteType int) error {
-	_, err := uc.threadRepo.GetByID(ctx, threadID)
-	if err != nil {
-		return err
-	}
-
-	oldVote, err := uc.voteRepo.GetThreadVote(ctx, userID, threadID)
-	oldVoteType := 0
-	if err != nil && err != domain.ErrNotFound {
+	if _, err := uc.threadRepo.GetByID(ctx, threadID); err != nil {
 		return err
 	}
 
-	if oldVote != nil {
-		oldVoteType = oldVote.VoteType
-	}
-
-	delta := voteType - oldVoteType
-
-	tx, err := uc.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return err
-	}
+	// The old vote is read under lock, so concurrent votes by the same user
+	// each compute their delta from the one committed before.
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		oldVote, err := uc.voteRepo.GetThreadVoteForUpdate(ctx, userID, threadID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
 
-	defer func() {
-		if r := recover(); r != nil {
-			log.Println("Recovered from panic, rolling back transaction")
-			tx.Rollback()
+		oldVoteType := 0
+		if oldVote != nil {
+			oldVoteType = oldVote.VoteType
 		}
-	}()
 
-	if voteType == 0 {
-		if err := uc.voteRepo.DeleteThreadVote(ctx, tx, userID, threadID); err != nil {
-			tx.Rollback()
-			return err
+		delta := voteType - oldVoteType
+
+		if voteType == 0 {
+			if err := uc.voteRepo.DeleteThreadVote(ctx, userID, threadID); err != nil {
+				return err
+			}
+		} else {
+			newVote := &domain.ThreadVote{
+				UserID:   userID,
+				ThreadID: threadID,
+				VoteType: voteType,
+			}
+
+			if err := uc.voteRepo.UpsertThreadVote(ctx, newVote); err != nil {
+				return err
+			}
 		}
-	} else {
-		newVote := &domain.ThreadVote{
-			UserID:   userID,
-			ThreadID: threadID,
-			VoteType: voteType,
+
+		if delta == 0 {
+			return nil
 		}
 
-		if err := uc.voteRepo.UpsertThreadVote(ctx, tx, newVote); err != nil {
-			tx.Rollback()
+		voteCount, err := uc.threadRepo.UpdateVoteCount(ctx, threadID, delta)
+		if err != nil {
 			return err
 		}
-	}
 
-	if delta != 0 {
-		if err := uc.threadRepo.UpdateVoteCount(ctx, tx, threadID, delta); err != nil {
-			tx.Rollback()
+		cast := &domain.VoteCastEventData{
+			ThreadID:      threadID,
+			UserID:        userID,
+			VoteType:      voteType,
+			VoteCount:     voteCount,
+			PreviousCount: voteCount - delta,
+		}
 
+		event, err := newOutboxEvent(domain.AggregateThread, threadID, domain.EventThreadVoted, cast)
+		if err != nil {
 			return err
 		}
-	}
 
-	return tx.Commit()
+		return uc.outboxRepo.Add(ctx, event)
+	})
 }
 
 func (uc *voteUsecase) VoteOnPost(ctx context.Context, userID uuid.UUID, postID uuid.UUID, voteType int) error {
-	_, err := uc.postRepo.GetByID(ctx, postID)
+	post, err := uc.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return err
 	}
 
-	oldVote, err := uc.voteRepo.GetPostVote(ctx, userID, postID)
-	oldVoteType := 0
-	if err != nil && err != domain.ErrNotFound {
+	// Posts of a trashed thread are hidden with it.
+	if _, err := uc.threadRepo.GetByID(ctx, post.ThreadID); err != nil {
 		return err
 	}
 
-	if oldVote != nil {
-		oldVoteType = oldVote.VoteType
-	}
-
-	delta := voteType - oldVoteType
-
-	tx, err := uc.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return err
-	}
+	// The old vote is read under lock, so concurrent votes by the same user
+	// each compute their delta from the one committed before.
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		oldVote, err := uc.voteRepo.GetPostVoteForUpdate(ctx, userID, postID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
 
-	defer func() {
-		if r := recover(); r != nil {
-			log.Println("[INFO]: Recovered from panic, rolling back transaction")
-			tx.Rollback()
+		oldVoteType := 0
+		if oldVote != nil {
+			oldVoteType = oldVote.VoteType
 		}
-	}()
 
-	if voteType == 0 {
-		if err := uc.voteRepo.DeletePostVote(ctx, tx, userID, postID); err != nil {
-			tx.Rollback()
-			return err
+		delta := voteType - oldVoteType
+
+		if voteType == 0 {
+			if err := uc.voteRepo.DeletePostVote(ctx, userID, postID); err != nil {
+				return err
+			}
+		} else {
+			newVote := &domain.ThreadVote{
+				UserID:   userID,
+				ThreadID: postID,
+				VoteType: voteType,
+			}
+
+			if err := uc.voteRepo.UpsertPostVote(ctx, newVote); err != nil {
+				return err
+			}
 		}
-	} else {
-		newVote := &domain.ThreadVote{
-			UserID:   userID,
-			ThreadID: postID,
-			VoteType: voteType,
+
+		if delta == 0 {
+			return nil
 		}
 
-		if err := uc.voteRepo.UpsertPostVote(ctx, tx, newVote); err != nil {
-			tx.Rollback()
+		voteCount, err := uc.postRepo.UpdateVoteCount(ctx, postID, delta)
+		if err != nil {
 			return err
 		}
-	}
 
-	if delta != 0 {
-		if err := uc.postRepo.UpdateVoteCount(ctx, tx, postID, delta); err != nil {
-			tx.Rollback()
+		cast := &domain.VoteCastEventData{
+			ThreadID:      post.ThreadID,
+			PostID:        &post.ID,
+			UserID:        userID,
+			VoteType:      voteType,
+			VoteCount:     voteCount,
+			PreviousCount: voteCount - delta,
+		}
 
+		event, err := newOutboxEvent(domain.AggregatePost, postID, domain.EventPostVoted, cast)
+		if err != nil {
 			return err
 		}
-	}
 
-	return tx.Commit()
+		return uc.outboxRepo.Add(ctx, event)
+	})
 }
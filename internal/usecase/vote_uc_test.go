@@ -0,0 +1,271 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+type voteFixture struct {
+	store     *memory.Store
+	threads   usecase.ThreadRepository
+	posts     usecase.PostRepository
+	notes     usecase.NotificationUsecase
+	publisher *recordingPublisher
+	votes     usecase.VoteUsecase
+	thread    *domain.Thread
+	post      *domain.Post
+}
+
+func newVoteFixture(t *testing.T) *voteFixture {
+	t.Helper()
+
+	ctx := context.Background()
+	s := memory.NewStore()
+	f := &voteFixture{
+		store:     s,
+		threads:   memory.NewMemoryThreadRepo(s),
+		posts:     memory.NewMemoryPostRepo(s),
+		publisher: &recordingPublisher{},
+	}
+	f.notes = usecase.NewNotificationUsecase(memory.NewMemoryNotificationRepo(s), memory.NewMemoryUserRepo(s), f.publisher, discardLogger)
+	f.votes = usecase.NewVoteUsecase(
+		memory.NewTxManager(s),
+		slowVoteRepo{memory.NewMemoryVoteRepo(s)},
+		f.threads,
+		f.posts,
+		memory.NewMemoryOutboxRepo(s),
+	)
+
+	now := time.Now()
+	f.thread = &domain.Thread{ID: uuid.New(), Title: "Votes", Slug: "votes", UserID: uuid.New(), CategoryID: uuid.New(), CreatedAt: now}
+	if err := f.threads.Create(ctx, f.thread); err != nil {
+		t.Fatalf("create thread: %v", err)
+	}
+
+	f.post = &domain.Post{ID: uuid.New(), ThreadID: f.thread.ID, UserID: uuid.New(), Content: "Reply", CreatedAt: now}
+	if err := f.posts.Create(ctx, f.post); err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+
+	return f
+}
+
+// slowVoteRepo pauses after reading a vote, so concurrent votes would all
+// read the same old vote unless the read locks it.
+type slowVoteRepo struct {
+	usecase.VoteRepository
+}
+
+func (r slowVoteRepo) GetThreadVoteForUpdate(ctx context.Context, userID, threadID uuid.UUID) (*domain.ThreadVote, error) {
+	vote, err := r.VoteRepository.GetThreadVoteForUpdate(ctx, userID, threadID)
+	time.Sleep(time.Millisecond)
+
+	return vote, err
+}
+
+// handle runs handler over the recorded outbox events, as the relay would.
+func (f *voteFixture) handle(t *testing.T, handler usecase.EventHandler) {
+	t.Helper()
+
+	for _, e := range f.store.OutboxEvents() {
+		if err := handler(context.Background(), e); err != nil {
+			t.Fatalf("handle %s: %v", e.EventType, err)
+		}
+	}
+}
+
+// voteCounts returns the vote counts carried by the recorded outbox events.
+func (f *voteFixture) voteCounts(t *testing.T) []int {
+	t.Helper()
+
+	counts := make([]int, 0)
+	for _, e := range f.store.OutboxEvents() {
+		var data domain.VoteCastEventData
+		if err := json.Unmarshal(e.Payload, &data); err != nil {
+			t.Fatalf("decode %s payload: %v", e.EventType, err)
+		}
+		counts = append(counts, data.VoteCount)
+	}
+
+	return counts
+}
+
+func TestVoteOnThreadAppliesTheChangeInVote(t *testing.T) {
+	ctx := context.Background()
+	f := newVoteFixture(t)
+	voter := uuid.New()
+
+	steps := []struct {
+		voteType int
+		want     int
+	}{
+		{1, 1},
+		{1, 1},
+		{-1, -1},
+		{0, 0},
+		{0, 0},
+		{-1, -1},
+	}
+
+	for _, step := range steps {
+		if err := f.votes.VoteOnThread(ctx, voter, f.thread.ID, step.voteType); err != nil {
+			t.Fatalf("VoteOnThread(%d): %v", step.voteType, err)
+		}
+
+		thread, err := f.threads.GetByID(ctx, f.thread.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+
+		if thread.VoteCount != step.want {
+			t.Fatalf("after voting %d: VoteCount = %d, want %d", step.voteType, thread.VoteCount, step.want)
+		}
+	}
+
+	// Repeated votes change nothing and record no event.
+	want := []int{1, -1, 0, -1}
+	if got := f.voteCounts(t); !slices.Equal(got, want) {
+		t.Errorf("outbox vote counts = %v, want %v", got, want)
+	}
+}
+
+func TestVoteOnPostEmitsTheUpdatedCount(t *testing.T) {
+	ctx := context.Background()
+	f := newVoteFixture(t)
+
+	// Another writer moved the count since the post was loaded.
+	if _, err := f.posts.UpdateVoteCount(ctx, f.post.ID, 5); err != nil {
+		t.Fatalf("UpdateVoteCount: %v", err)
+	}
+
+	if err := f.votes.VoteOnPost(ctx, uuid.New(), f.post.ID, 1); err != nil {
+		t.Fatalf("VoteOnPost: %v", err)
+	}
+
+	if got := f.voteCounts(t); !slices.Equal(got, []int{6}) {
+		t.Errorf("outbox vote counts = %v, want [6]", got)
+	}
+
+	f.handle(t, usecase.NewRealtimeHandler(f.publisher))
+
+	events := f.publisher.published()
+	if len(events) != 1 {
+		t.Fatalf("published %d events, want 1", len(events))
+	}
+
+	var data domain.VoteEventData
+	if err := json.Unmarshal(events[0].Data, &data); err != nil {
+		t.Fatalf("decode %s event: %v", events[0].Type, err)
+	}
+
+	if data.VoteCount != 6 || data.PostID == nil || *data.PostID != f.post.ID {
+		t.Errorf("published %s, want a vote count of 6 for the post", events[0].Data)
+	}
+}
+
+func TestConcurrentVotesBySameUserCountOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newVoteFixture(t)
+	voter := uuid.New()
+
+	const workers = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- f.votes.VoteOnThread(ctx, voter, f.thread.ID, 1)
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("VoteOnThread: %v", err)
+		}
+	}
+
+	thread, err := f.threads.GetByID(ctx, f.thread.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if thread.VoteCount != 1 {
+		t.Errorf("VoteCount = %d, want 1", thread.VoteCount)
+	}
+
+	if got := f.voteCounts(t); !slices.Equal(got, []int{1}) {
+		t.Errorf("outbox vote counts = %v, want [1]", got)
+	}
+}
+
+func TestVoteOnThreadNotifiesMilestonesFromTheUpdatedCount(t *testing.T) {
+	ctx := context.Background()
+	f := newVoteFixture(t)
+
+	if _, err := f.threads.UpdateVoteCount(ctx, f.thread.ID, 9); err != nil {
+		t.Fatalf("UpdateVoteCount: %v", err)
+	}
+
+	if err := f.votes.VoteOnThread(ctx, uuid.New(), f.thread.ID, 1); err != nil {
+		t.Fatalf("VoteOnThread: %v", err)
+	}
+
+	// The eleventh vote crosses no milestone.
+	if err := f.votes.VoteOnThread(ctx, uuid.New(), f.thread.ID, 1); err != nil {
+		t.Fatalf("VoteOnThread: %v", err)
+	}
+
+	f.handle(t, usecase.NewNotificationHandler(f.notes, f.threads, f.posts))
+
+	notifications, _, _, err := f.notes.GetByUserID(ctx, f.thread.UserID, false, usecase.PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	if len(notifications) != 1 || notifications[0].Milestone == nil || *notifications[0].Milestone != 10 {
+		t.Errorf("owner got %+v, want one notification for 10 votes", notifications)
+	}
+}
+
+func TestVoteOnPostInTrashedThreadIsNotFound(t *testing.T) {
+	ctx := context.Background()
+	f := newVoteFixture(t)
+
+	if _, err := f.posts.UpdateVoteCount(ctx, f.post.ID, 9); err != nil {
+		t.Fatalf("UpdateVoteCount: %v", err)
+	}
+
+	if err := f.votes.VoteOnPost(ctx, uuid.New(), f.post.ID, 1); err != nil {
+		t.Fatalf("VoteOnPost: %v", err)
+	}
+
+	// The thread is trashed before the milestone event is handled.
+	if err := f.threads.Delete(ctx, f.thread.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if err := f.votes.VoteOnPost(ctx, uuid.New(), f.post.ID, 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("VoteOnPost in a trashed thread = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	f.handle(t, usecase.NewNotificationHandler(f.notes, f.threads, f.posts))
+
+	if count, _ := f.notes.CountUnread(ctx, f.post.UserID); count != 0 {
+		t.Errorf("owner got %d notifications about a post in a trashed thread", count)
+	}
+}
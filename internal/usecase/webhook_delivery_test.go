@@ -0,0 +1,290 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+	"github.com/srgjo27/agora/internal/repository/memory"
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// sentWebhook is one request made through a scriptedSender.
+type sentWebhook struct {
+	url     string
+	headers map[string]string
+	body    []byte
+}
+
+// scriptedSender is a WebhookSender that answers every request with
+// response, or fails with err when it is set.
+type scriptedSender struct {
+	mu       sync.Mutex
+	response *domain.WebhookResponse
+	err      error
+	sent     []sentWebhook
+}
+
+func (s *scriptedSender) Post(ctx context.Context, url string, headers map[string]string, body []byte) (*domain.WebhookResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sent = append(s.sent, sentWebhook{url: url, headers: headers, body: body})
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	r := *s.response
+
+	return &r, nil
+}
+
+func (s *scriptedSender) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.sent)
+}
+
+type webhookFixture struct {
+	repo   usecase.WebhookRepository
+	sender *scriptedSender
+	uc     usecase.WebhookUsecase
+}
+
+func newWebhookFixture() *webhookFixture {
+	s := memory.NewStore()
+	f := &webhookFixture{
+		repo:   memory.NewMemoryWebhookRepo(s),
+		sender: &scriptedSender{response: &domain.WebhookResponse{StatusCode: 200}},
+	}
+	f.uc = usecase.NewWebhookUsecase(memory.NewTxManager(s), f.repo, f.sender, nil, discardLogger)
+
+	return f
+}
+
+func (f *webhookFixture) create(t *testing.T, url string, events ...string) *domain.Webhook {
+	t.Helper()
+
+	webhook, err := f.uc.Create(context.Background(), uuid.New(), "admin", usecase.CreateWebhookParams{URL: url, Events: events})
+	if err != nil {
+		t.Fatalf("Create webhook: %v", err)
+	}
+
+	return webhook
+}
+
+func (f *webhookFixture) deliveries(t *testing.T, webhookID uuid.UUID) []*domain.WebhookDelivery {
+	t.Helper()
+
+	deliveries, _, err := f.uc.GetDeliveries(context.Background(), webhookID, usecase.PaginationParams{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetDeliveries: %v", err)
+	}
+
+	return deliveries
+}
+
+// makeDue moves a delivery's next attempt to now, keeping its attempts.
+func (f *webhookFixture) makeDue(t *testing.T, d *domain.WebhookDelivery, attempts int) {
+	t.Helper()
+
+	d.Attempts = attempts
+	d.NextAttemptAt = time.Now()
+	if err := f.repo.SaveDeliveryResult(context.Background(), d); err != nil {
+		t.Fatalf("SaveDeliveryResult: %v", err)
+	}
+}
+
+func TestDispatchQueuesSubscribedActiveWebhooks(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture()
+
+	threads := f.create(t, "https://threads.example.com/hook", domain.WebhookEventThreadCreated)
+	both := f.create(t, "https://both.example.com/hook", domain.WebhookEventThreadCreated, domain.WebhookEventPostCreated)
+	posts := f.create(t, "https://posts.example.com/hook", domain.WebhookEventPostCreated)
+	inactive := f.create(t, "https://inactive.example.com/hook", domain.WebhookEventThreadCreated)
+
+	off := false
+	if _, err := f.uc.Update(ctx, uuid.New(), "admin", inactive.ID, usecase.UpdateWebhookParams{IsActive: &off}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	eventID := uuid.New()
+	data := &domain.ThreadEventData{ID: uuid.New(), Title: "Hello"}
+
+	// Dispatching the same event again queues nothing new.
+	for i := 0; i < 2; i++ {
+		if err := f.uc.Dispatch(ctx, eventID, domain.WebhookEventThreadCreated, data); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+	}
+
+	want := map[uuid.UUID]int{threads.ID: 1, both.ID: 1, posts.ID: 0, inactive.ID: 0}
+	for webhookID, n := range want {
+		if got := len(f.deliveries(t, webhookID)); got != n {
+			t.Errorf("webhook got %d deliveries, want %d", got, n)
+		}
+	}
+
+	d := f.deliveries(t, threads.ID)[0]
+	if d.EventID != eventID || d.EventType != domain.WebhookEventThreadCreated || d.Status != domain.WebhookDeliveryPending {
+		t.Errorf("delivery = %+v, want a pending thread.created delivery of the event", d)
+	}
+
+	var payload struct {
+		ID   uuid.UUID              `json:"id"`
+		Type string                 `json:"type"`
+		Data domain.ThreadEventData `json:"data"`
+	}
+	if err := json.Unmarshal(d.Payload, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+
+	if payload.ID != eventID || payload.Type != domain.WebhookEventThreadCreated || payload.Data.ID != data.ID {
+		t.Errorf("payload = %s, want the event and its data", d.Payload)
+	}
+}
+
+func TestDeliverDueSignsAndRecordsAcceptedDeliveries(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture()
+	webhook := f.create(t, "https://hooks.example.com/agora", domain.WebhookEventPostCreated)
+
+	if err := f.uc.Dispatch(ctx, uuid.New(), domain.WebhookEventPostCreated, map[string]string{"id": "1"}); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	f.sender.response = &domain.WebhookResponse{StatusCode: 204}
+	if delivered, err := f.uc.DeliverDue(ctx); err != nil || delivered != 1 {
+		t.Fatalf("DeliverDue = %d, %v; want 1, nil", delivered, err)
+	}
+
+	d := f.deliveries(t, webhook.ID)[0]
+	if d.Status != domain.WebhookDeliveryDelivered || d.DeliveredAt == nil || d.Attempts != 1 || d.LastError != nil {
+		t.Errorf("delivery = %+v, want delivered after one attempt", d)
+	}
+
+	sent := f.sender.sent[0]
+	if sent.url != webhook.URL || string(sent.body) != string(d.Payload) {
+		t.Errorf("sent %s to %s, want the payload posted to the webhook URL", sent.body, sent.url)
+	}
+
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write([]byte(sent.headers["X-Agora-Timestamp"] + "." + string(sent.body)))
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); sent.headers["X-Agora-Signature"] != want {
+		t.Errorf("signature = %s, want %s", sent.headers["X-Agora-Signature"], want)
+	}
+
+	// A delivered delivery is not sent again.
+	if delivered, err := f.uc.DeliverDue(ctx); err != nil || delivered != 0 || f.sender.requests() != 1 {
+		t.Errorf("DeliverDue after delivery = %d, %v with %d requests; want nothing sent", delivered, err, f.sender.requests())
+	}
+}
+
+func TestDeliverDueRetriesUntilTheLastAttempt(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture()
+	webhook := f.create(t, "https://hooks.example.com/agora", domain.WebhookEventVoteCast)
+
+	if err := f.uc.Dispatch(ctx, uuid.New(), domain.WebhookEventVoteCast, map[string]int{"vote_count": 1}); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	// A non-2xx response is a failed attempt.
+	f.sender.response = &domain.WebhookResponse{StatusCode: 500, Body: "oops"}
+	start := time.Now()
+	if delivered, err := f.uc.DeliverDue(ctx); err != nil || delivered != 0 {
+		t.Fatalf("DeliverDue = %d, %v; want 0, nil", delivered, err)
+	}
+
+	d := f.deliveries(t, webhook.ID)[0]
+	if d.Status != domain.WebhookDeliveryPending || d.Attempts != 1 || d.LastError == nil || *d.LastError != "unexpected status 500" {
+		t.Fatalf("after a 500: %+v, want pending with the status as the error", d)
+	}
+
+	if d.ResponseStatus == nil || *d.ResponseStatus != 500 || d.ResponseBody == nil || *d.ResponseBody != "oops" {
+		t.Errorf("after a 500: response %v %v, want it recorded", d.ResponseStatus, d.ResponseBody)
+	}
+
+	if delay := d.NextAttemptAt.Sub(start); delay < 30*time.Second || delay > 31*time.Second {
+		t.Errorf("first retry in %v, want about 30s", delay)
+	}
+
+	// Not yet due: nothing is sent.
+	if _, err := f.uc.DeliverDue(ctx); err != nil || f.sender.requests() != 1 {
+		t.Fatalf("DeliverDue before the retry sent %d requests, %v; want 1", f.sender.requests(), err)
+	}
+
+	// A transport error is a failed attempt too, and clears the old response.
+	f.makeDue(t, d, 1)
+	f.sender.err = errors.New("connection refused")
+	if _, err := f.uc.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+
+	d = f.deliveries(t, webhook.ID)[0]
+	if d.Status != domain.WebhookDeliveryPending || d.Attempts != 2 || d.LastError == nil || *d.LastError != "connection refused" || d.ResponseStatus != nil {
+		t.Fatalf("after a transport error: %+v, want pending with the error and no response", d)
+	}
+
+	// The tenth failed attempt is the last.
+	f.makeDue(t, d, 9)
+	if _, err := f.uc.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+
+	d = f.deliveries(t, webhook.ID)[0]
+	if d.Status != domain.WebhookDeliveryFailed || d.Attempts != 10 || d.DeliveredAt != nil {
+		t.Fatalf("after the last attempt: %+v, want failed after 10 attempts", d)
+	}
+
+	f.sender.err = nil
+	f.makeDue(t, d, 10)
+	if _, err := f.uc.DeliverDue(ctx); err != nil || f.sender.requests() != 3 {
+		t.Errorf("DeliverDue after failing sent %d requests, %v; want no more than 3", f.sender.requests(), err)
+	}
+}
+
+func TestRedeliverRequiresTheDeliveryOfThatWebhook(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture()
+	webhook := f.create(t, "https://hooks.example.com/agora", domain.WebhookEventThreadDeleted)
+	other := f.create(t, "https://other.example.com/agora", domain.WebhookEventThreadDeleted)
+
+	eventID := uuid.New()
+	if err := f.uc.Dispatch(ctx, eventID, domain.WebhookEventThreadDeleted, map[string]string{}); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	original := f.deliveries(t, webhook.ID)[0]
+
+	if _, err := f.uc.Redeliver(ctx, other.ID, original.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Redeliver through another webhook = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	if n := len(f.deliveries(t, other.ID)); n != 1 {
+		t.Errorf("other webhook has %d deliveries, want only its own", n)
+	}
+
+	copied, err := f.uc.Redeliver(ctx, webhook.ID, original.ID)
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+
+	if copied.EventID != eventID || copied.RedeliveryOf == nil || *copied.RedeliveryOf != original.ID || copied.Status != domain.WebhookDeliveryPending {
+		t.Errorf("redelivery = %+v, want a pending copy of the original event", copied)
+	}
+
+	if n := len(f.deliveries(t, webhook.ID)); n != 2 {
+		t.Errorf("webhook has %d deliveries, want the original and its copy", n)
+	}
+}
@@ -0,0 +1,446 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+const (
+	webhookBatchSize   = 50
+	webhookMaxAttempts = 10
+
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+
+	// webhookLease keeps a claimed delivery away from other workers while
+	// it is being sent. It must comfortably exceed the sender's timeout.
+	webhookLease = 2 * time.Minute
+
+	maxWebhookURLLength = 2048
+)
+
+type webhookUsecase struct {
+	txManager   TxManager
+	webhookRepo WebhookRepository
+	sender      WebhookSender
+	auditLogger AuditLogger
+	logger      *slog.Logger
+}
+
+func NewWebhookUsecase(tm TxManager, wr WebhookRepository, s WebhookSender, al AuditLogger, logger *slog.Logger) WebhookUsecase {
+	return &webhookUsecase{
+		txManager:   tm,
+		webhookRepo: wr,
+		sender:      s,
+		auditLogger: al,
+		logger:      logger,
+	}
+}
+
+func (uc *webhookUsecase) Create(ctx context.Context, actorID uuid.UUID, actorRole string, params CreateWebhookParams) (*domain.Webhook, error) {
+	if actorRole != "admin" {
+		return nil, domain.ErrForbidden
+	}
+
+	if err := validateWebhookURL(params.URL); err != nil {
+		return nil, err
+	}
+
+	events, err := normalizeWebhookEvents(params.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &domain.Webhook{
+		ID:          uuid.New(),
+		URL:         params.URL,
+		Secret:      rand.Text(),
+		Description: params.Description,
+		IsActive:    true,
+		CreatedBy:   &actorID,
+		CreatedAt:   time.Now(),
+		Events:      events,
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.webhookRepo.Create(ctx, webhook); err != nil {
+			return err
+		}
+
+		return uc.audit(ctx, actorID, actorRole, domain.AuditActionWebhookCreate, webhook)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (uc *webhookUsecase) GetAll(ctx context.Context) ([]*domain.Webhook, error) {
+	return uc.webhookRepo.GetAll(ctx)
+}
+
+func (uc *webhookUsecase) GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	return uc.webhookRepo.GetByID(ctx, id)
+}
+
+func (uc *webhookUsecase) Update(ctx context.Context, actorID uuid.UUID, actorRole string, id uuid.UUID, params UpdateWebhookParams) (*domain.Webhook, error) {
+	if actorRole != "admin" {
+		return nil, domain.ErrForbidden
+	}
+
+	webhook, err := uc.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.URL != nil {
+		if err := validateWebhookURL(*params.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *params.URL
+	}
+
+	if params.Events != nil {
+		events, err := normalizeWebhookEvents(*params.Events)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = events
+	}
+
+	if params.Description != nil {
+		webhook.Description = params.Description
+	}
+
+	if params.IsActive != nil {
+		webhook.IsActive = *params.IsActive
+	}
+
+	if params.RotateSecret {
+		webhook.Secret = rand.Text()
+	}
+
+	now := time.Now()
+	webhook.UpdatedAt = &now
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.webhookRepo.Update(ctx, webhook); err != nil {
+			return err
+		}
+
+		return uc.audit(ctx, actorID, actorRole, domain.AuditActionWebhookUpdate, webhook)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (uc *webhookUsecase) Delete(ctx context.Context, actorID uuid.UUID, actorRole string, id uuid.UUID) error {
+	if actorRole != "admin" {
+		return domain.ErrForbidden
+	}
+
+	webhook, err := uc.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.webhookRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return uc.audit(ctx, actorID, actorRole, domain.AuditActionWebhookDelete, webhook)
+	})
+}
+
+func (uc *webhookUsecase) audit(ctx context.Context, actorID uuid.UUID, actorRole, action string, webhook *domain.Webhook) error {
+	details := webhook.URL
+
+	return recordAudit(ctx, uc.auditLogger, &domain.AuditLog{
+		ActorID:    actorID,
+		ActorRole:  actorRole,
+		Action:     action,
+		TargetType: domain.AuditTargetWebhook,
+		TargetID:   webhook.ID,
+		Details:    &details,
+	})
+}
+
+func (uc *webhookUsecase) GetDeliveries(ctx context.Context, webhookID uuid.UUID, params PaginationParams) ([]*domain.WebhookDelivery, int, error) {
+	if _, err := uc.webhookRepo.GetByID(ctx, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.webhookRepo.CountDeliveriesByWebhookID(ctx, webhookID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, err := uc.webhookRepo.GetDeliveriesByWebhookID(ctx, webhookID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// Redeliver queues a fresh copy of a past delivery. The copy keeps the
+// event ID, so receivers can tell it apart from a new event.
+func (uc *webhookUsecase) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	original, err := uc.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.WebhookID != webhookID {
+		return nil, domain.ErrNotFound
+	}
+
+	now := time.Now()
+	delivery := &domain.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        domain.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		RedeliveryOf:  &original.ID,
+		CreatedAt:     now,
+	}
+
+	if err := uc.webhookRepo.CreateDeliveries(ctx, []*domain.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (uc *webhookUsecase) Dispatch(ctx context.Context, eventID uuid.UUID, eventType string, data interface{}) error {
+	webhooks, err := uc.webhookRepo.GetActiveByEvent(ctx, eventType)
+	if err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(&domain.WebhookPayload{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, len(webhooks))
+	for i, w := range webhooks {
+		deliveries[i] = &domain.WebhookDelivery{
+			ID:            uuid.New(),
+			WebhookID:     w.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+	}
+
+	return uc.webhookRepo.CreateDeliveries(ctx, deliveries)
+}
+
+// DeliverDue sends every pending delivery whose next attempt is due and
+// returns how many succeeded. Failed attempts are rescheduled with
+// exponential backoff until webhookMaxAttempts is reached.
+func (uc *webhookUsecase) DeliverDue(ctx context.Context) (int, error) {
+	delivered := 0
+	webhooks := make(map[uuid.UUID]*domain.Webhook)
+
+	for ctx.Err() == nil {
+		deliveries, err := uc.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), webhookLease, webhookBatchSize)
+		if err != nil {
+			return delivered, err
+		}
+
+		for _, d := range deliveries {
+			webhook, ok := webhooks[d.WebhookID]
+			if !ok {
+				webhook, err = uc.webhookRepo.GetByID(ctx, d.WebhookID)
+				if err != nil {
+					uc.logger.ErrorContext(ctx, "failed to load webhook", "webhook_id", d.WebhookID, "error", err)
+
+					continue
+				}
+				webhooks[d.WebhookID] = webhook
+			}
+
+			if uc.attempt(ctx, webhook, d) {
+				delivered++
+			}
+		}
+
+		if len(deliveries) < webhookBatchSize {
+			break
+		}
+	}
+
+	return delivered, ctx.Err()
+}
+
+// attempt sends one delivery and records the outcome. It reports whether
+// the receiver accepted it.
+func (uc *webhookUsecase) attempt(ctx context.Context, webhook *domain.Webhook, d *domain.WebhookDelivery) bool {
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	headers := map[string]string{
+		"Content-Type":      "application/json",
+		"User-Agent":        "Agora-Webhook/1.0",
+		"X-Agora-Event":     d.EventType,
+		"X-Agora-Event-ID":  d.EventID.String(),
+		"X-Agora-Delivery":  d.ID.String(),
+		"X-Agora-Timestamp": timestamp,
+		"X-Agora-Signature": "sha256=" + signWebhookPayload(webhook.Secret, timestamp, d.Payload),
+	}
+
+	res, err := uc.sender.Post(ctx, webhook.URL, headers, d.Payload)
+
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.ResponseStatus = nil
+	d.ResponseBody = nil
+	d.LastError = nil
+
+	switch {
+	case err != nil:
+		msg := err.Error()
+		d.LastError = &msg
+	case res.StatusCode < 200 || res.StatusCode > 299:
+		msg := fmt.Sprintf("unexpected status %d", res.StatusCode)
+		d.LastError = &msg
+	}
+
+	if res != nil {
+		d.ResponseStatus = &res.StatusCode
+		d.ResponseBody = &res.Body
+	}
+
+	switch {
+	case d.LastError == nil:
+		d.Status = domain.WebhookDeliveryDelivered
+		d.DeliveredAt = &now
+	case d.Attempts >= webhookMaxAttempts:
+		d.Status = domain.WebhookDeliveryFailed
+	default:
+		d.NextAttemptAt = now.Add(webhookBackoff(d.Attempts))
+	}
+
+	// A cancelled run still records the attempt it made.
+	if err := uc.webhookRepo.SaveDeliveryResult(context.WithoutCancel(ctx), d); err != nil {
+		uc.logger.ErrorContext(ctx, "failed to save webhook delivery", "delivery_id", d.ID, "error", err)
+	}
+
+	return d.Status == domain.WebhookDeliveryDelivered
+}
+
+func (uc *webhookUsecase) PurgeDeliveries(ctx context.Context, retention time.Duration) (int, error) {
+	return uc.webhookRepo.PurgeDeliveriesBefore(ctx, time.Now().Add(-retention))
+}
+
+// signWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>".
+// Signing the timestamp lets receivers reject replayed requests.
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff is the wait before the next attempt after the given number
+// of failed attempts.
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+
+	return backoff
+}
+
+func validateWebhookURL(raw string) error {
+	if len(raw) > maxWebhookURLLength {
+		return domain.Invalid(domain.FieldError{Field: "url", Rule: "max_length", Param: strconv.Itoa(maxWebhookURLLength)})
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.Invalid(domain.FieldError{Field: "url", Rule: "http_url"})
+	}
+
+	// Hostnames are checked when the sender connects; obvious internal
+	// hosts are refused here already.
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return domain.Invalid(domain.FieldError{Field: "url", Rule: "public_host"})
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil && !domain.IsPublicWebhookAddr(addr) {
+		return domain.Invalid(domain.FieldError{Field: "url", Rule: "public_host"})
+	}
+
+	return nil
+}
+
+// normalizeWebhookEvents validates the event types a webhook subscribes to
+// and drops duplicates. At least one event is required.
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	seen := make(map[string]bool, len(events))
+	normalized := make([]string, 0, len(events))
+
+	for _, e := range events {
+		if !domain.IsWebhookEventType(e) {
+			return nil, domain.Invalid(domain.FieldError{Field: "events", Rule: "event_type"})
+		}
+
+		if seen[e] {
+			continue
+		}
+
+		seen[e] = true
+		normalized = append(normalized, e)
+	}
+
+	if len(normalized) == 0 {
+		return nil, domain.Invalid(domain.FieldError{Field: "events", Rule: "min_items", Param: "1"})
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"type":"thread.created"}`)
+
+	got := signWebhookPayload("secret", "1700000000", body)
+
+	// What a receiver computes from the headers and the raw body.
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	if got != want {
+		t.Errorf("signWebhookPayload = %s, want %s", got, want)
+	}
+
+	variants := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+	}{
+		{"other secret", "other", "1700000000", body},
+		{"other timestamp", "secret", "1700000001", body},
+		{"other body", "secret", "1700000000", []byte(`{"type":"post.created"}`)},
+		{"timestamp moved into the body", "secret", "170000000", []byte("0." + string(body))},
+	}
+
+	for _, v := range variants {
+		if signWebhookPayload(v.secret, v.timestamp, v.body) == got {
+			t.Errorf("%s: signature did not change", v.name)
+		}
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{9, 128 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour},
+		{100, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		rule string
+	}{
+		{"https://hooks.example.com/agora", ""},
+		{"http://93.184.216.34:8080/hook", ""},
+		{"ftp://hooks.example.com", "http_url"},
+		{"https://", "http_url"},
+		{"http://localhost:8080/hook", "public_host"},
+		{"http://api.LOCALHOST/hook", "public_host"},
+		{"http://127.0.0.1/hook", "public_host"},
+		{"http://10.0.0.5/hook", "public_host"},
+		{"http://192.168.1.1/hook", "public_host"},
+		{"http://169.254.169.254/latest/meta-data", "public_host"},
+		{"http://100.64.0.1/hook", "public_host"},
+		{"http://0.0.0.0/hook", "public_host"},
+		{"http://[::1]/hook", "public_host"},
+		{"http://[fd00::1]/hook", "public_host"},
+		{"http://[::ffff:127.0.0.1]/hook", "public_host"},
+	}
+
+	for _, tt := range tests {
+		err := validateWebhookURL(tt.url)
+
+		var invalid *domain.Error
+		switch {
+		case tt.rule == "" && err != nil:
+			t.Errorf("validateWebhookURL(%q) = %v, want nil", tt.url, err)
+		case tt.rule != "" && (!errors.As(err, &invalid) || len(invalid.Fields) != 1 || invalid.Fields[0].Rule != tt.rule):
+			t.Errorf("validateWebhookURL(%q) = %v, want the %s rule", tt.url, err, tt.rule)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// DigestJob emails activity digests to members whose daily or weekly digest
+// has fallen due.
+type DigestJob struct {
+	digestUsecase usecase.DigestUsecase
+	interval      time.Duration
+	logger        *slog.Logger
+}
+
+func NewDigestJob(du usecase.DigestUsecase, interval time.Duration, logger *slog.Logger) *DigestJob {
+	return &DigestJob{
+		digestUsecase: du,
+		interval:      interval,
+		logger:        logger,
+	}
+}
+
+// Run sends once immediately and then on every interval until ctx is cancelled.
+func (j *DigestJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.RunOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.RunOnce(ctx)
+		}
+	}
+}
+
+func (j *DigestJob) RunOnce(ctx context.Context) {
+	sent, err := j.digestUsecase.SendDue(ctx)
+	if err != nil && ctx.Err() == nil {
+		j.logger.ErrorContext(ctx, "failed to send digests", "error", err)
+	}
+
+	if sent > 0 {
+		j.logger.InfoContext(ctx, "sent email digests", "count", sent)
+	}
+}
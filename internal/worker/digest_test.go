@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/srgjo27/agora/internal/domain"
+)
+
+// countingDigests is a DigestUsecase that reports each SendDue call on calls.
+type countingDigests struct {
+	calls chan struct{}
+}
+
+func (d *countingDigests) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.DigestPreferences, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (d *countingDigests) UpdateFrequency(ctx context.Context, userID uuid.UUID, frequency string) (*domain.DigestPreferences, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (d *countingDigests) Unsubscribe(ctx context.Context, token string) error {
+	return domain.ErrNotFound
+}
+
+func (d *countingDigests) SendDue(ctx context.Context) (int, error) {
+	d.calls <- struct{}{}
+
+	return 0, nil
+}
+
+// runDigestJob starts the job and returns a func that cancels it and waits
+// for Run to return.
+func runDigestJob(t *testing.T, digests *countingDigests, interval time.Duration) func() {
+	t.Helper()
+
+	job := NewDigestJob(digests, interval, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		job.Run(ctx)
+		close(done)
+	}()
+
+	return func() {
+		t.Helper()
+
+		cancel()
+		waitForRun(t, digests, done)
+	}
+}
+
+func TestDigestJobSendsAtStart(t *testing.T) {
+	digests := &countingDigests{calls: make(chan struct{})}
+	stop := runDigestJob(t, digests, time.Hour)
+	defer stop()
+
+	select {
+	case <-digests.calls:
+	case <-time.After(time.Second):
+		t.Fatal("SendDue not called before the first interval")
+	}
+}
+
+func TestDigestJobSendsOnEveryInterval(t *testing.T) {
+	digests := &countingDigests{calls: make(chan struct{})}
+	stop := runDigestJob(t, digests, 10*time.Millisecond)
+	defer stop()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-digests.calls:
+		case <-time.After(time.Second):
+			t.Fatalf("SendDue called %d times, want 3", i)
+		}
+	}
+}
+
+// waitForRun waits for Run to return, draining any send already under way.
+func waitForRun(t *testing.T, digests *countingDigests, done <-chan struct{}) {
+	t.Helper()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-digests.calls:
+		case <-time.After(time.Second):
+			t.Fatal("Run did not return after the context was cancelled")
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// OutboxRelayJob publishes domain events recorded in the outbox to the event
+// bus and prunes old published events.
+type OutboxRelayJob struct {
+	outboxUsecase usecase.OutboxUsecase
+	retention     time.Duration
+	interval      time.Duration
+	logger        *slog.Logger
+}
+
+func NewOutboxRelayJob(ou usecase.OutboxUsecase, retention, interval time.Duration, logger *slog.Logger) *OutboxRelayJob {
+	return &OutboxRelayJob{
+		outboxUsecase: ou,
+		retention:     retention,
+		interval:      interval,
+		logger:        logger,
+	}
+}
+
+// Run relays once immediately and then on every interval until ctx is cancelled.
+func (j *OutboxRelayJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.RunOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.RunOnce(ctx)
+		}
+	}
+}
+
+func (j *OutboxRelayJob) RunOnce(ctx context.Context) {
+	published, err := j.outboxUsecase.Relay(ctx)
+	if err != nil && ctx.Err() == nil {
+		j.logger.ErrorContext(ctx, "failed to relay outbox events", "error", err)
+	}
+
+	if published > 0 {
+		j.logger.InfoContext(ctx, "published outbox events", "count", published)
+	}
+
+	purged, err := j.outboxUsecase.Purge(ctx, j.retention)
+	if err != nil && ctx.Err() == nil {
+		j.logger.ErrorContext(ctx, "failed to purge outbox events", "error", err)
+	}
+
+	if purged > 0 {
+		j.logger.InfoContext(ctx, "purged outbox events", "count", purged, "retention", j.retention)
+	}
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// ThreadPurgeJob permanently removes soft-deleted threads once they have been
+// in the trash longer than the configured retention period, together with
+// the stored files of their attachments.
+type ThreadPurgeJob struct {
+	threadUsecase     usecase.ThreadUsecase
+	attachmentUsecase usecase.AttachmentUsecase
+	retention         time.Duration
+	interval          time.Duration
+	logger            *slog.Logger
+}
+
+func NewThreadPurgeJob(tu usecase.ThreadUsecase, au usecase.AttachmentUsecase, retention, interval time.Duration, logger *slog.Logger) *ThreadPurgeJob {
+	return &ThreadPurgeJob{
+		threadUsecase:     tu,
+		attachmentUsecase: au,
+		retention:         retention,
+		interval:          interval,
+		logger:            logger,
+	}
+}
+
+// Run purges once immediately and then on every interval until ctx is cancelled.
+func (j *ThreadPurgeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.purge(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.purge(ctx)
+		}
+	}
+}
+
+func (j *ThreadPurgeJob) purge(ctx context.Context) {
+	purged, err := j.threadUsecase.PurgeDeleted(ctx, j.retention)
+	if err != nil {
+		j.logger.ErrorContext(ctx, "failed to purge deleted threads", "error", err)
+
+		return
+	}
+
+	if purged > 0 {
+		j.logger.InfoContext(ctx, "purged deleted threads", "count", purged, "retention", j.retention)
+	}
+
+	// Runs even when no thread was purged, to retry files a previous run
+	// failed to delete.
+	removed, err := j.attachmentUsecase.PurgeOrphaned(ctx)
+	if err != nil {
+		j.logger.ErrorContext(ctx, "failed to purge orphaned attachments", "error", err)
+	}
+
+	if removed > 0 {
+		j.logger.InfoContext(ctx, "purged orphaned attachments", "count", removed)
+	}
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/srgjo27/agora/internal/usecase"
+)
+
+// WebhookDeliveryJob sends queued webhook deliveries and prunes old
+// delivery logs.
+type WebhookDeliveryJob struct {
+	webhookUsecase usecase.WebhookUsecase
+	retention      time.Duration
+	interval       time.Duration
+	logger         *slog.Logger
+}
+
+func NewWebhookDeliveryJob(wu usecase.WebhookUsecase, retention, interval time.Duration, logger *slog.Logger) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{
+		webhookUsecase: wu,
+		retention:      retention,
+		interval:       interval,
+		logger:         logger,
+	}
+}
+
+// Run delivers once immediately and then on every interval until ctx is cancelled.
+func (j *WebhookDeliveryJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.RunOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.RunOnce(ctx)
+		}
+	}
+}
+
+func (j *WebhookDeliveryJob) RunOnce(ctx context.Context) {
+	delivered, err := j.webhookUsecase.DeliverDue(ctx)
+	if err != nil && ctx.Err() == nil {
+		j.logger.ErrorContext(ctx, "failed to deliver webhooks", "error", err)
+	}
+
+	if delivered > 0 {
+		j.logger.InfoContext(ctx, "delivered webhooks", "count", delivered)
+	}
+
+	purged, err := j.webhookUsecase.PurgeDeliveries(ctx, j.retention)
+	if err != nil && ctx.Err() == nil {
+		j.logger.ErrorContext(ctx, "failed to purge webhook deliveries", "error", err)
+	}
+
+	if purged > 0 {
+		j.logger.InfoContext(ctx, "purged webhook deliveries", "count", purged, "retention", j.retention)
+	}
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS post_votes;
+DROP TABLE IF EXISTS thread_votes;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS threads;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,62 @@
+CREATE TABLE IF NOT EXISTS users (
+    id            UUID PRIMARY KEY,
+    username      VARCHAR(50)  NOT NULL UNIQUE,
+    email         VARCHAR(255) NOT NULL UNIQUE,
+    password_hash TEXT         NOT NULL,
+    avatar_url    TEXT,
+    role          VARCHAR(20)  NOT NULL DEFAULT 'member',
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id          UUID PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL UNIQUE,
+    slug        VARCHAR(120) NOT NULL UNIQUE,
+    description TEXT,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS threads (
+    id          UUID PRIMARY KEY,
+    title       VARCHAR(255) NOT NULL,
+    slug        VARCHAR(300) NOT NULL,
+    content     TEXT         NOT NULL,
+    user_id     UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    category_id UUID         NOT NULL REFERENCES categories (id) ON DELETE RESTRICT,
+    is_pinned   BOOLEAN      NOT NULL DEFAULT FALSE,
+    is_locked   BOOLEAN      NOT NULL DEFAULT FALSE,
+    vote_count  INTEGER      NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_threads_listing ON threads (is_pinned DESC, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id             UUID PRIMARY KEY,
+    content        TEXT        NOT NULL,
+    user_id        UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    thread_id      UUID        NOT NULL REFERENCES threads (id) ON DELETE CASCADE,
+    parent_post_id UUID        REFERENCES posts (id) ON DELETE CASCADE,
+    vote_count     INTEGER     NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_posts_thread_id ON posts (thread_id, created_at);
+
+CREATE TABLE IF NOT EXISTS thread_votes (
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    thread_id  UUID        NOT NULL REFERENCES threads (id) ON DELETE CASCADE,
+    vote_type  SMALLINT    NOT NULL CHECK (vote_type IN (-1, 1)),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, thread_id)
+);
+
+CREATE TABLE IF NOT EXISTS post_votes (
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    post_id    UUID        NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    vote_type  SMALLINT    NOT NULL CHECK (vote_type IN (-1, 1)),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id)
+);
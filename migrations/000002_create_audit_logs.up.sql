@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id          UUID PRIMARY KEY,
+    actor_id    UUID        NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    actor_role  VARCHAR(20) NOT NULL,
+    action      VARCHAR(50) NOT NULL,
+    target_type VARCHAR(30) NOT NULL,
+    target_id   UUID        NOT NULL,
+    reason      TEXT,
+    details     TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs (actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target ON audit_logs (target_type, target_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs (action, created_at DESC);
+
+-- audit_logs is append-only: UPDATE and DELETE are rejected at the database level.
+CREATE OR REPLACE FUNCTION audit_logs_reject_mutation() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_append_only
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION audit_logs_reject_mutation();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_threads_deleted_at;
+
+ALTER TABLE threads DROP COLUMN IF EXISTS deleted_at;
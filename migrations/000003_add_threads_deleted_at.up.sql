@@ -0,0 +1,3 @@
+ALTER TABLE threads ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_threads_deleted_at ON threads (deleted_at) WHERE deleted_at IS NOT NULL;
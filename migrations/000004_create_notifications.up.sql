@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    actor_id   UUID        REFERENCES users (id) ON DELETE SET NULL,
+    type       VARCHAR(30) NOT NULL,
+    thread_id  UUID        REFERENCES threads (id) ON DELETE CASCADE,
+    post_id    UUID        REFERENCES posts (id) ON DELETE CASCADE,
+    milestone  INTEGER,
+    is_read    BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications (user_id) WHERE is_read = FALSE;
+
+-- A vote milestone is announced at most once per target, even if votes are toggled back and forth.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notifications_vote_milestone
+    ON notifications (user_id, thread_id, COALESCE(post_id, '00000000-0000-0000-0000-000000000000'::uuid), milestone)
+    WHERE type = 'vote_milestone';
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id        UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    thread_reply   BOOLEAN     NOT NULL DEFAULT TRUE,
+    post_reply     BOOLEAN     NOT NULL DEFAULT TRUE,
+    mention        BOOLEAN     NOT NULL DEFAULT TRUE,
+    vote_milestone BOOLEAN     NOT NULL DEFAULT TRUE,
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id         UUID PRIMARY KEY,
+    name       VARCHAR(50) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS thread_tags (
+    thread_id UUID NOT NULL REFERENCES threads (id) ON DELETE CASCADE,
+    tag_id    UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (thread_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_thread_tags_tag ON thread_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS post_tags (
+    post_id UUID NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    tag_id  UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (post_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_tags_tag ON post_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS mentions (
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    actor_id   UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    thread_id  UUID        NOT NULL REFERENCES threads (id) ON DELETE CASCADE,
+    post_id    UUID        REFERENCES posts (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mentions_user ON mentions (user_id, created_at DESC);
+
+-- A user is recorded at most once per thread body and once per post, however often they are named.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_mentions_target
+    ON mentions (user_id, thread_id, COALESCE(post_id, '00000000-0000-0000-0000-000000000000'::uuid));
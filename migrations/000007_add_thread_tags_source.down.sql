@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_tags_name_prefix;
+
+DELETE FROM thread_tags a
+    USING thread_tags b
+    WHERE a.thread_id = b.thread_id AND a.tag_id = b.tag_id AND a.source = 'content' AND b.source = 'explicit';
+
+ALTER TABLE thread_tags DROP CONSTRAINT IF EXISTS thread_tags_pkey;
+ALTER TABLE thread_tags ADD PRIMARY KEY (thread_id, tag_id);
+ALTER TABLE thread_tags DROP COLUMN IF EXISTS source;
@@ -0,0 +1,8 @@
+-- Tags chosen by the author are tracked separately from #tags written in the
+-- thread body, so editing one never discards the other.
+ALTER TABLE thread_tags ADD COLUMN IF NOT EXISTS source VARCHAR(10) NOT NULL DEFAULT 'content';
+
+ALTER TABLE thread_tags DROP CONSTRAINT IF EXISTS thread_tags_pkey;
+ALTER TABLE thread_tags ADD PRIMARY KEY (thread_id, tag_id, source);
+
+CREATE INDEX IF NOT EXISTS idx_tags_name_prefix ON tags (name text_pattern_ops);
@@ -0,0 +1,20 @@
+-- An attachment belongs to either a thread body or a post. The reference is
+-- cleared rather than cascaded when its target is purged, so the cleanup job
+-- can still find the stored blob and delete it.
+CREATE TABLE IF NOT EXISTS attachments (
+    id           UUID PRIMARY KEY,
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    thread_id    UUID         REFERENCES threads (id) ON DELETE SET NULL,
+    post_id      UUID         REFERENCES posts (id) ON DELETE SET NULL,
+    storage_key  VARCHAR(255) NOT NULL UNIQUE,
+    filename     VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes   BIGINT       NOT NULL CHECK (size_bytes >= 0),
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CHECK (thread_id IS NULL OR post_id IS NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_thread ON attachments (thread_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_post ON attachments (post_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_user ON attachments (user_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_orphaned ON attachments (created_at) WHERE thread_id IS NULL AND post_id IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS thread_read_positions;
+DROP TABLE IF EXISTS thread_subscriptions;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS thread_subscriptions (
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    thread_id  UUID        NOT NULL REFERENCES threads (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, thread_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_thread_subscriptions_thread ON thread_subscriptions (thread_id);
+
+-- Read positions are kept whether or not the user is subscribed. last_read_at
+-- is the creation time of the last post read, or of the thread itself when
+-- only the opening post has been read.
+CREATE TABLE IF NOT EXISTS thread_read_positions (
+    user_id           UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    thread_id         UUID        NOT NULL REFERENCES threads (id) ON DELETE CASCADE,
+    last_read_post_id UUID        REFERENCES posts (id) ON DELETE SET NULL,
+    last_read_at      TIMESTAMPTZ NOT NULL,
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, thread_id)
+);
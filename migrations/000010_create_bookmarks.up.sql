@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS bookmark_folders (
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmark_folders_user_name ON bookmark_folders (user_id, LOWER(name));
+
+-- Every bookmark records its thread; post_id is set when a single post was
+-- saved. Deleting a folder keeps its bookmarks, they just become unfiled.
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    thread_id  UUID        NOT NULL REFERENCES threads (id) ON DELETE CASCADE,
+    post_id    UUID        REFERENCES posts (id) ON DELETE CASCADE,
+    folder_id  UUID        REFERENCES bookmark_folders (id) ON DELETE SET NULL,
+    note       TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmarks_user_thread ON bookmarks (user_id, thread_id) WHERE post_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmarks_user_post ON bookmarks (user_id, post_id) WHERE post_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_bookmarks_user_created ON bookmarks (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_folder ON bookmarks (folder_id);
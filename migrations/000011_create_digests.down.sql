@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_threads_category_created;
+DROP TABLE IF EXISTS digest_preferences;
+DROP TABLE IF EXISTS category_follows;
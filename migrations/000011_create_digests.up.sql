@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS category_follows (
+    user_id     UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    category_id UUID        NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, category_id)
+);
+
+-- Users without a row get the default weekly digest. The row, and with it the
+-- token behind the unsubscribe link, is created when the user's settings are
+-- first read or their first digest is prepared.
+CREATE TABLE IF NOT EXISTS digest_preferences (
+    user_id           UUID        PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    frequency         VARCHAR(10) NOT NULL DEFAULT 'weekly' CHECK (frequency IN ('off', 'daily', 'weekly')),
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    last_sent_at      TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_threads_category_created ON threads (category_id, created_at DESC) WHERE deleted_at IS NULL;
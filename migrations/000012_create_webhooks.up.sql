@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+    id          UUID PRIMARY KEY,
+    url         TEXT         NOT NULL,
+    secret      VARCHAR(64)  NOT NULL,
+    description VARCHAR(255),
+    is_active   BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_by  UUID         REFERENCES users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS webhook_events (
+    webhook_id UUID        NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    PRIMARY KEY (webhook_id, event_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_type ON webhook_events (event_type);
+
+-- One row per event per webhook; it doubles as the delivery log. Every
+-- delivery of the same event shares event_id, which receivers can use to
+-- drop duplicates.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              UUID PRIMARY KEY,
+    webhook_id      UUID        NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id        UUID        NOT NULL,
+    event_type      VARCHAR(50) NOT NULL,
+    payload         JSONB       NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts        INT         NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_attempt_at TIMESTAMPTZ,
+    response_status INT,
+    response_body   TEXT,
+    last_error      TEXT,
+    redelivery_of   UUID        REFERENCES webhook_deliveries (id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
@@ -0,0 +1,30 @@
+-- Domain events written in the same transaction as the change that raised
+-- them, then published by the relay in the API process. seq keeps the order in
+-- which events were recorded.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id              UUID PRIMARY KEY,
+    seq             BIGSERIAL   NOT NULL,
+    aggregate_type  VARCHAR(50) NOT NULL,
+    aggregate_id    UUID        NOT NULL,
+    event_type      VARCHAR(50) NOT NULL,
+    payload         JSONB       NOT NULL,
+    attempts        INT         NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (seq) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published ON outbox_events (published_at) WHERE published_at IS NOT NULL;
+
+-- Events each consumer has handled, keyed by event ID, so a redelivered
+-- event is recognised and skipped.
+CREATE TABLE IF NOT EXISTS processed_events (
+    consumer     VARCHAR(100) NOT NULL,
+    event_id     UUID         NOT NULL,
+    processed_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (consumer, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events (processed_at);
@@ -0,0 +1,5 @@
+-- An event is queued once per webhook, so dispatching it again after a
+-- failure cannot duplicate deliveries. Redeliveries are extra copies by design.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_webhook_deliveries_event
+    ON webhook_deliveries (webhook_id, event_id)
+    WHERE redelivery_of IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_notifications_event;
+ALTER TABLE notifications DROP COLUMN IF EXISTS event_id;
@@ -0,0 +1,7 @@
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS event_id UUID;
+
+-- A user is notified once per outbox event, so handling an event again after
+-- a failure cannot duplicate reply or mention notifications.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notifications_event
+    ON notifications (event_id, user_id)
+    WHERE event_id IS NOT NULL;